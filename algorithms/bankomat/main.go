@@ -34,21 +34,29 @@ var errInvalidAmount = errors.New("сумма должна быть положи
 // @return {map[int]int} - Карта с количеством банкнот каждого номинала.
 // @return {error} - Ошибка, если сумму выдать невозможно.
 func getMoney(value int) (result map[int]int, err error) {
+	return greedyChange(value, notes)
+}
+
+// greedyChange — жадный размен value на номиналы notes: берем как можно больше
+// самых крупных банкнот, затем переходим к следующему номиналу.
+// Срез notes не изменяется: сортировка выполняется на копии.
+func greedyChange(value int, notes []int) (map[int]int, error) {
 	// Проверка на корректность введенной суммы.
 	if value <= 0 {
 		return nil, errInvalidAmount
 	}
 
-	// Для наглядности, убедимся, что наши банкноты всегда отсортированы.
-	sort.Sort(sort.Reverse(sort.IntSlice(notes)))
+	// Жадному алгоритму нужны номиналы по убыванию.
+	sorted := append([]int(nil), notes...)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
 
-	result = make(map[int]int)
+	result := make(map[int]int)
 	remaining := value
 
 	// Итерируемся по банкнотам от большей к меньшей.
-	for _, note := range notes {
+	for _, note := range sorted {
 		// Если номинал банкноты больше оставшейся суммы, пропускаем его.
-		if note > remaining {
+		if note <= 0 || note > remaining {
 			continue
 		}
 
@@ -92,4 +100,12 @@ func main() {
 		}
 		fmt.Println("--------------------")
 	}
+
+	// Жадный алгоритм оптимален не для всех наборов номиналов.
+	oddNotes := []int{4, 3, 1}
+	fmt.Printf("Номиналы %v, сумма 6:\n", oddNotes)
+	for _, strategy := range []ChangeStrategy{GreedyStrategy{}, OptimalStrategy{}} {
+		money, err := strategy.MakeChange(6, oddNotes)
+		fmt.Printf("  %s: %v, ошибка: %v\n", strategy, money, err)
+	}
 }
@@ -0,0 +1,83 @@
+package main
+
+// Memo — таблица мемоизации для задач динамического программирования.
+//
+// Значение для каждого ключа вычисляется не более одного раза: повторные запросы
+// берут результат из таблицы. Memo рассчитан на рекурсивное использование
+// (compute может сам вызывать GetOrCompute для других ключей), но не на конкурентное.
+type Memo[K comparable, V any] struct {
+	values map[K]V
+}
+
+// NewMemo создает пустую таблицу.
+func NewMemo[K comparable, V any]() *Memo[K, V] {
+	return &Memo[K, V]{values: make(map[K]V)}
+}
+
+// GetOrCompute возвращает сохраненное значение для k или вычисляет его через compute и запоминает.
+func (m *Memo[K, V]) GetOrCompute(k K, compute func() V) V {
+	if v, ok := m.values[k]; ok {
+		return v
+	}
+	// Сохраняем после вычисления: за время рекурсии таблица могла пополниться другими ключами.
+	v := compute()
+	m.values[k] = v
+	return v
+}
+
+// Len возвращает количество запомненных значений.
+func (m *Memo[K, V]) Len() int {
+	return len(m.values)
+}
+
+// changeStep — решение подзадачи "выдать сумму": минимальное число банкнот
+// и номинал последней из них (для восстановления ответа). count < 0 — сумму выдать нельзя.
+type changeStep struct {
+	count int
+	note  int
+}
+
+// optimalChange раскладывает value на минимальное число банкнот из notes (классическая задача
+// о размене монет, coin change) с помощью рекурсии с мемоизацией.
+//
+// В отличие от жадного getMoney, находит решение и для "неканонических" наборов номиналов:
+// например, для notes = {4, 3, 1} и value = 6 жадный алгоритм дает 4+1+1, а оптимальный — 3+3.
+func optimalChange(value int, notes []int) (map[int]int, error) {
+	if value <= 0 {
+		return nil, errInvalidAmount
+	}
+
+	memo := NewMemo[int, changeStep]()
+	var solve func(v int) changeStep
+	solve = func(v int) changeStep {
+		if v == 0 {
+			return changeStep{count: 0}
+		}
+		return memo.GetOrCompute(v, func() changeStep {
+			best := changeStep{count: -1}
+			for _, note := range notes {
+				if note <= 0 || note > v {
+					continue
+				}
+				sub := solve(v - note)
+				if sub.count >= 0 && (best.count < 0 || sub.count+1 < best.count) {
+					best = changeStep{count: sub.count + 1, note: note}
+				}
+			}
+			return best
+		})
+	}
+
+	if solve(value).count < 0 {
+		return nil, errCannotDispense
+	}
+
+	// Восстанавливаем набор банкнот, проходя по сохраненным "последним" номиналам.
+	result := make(map[int]int)
+	for v := value; v > 0; {
+		step := solve(v) // Уже в таблице, повторного вычисления нет.
+		result[step.note]++
+		v -= step.note
+	}
+	return result, nil
+}
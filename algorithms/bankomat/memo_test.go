@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"maps"
+	"testing"
+)
+
+func TestMemoComputesOncePerKey(t *testing.T) {
+	memo := NewMemo[int, int]()
+	calls := make(map[int]int)
+
+	// Числа Фибоначчи: без мемоизации fib(30) вызывал бы compute более миллиона раз.
+	var fib func(n int) int
+	fib = func(n int) int {
+		if n < 2 {
+			return n
+		}
+		return memo.GetOrCompute(n, func() int {
+			calls[n]++
+			return fib(n-1) + fib(n-2)
+		})
+	}
+
+	if got := fib(30); got != 832040 {
+		t.Errorf("fib(30) = %d, ожидалось 832040", got)
+	}
+	fib(30) // Повторный вызов полностью берется из таблицы.
+
+	for n, c := range calls {
+		if c != 1 {
+			t.Errorf("compute для ключа %d вызван %d раз", n, c)
+		}
+	}
+	if memo.Len() != 29 {
+		t.Errorf("Len = %d, ожидалось 29 (ключи 2..30)", memo.Len())
+	}
+}
+
+func TestOptimalChange(t *testing.T) {
+	tests := []struct {
+		name  string
+		value int
+		notes []int
+		want  map[int]int
+	}{
+		{"неканонический набор", 6, []int{4, 3, 1}, map[int]int{3: 2}},
+		{"классический пример", 11, []int{1, 2, 5}, map[int]int{5: 2, 1: 1}},
+		{"без единицы", 30, []int{25, 10}, map[int]int{10: 3}},
+		{"рублевые номиналы", 5600, notes, map[int]int{5000: 1, 500: 1, 100: 1}},
+		{"несортированные номиналы", 12, []int{1, 6, 5}, map[int]int{6: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := optimalChange(tt.value, tt.notes)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("optimalChange(%d, %v) = %v, ожидалось %v", tt.value, tt.notes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptimalChangeErrors(t *testing.T) {
+	if _, err := optimalChange(7, []int{2, 4}); !errors.Is(err, errCannotDispense) {
+		t.Errorf("нечетная сумма четными номиналами: err = %v", err)
+	}
+	if _, err := optimalChange(0, notes); !errors.Is(err, errInvalidAmount) {
+		t.Errorf("нулевая сумма: err = %v", err)
+	}
+}
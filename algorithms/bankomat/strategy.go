@@ -0,0 +1,44 @@
+package main
+
+// ChangeStrategy — стратегия размена суммы на банкноты (паттерн "Стратегия").
+// Вызывающий код выбирает алгоритм, не меняя логику банкомата.
+type ChangeStrategy interface {
+	// MakeChange раскладывает value на номиналы notes и возвращает количество банкнот
+	// каждого номинала. Возвращает errInvalidAmount для неположительной суммы
+	// и errCannotDispense, если сумму выдать нельзя.
+	MakeChange(value int, notes []int) (map[int]int, error)
+}
+
+// GreedyStrategy — жадный размен. Быстрый, оптимален для канонических наборов
+// номиналов (как у настоящих банкнот), но на неканонических может выдать
+// лишние банкноты или вовсе не найти решение.
+type GreedyStrategy struct{}
+
+// MakeChange реализует ChangeStrategy.
+func (GreedyStrategy) MakeChange(value int, notes []int) (map[int]int, error) {
+	return greedyChange(value, notes)
+}
+
+// String возвращает название стратегии для логов.
+func (GreedyStrategy) String() string {
+	return "жадная"
+}
+
+// OptimalStrategy — размен минимальным числом банкнот (динамическое программирование).
+// Работает для любого набора номиналов, но требует O(value * len(notes)) времени.
+type OptimalStrategy struct{}
+
+// MakeChange реализует ChangeStrategy.
+func (OptimalStrategy) MakeChange(value int, notes []int) (map[int]int, error) {
+	return optimalChange(value, notes)
+}
+
+// String возвращает название стратегии для логов.
+func (OptimalStrategy) String() string {
+	return "оптимальная"
+}
+
+// dispense выдает value из номиналов банкомата с помощью выбранной стратегии.
+func dispense(value int, strategy ChangeStrategy) (map[int]int, error) {
+	return strategy.MakeChange(value, notes)
+}
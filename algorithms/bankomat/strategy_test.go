@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestStrategiesAgreeOnCanonicalNotes(t *testing.T) {
+	strategies := []ChangeStrategy{GreedyStrategy{}, OptimalStrategy{}}
+	for _, value := range []int{5600, 2480, 7770, 50} {
+		greedy, err := strategies[0].MakeChange(value, notes)
+		if err != nil {
+			t.Fatalf("жадная стратегия, сумма %d: %v", value, err)
+		}
+		optimal, err := strategies[1].MakeChange(value, notes)
+		if err != nil {
+			t.Fatalf("оптимальная стратегия, сумма %d: %v", value, err)
+		}
+		if !maps.Equal(greedy, optimal) {
+			t.Errorf("сумма %d: жадная %v, оптимальная %v — на каноническом наборе должны совпадать", value, greedy, optimal)
+		}
+	}
+}
+
+func TestStrategiesDifferOnNonCanonicalNotes(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       int
+		notes       []int
+		wantGreedy  map[int]int
+		greedyErr   error
+		wantOptimal map[int]int
+	}{
+		{
+			name:        "жадная берет лишние банкноты",
+			value:       6,
+			notes:       []int{4, 3, 1},
+			wantGreedy:  map[int]int{4: 1, 1: 2},
+			wantOptimal: map[int]int{3: 2},
+		},
+		{
+			name:        "жадная не находит решение",
+			value:       6,
+			notes:       []int{4, 3},
+			greedyErr:   errCannotDispense,
+			wantOptimal: map[int]int{3: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			greedy, err := GreedyStrategy{}.MakeChange(tt.value, tt.notes)
+			if !errors.Is(err, tt.greedyErr) {
+				t.Fatalf("жадная: err = %v, ожидалось %v", err, tt.greedyErr)
+			}
+			if !maps.Equal(greedy, tt.wantGreedy) {
+				t.Errorf("жадная: %v, ожидалось %v", greedy, tt.wantGreedy)
+			}
+
+			optimal, err := OptimalStrategy{}.MakeChange(tt.value, tt.notes)
+			if err != nil {
+				t.Fatalf("оптимальная: неожиданная ошибка %v", err)
+			}
+			if !maps.Equal(optimal, tt.wantOptimal) {
+				t.Errorf("оптимальная: %v, ожидалось %v", optimal, tt.wantOptimal)
+			}
+		})
+	}
+}
+
+func TestGreedyStrategyDoesNotMutateNotes(t *testing.T) {
+	in := []int{1, 3, 4}
+	if _, err := (GreedyStrategy{}).MakeChange(6, in); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if !slices.Equal(in, []int{1, 3, 4}) {
+		t.Errorf("срез номиналов изменен: %v", in)
+	}
+}
+
+func TestDispenseUsesStrategy(t *testing.T) {
+	for _, s := range []ChangeStrategy{GreedyStrategy{}, OptimalStrategy{}} {
+		if _, err := dispense(1234, s); !errors.Is(err, errCannotDispense) {
+			t.Errorf("%v: err = %v, ожидалось %v", s, err, errCannotDispense)
+		}
+		if _, err := dispense(0, s); !errors.Is(err, errInvalidAmount) {
+			t.Errorf("%v: err = %v, ожидалось %v", s, err, errInvalidAmount)
+		}
+	}
+}
@@ -3,12 +3,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
+	"sync"
 	"time"
-
-	"golang.org/x/sync/errgroup"
 )
 
 // Data - структура данных, которую мы обрабатываем в нашем конвейере.
@@ -36,6 +37,7 @@ type Writer interface {
 // Manager управляет всем процессом конвейера.
 type Manager interface {
 	Manage()
+	ManageBestEffort() (written []*Data, failed []int, err error)
 }
 
 // managerImpl - конкретная реализация интерфейса Manager.
@@ -60,56 +62,21 @@ func (m *managerImpl) Manage() {
 	dataList := m.reader.Read()
 	log.Printf("Прочитано %d записей.", len(dataList))
 
-	// Канал для сбора обработанных данных от параллельных воркеров.
-	// Буфер канала равен количеству данных, чтобы воркеры не блокировались.
-	dataChan := make(chan *Data, len(dataList))
-
-	// errgroup используется для управления группой горутин и их ошибками.
-	// Он позволяет легко дождаться завершения всех горутин и получить первую возникшую ошибку.
-	var g errgroup.Group
-
 	// Шаг 2: Параллельная обработка каждой записи.
-	for _, data := range dataList {
-		// Создаем локальную копию переменной `data` для безопасного использования в замыкании (closure).
-		// Это предотвращает гонку данных, когда несколько горутин могут получить указатель на одну и ту же переменную цикла.
-		d := data
-		g.Go(func() error {
-			tempData := d
-			// Последовательно применяем все процессоры к одной записи.
-			for _, processor := range m.processors {
-				var err error
-				tempData, err = processor.Process(*tempData)
-				if err != nil {
-					// Если любой из процессоров возвращает ошибку, вся группа горутин будет отменена.
-					return fmt.Errorf("ошибка обработки данных с ID %d: %w", d.ID, err)
-				}
-			}
-			// Отправляем успешно обработанные данные в канал.
-			dataChan <- tempData
-			return nil
-		})
-	}
-
-	// Ожидаем завершения всех горутин в группе.
-	// Если хотя бы одна горутина вернула ошибку, wg.Wait() вернет эту ошибку.
-	if err := g.Wait(); err != nil {
+	// ParallelMap построен на errgroup: он дожидается всех горутин, возвращает первую
+	// возникшую ошибку и сохраняет порядок результатов в соответствии с входными данными.
+	// Если любой из процессоров возвращает ошибку, вся группа горутин будет отменена.
+	processedData, err := ParallelMap(context.Background(), dataList, 0, func(_ context.Context, d *Data) (*Data, error) {
+		return m.process(d)
+	})
+	if err != nil {
 		log.Printf("Произошла ошибка во время обработки: %v. Процесс остановлен.", err)
-		close(dataChan) // Закрываем канал, чтобы цикл сбора данных завершился.
-		return          // Прекращаем выполнение.
-	}
-	// Закрываем канал после того, как все горутины успешно завершились.
-	// Это сигнал для цикла ниже, что больше данных не будет.
-	close(dataChan)
-
-	// Шаг 3: Сбор всех обработанных данных из канала.
-	var processedData []*Data
-	for d := range dataChan {
-		processedData = append(processedData, d)
+		return // Прекращаем выполнение.
 	}
 
 	log.Printf("Успешно обработано %d записей.", len(processedData))
 
-	// Шаг 4: Запись обработанных данных.
+	// Шаг 3: Запись обработанных данных.
 	if len(processedData) > 0 {
 		m.writer.Write(processedData)
 	} else {
@@ -117,6 +84,62 @@ func (m *managerImpl) Manage() {
 	}
 }
 
+// ManageBestEffort — вариант Manage без fail-fast: ошибка одной записи не останавливает
+// обработку остальных. Успешно обработанные записи записываются (в порядке чтения),
+// ID неудачных возвращаются в failed, а err объединяет (errors.Join) все ошибки обработки.
+// При полном успехе failed и err равны nil.
+func (m *managerImpl) ManageBestEffort() (written []*Data, failed []int, err error) {
+	dataList := m.reader.Read()
+	log.Printf("Прочитано %d записей.", len(dataList))
+
+	// Каждая горутина пишет только в свои ячейки, поэтому мьютекс не нужен.
+	results := make([]*Data, len(dataList))
+	errs := make([]error, len(dataList))
+
+	var wg sync.WaitGroup
+	wg.Add(len(dataList))
+	for i, d := range dataList {
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = m.process(d)
+		}()
+	}
+	wg.Wait()
+
+	for i, d := range dataList {
+		if errs[i] != nil {
+			failed = append(failed, d.ID)
+			continue
+		}
+		written = append(written, results[i])
+	}
+	err = errors.Join(errs...)
+	if err != nil {
+		log.Printf("Не удалось обработать %d записей: %v", len(failed), failed)
+	}
+
+	log.Printf("Успешно обработано %d записей.", len(written))
+	if len(written) > 0 {
+		m.writer.Write(written)
+	} else {
+		log.Println("Нет данных для записи.")
+	}
+	return written, failed, err
+}
+
+// process последовательно применяет все процессоры к одной записи.
+func (m *managerImpl) process(d *Data) (*Data, error) {
+	tempData := d
+	for _, processor := range m.processors {
+		var err error
+		tempData, err = processor.Process(*tempData)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка обработки данных с ID %d: %w", d.ID, err)
+		}
+	}
+	return tempData, nil
+}
+
 // --- Mock-реализации для демонстрации работы ---
 
 // mockReader имитирует чтение данных из источника.
@@ -179,5 +202,11 @@ func main() {
 	manager := NewManager(reader, processors, writer)
 	manager.Manage()
 
+	log.Println("Запуск конвейера в режиме best effort...")
+	_, failed, err := manager.ManageBestEffort()
+	if err != nil {
+		log.Printf("Записи с ошибками: %v", failed)
+	}
+
 	log.Println("Конвейер завершил работу.")
 }
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// sliceReader возвращает заранее заданные записи.
+type sliceReader struct {
+	data []*Data
+}
+
+func (r *sliceReader) Read() []*Data {
+	return r.data
+}
+
+// failingProcessor возвращает ошибку для записей с ID из failIDs.
+type failingProcessor struct {
+	failIDs map[int]bool
+	err     error
+
+	mu        sync.Mutex
+	processed []int
+}
+
+func (p *failingProcessor) Process(d Data) (*Data, error) {
+	p.mu.Lock()
+	p.processed = append(p.processed, d.ID)
+	p.mu.Unlock()
+	if p.failIDs[d.ID] {
+		return nil, p.err
+	}
+	return &d, nil
+}
+
+// recordingWriter запоминает записанные данные.
+type recordingWriter struct {
+	calls int
+	data  []*Data
+}
+
+func (w *recordingWriter) Write(data []*Data) {
+	w.calls++
+	w.data = append(w.data, data...)
+}
+
+func newRecords(n int) []*Data {
+	records := make([]*Data, n)
+	for i := range records {
+		records[i] = &Data{ID: i + 1, Payload: map[string]interface{}{"value": i}}
+	}
+	return records
+}
+
+func TestManageBestEffortWritesSuccessfulRecords(t *testing.T) {
+	errBad := errors.New("bad record")
+	proc := &failingProcessor{failIDs: map[int]bool{3: true}, err: errBad}
+	writer := &recordingWriter{}
+	m := NewManager(&sliceReader{data: newRecords(5)}, []Processor{proc}, writer)
+
+	written, failed, err := m.ManageBestEffort()
+
+	if !errors.Is(err, errBad) {
+		t.Errorf("err = %v, ожидалась обертка над %v", err, errBad)
+	}
+	if len(failed) != 1 || failed[0] != 3 {
+		t.Errorf("failed = %v, ожидалось [3]", failed)
+	}
+	if len(proc.processed) != 5 {
+		t.Errorf("обработано %d записей, ожидалось 5 (без fail-fast)", len(proc.processed))
+	}
+
+	wantIDs := []int{1, 2, 4, 5}
+	if len(written) != len(wantIDs) {
+		t.Fatalf("written = %d записей, ожидалось %d", len(written), len(wantIDs))
+	}
+	for i, id := range wantIDs {
+		if written[i].ID != id {
+			t.Errorf("written[%d].ID = %d, ожидалось %d", i, written[i].ID, id)
+		}
+	}
+	if writer.calls != 1 || len(writer.data) != len(wantIDs) {
+		t.Errorf("writer: вызовов %d, записей %d", writer.calls, len(writer.data))
+	}
+}
+
+func TestManageBestEffortAllSucceed(t *testing.T) {
+	writer := &recordingWriter{}
+	m := NewManager(&sliceReader{data: newRecords(3)}, []Processor{&stringifyValueProcessor{}}, writer)
+
+	written, failed, err := m.ManageBestEffort()
+	if err != nil || failed != nil {
+		t.Errorf("failed = %v, err = %v; ожидался полный успех", failed, err)
+	}
+	if len(written) != 3 || len(writer.data) != 3 {
+		t.Errorf("written = %d, в writer %d, ожидалось 3", len(written), len(writer.data))
+	}
+}
+
+func TestManageBestEffortAllFail(t *testing.T) {
+	proc := &failingProcessor{failIDs: map[int]bool{1: true, 2: true}, err: errors.New("boom")}
+	writer := &recordingWriter{}
+	m := NewManager(&sliceReader{data: newRecords(2)}, []Processor{proc}, writer)
+
+	written, failed, err := m.ManageBestEffort()
+	if err == nil || len(failed) != 2 || len(written) != 0 {
+		t.Errorf("written = %v, failed = %v, err = %v", written, failed, err)
+	}
+	if writer.calls != 0 {
+		t.Errorf("writer вызван %d раз при отсутствии успешных записей", writer.calls)
+	}
+}
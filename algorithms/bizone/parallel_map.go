@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ParallelMap применяет f к каждому элементу items с ограниченной конкурентностью.
+//
+// Одновременно выполняется не более limit вызовов f (limit <= 0 — без ограничения).
+// Результат i-го элемента попадает в i-ю позицию выходного среза, поэтому порядок
+// результатов совпадает с порядком входных данных, независимо от того, какая горутина
+// закончила раньше.
+//
+// При первой ошибке контекст, переданный в f, отменяется, новые вызовы не планируются,
+// а функция возвращает эту ошибку. Сама f должна уважать ctx, чтобы отмена была быстрой.
+func ParallelMap[T, U any](ctx context.Context, items []T, limit int, f func(ctx context.Context, item T) (U, error)) ([]U, error) {
+	g, gctx := errgroup.WithContext(ctx)
+	if limit > 0 {
+		g.SetLimit(limit)
+	}
+
+	// Каждая горутина пишет только в свою ячейку, поэтому мьютекс не нужен.
+	results := make([]U, len(items))
+
+	for i, item := range items {
+		// Если группа уже отменена (ошибка или отмена родительского контекста),
+		// новые задачи не запускаем.
+		if gctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			res, err := f(gctx, item)
+			if err != nil {
+				return err
+			}
+			results[i] = res
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	// Отмена родительского контекста без ошибок в f тоже считается неудачей.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
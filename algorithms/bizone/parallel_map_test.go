@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelMapPreservesOrder(t *testing.T) {
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+
+	got, err := ParallelMap(context.Background(), items, 8, func(_ context.Context, v int) (int, error) {
+		// Случайная задержка перемешивает порядок завершения горутин.
+		time.Sleep(time.Duration(rand.Intn(3)) * time.Millisecond)
+		return v * v, nil
+	})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(got) != len(items) {
+		t.Fatalf("len = %d, ожидалось %d", len(got), len(items))
+	}
+	for i, v := range got {
+		if v != i*i {
+			t.Errorf("got[%d] = %d, ожидалось %d", i, v, i*i)
+		}
+	}
+}
+
+func TestParallelMapRespectsLimit(t *testing.T) {
+	const limit = 3
+	var current, maxSeen atomic.Int32
+
+	items := make([]int, 20)
+	_, err := ParallelMap(context.Background(), items, limit, func(_ context.Context, v int) (int, error) {
+		n := current.Add(1)
+		defer current.Add(-1)
+		for {
+			m := maxSeen.Load()
+			if n <= m || maxSeen.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return v, nil
+	})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if got := maxSeen.Load(); got > limit {
+		t.Errorf("одновременно выполнялось %d вызовов, лимит %d", got, limit)
+	}
+}
+
+func TestParallelMapCancelsOnFirstError(t *testing.T) {
+	errBoom := errors.New("boom")
+	var cancelled atomic.Int32
+
+	items := []int{0, 1, 2, 3}
+	// Барьер: элемент 0 падает только после старта остальных вызовов, иначе ParallelMap
+	// мог бы увидеть отмену раньше и не запустить их вовсе.
+	var started sync.WaitGroup
+	started.Add(len(items) - 1)
+	got, err := ParallelMap(context.Background(), items, 0, func(ctx context.Context, v int) (int, error) {
+		if v == 0 {
+			started.Wait()
+			return 0, errBoom
+		}
+		started.Done()
+		// Остальные вызовы ждут отмены и фиксируют, что она произошла.
+		select {
+		case <-ctx.Done():
+			cancelled.Add(1)
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return v, nil
+		}
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("err = %v, ожидалось %v", err, errBoom)
+	}
+	if got != nil {
+		t.Errorf("при ошибке результаты должны быть nil, получено %v", got)
+	}
+	if n := cancelled.Load(); n != int32(len(items)-1) {
+		t.Errorf("отменено %d вызовов, ожидалось %d", n, len(items)-1)
+	}
+}
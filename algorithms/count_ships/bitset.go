@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// wordSize — количество бит в одном слове BitSet.
+const wordSize = 64
+
+// BitSet — компактное множество неотрицательных целых чисел фиксированного размера.
+//
+// Каждый бит слова uint64 отвечает за одно число, поэтому поле 1000x1000 занимает
+// ~122 КБ вместо нескольких мегабайт у map[int]bool. Используется как "visited"
+// при обходе поля кораблей (flood fill).
+type BitSet struct {
+	words []uint64
+	n     int
+}
+
+// NewBitSet создает BitSet, вмещающий числа из диапазона [0, n).
+func NewBitSet(n int) *BitSet {
+	return &BitSet{
+		words: make([]uint64, (n+wordSize-1)/wordSize),
+		n:     n,
+	}
+}
+
+// Set помечает число i как присутствующее в множестве.
+func (b *BitSet) Set(i int) {
+	b.checkIndex(i)
+	b.words[i/wordSize] |= 1 << (i % wordSize)
+}
+
+// Clear удаляет число i из множества.
+func (b *BitSet) Clear(i int) {
+	b.checkIndex(i)
+	b.words[i/wordSize] &^= 1 << (i % wordSize)
+}
+
+// Test сообщает, присутствует ли число i в множестве.
+func (b *BitSet) Test(i int) bool {
+	b.checkIndex(i)
+	return b.words[i/wordSize]&(1<<(i%wordSize)) != 0
+}
+
+// Len возвращает размер множества в битах (емкость, а не количество установленных бит).
+func (b *BitSet) Len() int {
+	return b.n
+}
+
+// Count возвращает количество установленных бит.
+func (b *BitSet) Count() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// checkIndex паникует при выходе за границы, как это делает обычный срез.
+// Без проверки биты в "хвосте" последнего слова были бы молча доступны.
+func (b *BitSet) checkIndex(i int) {
+	if i < 0 || i >= b.n {
+		panic(fmt.Sprintf("BitSet: индекс %d вне диапазона [0, %d)", i, b.n))
+	}
+}
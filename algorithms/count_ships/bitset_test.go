@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestBitSetAcrossWordBoundaries(t *testing.T) {
+	b := NewBitSet(200)
+	indices := []int{0, 1, 63, 64, 65, 127, 128, 199}
+
+	for _, i := range indices {
+		b.Set(i)
+	}
+	for _, i := range indices {
+		if !b.Test(i) {
+			t.Errorf("Test(%d) = false после Set", i)
+		}
+	}
+	for _, i := range []int{2, 62, 66, 126, 129, 198} {
+		if b.Test(i) {
+			t.Errorf("Test(%d) = true, хотя бит не устанавливался", i)
+		}
+	}
+
+	b.Clear(63)
+	b.Clear(64)
+	if b.Test(63) || b.Test(64) {
+		t.Error("Clear не сбросил биты на границе слов")
+	}
+	if !b.Test(65) || !b.Test(1) {
+		t.Error("Clear задел соседние биты")
+	}
+}
+
+func TestBitSetCount(t *testing.T) {
+	b := NewBitSet(130)
+	if b.Len() != 130 {
+		t.Fatalf("Len = %d, ожидалось 130", b.Len())
+	}
+	if b.Count() != 0 {
+		t.Fatalf("Count пустого множества = %d", b.Count())
+	}
+
+	for i := 0; i < 130; i += 3 {
+		b.Set(i)
+	}
+	b.Set(3) // Повторная установка не должна менять счетчик.
+	if got, want := b.Count(), 44; got != want {
+		t.Errorf("Count = %d, ожидалось %d", got, want)
+	}
+
+	b.Clear(0)
+	b.Clear(1) // Сброс неустановленного бита тоже не влияет на счетчик.
+	if got, want := b.Count(), 43; got != want {
+		t.Errorf("Count после Clear = %d, ожидалось %d", got, want)
+	}
+}
+
+func TestBitSetOutOfRangePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ожидалась паника при выходе за границы")
+		}
+	}()
+	NewBitSet(10).Set(10)
+}
@@ -3,9 +3,33 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
+// DimensionError сообщает, что поле нельзя разбить на целые строки: ширина
+// не положительна или длина поля не кратна ей.
+type DimensionError struct {
+	Length int // Длина одномерного среза поля.
+	Width  int // Заявленная ширина поля.
+}
+
+func (e *DimensionError) Error() string {
+	if e.Width <= 0 {
+		return fmt.Sprintf("ширина поля должна быть положительной, получено %d", e.Width)
+	}
+	return fmt.Sprintf("длина поля (%d) не кратна его ширине (%d)", e.Length, e.Width)
+}
+
+// Padding возвращает, сколько ячеек не хватает до последней полной строки.
+// При неположительной ширине дополнение не поможет, и Padding возвращает 0.
+func (e *DimensionError) Padding() int {
+	if e.Width <= 0 {
+		return 0
+	}
+	return e.Width - e.Length%e.Width
+}
+
 // calculateShips считает количество кораблей на поле боя.
 // Корабль — это одна или несколько смежных (по горизонтали или вертикали) ячеек со значением 1.
 //
@@ -18,11 +42,14 @@ import (
 // @param {int} height - высота поля (для полноты картины, хотя в данном алгоритме не используется напрямую).
 // @return {int} - количество кораблей.
 func calculateShips(battleField []int, width int) (int, error) {
+	if width <= 0 {
+		return 0, &DimensionError{Length: len(battleField), Width: width}
+	}
 	if len(battleField) == 0 {
 		return 0, nil
 	}
 	if len(battleField)%width != 0 {
-		return 0, fmt.Errorf("длина поля (%d) не кратна его ширине (%d)", len(battleField), width)
+		return 0, &DimensionError{Length: len(battleField), Width: width}
 	}
 
 	shipCount := 0
@@ -120,4 +147,23 @@ func main() {
 		// 3. (2,0)-(2,1)
 		fmt.Printf("Количество кораблей на поле боя 2: %d\n", shipCount2)
 	}
+
+	fmt.Println("\n--- Поле 1 построчно (ShipCounter) ---")
+	counter := NewShipCounter(width1)
+	for start := 0; start < len(battleField1); start += width1 {
+		if err := counter.AddRow(battleField1[start : start+width1]); err != nil {
+			fmt.Printf("Ошибка: %v\n", err)
+			break
+		}
+		fmt.Printf("После строки %d: %d\n", start/width1+1, counter.Count())
+	}
+
+	fmt.Println("\n--- Отчет по полю 2 в JSON ---")
+	report, err := buildShipReport(battleField2, width2)
+	if err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		return
+	}
+	out, _ := json.Marshal(report)
+	fmt.Println(string(out))
 }
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCalculateShips(t *testing.T) {
+	field := []int{
+		1, 1, 0, 0,
+		0, 0, 0, 1,
+		1, 1, 0, 1,
+	}
+	got, err := calculateShips(field, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 3 {
+		t.Errorf("calculateShips = %d, ожидалось 3", got)
+	}
+}
+
+func TestCalculateShipsDimensionError(t *testing.T) {
+	_, err := calculateShips(make([]int, 7), 3)
+
+	var dimErr *DimensionError
+	if !errors.As(err, &dimErr) {
+		t.Fatalf("err = %v (%T), ожидалась *DimensionError", err, err)
+	}
+	if dimErr.Length != 7 || dimErr.Width != 3 {
+		t.Errorf("DimensionError = %+v, ожидалось {Length:7 Width:3}", *dimErr)
+	}
+	if p := dimErr.Padding(); p != 2 {
+		t.Errorf("Padding() = %d, ожидалось 2", p)
+	}
+	if want := "длина поля (7) не кратна его ширине (3)"; err.Error() != want {
+		t.Errorf("Error() = %q, ожидалось %q", err.Error(), want)
+	}
+}
+
+func TestCalculateShipsNonPositiveWidth(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		field []int
+		width int
+	}{
+		{"нулевая ширина", []int{1, 0, 1}, 0},
+		{"отрицательная ширина", []int{1, 0, 1}, -2},
+		{"пустое поле с нулевой шириной", nil, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := calculateShips(tc.field, tc.width)
+			var dimErr *DimensionError
+			if !errors.As(err, &dimErr) {
+				t.Fatalf("err = %v, ожидалась *DimensionError", err)
+			}
+			if p := dimErr.Padding(); p != 0 {
+				t.Errorf("Padding() = %d, ожидалось 0", p)
+			}
+		})
+	}
+}
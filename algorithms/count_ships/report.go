@@ -0,0 +1,96 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+)
+
+// Coord — координаты ячейки поля. В JSON сериализуется парой [row, col].
+type Coord struct {
+	Row, Col int
+}
+
+// MarshalJSON реализует json.Marshaler.
+func (c Coord) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]int{c.Row, c.Col})
+}
+
+// ShipReport — подробный результат разбора поля: не только число кораблей,
+// но и их размеры и координаты, например для CLI или API.
+type ShipReport struct {
+	Count int         // Количество кораблей.
+	Sizes map[int]int // Размер корабля (в ячейках) -> сколько таких кораблей.
+	Ships [][]Coord   // Ячейки каждого корабля.
+}
+
+// MarshalJSON реализует json.Marshaler: поля называются в нижнем регистре (count, sizes, ships),
+// а у пустого отчета sizes и ships сериализуются пустыми, а не null.
+func (r ShipReport) MarshalJSON() ([]byte, error) {
+	sizes, ships := r.Sizes, r.Ships
+	if sizes == nil {
+		sizes = map[int]int{}
+	}
+	if ships == nil {
+		ships = [][]Coord{}
+	}
+	return json.Marshal(struct {
+		Count int         `json:"count"`
+		Sizes map[int]int `json:"sizes"`
+		Ships [][]Coord   `json:"ships"`
+	}{r.Count, sizes, ships})
+}
+
+// buildShipReport находит корабли обходом в ширину по связным компонентам поля.
+// Посещенные ячейки отмечаются в BitSet. Корабли перечислены в порядке их первой
+// ячейки при обходе поля по строкам, ячейки корабля — в том же порядке.
+func buildShipReport(battleField []int, width int) (ShipReport, error) {
+	if width <= 0 {
+		return ShipReport{}, &DimensionError{Length: len(battleField), Width: width}
+	}
+	report := ShipReport{Sizes: make(map[int]int)}
+	if len(battleField) == 0 {
+		return report, nil
+	}
+	if len(battleField)%width != 0 {
+		return ShipReport{}, &DimensionError{Length: len(battleField), Width: width}
+	}
+
+	height := len(battleField) / width
+	visited := NewBitSet(len(battleField))
+	for start, cell := range battleField {
+		if cell == 0 || visited.Test(start) {
+			continue
+		}
+
+		var ship []Coord
+		queue := []int{start}
+		visited.Set(start)
+		for len(queue) > 0 {
+			i := queue[0]
+			queue = queue[1:]
+			row, col := i/width, i%width
+			ship = append(ship, Coord{Row: row, Col: col})
+
+			neighbors := [4][2]int{{row - 1, col}, {row + 1, col}, {row, col - 1}, {row, col + 1}}
+			for _, n := range neighbors {
+				r, c := n[0], n[1]
+				if r < 0 || r >= height || c < 0 || c >= width {
+					continue
+				}
+				if j := r*width + c; battleField[j] == 1 && !visited.Test(j) {
+					visited.Set(j)
+					queue = append(queue, j)
+				}
+			}
+		}
+
+		slices.SortFunc(ship, func(a, b Coord) int {
+			return cmp.Or(cmp.Compare(a.Row, b.Row), cmp.Compare(a.Col, b.Col))
+		})
+		report.Ships = append(report.Ships, ship)
+		report.Sizes[len(ship)]++
+		report.Count++
+	}
+	return report, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestBuildShipReportJSON(t *testing.T) {
+	field := []int{
+		1, 1, 0, 0,
+		0, 0, 0, 1,
+		1, 0, 0, 1,
+	}
+	report, err := buildShipReport(field, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"count":3,"sizes":{"1":1,"2":2},"ships":[[[0,0],[0,1]],[[1,3],[2,3]],[[2,0]]]}`
+	if string(got) != want {
+		t.Errorf("JSON:\n%s\nожидалось:\n%s", got, want)
+	}
+}
+
+func TestBuildShipReportCoordinates(t *testing.T) {
+	// Г-образный корабль: координаты идут парами (row, col) в порядке обхода по строкам.
+	field := []int{
+		0, 1,
+		1, 1,
+	}
+	report, err := buildShipReport(field, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Count != 1 || report.Sizes[3] != 1 {
+		t.Fatalf("report = %+v, ожидался один корабль из 3 ячеек", report)
+	}
+	want := []Coord{{0, 1}, {1, 0}, {1, 1}}
+	for i, c := range report.Ships[0] {
+		if c != want[i] {
+			t.Errorf("ячейка %d = %+v, ожидалось %+v", i, c, want[i])
+		}
+	}
+}
+
+func TestBuildShipReportMatchesCalculateShips(t *testing.T) {
+	field := []int{
+		1, 0, 0, 1, 1,
+		0, 1, 0, 0, 0,
+		0, 1, 0, 1, 1,
+		0, 1, 0, 0, 0,
+		0, 1, 0, 1, 1,
+	}
+	report, err := buildShipReport(field, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, _ := calculateShips(field, 5)
+	if report.Count != count {
+		t.Errorf("Count = %d, calculateShips = %d", report.Count, count)
+	}
+}
+
+func TestBuildShipReportEmptyAndInvalid(t *testing.T) {
+	report, err := buildShipReport(nil, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := json.Marshal(report); string(got) != `{"count":0,"sizes":{},"ships":[]}` {
+		t.Errorf("JSON пустого поля = %s", got)
+	}
+
+	var dimErr *DimensionError
+	if _, err := buildShipReport(make([]int, 5), 2); !errors.As(err, &dimErr) {
+		t.Errorf("err = %v, ожидалась *DimensionError", err)
+	}
+	for _, width := range []int{0, -1} {
+		if _, err := buildShipReport(make([]int, 4), width); !errors.As(err, &dimErr) {
+			t.Errorf("width = %d: err = %v, ожидалась *DimensionError", width, err)
+		}
+	}
+}
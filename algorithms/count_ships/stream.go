@@ -0,0 +1,107 @@
+package main
+
+import "fmt"
+
+// ShipCounter считает корабли на поле, строки которого поступают по одной, — для полей,
+// которые не помещаются в память целиком. Хранится только разметка предыдущей строки,
+// поэтому память — O(ширины), независимо от высоты поля.
+//
+// В отличие от calculateShips, который верен только для прямых кораблей (как в
+// "морском бое"), ShipCounter считает связные компоненты любой формы: корабль, у
+// которого несколько "верхних левых" ячеек (например, буквой П), учитывается один раз.
+// На полях из прямых кораблей результаты совпадают.
+//
+// Алгоритм — однопроходная разметка связных компонент. Каждый горизонтальный отрезок
+// из единиц получает новую метку и считается новым кораблем; если отрезок касается
+// корабля из предыдущей строки, их метки объединяются (система непересекающихся
+// множеств), и счетчик уменьшается на каждое объединение двух разных кораблей. После
+// строки метки перенумеровываются в 1..width, так что множества не растут.
+type ShipCounter struct {
+	width int
+	count int
+	rows  int
+
+	prev   []int // Метки предыдущей строки: 0 — вода, иначе номер корабля от 1 до width.
+	cur    []int // Метки текущей строки; номера width+1.. — отрезки этой строки.
+	parent []int // Система непересекающихся множеств над метками prev и cur.
+	remap  []int // Новый номер для корня при перенумерации.
+}
+
+// NewShipCounter создает счетчик для поля шириной width. Паникует, если width меньше 1.
+func NewShipCounter(width int) *ShipCounter {
+	if width < 1 {
+		panic("NewShipCounter: ширина поля должна быть не меньше 1")
+	}
+	return &ShipCounter{
+		width:  width,
+		prev:   make([]int, width),
+		cur:    make([]int, width),
+		parent: make([]int, 2*width+1),
+		remap:  make([]int, 2*width+1),
+	}
+}
+
+// AddRow добавляет очередную строку поля. Строка не сохраняется: после вызова ее
+// можно переиспользовать. Строка неверной длины отклоняется, состояние не меняется.
+func (c *ShipCounter) AddRow(row []int) error {
+	if len(row) != c.width {
+		return fmt.Errorf("строка %d: %d ячеек, ожидалось %d", c.rows, len(row), c.width)
+	}
+	for i := range c.parent {
+		c.parent[i] = i
+	}
+
+	next := c.width // Метки отрезков текущей строки идут после меток предыдущей.
+	for col, cell := range row {
+		if cell == 0 {
+			c.cur[col] = 0
+			continue
+		}
+		if col > 0 && c.cur[col-1] != 0 {
+			c.cur[col] = c.cur[col-1] // Продолжение отрезка.
+		} else {
+			next++
+			c.cur[col] = next
+			c.count++ // Новый отрезок — новый корабль, пока не выяснится обратное.
+		}
+		if top := c.prev[col]; top != 0 {
+			if a, b := c.find(c.cur[col]), c.find(top); a != b {
+				c.parent[a] = b
+				c.count-- // Два корабля оказались одним.
+			}
+		}
+	}
+
+	// Перенумеровываем корабли текущей строки в 1..width: она станет предыдущей.
+	clear(c.remap)
+	label := 0
+	for col, id := range c.cur {
+		if id == 0 {
+			c.prev[col] = 0
+			continue
+		}
+		root := c.find(id)
+		if c.remap[root] == 0 {
+			label++
+			c.remap[root] = label
+		}
+		c.prev[col] = c.remap[root]
+	}
+	c.rows++
+	return nil
+}
+
+// Count возвращает число кораблей в добавленных строках. Корабль, продолжающийся
+// в еще не добавленные строки, уже учтен; если ниже он сольется с другим, Count уменьшится.
+func (c *ShipCounter) Count() int {
+	return c.count
+}
+
+// find возвращает корень множества метки id, сокращая путь.
+func (c *ShipCounter) find(id int) int {
+	for c.parent[id] != id {
+		c.parent[id] = c.parent[c.parent[id]]
+		id = c.parent[id]
+	}
+	return id
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// countStreaming подает поле в ShipCounter по строкам.
+func countStreaming(t *testing.T, field []int, width int) int {
+	t.Helper()
+	c := NewShipCounter(width)
+	for start := 0; start < len(field); start += width {
+		if err := c.AddRow(field[start : start+width]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c.Count()
+}
+
+func TestShipCounterMatchesCalculateShips(t *testing.T) {
+	fields := []struct {
+		field []int
+		width int
+	}{
+		{[]int{
+			1, 0, 0, 1, 1,
+			0, 1, 0, 0, 0,
+			0, 1, 0, 1, 1,
+			0, 1, 0, 0, 0,
+			0, 1, 0, 1, 1,
+		}, 5},
+		{[]int{
+			1, 1, 0, 0,
+			0, 0, 0, 1,
+			1, 1, 0, 1,
+		}, 4},
+		{[]int{1, 0, 1, 0, 1}, 1},
+		{[]int{0, 0, 0, 0}, 2},
+	}
+	for _, f := range fields {
+		want, err := calculateShips(f.field, f.width)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := countStreaming(t, f.field, f.width); got != want {
+			t.Errorf("поле %v: ShipCounter = %d, calculateShips = %d", f.field, got, want)
+		}
+	}
+}
+
+func TestShipCounterRunningCount(t *testing.T) {
+	// Корабль буквой П: две "ножки" сверху сливаются в один корабль в последней строке.
+	c := NewShipCounter(3)
+	rows := [][]int{
+		{1, 0, 1},
+		{1, 0, 1},
+		{1, 1, 1},
+	}
+	want := []int{2, 2, 1}
+	for i, row := range rows {
+		if err := c.AddRow(row); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Count(); got != want[i] {
+			t.Errorf("после строки %d Count = %d, ожидалось %d", i, got, want[i])
+		}
+	}
+}
+
+func TestShipCounterMatchesReportOnArbitraryShapes(t *testing.T) {
+	// На кораблях произвольной формы эталон — обход в ширину из buildShipReport.
+	rnd := rand.New(rand.NewSource(1))
+	for range 200 {
+		width, height := 1+rnd.Intn(12), 1+rnd.Intn(12)
+		field := make([]int, width*height)
+		for i := range field {
+			if rnd.Intn(100) < 45 {
+				field[i] = 1
+			}
+		}
+		report, err := buildShipReport(field, width)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := countStreaming(t, field, width); got != report.Count {
+			t.Fatalf("поле %v (ширина %d): ShipCounter = %d, buildShipReport = %d", field, width, got, report.Count)
+		}
+	}
+}
+
+func TestShipCounterRejectsWrongRowLength(t *testing.T) {
+	c := NewShipCounter(3)
+	if err := c.AddRow([]int{1, 1, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddRow([]int{1, 1}); err == nil {
+		t.Fatal("строка из 2 ячеек при ширине 3 принята")
+	}
+	// Отклоненная строка не меняет состояние.
+	if err := c.AddRow([]int{0, 1, 0}); err != nil || c.Count() != 1 {
+		t.Errorf("после отклоненной строки Count = %d, err = %v; ожидалось 1, nil", c.Count(), err)
+	}
+}
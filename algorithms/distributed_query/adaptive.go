@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+)
+
+// adaptiveProbeEvery — через сколько пропущенных запросов "плохой" хост получает
+// одну пробную попытку, чтобы планировщик заметил его восстановление.
+const adaptiveProbeEvery = 10
+
+// AdaptiveScheduler распределяет попытки между хостами по их недавней успешности.
+//
+// Для каждого хоста хранится EWMA доли успешных попыток (1 — успех или ErrNotFound,
+// 0 — прочая ошибка). Хост с долей успеха r получает ceil(maxAttempts*r) попыток,
+// но не меньше одной, а при r ниже порога skipBelow пропускается вовсе — не тратим
+// ретраи и время на хост, который почти всегда падает. Каждый adaptiveProbeEvery-й
+// запрос такой хост все же получает одну пробную попытку; если она успешна, доля
+// успеха растет и хост постепенно возвращается в работу. Если пропустить пришлось бы
+// все хосты запроса, каждый получает по одной попытке.
+//
+// Новые хосты считаются здоровыми. Один планировщик разделяется между вызовами Query
+// (и горутинами), поэтому статистика накапливается между запросами.
+type AdaptiveScheduler struct {
+	alpha      float64
+	skipBelow  float64
+	probeEvery int
+
+	mu    sync.Mutex
+	hosts map[string]*hostHealth
+}
+
+// hostHealth — статистика одного хоста.
+type hostHealth struct {
+	success *EWMA
+	skipped int // Сколько запросов подряд хост пропущен.
+}
+
+// NewAdaptiveScheduler создает планировщик со сглаживающим коэффициентом alpha
+// (см. NewEWMA) и порогом пропуска skipBelow из [0, 1): 0 не пропускает хосты,
+// а порог 1 и выше пропускал бы даже здоровые. Паникует, если skipBelow вне диапазона.
+func NewAdaptiveScheduler(alpha, skipBelow float64) *AdaptiveScheduler {
+	NewEWMA(alpha) // Проверяем alpha сразу, а не при первом запросе к новому хосту.
+	if skipBelow < 0 || skipBelow >= 1 {
+		panic("NewAdaptiveScheduler: skipBelow должен быть в диапазоне [0, 1)")
+	}
+	return &AdaptiveScheduler{
+		alpha:      alpha,
+		skipBelow:  skipBelow,
+		probeEvery: adaptiveProbeEvery,
+		hosts:      make(map[string]*hostHealth),
+	}
+}
+
+// Query — сокращение для DistributedQuery с опциями opts и WithScheduler(s):
+// число попыток на хост подбирается по истории, а статистика обновляется по результатам.
+func (s *AdaptiveScheduler) Query(ctx context.Context, query string, replicas []DatabaseHost, opts ...QueryOption) (string, error) {
+	return DistributedQuery(ctx, query, replicas, append(slices.Clip(opts), WithScheduler(s))...)
+}
+
+// SuccessRatio возвращает текущую оценку доли успешных попыток хоста (1 для неизвестного).
+func (s *AdaptiveScheduler) SuccessRatio(host string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.hosts[host]
+	if !ok {
+		return 1
+	}
+	return h.success.Value()
+}
+
+// plan возвращает число попыток для каждого из хостов names при лимите maxAttempts.
+func (s *AdaptiveScheduler) plan(names []string, maxAttempts int) []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]int, len(names))
+	anyScheduled := false
+	for i, name := range names {
+		res[i] = s.attemptsLocked(name, maxAttempts)
+		anyScheduled = anyScheduled || res[i] > 0
+	}
+	if !anyScheduled {
+		// Лучше дать шанс плохим хостам, чем заведомо вернуть ошибку без запросов.
+		for i := range res {
+			res[i] = 1
+		}
+	}
+	return res
+}
+
+func (s *AdaptiveScheduler) attemptsLocked(name string, maxAttempts int) int {
+	h := s.healthLocked(name)
+	ratio := h.success.Value()
+	if ratio < s.skipBelow {
+		if h.skipped >= s.probeEvery {
+			h.skipped = 0
+			return 1
+		}
+		h.skipped++
+		return 0
+	}
+	h.skipped = 0
+	return max(1, int(math.Ceil(float64(maxAttempts)*ratio)))
+}
+
+// observe учитывает результат одной попытки к хосту.
+func (s *AdaptiveScheduler) observe(name string, success bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sample := 0.0
+	if success {
+		sample = 1
+	}
+	s.healthLocked(name).success.Add(sample)
+}
+
+func (s *AdaptiveScheduler) healthLocked(name string) *hostHealth {
+	h, ok := s.hosts[name]
+	if !ok {
+		h = &hostHealth{success: NewEWMA(s.alpha)}
+		h.success.Add(1) // Оптимистичная начальная оценка: первая ошибка не обнуляет хост.
+		s.hosts[name] = h
+	}
+	return h
+}
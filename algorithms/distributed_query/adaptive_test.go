@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// newTestScheduler создает планировщик с заданной частотой пробных попыток.
+func newTestScheduler(alpha, skipBelow float64, probeEvery int) *AdaptiveScheduler {
+	s := NewAdaptiveScheduler(alpha, skipBelow)
+	s.probeEvery = probeEvery
+	return s
+}
+
+// attemptsOf выполняет запрос через планировщик и возвращает число попыток к хосту name.
+func attemptsOf(t *testing.T, s *AdaptiveScheduler, replicas []DatabaseHost, name string) int {
+	t.Helper()
+	_, report, _ := DistributedQueryDetailed(context.Background(), "q", replicas, append(fastOptions(), WithScheduler(s))...)
+	return report[name].Attempts
+}
+
+func TestAdaptiveSchedulerReducesAttemptsForFailingHost(t *testing.T) {
+	s := newTestScheduler(0.5, 0.1, 3)
+	dead := &scriptedHost{name: "dead", failFirst: -1, err: errTemporary}
+	// Хост, отвечающий ErrNotFound, здоров, но не завершает запрос досрочно,
+	// поэтому dead успевает потратить все выделенные попытки.
+	healthy := &scriptedHost{name: "healthy", failFirst: -1, err: ErrNotFound}
+	replicas := []DatabaseHost{dead, healthy}
+
+	var got []int
+	for i := 0; i < 7; i++ {
+		got = append(got, attemptsOf(t, s, replicas, "dead"))
+	}
+	// 3 попытки опускают долю успеха до 0.125 -> 1 попытка -> ниже порога:
+	// три запроса пропуска, затем одна пробная попытка.
+	want := []int{3, 1, 0, 0, 0, 1, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("попытки к dead по запросам = %v, ожидалось %v", got, want)
+		}
+	}
+
+	if r := s.SuccessRatio("healthy"); r != 1 {
+		t.Errorf("доля успеха healthy = %v, ErrNotFound должен считаться успехом", r)
+	}
+	if s.SuccessRatio("dead") >= 0.1 {
+		t.Errorf("доля успеха dead = %v, ожидалась ниже порога", s.SuccessRatio("dead"))
+	}
+}
+
+func TestAdaptiveSchedulerRecoversAfterProbe(t *testing.T) {
+	s := newTestScheduler(0.5, 0.1, 1)
+	host := &scriptedHost{name: "flaky", failFirst: 4, err: errTemporary}
+	other := &scriptedHost{name: "other", failFirst: -1, err: ErrNotFound}
+	replicas := []DatabaseHost{host, other}
+
+	// Первые 4 вызова DoQuery падают: 3 попытки, затем 1.
+	attemptsOf(t, s, replicas, "flaky")
+	attemptsOf(t, s, replicas, "flaky")
+	if n := attemptsOf(t, s, replicas, "flaky"); n != 0 {
+		t.Fatalf("после серии ошибок хост должен пропускаться, попыток: %d", n)
+	}
+
+	// Пробная попытка успешна, и доля успеха начинает расти.
+	before := s.SuccessRatio("flaky")
+	if n := attemptsOf(t, s, replicas, "flaky"); n != 1 {
+		t.Fatalf("ожидалась одна пробная попытка, получено %d", n)
+	}
+	if after := s.SuccessRatio("flaky"); after <= before {
+		t.Errorf("доля успеха не выросла после успешной пробы: %v -> %v", before, after)
+	}
+	for i := 0; i < 5; i++ {
+		attemptsOf(t, s, replicas, "flaky")
+	}
+	if n := attemptsOf(t, s, replicas, "flaky"); n < 1 {
+		t.Errorf("восстановившийся хост пропускается")
+	}
+}
+
+func TestAdaptiveSchedulerNeverSkipsAllHosts(t *testing.T) {
+	s := newTestScheduler(0.9, 0.5, 100)
+	dead := &scriptedHost{name: "dead", failFirst: -1, err: errTemporary}
+	replicas := []DatabaseHost{dead}
+
+	for i := 0; i < 5; i++ {
+		if n := attemptsOf(t, s, replicas, "dead"); n == 0 {
+			t.Fatalf("запрос %d: единственный хост пропущен без попыток", i)
+		}
+	}
+}
+
+func TestAdaptiveSchedulerSkippedHostKeepsNotFound(t *testing.T) {
+	s := newTestScheduler(0.9, 0.5, 100)
+	dead := &scriptedHost{name: "dead", failFirst: -1, err: errTemporary}
+	missing := &scriptedHost{name: "missing", failFirst: -1, err: ErrNotFound}
+	replicas := []DatabaseHost{dead, missing}
+
+	// Первый запрос опускает долю успеха dead ниже порога.
+	attemptsOf(t, s, replicas, "dead")
+
+	_, report, err := DistributedQueryDetailed(context.Background(), "q", replicas, append(fastOptions(), WithScheduler(s))...)
+	if n := report["dead"].Attempts; n != 0 {
+		t.Fatalf("dead: %d попыток, ожидался пропуск", n)
+	}
+	// Пропущенная реплика не ответила; единственная ответившая не нашла данных.
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("ошибка %v, ожидалась ErrNotFound", err)
+	}
+}
+
+func TestAdaptiveSchedulerQuery(t *testing.T) {
+	s := NewAdaptiveScheduler(0.3, 0.1)
+	res, err := s.Query(context.Background(), "q", []DatabaseHost{&scriptedHost{name: "ok"}})
+	if err != nil || res != "result from ok" {
+		t.Fatalf("Query = (%q, %v)", res, err)
+	}
+	if r := s.SuccessRatio("unknown"); r != 1 {
+		t.Errorf("неизвестный хост: доля успеха %v, ожидалась 1", r)
+	}
+	if _, err := s.Query(context.Background(), "q", []DatabaseHost{&scriptedHost{name: "nf", failFirst: -1, err: ErrNotFound}}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("единогласный ErrNotFound: ошибка %v, ожидалась ErrNotFound", err)
+	}
+}
+
+func TestNewAdaptiveSchedulerPanicsOnInvalidSkipBelow(t *testing.T) {
+	for _, skipBelow := range []float64{-0.1, 1, 1.5} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewAdaptiveScheduler(0.3, %v) должен паниковать", skipBelow)
+				}
+			}()
+			NewAdaptiveScheduler(0.3, skipBelow)
+		}()
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// QueryCoalescer объединяет одинаковые запросы к одному набору реплик.
+//
+// Одновременные вызовы Query с одинаковой строкой запроса выполняют DistributedQuery
+// только один раз (singleflight по тексту запроса), а результат — успех или ошибка —
+// возвращается всем ожидающим. Это снимает нагрузку с реплик для "горячих" ключей.
+//
+// Дополнительно можно задать окно window: успешный результат запоминается на это время,
+// и повторные запросы в пределах окна отвечаются без обращения к репликам.
+// Ошибки не кэшируются, чтобы следующий вызов мог повторить попытку. Просроченные
+// результаты удаляются при добавлении новых, так что память ограничена запросами,
+// сделанными примерно за два последних окна.
+type QueryCoalescer struct {
+	replicas []DatabaseHost
+	window   time.Duration
+	cfg      queryConfig
+	now      func() time.Time
+
+	group singleflight.Group
+
+	mu        sync.Mutex
+	recent    map[string]coalescedResult
+	nextSweep time.Time // Не раньше этого момента recent очищается от просроченных записей.
+}
+
+// coalescedResult — успешный результат запроса и момент, до которого он действителен.
+type coalescedResult struct {
+	value   string
+	expires time.Time
+}
+
+// NewQueryCoalescer создает объединитель запросов к replicas; opts задают параметры
+// каждого DistributedQuery, как в самой DistributedQuery.
+// window = 0 отключает кэширование: объединяются только одновременные вызовы.
+// Паникует при недопустимых опциях (см. ErrInvalidQueryOptions).
+func NewQueryCoalescer(replicas []DatabaseHost, window time.Duration, opts ...QueryOption) *QueryCoalescer {
+	cfg := configWith(opts)
+	if err := cfg.validate(); err != nil {
+		panic("NewQueryCoalescer: " + err.Error())
+	}
+	return &QueryCoalescer{
+		replicas: replicas,
+		window:   window,
+		cfg:      cfg,
+		now:      time.Now,
+		recent:   make(map[string]coalescedResult),
+	}
+}
+
+// Query выполняет запрос через DistributedQuery, объединяя его с одинаковыми
+// одновременными запросами и, при заданном окне, с недавними успешными.
+//
+// Отмена ctx прерывает ожидание только этого вызова: он сразу возвращает ctx.Err().
+// Общий запрос к репликам нужен и другим ожидающим, поэтому он не отменяется
+// (значения ctx ему передаются) и ограничен лишь общим таймаутом.
+func (c *QueryCoalescer) Query(ctx context.Context, query string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if v, ok := c.cached(query); ok {
+		return v, nil
+	}
+
+	ch := c.group.DoChan(query, func() (any, error) {
+		res, err := distributedQuery(context.WithoutCancel(ctx), query, c.replicas, c.cfg, nil)
+		if err == nil && c.window > 0 {
+			c.store(query, res)
+		}
+		return res, err
+	})
+	select {
+	case r := <-ch:
+		if r.Err != nil {
+			return "", r.Err
+		}
+		return r.Val.(string), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// store запоминает успешный результат на окно. Раз в окно заодно удаляет все
+// просроченные записи: иначе поток разных запросов, которые больше не повторяются,
+// копился бы в recent бесконечно. Полный проход не чаще раза в окно дает O(1)
+// амортизированно на вызов.
+func (c *QueryCoalescer) store(query, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if !now.Before(c.nextSweep) {
+		for q, res := range c.recent {
+			if !now.Before(res.expires) {
+				delete(c.recent, q)
+			}
+		}
+		c.nextSweep = now.Add(c.window)
+	}
+	c.recent[query] = coalescedResult{value: value, expires: now.Add(c.window)}
+}
+
+// cached возвращает недавний успешный результат, если окно еще не истекло.
+// Просроченные записи удаляются при обращении к ним.
+func (c *QueryCoalescer) cached(query string) (string, bool) {
+	if c.window <= 0 {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res, ok := c.recent[query]
+	if !ok {
+		return "", false
+	}
+	if !c.now().Before(res.expires) {
+		delete(c.recent, query)
+		return "", false
+	}
+	return res.value, true
+}
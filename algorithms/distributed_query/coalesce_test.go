@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gatedHost отвечает только после закрытия канала release и считает вызовы.
+type gatedHost struct {
+	name    string
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (h *gatedHost) Name() string { return h.name }
+
+func (h *gatedHost) DoQuery(ctx context.Context, query string) (string, error) {
+	h.calls.Add(1)
+	select {
+	case <-h.release:
+		return "result from " + h.name, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func TestQueryCoalescerSharesConcurrentCalls(t *testing.T) {
+	release := make(chan struct{})
+	hosts := []*gatedHost{
+		{name: "a", release: release},
+		{name: "b", release: release},
+	}
+	c := NewQueryCoalescer([]DatabaseHost{hosts[0], hosts[1]}, 0, fastOptions()...)
+
+	const callers = 20
+	results := make([]string, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = c.Query(context.Background(), "SELECT 1")
+		}()
+	}
+
+	// Даем всем вызовам присоединиться к уже выполняющемуся запросу.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := range results {
+		if errs[i] != nil {
+			t.Fatalf("вызов %d: ошибка %v", i, errs[i])
+		}
+		if results[i] != results[0] {
+			t.Errorf("вызов %d получил %q, а вызов 0 — %q", i, results[i], results[0])
+		}
+	}
+	for _, h := range hosts {
+		if n := h.calls.Load(); n != 1 {
+			t.Errorf("реплика %s вызвана %d раз, ожидался 1", h.name, n)
+		}
+	}
+}
+
+func TestQueryCoalescerCallerCancelDoesNotAbortSharedQuery(t *testing.T) {
+	release := make(chan struct{})
+	h := &gatedHost{name: "r", release: release}
+	c := NewQueryCoalescer([]DatabaseHost{h}, 0, fastOptions()...)
+
+	patient := make(chan string, 1)
+	go func() {
+		res, _ := c.Query(context.Background(), "q")
+		patient <- res
+	}()
+	for h.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Нетерпеливый вызывающий присоединяется к запросу и отменяет ожидание.
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := c.Query(ctx, "q"); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, ожидалась context.Canceled", err)
+	}
+
+	close(release)
+	if res := <-patient; res != "result from r" {
+		t.Errorf("терпеливый вызывающий получил %q: общий запрос прерван чужой отменой", res)
+	}
+	if n := h.calls.Load(); n != 1 {
+		t.Errorf("реплика вызвана %d раз, ожидался 1", n)
+	}
+}
+
+func TestQueryCoalescerDistinctQueriesNotShared(t *testing.T) {
+	h := &scriptedHost{name: "r"}
+	c := NewQueryCoalescer([]DatabaseHost{h}, 0, fastOptions()...)
+
+	for _, q := range []string{"q1", "q2", "q1"} {
+		if _, err := c.Query(context.Background(), q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Без окна последовательные вызовы не объединяются.
+	if n := h.calls.Load(); n != 3 {
+		t.Errorf("DoQuery вызван %d раз, ожидалось 3", n)
+	}
+}
+
+func TestQueryCoalescerWindow(t *testing.T) {
+	h := &scriptedHost{name: "r"}
+	c := NewQueryCoalescer([]DatabaseHost{h}, time.Minute, fastOptions()...)
+	now := time.Unix(1_000_000, 0)
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Query(context.Background(), "hot"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := h.calls.Load(); n != 1 {
+		t.Fatalf("в пределах окна DoQuery вызван %d раз, ожидался 1", n)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := c.Query(context.Background(), "hot"); err != nil {
+		t.Fatal(err)
+	}
+	if n := h.calls.Load(); n != 2 {
+		t.Errorf("после окна DoQuery вызван %d раз, ожидалось 2", n)
+	}
+}
+
+func TestQueryCoalescerEvictsExpiredDistinctQueries(t *testing.T) {
+	h := &scriptedHost{name: "r"}
+	c := NewQueryCoalescer([]DatabaseHost{h}, time.Minute, fastOptions()...)
+	now := time.Unix(1_000_000, 0)
+	c.now = func() time.Time { return now }
+
+	// Поток разных запросов, ни один из которых не повторяется.
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 100; i++ {
+			if _, err := c.Query(context.Background(), fmt.Sprintf("q-%d-%d", round, i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		now = now.Add(time.Minute)
+	}
+
+	c.mu.Lock()
+	n := len(c.recent)
+	c.mu.Unlock()
+	// Живы только записи последних окон, а не все 500.
+	if n > 200 {
+		t.Errorf("в кэше %d записей, ожидалось не больше 200", n)
+	}
+}
+
+func TestQueryCoalescerDoesNotCacheErrors(t *testing.T) {
+	h := &scriptedHost{name: "r", failFirst: 3, err: errTemporary}
+	c := NewQueryCoalescer([]DatabaseHost{h}, time.Minute, fastOptions()...)
+
+	if _, err := c.Query(context.Background(), "q"); err == nil {
+		t.Fatal("первый вызов должен завершиться ошибкой: все 3 попытки неудачны")
+	}
+	if res, err := c.Query(context.Background(), "q"); err != nil || res != "result from r" {
+		t.Errorf("второй вызов: %q, %v; ошибка не должна кэшироваться", res, err)
+	}
+}
+
+func TestQueryCoalescerAppliesOptions(t *testing.T) {
+	h := &scriptedHost{name: "r", failFirst: -1, err: errTemporary}
+	c := NewQueryCoalescer([]DatabaseHost{h}, 0, append(fastOptions(), WithMaxAttempts(1))...)
+
+	if _, err := c.Query(context.Background(), "q"); err == nil {
+		t.Fatal("ожидалась ошибка: реплика недоступна")
+	}
+	if n := h.calls.Load(); n != 1 {
+		t.Errorf("вызовов %d, ожидался 1: WithMaxAttempts(1) не учтена", n)
+	}
+}
+
+func TestNewQueryCoalescerPanicsOnInvalidOptions(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ожидалась паника")
+		}
+	}()
+	NewQueryCoalescer(nil, 0, WithMaxAttempts(0))
+}
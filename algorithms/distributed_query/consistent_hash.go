@@ -0,0 +1,123 @@
+package main
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// ConsistentHash — кольцо консистентного хеширования для "липкого" выбора реплики:
+// один и тот же ключ (например, текст запроса) всегда попадает на один и тот же хост,
+// что сохраняет горячим его кеш. Дополняет DistributedQuery, который опрашивает все реплики.
+//
+// Каждый хост размещается на кольце в virtualNodes точках (виртуальные узлы), что
+// выравнивает распределение ключей. Ключ обслуживает первый по часовой стрелке узел
+// после хеша ключа, поэтому при добавлении или удалении хоста переезжает лишь доля
+// ключей порядка 1/N, а не почти все, как при hash(key) % N.
+//
+// Безопасен для конкурентного использования.
+type ConsistentHash struct {
+	virtualNodes int
+
+	mu     sync.RWMutex
+	ring   []uint64                // Отсортированные хеши виртуальных узлов.
+	owners map[uint64]string       // Хеш виртуального узла -> имя хоста.
+	hosts  map[string]DatabaseHost // Имя хоста -> хост.
+}
+
+// NewConsistentHash создает пустое кольцо с virtualNodes виртуальными узлами на хост.
+// Паникует, если virtualNodes < 1.
+func NewConsistentHash(virtualNodes int) *ConsistentHash {
+	if virtualNodes < 1 {
+		panic("NewConsistentHash: virtualNodes должен быть положительным")
+	}
+	return &ConsistentHash{
+		virtualNodes: virtualNodes,
+		owners:       make(map[uint64]string),
+		hosts:        make(map[string]DatabaseHost),
+	}
+}
+
+// hashKey хеширует строку алгоритмом FNV-1a с финальным перемешиванием битов:
+// без него хеши похожих строк ("host#1", "host#2") ложатся на кольцо кучно.
+func hashKey(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	x := h.Sum64()
+	// Финализатор splitmix64.
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// Add добавляет хост под именем name. Если хост с таким именем уже есть, он заменяется
+// без изменения разметки кольца.
+func (c *ConsistentHash) Add(name string, host DatabaseHost) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, found := c.hosts[name]; found {
+		c.hosts[name] = host
+		return
+	}
+	c.hosts[name] = host
+	for i := 0; i < c.virtualNodes; i++ {
+		h := hashKey(name + "#" + strconv.Itoa(i))
+		// Коллизии 64-битных хешей маловероятны; при совпадении узел остается за первым хостом.
+		if _, taken := c.owners[h]; taken {
+			continue
+		}
+		c.owners[h] = name
+		c.ring = append(c.ring, h)
+	}
+	slices.Sort(c.ring)
+}
+
+// Remove удаляет хост name. Его ключи переходят к соседним по кольцу хостам,
+// остальные ключи не переезжают. Возвращает false, если такого хоста не было.
+func (c *ConsistentHash) Remove(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, found := c.hosts[name]; !found {
+		return false
+	}
+	delete(c.hosts, name)
+	c.ring = slices.DeleteFunc(c.ring, func(h uint64) bool {
+		if c.owners[h] != name {
+			return false
+		}
+		delete(c.owners, h)
+		return true
+	})
+	return true
+}
+
+// Get возвращает хост, обслуживающий key, и его имя. ok == false, если кольцо пусто.
+func (c *ConsistentHash) Get(key string) (host DatabaseHost, name string, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.ring) == 0 {
+		return nil, "", false
+	}
+	h := hashKey(key)
+	// Первый узел с хешем >= h; после последнего узла кольцо замыкается на первый.
+	i, _ := slices.BinarySearch(c.ring, h)
+	if i == len(c.ring) {
+		i = 0
+	}
+	name = c.owners[c.ring[i]]
+	return c.hosts[name], name, true
+}
+
+// Len возвращает количество хостов на кольце.
+func (c *ConsistentHash) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.hosts)
+}
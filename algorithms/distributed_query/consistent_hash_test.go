@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// newTestRing создает кольцо с хостами host-0 ... host-(n-1).
+func newTestRing(n int) *ConsistentHash {
+	c := NewConsistentHash(100)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("host-%d", i)
+		c.Add(name, &mockHost{name: name})
+	}
+	return c
+}
+
+// assignments возвращает имя хоста для каждого из n ключей.
+func assignments(c *ConsistentHash, n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		_, names[i], _ = c.Get(fmt.Sprintf("SELECT * FROM users WHERE id=%d", i))
+	}
+	return names
+}
+
+func TestConsistentHashIsSticky(t *testing.T) {
+	c := newTestRing(5)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		host, name, ok := c.Get(key)
+		if !ok {
+			t.Fatalf("Get(%q): кольцо считается пустым", key)
+		}
+		if host.(*mockHost).name != name {
+			t.Fatalf("Get(%q) вернул хост %q под именем %q", key, host.(*mockHost).name, name)
+		}
+		for j := 0; j < 3; j++ {
+			if _, again, _ := c.Get(key); again != name {
+				t.Fatalf("Get(%q) = %q, ранее был %q", key, again, name)
+			}
+		}
+	}
+}
+
+func TestConsistentHashEmpty(t *testing.T) {
+	c := NewConsistentHash(10)
+	if _, _, ok := c.Get("key"); ok {
+		t.Error("Get на пустом кольце вернул ok = true")
+	}
+	c.Add("only", &mockHost{name: "only"})
+	c.Remove("only")
+	if _, _, ok := c.Get("key"); ok {
+		t.Error("Get после удаления последнего хоста вернул ok = true")
+	}
+	if c.Remove("only") {
+		t.Error("повторный Remove вернул true")
+	}
+}
+
+func TestConsistentHashAddRemapsFewKeys(t *testing.T) {
+	const keys = 10000
+	c := newTestRing(4)
+	before := assignments(c, keys)
+
+	c.Add("host-4", &mockHost{name: "host-4"})
+	after := assignments(c, keys)
+
+	moved := 0
+	for i := range before {
+		if before[i] != after[i] {
+			moved++
+			// Ключи переезжают только на новый хост.
+			if after[i] != "host-4" {
+				t.Fatalf("ключ %d переехал с %s на %s, а не на новый хост", i, before[i], after[i])
+			}
+		}
+	}
+	// В идеале переезжает 1/5 ключей; при hash % N переехало бы около 4/5.
+	if frac := float64(moved) / keys; frac < 0.1 || frac > 0.3 {
+		t.Errorf("переехало %.1f%% ключей, ожидалось около 20%%", frac*100)
+	}
+
+	// Удаление хоста возвращает прежнее распределение.
+	c.Remove("host-4")
+	for i, name := range assignments(c, keys) {
+		if name != before[i] {
+			t.Fatalf("после удаления host-4 ключ %d на %s, ранее был на %s", i, name, before[i])
+		}
+	}
+}
+
+func TestConsistentHashDistributionIsEven(t *testing.T) {
+	const hosts, keys = 5, 50000
+	c := newTestRing(hosts)
+
+	counts := make(map[string]int)
+	for _, name := range assignments(c, keys) {
+		counts[name]++
+	}
+	if len(counts) != hosts {
+		t.Fatalf("ключи достались %d хостам из %d", len(counts), hosts)
+	}
+	ideal := keys / hosts
+	for name, n := range counts {
+		// 100 виртуальных узлов дают отклонение порядка 10%; допускаем до 30%.
+		if n < ideal*7/10 || n > ideal*13/10 {
+			t.Errorf("%s: %d ключей, ожидалось около %d", name, n, ideal)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import "sync"
+
+// EWMA — экспоненциально взвешенное скользящее среднее (exponential moving average).
+//
+// Каждое новое значение учитывается с весом alpha, а накопленное среднее — с весом 1-alpha:
+//
+//	value = alpha*sample + (1-alpha)*value
+//
+// Чем больше alpha, тем быстрее среднее реагирует на изменения и тем сильнее шумит.
+// Удобно для оценки задержек реплик: старые замеры "забываются" экспоненциально,
+// без хранения истории. Первый замер становится начальным значением среднего.
+//
+// EWMA не потокобезопасен; для общего доступа используйте SyncEWMA.
+type EWMA struct {
+	alpha       float64
+	value       float64
+	initialized bool
+}
+
+// NewEWMA создает среднее со сглаживающим коэффициентом alpha из (0, 1].
+// Паникует при недопустимом alpha.
+func NewEWMA(alpha float64) *EWMA {
+	if alpha <= 0 || alpha > 1 {
+		panic("NewEWMA: alpha должен быть в диапазоне (0, 1]")
+	}
+	return &EWMA{alpha: alpha}
+}
+
+// Add учитывает новый замер.
+func (e *EWMA) Add(sample float64) {
+	if !e.initialized {
+		e.value = sample
+		e.initialized = true
+		return
+	}
+	e.value += e.alpha * (sample - e.value)
+}
+
+// Value возвращает текущее значение среднего (0, если замеров еще не было).
+func (e *EWMA) Value() float64 {
+	return e.value
+}
+
+// SyncEWMA — потокобезопасная обертка над EWMA. Подходит для случая, когда
+// горутины разных запросов одновременно сообщают задержки одного хоста.
+type SyncEWMA struct {
+	mu sync.Mutex
+	e  EWMA
+}
+
+// NewSyncEWMA создает потокобезопасное среднее со сглаживающим коэффициентом alpha.
+func NewSyncEWMA(alpha float64) *SyncEWMA {
+	return &SyncEWMA{e: *NewEWMA(alpha)}
+}
+
+// Add учитывает новый замер.
+func (s *SyncEWMA) Add(sample float64) {
+	s.mu.Lock()
+	s.e.Add(sample)
+	s.mu.Unlock()
+}
+
+// Value возвращает текущее значение среднего.
+func (s *SyncEWMA) Value() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.e.Value()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEWMAKnownSequence(t *testing.T) {
+	e := NewEWMA(0.5)
+	if e.Value() != 0 {
+		t.Fatalf("Value() без замеров = %v, ожидалось 0", e.Value())
+	}
+
+	// Первый замер — начальное значение, далее value = (value + sample) / 2.
+	samples := []float64{10, 20, 20, 0}
+	want := []float64{10, 15, 17.5, 8.75}
+	for i, s := range samples {
+		e.Add(s)
+		if !approxEqual(e.Value(), want[i]) {
+			t.Errorf("после замера %d: Value() = %v, ожидалось %v", i, e.Value(), want[i])
+		}
+	}
+}
+
+func TestEWMAConvergesToConstant(t *testing.T) {
+	e := NewEWMA(0.2)
+	e.Add(0)
+	for i := 0; i < 100; i++ {
+		e.Add(50)
+	}
+	if math.Abs(e.Value()-50) > 1e-6 {
+		t.Errorf("Value() = %v, ожидалось схождение к 50", e.Value())
+	}
+}
+
+func TestEWMAStepChange(t *testing.T) {
+	const alpha = 0.3
+	e := NewEWMA(alpha)
+	for i := 0; i < 20; i++ {
+		e.Add(100)
+	}
+
+	// После скачка с 100 до 200 отклонение от нового уровня убывает как (1-alpha)^n.
+	prev := e.Value()
+	for n := 1; n <= 10; n++ {
+		e.Add(200)
+		v := e.Value()
+		if v <= prev {
+			t.Fatalf("шаг %d: среднее не растет (%v -> %v)", n, prev, v)
+		}
+		want := 200 - 100*math.Pow(1-alpha, float64(n))
+		if math.Abs(v-want) > 1e-6 {
+			t.Errorf("шаг %d: Value() = %v, ожидалось %v", n, v, want)
+		}
+		prev = v
+	}
+}
+
+func TestEWMAHigherAlphaReactsFaster(t *testing.T) {
+	slow, fast := NewEWMA(0.1), NewEWMA(0.9)
+	for _, e := range []*EWMA{slow, fast} {
+		e.Add(10)
+		e.Add(100)
+	}
+	if fast.Value() <= slow.Value() {
+		t.Errorf("fast = %v, slow = %v: больший alpha должен быстрее реагировать", fast.Value(), slow.Value())
+	}
+}
+
+func TestNewEWMAPanicsOnInvalidAlpha(t *testing.T) {
+	for _, alpha := range []float64{0, -0.1, 1.5} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewEWMA(%v) должен паниковать", alpha)
+				}
+			}()
+			NewEWMA(alpha)
+		}()
+	}
+}
+
+func TestSyncEWMAConcurrent(t *testing.T) {
+	e := NewSyncEWMA(0.1)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				e.Add(42)
+				_ = e.Value()
+			}
+		}()
+	}
+	wg.Wait()
+	if !approxEqual(e.Value(), 42) {
+		t.Errorf("Value() = %v, ожидалось 42", e.Value())
+	}
+}
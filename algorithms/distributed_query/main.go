@@ -9,12 +9,14 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/andrewhigh08/exp/internal/errclass"
 )
 
 // ErrNotFound — это специальная ошибка, которая означает, что данные не найдены.
 // При получении этой ошибки мы не должны делать повторные запросы (retry),
-// так как это окончательный ответ от реплики.
-var ErrNotFound = errors.New("not found")
+// так как это окончательный ответ от реплики, поэтому она помечена как постоянная (см. errclass).
+var ErrNotFound = errclass.Permanent(errors.New("not found"))
 
 // DatabaseHost определяет интерфейс для взаимодействия с хостом базы данных.
 // Это позволяет нам использовать как реальные, так и тестовые (mock) реализации.
@@ -36,45 +38,207 @@ const (
 	totalTimeout  = 2 * time.Second // Общий таймаут для всей операции DistributedQuery.
 )
 
+// NamedHost — необязательный интерфейс хоста, позволяющий узнать его имя для логов и отчетов.
+type NamedHost interface {
+	Name() string
+}
+
+// hostName возвращает имя хоста, если он реализует NamedHost, иначе — имя по индексу.
+func hostName(h DatabaseHost, i int) string {
+	if named, ok := h.(NamedHost); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("replica-%d", i)
+}
+
+// HostAttemptInfo — статистика обращений к одному хосту в рамках одного запроса.
+type HostAttemptInfo struct {
+	Attempts  int   // Сколько раз вызывался DoQuery.
+	LastErr   error // Ошибка последней попытки (nil, если она была успешной).
+	Succeeded bool  // Вернул ли хост успешный ответ.
+}
+
+// queryConfig — параметры выполнения DistributedQuery.
+// Вынесены в структуру, чтобы тесты могли запускать запрос с короткими интервалами.
+type queryConfig struct {
+	QueryOptions
+
+	// after заменяет time.After при ожидании между попытками; тесты подставляют фейковые часы.
+	after func(time.Duration) <-chan time.Time
+}
+
+// defaultQueryConfig возвращает параметры, соответствующие константам пакета.
+func defaultQueryConfig() queryConfig {
+	return queryConfig{
+		QueryOptions: defaultQueryOptions(),
+		after:        time.After,
+	}
+}
+
 // DistributedQuery выполняет запрос параллельно к нескольким репликам.
 // Она возвращает первый полученный успешный ответ.
-// Если все реплики вернули ошибку или истек общий таймаут, функция вернет ошибку.
-func DistributedQuery(query string, replicas []DatabaseHost) (string, error) {
-	// Создаем контекст с общим таймаутом. Это гарантирует, что функция не будет выполняться вечно.
-	ctx, cancel := context.WithTimeout(context.Background(), totalTimeout)
-	defer cancel() // Важно вызвать cancel, чтобы освободить ресурсы контекста.
+// Если все реплики вернули ошибку, истек общий таймаут или отменен ctx,
+// функция вернет ошибку. Если все реплики ответили ErrNotFound, возвращается
+// именно ErrNotFound: данных нет, и это не сбой.
+//
+// Число попыток, интервал между ними и общий таймаут задаются опциями
+// (WithMaxAttempts, WithRetryInterval, WithTotalTimeout); без опций действуют
+// константы пакета. WithScheduler и WithRetryBudget подключают адаптивный
+// планировщик попыток и общий бюджет ретраев. Недопустимые значения опций
+// возвращаются ошибкой ErrInvalidQueryOptions без обращения к репликам.
+//
+// Таймаут накладывается поверх ctx: запрос завершится при первом из двух событий.
+// Так запрос можно привязать к времени жизни внешней операции (например, HTTP-запроса),
+// и при ее отмене горутины всех реплик сразу прекращают работу.
+func DistributedQuery(ctx context.Context, query string, replicas []DatabaseHost, opts ...QueryOption) (string, error) {
+	cfg := configWith(opts)
+	if err := cfg.validate(); err != nil {
+		return "", err
+	}
+	return distributedQuery(ctx, query, replicas, cfg, nil)
+}
+
+// DistributedQueryDetailed работает как DistributedQuery, но дополнительно возвращает
+// отчет по каждому хосту: сколько попыток он потребовал и чем закончилась последняя.
+// Отчет возвращается и при ошибке — именно тогда он особенно полезен.
+// Ключ отчета — имя хоста (см. NamedHost); если имя уже занято другой репликой
+// того же запроса, к нему добавляется индекс реплики ("name#i"), чтобы записи
+// не затирали друг друга.
+//
+// В отличие от DistributedQuery, функция дожидается завершения всех горутин реплик,
+// чтобы отчет был полным. Отмена ctx и недопустимые опции действуют так же, как
+// в DistributedQuery; в последнем случае отчет пуст.
+func DistributedQueryDetailed(ctx context.Context, query string, replicas []DatabaseHost, opts ...QueryOption) (string, map[string]HostAttemptInfo, error) {
+	cfg := configWith(opts)
+	if err := cfg.validate(); err != nil {
+		return "", nil, err
+	}
+	return distributedQueryDetailed(ctx, query, replicas, cfg)
+}
+
+func distributedQueryDetailed(ctx context.Context, query string, replicas []DatabaseHost, cfg queryConfig) (string, map[string]HostAttemptInfo, error) {
+	infos := make([]HostAttemptInfo, len(replicas))
+	result, err := distributedQuery(ctx, query, replicas, cfg, infos)
+
+	report := make(map[string]HostAttemptInfo, len(replicas))
+	for i, rep := range replicas {
+		key := hostName(rep, i)
+		if _, taken := report[key]; taken {
+			key = fmt.Sprintf("%s#%d", key, i)
+		}
+		report[key] = infos[i]
+	}
+	return result, report, err
+}
+
+// distributedQuery — общая реализация. Если infos != nil, каждая горутина записывает
+// статистику своей реплики в infos[i] (у каждой горутины своя ячейка, поэтому гонки нет),
+// а функция перед возвратом дожидается завершения всех горутин.
+func distributedQuery(parent context.Context, query string, replicas []DatabaseHost, cfg queryConfig, infos []HostAttemptInfo) (string, error) {
+	// Создаем контекст с общим таймаутом поверх контекста вызывающего. Это гарантирует,
+	// что функция не будет выполняться вечно, и позволяет вызывающему отменить ее раньше.
+	ctx, cancel := context.WithTimeout(parent, cfg.TotalTimeout)
 
 	// Буферизированный канал для результатов. Размер буфера равен количеству реплик,
 	// чтобы ни одна горутина не заблокировалась при отправке результата.
 	resCh := make(chan Response, len(replicas))
 	var wg sync.WaitGroup
 
+	// Важно вызвать cancel, чтобы освободить ресурсы контекста.
+	// Если нужен отчет, после отмены дожидаемся, пока все горутины запишут свою статистику.
+	defer func() {
+		cancel()
+		if infos != nil {
+			wg.Wait()
+		}
+	}()
+
+	// Сколько попыток дать каждой реплике: поровну или по истории хостов.
+	names := make([]string, len(replicas))
+	for i, rep := range replicas {
+		names[i] = hostName(rep, i)
+	}
+	attempts := make([]int, len(replicas))
+	if cfg.Scheduler != nil {
+		attempts = cfg.Scheduler.plan(names, cfg.MaxAttempts)
+	} else {
+		for i := range attempts {
+			attempts[i] = cfg.MaxAttempts
+		}
+	}
+
 	wg.Add(len(replicas))
 
 	// Запускаем по одной горутине на каждую реплику.
-	for _, rep := range replicas {
-		go func(rep DatabaseHost) {
+	for idx, rep := range replicas {
+		go func(idx int, rep DatabaseHost) {
 			defer wg.Done()
 
-			for i := 0; i < maxAttempts; i++ {
+			// Статистику копим в локальной переменной и записываем в общий срез при выходе.
+			var info HostAttemptInfo
+			if infos != nil {
+				defer func() { infos[idx] = info }()
+			}
+			name := names[idx]
+
+			// fail сообщает основному циклу, что реплика исчерпала попытки, и передает
+			// ее последнюю ошибку. При отмене запроса ничего не отправляется: ошибка
+			// контекста постоянная (см. errclass), но это не сбой реплики, и основной
+			// цикл сам сообщит об отмене или таймауте.
+			fail := func(err error) {
+				if ctx.Err() != nil {
+					return
+				}
+				resCh <- Response{Err: err, Host: name}
+			}
+
+			for i := 0; i < attempts[idx]; i++ {
 				// Перед каждой попыткой проверяем, не был ли отменен контекст (например, по таймауту).
 				if ctx.Err() != nil {
 					return // Выходим, если операция уже отменена.
 				}
+				if cfg.Budget != nil {
+					if i == 0 {
+						cfg.Budget.RecordRequest()
+					} else if !cfg.Budget.TryRetry() {
+						fail(info.LastErr) // Бюджет ретраев исчерпан: не добавляем нагрузки бэкенду.
+						return
+					}
+				}
 
 				resp, err := rep.DoQuery(ctx, query)
+				info.Attempts++
+				info.LastErr = err
+				info.Succeeded = err == nil
+				// Ошибки из-за отмены запроса (таймаут или успех другой реплики) не говорят
+				// о здоровье хоста, поэтому в статистику не попадают.
+				if cfg.Scheduler != nil && (err == nil || ctx.Err() == nil) {
+					cfg.Scheduler.observe(name, err == nil || errors.Is(err, ErrNotFound))
+				}
 
 				// Успешный результат или ошибка ErrNotFound - отправляем в канал и выходим.
 				if err == nil || errors.Is(err, ErrNotFound) {
-					resCh <- Response{Message: resp, Err: err}
+					resCh <- Response{Message: resp, Err: err, Host: name}
 					return
 				}
 
-				// Для всех остальных ошибок делаем повторную попытку (retry).
+				// Постоянная ошибка (см. errclass): повтор к этой реплике не поможет.
+				if !errclass.IsTransient(err) {
+					fail(err)
+					return
+				}
+
+				// Для временных ошибок делаем повторную попытку (retry) — через интервал,
+				// подсказанный бэкендом (см. RetryAfterError), или через обычный.
 				// Используем select, чтобы не блокировать горутину надолго и вовремя среагировать
 				// на отмену контекста.
+				// Нулевой интервал без подсказки — повторяем сразу, не заводя таймер.
+				delay := retryDelay(err, cfg.RetryInterval)
+				if delay <= 0 {
+					continue
+				}
 				select {
-				case <-time.After(retryInterval):
+				case <-cfg.after(delay):
 					// Интервал ожидания прошел, продолжаем цикл для следующей попытки.
 					continue
 				case <-ctx.Done():
@@ -82,7 +246,11 @@ func DistributedQuery(query string, replicas []DatabaseHost) (string, error) {
 					return
 				}
 			}
-		}(rep)
+			// Попытки кончились (если планировщик не выделил ни одной, сообщать нечего).
+			if info.LastErr != nil {
+				fail(info.LastErr)
+			}
+		}(idx, rep)
 	}
 
 	// Запускаем отдельную горутину, которая закроет канал resCh после того,
@@ -92,15 +260,33 @@ func DistributedQuery(query string, replicas []DatabaseHost) (string, error) {
 		close(resCh)
 	}()
 
+	// Последние ошибки реплик (кроме ErrNotFound) с именем хоста — на случай, если
+	// не ответит ни одна.
+	var errs []error
+	// Сколько реплик прислало окончательный ответ и сколько из них — ErrNotFound.
+	// Пропущенные планировщиком реплики ответа не присылают и в счет не идут.
+	responded, notFound := 0, 0
+
 	// Основной цикл ожидания результатов.
 	for {
 		select {
 		case resp, ok := <-resCh:
 			if !ok {
 				// Канал закрыт, и мы не получили ни одного успешного ответа.
-				// Это означает, что все реплики вернули ошибку (кроме ErrNotFound).
-				return "", errors.New("all replicas failed after multiple retries")
+				// Если все ответившие реплики единогласно ответили ErrNotFound, это
+				// окончательный ответ, а не сбой: вызывающий должен отличать "нет данных"
+				// от "не работает". Без единого ответа (пустой список реплик) это сбой.
+				if responded > 0 && notFound == responded && len(errs) == 0 {
+					return "", ErrNotFound
+				}
+				// Иначе возвращаем ошибки всех реплик, чтобы вызывающий мог разобрать их
+				// через errors.Is/As.
+				if len(errs) == 0 {
+					return "", errors.New("all replicas failed after multiple retries")
+				}
+				return "", fmt.Errorf("all replicas failed after multiple retries: %w", errors.Join(errs...))
 			}
+			responded++
 
 			// Получили первый ответ. Если это не ошибка, возвращаем результат.
 			if resp.Err == nil {
@@ -114,13 +300,21 @@ func DistributedQuery(query string, replicas []DatabaseHost) (string, error) {
 			// и ждем ответов от других реплик.
 			if errors.Is(resp.Err, ErrNotFound) {
 				fmt.Printf("Result from %s: %s\n", resp.Host, resp.Err)
+				notFound++
 				// Продолжаем ждать более подходящего ответа.
 				continue
 			}
 
+			// Реплика исчерпала попытки: запоминаем ее последнюю ошибку.
+			errs = append(errs, fmt.Errorf("%s: %w", resp.Host, resp.Err))
+
 		case <-ctx.Done():
+			// Вызывающий отменил запрос или его собственный срок истек раньше нашего таймаута.
+			if err := parent.Err(); err != nil {
+				return "", fmt.Errorf("query canceled: %w", err)
+			}
 			// Сработал общий таймаут.
-			return "", fmt.Errorf("query timed out after %s", totalTimeout)
+			return "", fmt.Errorf("query timed out after %s", cfg.TotalTimeout)
 		}
 	}
 }
@@ -134,6 +328,12 @@ type mockHost struct {
 	notFound     bool // Если true, хост вернет ошибку ErrNotFound.
 	slow         bool // Если true, хост будет отвечать медленно.
 	flakyCounter int
+	retryAfter   time.Duration // Если задан, ошибки flaky-хоста содержат подсказку Retry-After.
+}
+
+// Name реализует интерфейс NamedHost.
+func (h *mockHost) Name() string {
+	return h.name
 }
 
 // DoQuery реализует интерфейс DatabaseHost для mockHost.
@@ -156,6 +356,9 @@ func (h *mockHost) DoQuery(ctx context.Context, query string) (string, error) {
 		h.flakyCounter++
 		// Допустим, хост отвечает успешно только с третьей попытки.
 		if h.flakyCounter < 3 {
+			if h.retryAfter > 0 {
+				return "", &RetryAfterError{Err: errors.New("too many requests"), Delay: h.retryAfter}
+			}
 			return "", errors.New("temporary connection error")
 		}
 	}
@@ -170,7 +373,7 @@ func main() {
 		&mockHost{name: "Replica 2 (ok)"},
 		&mockHost{name: "Replica 3 (slow)", slow: true},
 	}
-	result, err := DistributedQuery("SELECT * FROM users", replicas1)
+	result, err := DistributedQuery(context.Background(), "SELECT * FROM users", replicas1)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 	} else {
@@ -184,11 +387,15 @@ func main() {
 		&mockHost{name: "Replica 1 (flaky)", flaky: true},
 		&mockHost{name: "Replica 2 (flaky)", flaky: true},
 	}
-	result, err = DistributedQuery("SELECT * FROM users", replicas2)
+	result, report, err := DistributedQueryDetailed(context.Background(), "SELECT * FROM users", replicas2)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 	}
-	// Ожидаемый результат: "all replicas failed after multiple retries"
+	for host, info := range report {
+		fmt.Printf("  %s: attempts=%d, succeeded=%t, last error=%v\n", host, info.Attempts, info.Succeeded, info.LastErr)
+	}
+	// Если не ответит ни одна реплика: "all replicas failed after multiple retries: ..."
+	// с последней ошибкой каждой реплики (errors.Join), доступной через errors.Is/As.
 
 
 	fmt.Println("\n--- Сценарий 3: Таймаут ---")
@@ -199,7 +406,7 @@ func main() {
 	// Установим таймаут меньше, чем время ответа реплик
 	// (Для этого сценария, можно было бы передать кастомный таймаут в функцию,
 	// но для простоты примера оставим глобальный)
-	result, err = DistributedQuery("SELECT * FROM users", replicas3)
+	result, err = DistributedQuery(context.Background(), "SELECT * FROM users", replicas3)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 	}
@@ -211,11 +418,88 @@ func main() {
 		&mockHost{name: "Replica 1 (not found)", notFound: true},
 		&mockHost{name: "Replica 2 (ok)"},
 	}
-	result, err = DistributedQuery("SELECT * FROM users WHERE id=123", replicas4)
+	result, err = DistributedQuery(context.Background(), "SELECT * FROM users WHERE id=123", replicas4)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 	} else {
 		fmt.Printf("Final Result: %s\n", result)
 	}
 	// Ожидаемый результат: "result from Replica 2 (ok)"
+
+	fmt.Println("\n--- Сценарий 5: Одинаковые одновременные запросы объединяются ---")
+	coalescer := NewQueryCoalescer([]DatabaseHost{&mockHost{name: "Replica 1 (ok)"}}, time.Second)
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, _ := coalescer.Query(context.Background(), "SELECT * FROM hot_table")
+			fmt.Printf("Caller %d got: %s\n", i, result)
+		}()
+	}
+	wg.Wait()
+	// Ожидаемый результат: одна строка "Success from ...", три одинаковых ответа.
+
+	fmt.Println("\n--- Сценарий 6: Адаптивные ретраи для постоянно падающего хоста ---")
+	scheduler := NewAdaptiveScheduler(0.5, 0.1)
+	// Отрицательный счетчик: хост падает на ближайших ~100 вызовах.
+	broken := &mockHost{name: "Replica 1 (broken)", flaky: true, flakyCounter: -100}
+	for i := 1; i <= 3; i++ {
+		_, _ = scheduler.Query(context.Background(), "SELECT 1", []DatabaseHost{broken, &mockHost{name: "Replica 2 (not found)", notFound: true}})
+		fmt.Printf("Запрос %d: доля успеха %s = %.3f\n", i, broken.name, scheduler.SuccessRatio(broken.name))
+	}
+	// Ожидаемый результат: доля успеха падает, и со временем хост перестает получать попытки.
+
+	fmt.Println("\n--- Сценарий 7: Липкое чтение через консистентное хеширование ---")
+	ring := NewConsistentHash(100)
+	for _, name := range []string{"Replica 1", "Replica 2", "Replica 3"} {
+		ring.Add(name, &mockHost{name: name})
+	}
+	for i := 0; i < 2; i++ {
+		host, name, _ := ring.Get("SELECT * FROM users WHERE id=42")
+		result, _ := host.DoQuery(context.Background(), "SELECT * FROM users WHERE id=42")
+		fmt.Printf("Запрос %d -> %s: %s\n", i+1, name, result)
+	}
+	// Ожидаемый результат: оба раза запрос обслуживает одна и та же реплика.
+
+	fmt.Println("\n--- Сценарий 8: Бюджет ретраев при массовом сбое ---")
+	budget := NewRetryBudget(0.1, 2, 10*time.Second)
+	failing := &mockHost{name: "Replica 1 (failing)", flaky: true, flakyCounter: -100}
+	for i := 1; i <= 5; i++ {
+		_, report, _ := DistributedQueryDetailed(context.Background(), "SELECT 1", []DatabaseHost{failing}, WithRetryBudget(budget))
+		fmt.Printf("Запрос %d: попыток к %s = %d\n", i, failing.name, report[failing.name].Attempts)
+	}
+	// Ожидаемый результат: первые запросы ретраят, затем бюджет исчерпан и делается по одной попытке.
+
+	fmt.Println("\n--- Сценарий 9: Бэкенд подсказывает задержку перед повтором (Retry-After) ---")
+	throttled := &mockHost{name: "Replica 1 (throttled)", flaky: true, retryAfter: 300 * time.Millisecond}
+	start := time.Now()
+	result, err = DistributedQuery(context.Background(), "SELECT * FROM users", []DatabaseHost{throttled})
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		fmt.Printf("Final Result: %s (за %s)\n", result, time.Since(start).Round(100*time.Millisecond))
+	}
+	// Ожидаемый результат: успех с третьей попытки примерно через 600ms вместо обычных интервалов.
+
+	fmt.Println("\n--- Сценарий 10: Параметры повторов через опции ---")
+	impatient := &mockHost{name: "Replica 1 (flaky)", flaky: true}
+	start = time.Now()
+	result, err = DistributedQuery(context.Background(), "SELECT * FROM users", []DatabaseHost{impatient},
+		WithMaxAttempts(5), WithRetryInterval(0))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		fmt.Printf("Final Result: %s (за %s)\n", result, time.Since(start).Round(100*time.Millisecond))
+	}
+	// Ожидаемый результат: успех с третьей попытки сразу, без пауз между повторами.
+
+	fmt.Println("\n--- Сценарий 11: Данных нет ни на одной реплике ---")
+	replicas11 := []DatabaseHost{
+		&mockHost{name: "Replica 1 (not found)", notFound: true},
+		&mockHost{name: "Replica 2 (not found)", notFound: true},
+	}
+	_, err = DistributedQuery(context.Background(), "SELECT * FROM users WHERE id=404", replicas11)
+	fmt.Printf("Error: %v (ErrNotFound: %t)\n", err, errors.Is(err, ErrNotFound))
+	// Ожидаемый результат: ErrNotFound, а не "all replicas failed" — данных нет, сбоя нет.
 }
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andrewhigh08/exp/internal/errclass"
+)
+
+// errTemporary — повторяемая ошибка для тестовых хостов.
+var errTemporary = errors.New("temporary connection error")
+
+// scriptedHost — тестовый хост, который первые failFirst вызовов возвращает err,
+// а затем отвечает успешно. failFirst < 0 означает "всегда ошибка".
+type scriptedHost struct {
+	name      string
+	failFirst int
+	err       error
+	calls     atomic.Int32
+}
+
+func (h *scriptedHost) Name() string { return h.name }
+
+func (h *scriptedHost) DoQuery(ctx context.Context, query string) (string, error) {
+	n := int(h.calls.Add(1))
+	if h.failFirst < 0 || n <= h.failFirst {
+		return "", h.err
+	}
+	return "result from " + h.name, nil
+}
+
+// fastOptions — параметры с короткими интервалами, чтобы тесты не ждали секундами.
+func fastOptions() []QueryOption {
+	return []QueryOption{
+		WithMaxAttempts(3),
+		WithRetryInterval(5 * time.Millisecond),
+		WithTotalTimeout(time.Second),
+	}
+}
+
+// fastConfig — конфигурация с fastOptions для тестов внутренней distributedQuery.
+func fastConfig() queryConfig {
+	return configWith(fastOptions())
+}
+
+func TestDistributedQueryDetailedFlakyHost(t *testing.T) {
+	flaky := &scriptedHost{name: "flaky", failFirst: 2, err: errTemporary}
+
+	result, report, err := distributedQueryDetailed(context.Background(), "q", []DatabaseHost{flaky}, fastConfig())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if result != "result from flaky" {
+		t.Errorf("result = %q", result)
+	}
+
+	info := report["flaky"]
+	if info.Attempts != 3 || !info.Succeeded || info.LastErr != nil {
+		t.Errorf("flaky: %+v, ожидалось 3 попытки и успех", info)
+	}
+}
+
+func TestDistributedQueryDetailedDeadHosts(t *testing.T) {
+	dead1 := &scriptedHost{name: "dead-1", failFirst: -1, err: errTemporary}
+	dead2 := &scriptedHost{name: "dead-2", failFirst: -1, err: errTemporary}
+
+	_, report, err := distributedQueryDetailed(context.Background(), "q", []DatabaseHost{dead1, dead2}, fastConfig())
+	if err == nil {
+		t.Fatal("ожидалась ошибка: все хосты недоступны")
+	}
+
+	if len(report) != 2 {
+		t.Fatalf("отчет содержит %d хостов, ожидалось 2", len(report))
+	}
+	for name, info := range report {
+		if info.Attempts != 3 {
+			t.Errorf("%s: %d попыток, ожидалось 3", name, info.Attempts)
+		}
+		if info.Succeeded || !errors.Is(info.LastErr, errTemporary) {
+			t.Errorf("%s: %+v, ожидалась неудача с errTemporary", name, info)
+		}
+	}
+}
+
+// connError — ошибка соединения с адресом, как у сетевых драйверов.
+type connError struct{ addr string }
+
+func (e *connError) Error() string { return "connection refused: " + e.addr }
+
+func TestDistributedQueryJoinsReplicaErrors(t *testing.T) {
+	refused := &scriptedHost{
+		name:      "refused",
+		failFirst: -1,
+		err:       errclass.Permanent(fmt.Errorf("dial: %w", &connError{addr: "10.0.0.1:5432"})),
+	}
+	flaky := &scriptedHost{name: "flaky", failFirst: -1, err: errTemporary}
+	missing := &scriptedHost{name: "missing", failFirst: -1, err: ErrNotFound}
+
+	_, err := distributedQuery(context.Background(), "q", []DatabaseHost{refused, flaky, missing}, fastConfig(), nil)
+	if err == nil {
+		t.Fatal("ожидалась ошибка: ни одна реплика не ответила")
+	}
+
+	var ce *connError
+	if !errors.As(err, &ce) || ce.addr != "10.0.0.1:5432" {
+		t.Errorf("ошибка соединения потеряна: %v", err)
+	}
+	if !errors.Is(err, errTemporary) {
+		t.Errorf("ошибка flaky потеряна: %v", err)
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Errorf("ErrNotFound не должна попадать в итоговую ошибку: %v", err)
+	}
+	for _, host := range []string{"refused", "flaky"} {
+		if !strings.Contains(err.Error(), host+": ") {
+			t.Errorf("в ошибке нет хоста %s: %v", host, err)
+		}
+	}
+}
+
+func TestDistributedQueryAllNotFound(t *testing.T) {
+	a := &scriptedHost{name: "a", failFirst: -1, err: ErrNotFound}
+	b := &scriptedHost{name: "b", failFirst: -1, err: ErrNotFound}
+
+	_, err := distributedQuery(context.Background(), "q", []DatabaseHost{a, b}, fastConfig(), nil)
+	if err != ErrNotFound {
+		t.Errorf("ошибка %v, ожидалась ErrNotFound", err)
+	}
+}
+
+func TestDistributedQueryNotFoundAndFailure(t *testing.T) {
+	missing := &scriptedHost{name: "missing", failFirst: -1, err: ErrNotFound}
+	broken := &scriptedHost{name: "broken", failFirst: -1, err: errTemporary}
+
+	_, err := distributedQuery(context.Background(), "q", []DatabaseHost{missing, broken}, fastConfig(), nil)
+	// Сломанная реплика могла бы найти данные: "не найдено" здесь не окончательно.
+	if errors.Is(err, ErrNotFound) {
+		t.Errorf("ошибка %v не должна быть ErrNotFound", err)
+	}
+	if !errors.Is(err, errTemporary) {
+		t.Errorf("ошибка %v, ожидалась обертка errTemporary", err)
+	}
+}
+
+func TestDistributedQueryNoReplicasIsNotNotFound(t *testing.T) {
+	_, err := distributedQuery(context.Background(), "q", nil, fastConfig(), nil)
+	// Ни одна реплика не ответила: это сбой, а не "данных нет".
+	if err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("ошибка %v, ожидался сбой, а не ErrNotFound", err)
+	}
+}
+
+func TestDistributedQueryDetailedUnnamedHosts(t *testing.T) {
+	// Хост без метода Name получает имя по индексу.
+	type unnamed struct{ DatabaseHost }
+	host := unnamed{&scriptedHost{name: "x"}}
+
+	_, report, err := distributedQueryDetailed(context.Background(), "q", []DatabaseHost{host}, fastConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, ok := report["replica-0"]; !ok || info.Attempts != 1 {
+		t.Errorf("report = %+v, ожидалась запись replica-0 с одной попыткой", report)
+	}
+}
+
+func TestDistributedQueryDetailedDuplicateNames(t *testing.T) {
+	first := &scriptedHost{name: "db", failFirst: -1, err: errTemporary}
+	second := &scriptedHost{name: "db", failFirst: -1, err: ErrNotFound}
+
+	_, report, _ := distributedQueryDetailed(context.Background(), "q", []DatabaseHost{first, second}, fastConfig())
+	if len(report) != 2 {
+		t.Fatalf("report = %+v, ожидалось две записи", report)
+	}
+	if n := report["db"].Attempts; n != 3 {
+		t.Errorf("db: %d попыток, ожидалось 3", n)
+	}
+	if n := report["db#1"].Attempts; n != 1 {
+		t.Errorf("db#1: %d попыток, ожидалась 1", n)
+	}
+}
+
+func TestErrNotFoundIsPermanent(t *testing.T) {
+	if errclass.IsTransient(ErrNotFound) {
+		t.Error("ErrNotFound классифицирована как временная")
+	}
+	if !errclass.IsTransient(errTemporary) {
+		t.Error("непомеченная ошибка соединения классифицирована как постоянная")
+	}
+}
+
+func TestDistributedQueryStopsRetryingPermanentError(t *testing.T) {
+	errAuth := errclass.Permanent(errors.New("authentication failed"))
+	denied := &scriptedHost{name: "denied", failFirst: -1, err: errAuth}
+	flaky := &scriptedHost{name: "flaky", failFirst: 1, err: errclass.Transient(errTemporary)}
+
+	_, report, err := distributedQueryDetailed(context.Background(), "q", []DatabaseHost{denied}, fastConfig())
+	if err == nil {
+		t.Fatal("ожидалась ошибка: единственный хост отказывает")
+	}
+	if info := report["denied"]; info.Attempts != 1 || !errors.Is(info.LastErr, errAuth) {
+		t.Errorf("denied: %+v, ожидалась одна попытка без повторов", info)
+	}
+
+	// Явно помеченная временная ошибка повторяется как обычно.
+	if _, report, err = distributedQueryDetailed(context.Background(), "q", []DatabaseHost{flaky}, fastConfig()); err != nil {
+		t.Fatal(err)
+	}
+	if info := report["flaky"]; info.Attempts != 2 || !info.Succeeded {
+		t.Errorf("flaky: %+v, ожидалось 2 попытки и успех", info)
+	}
+}
+
+// blockingHost не отвечает, пока не отменят контекст запроса.
+type blockingHost struct {
+	active atomic.Int32 // Незавершенные вызовы DoQuery.
+}
+
+func (h *blockingHost) DoQuery(ctx context.Context, query string) (string, error) {
+	h.active.Add(1)
+	defer h.active.Add(-1)
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestDistributedQueryParentCancel(t *testing.T) {
+	hosts := []*blockingHost{{}, {}, {}}
+	replicas := []DatabaseHost{hosts[0], hosts[1], hosts[2]}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	// С отчетом функция дожидается всех горутин реплик перед возвратом.
+	_, err := distributedQuery(ctx, "q", replicas, fastConfig(), make([]HostAttemptInfo, len(replicas)))
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("запрос завершился через %v после отмены, ожидалось сразу", elapsed)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, ожидалась context.Canceled", err)
+	}
+	for i, h := range hosts {
+		if n := h.active.Load(); n != 0 {
+			t.Errorf("реплика %d: %d незавершенных запросов", i, n)
+		}
+	}
+}
+
+func TestDistributedQueryTimeoutLayeredOnParent(t *testing.T) {
+	cfg := fastConfig()
+	cfg.TotalTimeout = 20 * time.Millisecond
+
+	// Собственный таймаут срабатывает и без срока у вызывающего.
+	_, err := distributedQuery(context.Background(), "q", []DatabaseHost{&blockingHost{}}, cfg, nil)
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, ожидалась ошибка таймаута запроса", err)
+	}
+
+	// Более ранний срок вызывающего побеждает и возвращается как причина.
+	cfg.TotalTimeout = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = distributedQuery(ctx, "q", []DatabaseHost{&blockingHost{}}, cfg, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, ожидалась context.DeadlineExceeded вызывающего", err)
+	}
+}
+
+func TestDistributedQueryCancelIsNotReplicaFailure(t *testing.T) {
+	// Отмененный DoQuery возвращает ошибку контекста, а она постоянная (см. errclass).
+	// Реплика не должна сообщать ее как свой сбой: причина — отмена запроса.
+	for i := 0; i < 20; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(time.Millisecond, cancel)
+		_, err := distributedQuery(ctx, "q", []DatabaseHost{&blockingHost{}, &blockingHost{}}, fastConfig(), nil)
+		if err == nil || !strings.HasPrefix(err.Error(), "query canceled") {
+			t.Fatalf("запуск %d: err = %v, ожидалась ошибка отмены запроса", i, err)
+		}
+
+		cfg := fastConfig()
+		cfg.TotalTimeout = time.Millisecond
+		_, err = distributedQuery(context.Background(), "q", []DatabaseHost{&blockingHost{}, &blockingHost{}}, cfg, nil)
+		if err == nil || !strings.HasPrefix(err.Error(), "query timed out") {
+			t.Fatalf("запуск %d: err = %v, ожидалась ошибка таймаута запроса", i, err)
+		}
+	}
+}
+
+func TestQueryEntryPointsHonorCallerCancel(t *testing.T) {
+	entryPoints := map[string]func(ctx context.Context, replicas []DatabaseHost) error{
+		"DistributedQueryDetailed": func(ctx context.Context, replicas []DatabaseHost) error {
+			_, _, err := DistributedQueryDetailed(ctx, "q", replicas)
+			return err
+		},
+		"AdaptiveScheduler.Query": func(ctx context.Context, replicas []DatabaseHost) error {
+			_, err := NewAdaptiveScheduler(0.3, 0.1).Query(ctx, "q", replicas)
+			return err
+		},
+		"RetryBudget.Query": func(ctx context.Context, replicas []DatabaseHost) error {
+			_, err := NewRetryBudget(0.1, 10, time.Second).Query(ctx, "q", replicas)
+			return err
+		},
+		"QueryCoalescer.Query": func(ctx context.Context, replicas []DatabaseHost) error {
+			_, err := NewQueryCoalescer(replicas, 0).Query(ctx, "q")
+			return err
+		},
+	}
+	for name, query := range entryPoints {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+
+			start := time.Now()
+			err := query(ctx, []DatabaseHost{&blockingHost{}})
+			// Общий таймаут по умолчанию — 2s: без отмены вызов ждал бы его.
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("вызов завершился через %v после отмены, ожидалось сразу", elapsed)
+			}
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("err = %v, ожидалась context.Canceled", err)
+			}
+		})
+	}
+}
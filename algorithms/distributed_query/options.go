@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidQueryOptions возвращается запросом с недопустимыми параметрами
+// (например, WithMaxAttempts(0)); конкретная причина — в тексте обертки.
+var ErrInvalidQueryOptions = errors.New("invalid query options")
+
+// QueryOptions — параметры повторов и таймаута DistributedQuery.
+// Без опций используются значения констант пакета (maxAttempts, retryInterval, totalTimeout),
+// а планировщик и бюджет ретраев не подключены.
+type QueryOptions struct {
+	MaxAttempts   int           // Максимум попыток к одной реплике, включая первую.
+	RetryInterval time.Duration // Пауза между попытками; 0 — повторять сразу.
+	TotalTimeout  time.Duration // Общий таймаут всего запроса.
+
+	Scheduler *AdaptiveScheduler // Если задан, число попыток на хост выбирает он (см. AdaptiveScheduler).
+	Budget    *RetryBudget       // Если задан, каждый ретрай должен уложиться в бюджет (см. RetryBudget).
+}
+
+// QueryOption изменяет QueryOptions; передается в DistributedQuery и DistributedQueryDetailed.
+type QueryOption func(*QueryOptions)
+
+// WithMaxAttempts задает число попыток к одной реплике. 1 отключает повторы.
+// При n меньше 1 запрос завершается ошибкой ErrInvalidQueryOptions.
+func WithMaxAttempts(n int) QueryOption {
+	return func(o *QueryOptions) { o.MaxAttempts = n }
+}
+
+// WithRetryInterval задает паузу между попытками. При 0 повтор выполняется сразу,
+// но подсказка бэкенда (см. RetryAfterError) по-прежнему соблюдается.
+// При отрицательном d запрос завершается ошибкой ErrInvalidQueryOptions.
+func WithRetryInterval(d time.Duration) QueryOption {
+	return func(o *QueryOptions) { o.RetryInterval = d }
+}
+
+// WithTotalTimeout задает общий таймаут запроса. При неположительном d запрос
+// завершается ошибкой ErrInvalidQueryOptions.
+func WithTotalTimeout(d time.Duration) QueryOption {
+	return func(o *QueryOptions) { o.TotalTimeout = d }
+}
+
+// WithScheduler подключает адаптивный планировщик: число попыток к каждому хосту
+// (не больше MaxAttempts) выбирается по его истории, а результаты запроса ее обновляют.
+// nil отключает планировщик.
+func WithScheduler(s *AdaptiveScheduler) QueryOption {
+	return func(o *QueryOptions) { o.Scheduler = s }
+}
+
+// WithRetryBudget подключает общий бюджет ретраев: повторные попытки делаются, только
+// пока он не исчерпан. Один бюджет обычно разделяют все запросы к сервису.
+// nil отключает бюджет.
+func WithRetryBudget(b *RetryBudget) QueryOption {
+	return func(o *QueryOptions) { o.Budget = b }
+}
+
+// defaultQueryOptions возвращает параметры, соответствующие константам пакета.
+func defaultQueryOptions() QueryOptions {
+	return QueryOptions{
+		MaxAttempts:   maxAttempts,
+		RetryInterval: retryInterval,
+		TotalTimeout:  totalTimeout,
+	}
+}
+
+// validate проверяет параметры. Опции вызываются на пути запроса, поэтому
+// недопустимое значение — ошибка запроса, а не паника.
+func (o QueryOptions) validate() error {
+	switch {
+	case o.MaxAttempts < 1:
+		return fmt.Errorf("%w: MaxAttempts = %d, must be at least 1", ErrInvalidQueryOptions, o.MaxAttempts)
+	case o.RetryInterval < 0:
+		return fmt.Errorf("%w: RetryInterval = %s, must not be negative", ErrInvalidQueryOptions, o.RetryInterval)
+	case o.TotalTimeout <= 0:
+		return fmt.Errorf("%w: TotalTimeout = %s, must be positive", ErrInvalidQueryOptions, o.TotalTimeout)
+	}
+	return nil
+}
+
+// configWith возвращает параметры по умолчанию с примененными опциями.
+func configWith(opts []QueryOption) queryConfig {
+	cfg := defaultQueryConfig()
+	for _, opt := range opts {
+		opt(&cfg.QueryOptions)
+	}
+	return cfg
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConfigWithDefaults(t *testing.T) {
+	got := configWith(nil).QueryOptions
+	want := QueryOptions{MaxAttempts: maxAttempts, RetryInterval: retryInterval, TotalTimeout: totalTimeout}
+	if got != want {
+		t.Errorf("параметры по умолчанию %+v, ожидалось %+v", got, want)
+	}
+}
+
+func TestConfigWithOptions(t *testing.T) {
+	got := configWith([]QueryOption{
+		WithMaxAttempts(5),
+		WithRetryInterval(time.Second),
+		WithTotalTimeout(time.Minute),
+	}).QueryOptions
+	want := QueryOptions{MaxAttempts: 5, RetryInterval: time.Second, TotalTimeout: time.Minute}
+	if got != want {
+		t.Errorf("параметры %+v, ожидалось %+v", got, want)
+	}
+}
+
+func TestZeroRetryIntervalSkipsWait(t *testing.T) {
+	flaky := &scriptedHost{name: "flaky", failFirst: 2, err: errTemporary}
+	clock := &fakeClock{}
+	cfg := configWith([]QueryOption{WithRetryInterval(0)})
+	cfg.after = clock.After
+
+	if _, err := distributedQuery(context.Background(), "q", []DatabaseHost{flaky}, cfg, nil); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if n := flaky.calls.Load(); n != 3 {
+		t.Errorf("вызовов %d, ожидалось 3", n)
+	}
+	if delays := clock.Delays(); len(delays) != 0 {
+		t.Errorf("ожидания между попытками %v, ожидалось ни одного", delays)
+	}
+}
+
+func TestMaxAttemptsOneCallsEachReplicaOnce(t *testing.T) {
+	a := &scriptedHost{name: "a", failFirst: -1, err: errTemporary}
+	b := &scriptedHost{name: "b", failFirst: -1, err: errTemporary}
+
+	_, err := DistributedQuery(context.Background(), "q", []DatabaseHost{a, b}, WithMaxAttempts(1))
+	if err == nil {
+		t.Fatal("ожидалась ошибка: все реплики недоступны")
+	}
+	for _, h := range []*scriptedHost{a, b} {
+		if n := h.calls.Load(); n != 1 {
+			t.Errorf("%s: вызовов %d, ожидалось 1", h.name, n)
+		}
+	}
+}
+
+func TestInvalidOptionsReturnError(t *testing.T) {
+	tests := map[string]QueryOption{
+		"WithMaxAttempts(0)":    WithMaxAttempts(0),
+		"WithRetryInterval(-1)": WithRetryInterval(-1),
+		"WithTotalTimeout(0)":   WithTotalTimeout(0),
+	}
+	for name, opt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &scriptedHost{name: "ok"}
+			if _, err := DistributedQuery(context.Background(), "q", []DatabaseHost{h}, opt); !errors.Is(err, ErrInvalidQueryOptions) {
+				t.Errorf("DistributedQuery: ошибка %v, ожидалась ErrInvalidQueryOptions", err)
+			}
+			if _, _, err := DistributedQueryDetailed(context.Background(), "q", []DatabaseHost{h}, opt); !errors.Is(err, ErrInvalidQueryOptions) {
+				t.Errorf("DistributedQueryDetailed: ошибка %v, ожидалась ErrInvalidQueryOptions", err)
+			}
+			if n := h.calls.Load(); n != 0 {
+				t.Errorf("реплика вызвана %d раз, ожидалось ни одного", n)
+			}
+		})
+	}
+}
+
+func TestQueryHelpersAcceptOptions(t *testing.T) {
+	helpers := map[string]func(replicas []DatabaseHost, opts ...QueryOption) error{
+		"AdaptiveScheduler.Query": func(replicas []DatabaseHost, opts ...QueryOption) error {
+			_, err := NewAdaptiveScheduler(0.3, 0.1).Query(context.Background(), "q", replicas, opts...)
+			return err
+		},
+		"RetryBudget.Query": func(replicas []DatabaseHost, opts ...QueryOption) error {
+			_, err := NewRetryBudget(1, 10, time.Second).Query(context.Background(), "q", replicas, opts...)
+			return err
+		},
+	}
+	for name, query := range helpers {
+		t.Run(name, func(t *testing.T) {
+			dead := &scriptedHost{name: "dead", failFirst: -1, err: errTemporary}
+			if err := query([]DatabaseHost{dead}, WithMaxAttempts(1)); err == nil {
+				t.Fatal("ожидалась ошибка: хост недоступен")
+			}
+			if n := dead.calls.Load(); n != 1 {
+				t.Errorf("вызовов %d, ожидался 1: WithMaxAttempts(1) не учтена", n)
+			}
+		})
+	}
+}
+
+func TestWithRetryBudgetLimitsDetailedQuery(t *testing.T) {
+	b, _ := newTestBudget(0, 0, time.Second) // Бюджет без единого ретрая.
+	dead := &scriptedHost{name: "dead", failFirst: -1, err: errTemporary}
+
+	_, report, err := DistributedQueryDetailed(context.Background(), "q", []DatabaseHost{dead},
+		append(fastOptions(), WithRetryBudget(b))...)
+	if err == nil {
+		t.Fatal("ожидалась ошибка: хост недоступен")
+	}
+	if n := report["dead"].Attempts; n != 1 {
+		t.Errorf("попыток %d, ожидалась 1: бюджет не допускает ретраев", n)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RetryAfterError — временная ошибка с подсказкой бэкенда, через сколько повторить запрос
+// (аналог HTTP-заголовка Retry-After при 429 или 503).
+//
+// DistributedQuery распознает любую ошибку в цепочке с методом RetryAfter() time.Duration,
+// не только этот тип: подходящий тип ошибки может объявить и драйвер бэкенда.
+type RetryAfterError struct {
+	Err   error
+	Delay time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v (retry after %s)", e.Err, e.Delay)
+}
+
+func (e *RetryAfterError) Unwrap() error { return e.Err }
+
+// RetryAfter возвращает подсказанную бэкендом задержку перед повтором.
+func (e *RetryAfterError) RetryAfter() time.Duration { return e.Delay }
+
+// retryDelay возвращает задержку перед повтором после ошибки err: подсказку бэкенда,
+// если она есть и положительна, иначе fallback. Подсказка может быть и меньше fallback:
+// бэкенд лучше знает, когда снова будет готов.
+func retryDelay(err error, fallback time.Duration) time.Duration {
+	var hint interface{ RetryAfter() time.Duration }
+	if errors.As(err, &hint) {
+		if d := hint.RetryAfter(); d > 0 {
+			return d
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock подменяет time.After: запоминает запрошенные задержки и срабатывает сразу,
+// так что тест проверяет выбор интервала, не ожидая его.
+type fakeClock struct {
+	mu     sync.Mutex
+	delays []time.Duration
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	c.delays = append(c.delays, d)
+	c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+func (c *fakeClock) Delays() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.delays)
+}
+
+func TestRetryHonorsRetryAfterHint(t *testing.T) {
+	const hint = 7 * time.Second // Больше общего таймаута: реальное ожидание провалило бы тест.
+	throttled := &scriptedHost{
+		name:      "throttled",
+		failFirst: 2,
+		err:       &RetryAfterError{Err: errTemporary, Delay: hint},
+	}
+	clock := &fakeClock{}
+	cfg := fastConfig()
+	cfg.after = clock.After
+
+	result, err := distributedQuery(context.Background(), "q", []DatabaseHost{throttled}, cfg, nil)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if result != "result from throttled" {
+		t.Errorf("result = %q", result)
+	}
+	if got, want := clock.Delays(), []time.Duration{hint, hint}; !slices.Equal(got, want) {
+		t.Errorf("задержки перед повторами %v, ожидалось %v", got, want)
+	}
+}
+
+func TestRetryWithoutHintUsesDefaultInterval(t *testing.T) {
+	flaky := &scriptedHost{name: "flaky", failFirst: 1, err: errTemporary}
+	clock := &fakeClock{}
+	cfg := fastConfig()
+	cfg.after = clock.After
+
+	if _, err := distributedQuery(context.Background(), "q", []DatabaseHost{flaky}, cfg, nil); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if got, want := clock.Delays(), []time.Duration{cfg.RetryInterval}; !slices.Equal(got, want) {
+		t.Errorf("задержки перед повторами %v, ожидалось %v", got, want)
+	}
+}
+
+// hintError — ошибка стороннего типа с методом RetryAfter, без RetryAfterError.
+type hintError time.Duration
+
+func (e hintError) Error() string             { return "throttled" }
+func (e hintError) RetryAfter() time.Duration { return time.Duration(e) }
+
+func TestRetryDelay(t *testing.T) {
+	const fallback = time.Second
+	tests := []struct {
+		name string
+		err  error
+		want time.Duration
+	}{
+		{"без подсказки", errTemporary, fallback},
+		{"RetryAfterError", &RetryAfterError{Err: errTemporary, Delay: 3 * time.Second}, 3 * time.Second},
+		{"подсказка меньше интервала", &RetryAfterError{Err: errTemporary, Delay: time.Millisecond}, time.Millisecond},
+		{"обернутая ошибка", fmt.Errorf("query: %w", hintError(2*time.Second)), 2 * time.Second},
+		{"нулевая подсказка", hintError(0), fallback},
+		{"отрицательная подсказка", hintError(-time.Second), fallback},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryDelay(tt.err, fallback); got != tt.want {
+				t.Errorf("retryDelay = %v, ожидалось %v", got, tt.want)
+			}
+		})
+	}
+
+	// Подсказка не скрывает исходную ошибку.
+	if err := (&RetryAfterError{Err: errTemporary, Delay: time.Second}); !errors.Is(err, errTemporary) {
+		t.Error("RetryAfterError должна разворачиваться до исходной ошибки")
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// retryBudgetBuckets — на сколько интервалов делится окно RetryBudget.
+// Окно сдвигается скачками по одному интервалу: точность учета — window/retryBudgetBuckets.
+const retryBudgetBuckets = 10
+
+// RetryBudget ограничивает долю повторных попыток относительно первичных запросов
+// в скользящем окне (паттерн retry budget из Google SRE Book).
+//
+// Ретраи отдельного запроса безобидны, но во время аварии все запросы ретраят разом
+// и умножают нагрузку на и без того больной бэкенд. Бюджет разрешает ретрай, только
+// пока ретраев в окне не больше ratio*запросов + minRetries; остальные ретраи
+// пропускаются, и запрос завершается с тем, что есть. minRetries позволяет ретраить
+// при малом трафике, когда ratio*запросов меньше единицы.
+//
+// Один бюджет разделяется между вызовами Query и горутинами. Безопасен для конкурентного использования.
+type RetryBudget struct {
+	ratio      float64
+	minRetries int
+	bucketSize time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	buckets [retryBudgetBuckets]retryBucket
+}
+
+// retryBucket — счетчики одного интервала окна.
+type retryBucket struct {
+	epoch    int64 // Номер интервала (время / bucketSize); устаревший бакет обнуляется.
+	requests int
+	retries  int
+}
+
+// NewRetryBudget создает бюджет, разрешающий в окне window не больше ratio ретраев
+// на запрос плюс minRetries. Паникует при отрицательных ratio, minRetries или
+// неположительном window.
+func NewRetryBudget(ratio float64, minRetries int, window time.Duration) *RetryBudget {
+	if ratio < 0 || minRetries < 0 || window <= 0 {
+		panic("NewRetryBudget: ratio и minRetries не могут быть отрицательными, window должно быть положительным")
+	}
+	return &RetryBudget{
+		ratio:      ratio,
+		minRetries: minRetries,
+		bucketSize: max(window/retryBudgetBuckets, 1),
+		now:        time.Now,
+	}
+}
+
+// Query — сокращение для DistributedQuery с опциями opts и WithRetryBudget(b):
+// ретраи расходуют общий бюджет.
+func (b *RetryBudget) Query(ctx context.Context, query string, replicas []DatabaseHost, opts ...QueryOption) (string, error) {
+	return DistributedQuery(ctx, query, replicas, append(slices.Clip(opts), WithRetryBudget(b))...)
+}
+
+// RecordRequest учитывает первичную попытку запроса.
+func (b *RetryBudget) RecordRequest() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.currentLocked().requests++
+}
+
+// TryRetry сообщает, разрешен ли еще один ретрай, и если да — списывает его из бюджета.
+func (b *RetryBudget) TryRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cur := b.currentLocked()
+	requests, retries := b.totalsLocked()
+	if float64(retries+1) > b.ratio*float64(requests)+float64(b.minRetries) {
+		return false
+	}
+	cur.retries++
+	return true
+}
+
+// currentLocked возвращает бакет текущего интервала, обнуляя его, если он остался
+// от прошлого оборота окна. Вызывается под b.mu.
+func (b *RetryBudget) currentLocked() *retryBucket {
+	epoch := b.now().UnixNano() / int64(b.bucketSize)
+	bucket := &b.buckets[epoch%retryBudgetBuckets]
+	if bucket.epoch != epoch {
+		*bucket = retryBucket{epoch: epoch}
+	}
+	return bucket
+}
+
+// totalsLocked суммирует счетчики интервалов, попадающих в окно. Вызывается под b.mu.
+func (b *RetryBudget) totalsLocked() (requests, retries int) {
+	epoch := b.now().UnixNano() / int64(b.bucketSize)
+	for _, bucket := range b.buckets {
+		if epoch-bucket.epoch < retryBudgetBuckets {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+	return requests, retries
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestBudget создает бюджет с ручными часами.
+func newTestBudget(ratio float64, minRetries int, window time.Duration) (*RetryBudget, *time.Time) {
+	now := time.Unix(0, 0)
+	b := NewRetryBudget(ratio, minRetries, window)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestRetryBudgetRatio(t *testing.T) {
+	b, _ := newTestBudget(0.2, 1, time.Second)
+	for i := 0; i < 10; i++ {
+		b.RecordRequest()
+	}
+	// 0.2*10 + 1 = 3 ретрая.
+	for i := 0; i < 3; i++ {
+		if !b.TryRetry() {
+			t.Fatalf("ретрай %d отклонен, бюджет еще не исчерпан", i+1)
+		}
+	}
+	if b.TryRetry() {
+		t.Fatal("ретрай сверх бюджета разрешен")
+	}
+	for i := 0; i < 5; i++ {
+		b.RecordRequest()
+	}
+	if !b.TryRetry() {
+		t.Error("новые запросы должны пополнять бюджет")
+	}
+}
+
+func TestRetryBudgetWindowSlides(t *testing.T) {
+	b, now := newTestBudget(0, 2, time.Second)
+	if !b.TryRetry() || !b.TryRetry() || b.TryRetry() {
+		t.Fatal("ожидалось ровно minRetries ретраев")
+	}
+
+	// Внутри окна потраченные ретраи продолжают учитываться.
+	*now = now.Add(900 * time.Millisecond)
+	if b.TryRetry() {
+		t.Fatal("ретрай разрешен до выхода старых ретраев из окна")
+	}
+
+	// Окно сдвинулось: старый интервал забыт, бюджет восстановлен.
+	*now = now.Add(200 * time.Millisecond)
+	if !b.TryRetry() {
+		t.Error("после сдвига окна бюджет должен восстановиться")
+	}
+}
+
+func TestRetryBudgetThrottlesQueryRetries(t *testing.T) {
+	b, _ := newTestBudget(0.1, 2, time.Minute)
+	dead := &scriptedHost{name: "dead", failFirst: -1, err: errTemporary}
+	replicas := []DatabaseHost{dead}
+
+	const queries = 20
+	for i := 0; i < queries; i++ {
+		if _, err := b.Query(context.Background(), "q", replicas, fastOptions()...); err == nil {
+			t.Fatalf("запрос %d к падающему хосту завершился успешно", i)
+		}
+	}
+
+	// Без бюджета было бы queries*maxAttempts = 60 вызовов. С бюджетом:
+	// 20 первичных попыток и не больше 0.1*20 + 2 = 4 ретраев.
+	calls := int(dead.calls.Load())
+	if calls > queries+4 {
+		t.Errorf("вызовов DoQuery %d, ожидалось не больше %d", calls, queries+4)
+	}
+	if calls < queries {
+		t.Errorf("вызовов DoQuery %d: первичные попытки не должны ограничиваться бюджетом", calls)
+	}
+}
+
+func TestRetryBudgetAllowsRetriesWhenHealthy(t *testing.T) {
+	b, _ := newTestBudget(0.5, 0, time.Minute)
+	ok := &scriptedHost{name: "ok"}
+	for i := 0; i < 10; i++ {
+		if _, err := b.Query(context.Background(), "q", []DatabaseHost{ok}, fastOptions()...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Накопленный бюджет позволяет переждать сбой хоста.
+	flaky := &scriptedHost{name: "flaky", failFirst: 2, err: errTemporary}
+	res, err := b.Query(context.Background(), "q", []DatabaseHost{flaky}, fastOptions()...)
+	if err != nil || res != "result from flaky" {
+		t.Fatalf("Query = (%q, %v), ретраи должны были уложиться в бюджет", res, err)
+	}
+}
+
+func TestNewRetryBudgetPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ожидалась паника при нулевом окне")
+		}
+	}()
+	NewRetryBudget(0.1, 1, 0)
+}
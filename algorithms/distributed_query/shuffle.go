@@ -0,0 +1,19 @@
+package main
+
+import "math/rand"
+
+// ShuffleSeeded перемешивает срез на месте алгоритмом Фишера–Йетса.
+//
+// Источник случайности инициализируется переданным seed, поэтому один и тот же seed
+// всегда дает одну и ту же перестановку. В тестах это делает порядок реплик
+// воспроизводимым, а в продакшене (например, с seed = time.Now().UnixNano())
+// позволяет равномерно распределять нагрузку между хостами.
+func ShuffleSeeded[T any](s []T, seed int64) {
+	rnd := rand.New(rand.NewSource(seed))
+	// Идем с конца: каждый элемент меняется местами со случайным элементом
+	// из еще не перемешанной части среза [0, i].
+	for i := len(s) - 1; i > 0; i-- {
+		j := rnd.Intn(i + 1)
+		s[i], s[j] = s[j], s[i]
+	}
+}
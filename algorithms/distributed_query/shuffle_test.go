@@ -0,0 +1,31 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestShuffleSeededIsDeterministic(t *testing.T) {
+	a := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	b := slices.Clone(a)
+
+	ShuffleSeeded(a, 42)
+	ShuffleSeeded(b, 42)
+
+	if !slices.Equal(a, b) {
+		t.Errorf("один и тот же seed дал разные перестановки: %v и %v", a, b)
+	}
+}
+
+func TestShuffleSeededPreservesElements(t *testing.T) {
+	original := []string{"replica-1", "replica-2", "replica-3", "replica-4", "replica-5"}
+	shuffled := slices.Clone(original)
+
+	ShuffleSeeded(shuffled, 7)
+
+	got := slices.Clone(shuffled)
+	slices.Sort(got)
+	if !slices.Equal(got, original) {
+		t.Errorf("после перемешивания набор элементов изменился: %v", shuffled)
+	}
+}
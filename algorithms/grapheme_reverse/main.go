@@ -0,0 +1,64 @@
+// Package main содержит разворот строки с учетом графемных кластеров.
+//
+// Графемный кластер — это то, что пользователь видит как один символ. Он может состоять
+// из нескольких рун: буква с комбинируемым диакритическим знаком ("й" как "и" + U+0306),
+// эмодзи с модификатором цвета кожи, флаг из двух региональных индикаторов или
+// составное эмодзи, склеенное ZWJ (U+200D). Наивный разворот по рунам разрывает такие
+// кластеры, и знаки "переезжают" на соседние символы.
+package main
+
+import (
+	"fmt"
+
+	"github.com/rivo/uniseg"
+)
+
+// Graphemes разбивает строку на графемные кластеры по правилам UAX #29
+// (расширенные кластеры): CR LF, комбинируемые знаки и модификаторы эмодзи,
+// составные эмодзи через ZWJ, пары региональных индикаторов, слоги хангыль из чамо,
+// знаки Prepend и т.д. Сегментацию выполняет github.com/rivo/uniseg.
+func Graphemes(s string) []string {
+	var clusters []string
+	state := -1
+	for len(s) > 0 {
+		var cluster string
+		cluster, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
+
+// Reverse разворачивает строку по графемным кластерам, сохраняя каждый кластер целиком.
+// Байты некорректного UTF-8 переносятся как есть, каждый — отдельным кластером.
+func Reverse(s string) string {
+	clusters := Graphemes(s)
+	buf := make([]byte, 0, len(s))
+	for i := len(clusters) - 1; i >= 0; i-- {
+		buf = append(buf, clusters[i]...)
+	}
+	return string(buf)
+}
+
+// reverseRunes — наивный разворот по рунам, для сравнения.
+func reverseRunes(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func main() {
+	examples := []string{
+		"hello",
+		"привет",
+		"\u0438\u0306о-\u0438\u0306о", // "йо-йо", где "й" записана как "и" + комбинируемая бреве (U+0306)
+		"семья: 👨‍👩‍👧",
+		"флаги: 🇷🇺🇩🇪",
+		"👍🏽 ок",
+	}
+
+	for _, s := range examples {
+		fmt.Printf("%q\n  по рунам:     %q\n  по графемам:  %q\n", s, reverseRunes(s), Reverse(s))
+	}
+}
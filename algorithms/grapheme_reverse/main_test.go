@@ -0,0 +1,63 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReverse(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"пустая строка", "", ""},
+		{"ASCII", "hello", "olleh"},
+		{"кириллица", "привет", "тевирп"},
+		// "й" = "и" + U+0306: знак должен остаться при своей букве.
+		{"комбинируемые знаки", "\u0438\u0306ог", "го\u0438\u0306"},
+		{"несколько знаков подряд", "a\u0301\u0323b", "ba\u0301\u0323"},
+		// Семья 👨‍👩‍👧: три эмодзи, склеенные ZWJ.
+		{"ZWJ эмодзи", "x\U0001F468\u200d\U0001F469\u200d\U0001F467y", "y\U0001F468\u200d\U0001F469\u200d\U0001F467x"},
+		// 👍🏽: эмодзи с модификатором цвета кожи.
+		{"модификатор эмодзи", "a\U0001F44D\U0001F3FDb", "b\U0001F44D\U0001F3FDa"},
+		// ❤️: символ с селектором варианта U+FE0F.
+		{"селектор варианта", "1\u2764\ufe0f2", "2\u2764\ufe0f1"},
+		// 🇷🇺🇩🇪: два флага по два региональных индикатора.
+		{"флаги", "\U0001F1F7\U0001F1FA\U0001F1E9\U0001F1EA", "\U0001F1E9\U0001F1EA\U0001F1F7\U0001F1FA"},
+		{"CRLF", "a\r\nb", "b\r\na"},
+		// 각 из чамо: начальная согласная + гласная + конечная согласная.
+		{"слог хангыль из чамо", "x\u1100\u1161\u11a8y", "y\u1100\u1161\u11a8x"},
+		// U+0600 (Prepend) присоединяется к следующему символу.
+		{"знак Prepend", "\u06001a", "a\u06001"},
+		// После ZWJ склеивается только эмодзи: буква начинает новый кластер.
+		{"ZWJ перед буквой", "a\u200db", "ba\u200d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Reverse(tt.in); got != tt.want {
+				t.Errorf("Reverse(%q) = %q, ожидалось %q", tt.in, got, tt.want)
+			}
+			// Двойной разворот возвращает исходную строку.
+			if got := Reverse(Reverse(tt.in)); got != tt.in {
+				t.Errorf("Reverse(Reverse(%q)) = %q", tt.in, got)
+			}
+		})
+	}
+}
+
+func TestGraphemes(t *testing.T) {
+	in := "e\u0301\U0001F468\u200d\U0001F469!\U0001F1F7\U0001F1FA"
+	want := []string{"e\u0301", "\U0001F468\u200d\U0001F469", "!", "\U0001F1F7\U0001F1FA"}
+	if got := Graphemes(in); !slices.Equal(got, want) {
+		t.Errorf("Graphemes(%q) = %q, ожидалось %q", in, got, want)
+	}
+}
+
+func TestReverseKeepsInvalidBytes(t *testing.T) {
+	in := "a\xffb"
+	if got, want := Reverse(in), "b\xffa"; got != want {
+		t.Errorf("Reverse(%q) = %q, ожидалось %q", in, got, want)
+	}
+}
@@ -0,0 +1,152 @@
+// Package main содержит классические алгоритмы сортировки: сортировку слиянием (merge sort)
+// и быструю сортировку (quicksort), реализованные через дженерики.
+//
+// Обе функции принимают функцию сравнения less, поэтому работают с любым типом.
+// Обернув less в CountComparisons, можно посчитать количество сравнений
+// и убедиться на практике, что оба алгоритма в среднем делают O(n log n) сравнений.
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// CountComparisons оборачивает функцию сравнения так, что каждый ее вызов
+// увеличивает *counter. Полезно для демонстрации сложности алгоритмов.
+func CountComparisons[T any](less func(a, b T) bool, counter *int) func(a, b T) bool {
+	return func(a, b T) bool {
+		*counter++
+		return less(a, b)
+	}
+}
+
+// MergeSort сортирует срез на месте сортировкой слиянием.
+//
+// Сложность: O(n log n) в любом случае, дополнительная память O(n).
+// Сортировка устойчивая: равные элементы сохраняют исходный порядок.
+func MergeSort[T any](s []T, less func(a, b T) bool) {
+	if len(s) < 2 {
+		return
+	}
+	// Один вспомогательный буфер на всю сортировку вместо аллокации на каждом уровне.
+	buf := make([]T, len(s))
+	mergeSort(s, buf, less)
+}
+
+func mergeSort[T any](s, buf []T, less func(a, b T) bool) {
+	if len(s) < 2 {
+		return
+	}
+	mid := len(s) / 2
+	mergeSort(s[:mid], buf[:mid], less)
+	mergeSort(s[mid:], buf[mid:], less)
+
+	// Сливаем две отсортированные половины в буфер, затем копируем обратно.
+	i, j, k := 0, mid, 0
+	for i < mid && j < len(s) {
+		// Берем из правой половины только если она строго меньше — это дает устойчивость.
+		if less(s[j], s[i]) {
+			buf[k] = s[j]
+			j++
+		} else {
+			buf[k] = s[i]
+			i++
+		}
+		k++
+	}
+	k += copy(buf[k:], s[i:mid])
+	copy(buf[k:], s[j:])
+	copy(s, buf[:len(s)])
+}
+
+// QuickSort сортирует срез на месте быстрой сортировкой.
+//
+// Сложность: O(n log n) в среднем и O(n^2) в худшем случае. Выбор опорного элемента
+// медианой из трех защищает от худшего случая на уже отсортированных данных, а схема
+// разбиения Хоара — на срезах с повторяющимися ключами: равные опорному элементы
+// расходятся по обе стороны, и разбиение остается сбалансированным.
+// Дополнительная память — O(log n) на стек: рекурсия идет только в меньшую часть.
+// Сортировка неустойчивая.
+func QuickSort[T any](s []T, less func(a, b T) bool) {
+	for len(s) > 1 {
+		p := partition(s, less)
+		// Рекурсивно сортируем меньшую часть, а большую обрабатываем в цикле.
+		if p < len(s)-p {
+			QuickSort(s[:p], less)
+			s = s[p+1:]
+		} else {
+			QuickSort(s[p+1:], less)
+			s = s[:p]
+		}
+	}
+}
+
+// partition разбивает срез (схема Хоара) и возвращает итоговую позицию опорного элемента:
+// слева от нее элементы не больше опорного, справа — не меньше.
+//
+// Оба указателя останавливаются на элементах, равных опорному, и меняют их местами.
+// Это лишние обмены, зато на срезе из одинаковых элементов указатели встречаются
+// посередине, а не у края, как в схеме Ломуто, где такой срез дает O(n^2).
+func partition[T any](s []T, less func(a, b T) bool) int {
+	last := len(s) - 1
+	medianOfThree(s, 0, len(s)/2, last, less)
+	// После medianOfThree медиана стоит в середине; переносим ее в начало как опорный элемент.
+	// Теперь s[last] не меньше опорного и останавливает левый указатель, а сам опорный
+	// элемент в s[0] — правый.
+	s[0], s[len(s)/2] = s[len(s)/2], s[0]
+	pivot := s[0]
+
+	i, j := 0, len(s)
+	for {
+		for i++; i < last && less(s[i], pivot); i++ {
+		}
+		for j--; less(pivot, s[j]); j-- {
+		}
+		if i >= j {
+			break
+		}
+		s[i], s[j] = s[j], s[i]
+	}
+	s[0], s[j] = s[j], s[0]
+	return j
+}
+
+// medianOfThree упорядочивает элементы с индексами a, b, c так, что s[a] <= s[b] <= s[c].
+func medianOfThree[T any](s []T, a, b, c int, less func(x, y T) bool) {
+	if less(s[b], s[a]) {
+		s[a], s[b] = s[b], s[a]
+	}
+	if less(s[c], s[b]) {
+		s[b], s[c] = s[c], s[b]
+		if less(s[b], s[a]) {
+			s[a], s[b] = s[b], s[a]
+		}
+	}
+}
+
+func main() {
+	const n = 10000
+	less := func(a, b int) bool { return a < b }
+	nLogN := float64(n) * math.Log2(n)
+
+	data := rand.Perm(n)
+	fmt.Printf("Сортируем %d случайных чисел (n*log2(n) ≈ %.0f)\n", n, nLogN)
+
+	mergeData := append([]int(nil), data...)
+	mergeCount := 0
+	MergeSort(mergeData, CountComparisons(less, &mergeCount))
+	fmt.Printf("MergeSort: %d сравнений (%.2f * n*log2(n)), первые элементы: %v\n",
+		mergeCount, float64(mergeCount)/nLogN, mergeData[:5])
+
+	quickData := append([]int(nil), data...)
+	quickCount := 0
+	QuickSort(quickData, CountComparisons(less, &quickCount))
+	fmt.Printf("QuickSort: %d сравнений (%.2f * n*log2(n)), первые элементы: %v\n",
+		quickCount, float64(quickCount)/nLogN, quickData[:5])
+
+	// Дженерики позволяют сортировать любые типы, например, строки по длине.
+	words := []string{"kubernetes", "go", "docker", "k8s", "linux"}
+	MergeSort(words, func(a, b string) bool { return len(a) < len(b) })
+	fmt.Println("Строки по длине:", words)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+var sorters = map[string]func([]int, func(a, b int) bool){
+	"MergeSort": MergeSort[int],
+	"QuickSort": QuickSort[int],
+}
+
+func intLess(a, b int) bool { return a < b }
+
+func TestSortMatchesSortSlice(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	inputs := [][]int{
+		nil,
+		{},
+		{42},
+		{2, 1},
+		{1, 2, 3, 4, 5},          // уже отсортирован
+		{5, 4, 3, 2, 1},          // в обратном порядке
+		{3, 3, 3, 1, 1, 2, 2, 2}, // много дубликатов
+	}
+	for i := 0; i < 20; i++ {
+		s := make([]int, rnd.Intn(500))
+		for j := range s {
+			s[j] = rnd.Intn(100) - 50
+		}
+		inputs = append(inputs, s)
+	}
+
+	for name, sortFn := range sorters {
+		for _, in := range inputs {
+			got := slices.Clone(in)
+			want := slices.Clone(in)
+			sortFn(got, intLess)
+			sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+			if !slices.Equal(got, want) {
+				t.Errorf("%s(%v) = %v, ожидалось %v", name, in, got, want)
+			}
+		}
+	}
+}
+
+func TestMergeSortIsStable(t *testing.T) {
+	type item struct{ key, order int }
+	items := []item{{2, 0}, {1, 1}, {2, 2}, {1, 3}, {2, 4}}
+	MergeSort(items, func(a, b item) bool { return a.key < b.key })
+
+	want := []item{{1, 1}, {1, 3}, {2, 0}, {2, 2}, {2, 4}}
+	if !slices.Equal(items, want) {
+		t.Errorf("MergeSort нарушил устойчивость: %v", items)
+	}
+}
+
+func TestComparisonCountIsNLogN(t *testing.T) {
+	const n = 4096
+	nLogN := float64(n) * math.Log2(n)
+
+	for name, sortFn := range sorters {
+		// Проверяем и случайные, и уже отсортированные данные:
+		// на последних наивный quicksort деградировал бы до O(n^2).
+		for _, data := range [][]int{rand.New(rand.NewSource(2)).Perm(n), sortedInts(n)} {
+			count := 0
+			sortFn(data, CountComparisons(intLess, &count))
+
+			if ratio := float64(count) / nLogN; ratio > 2 {
+				t.Errorf("%s: %d сравнений = %.2f * n*log2(n), ожидалось O(n log n)", name, count, ratio)
+			}
+			if count < n-1 {
+				t.Errorf("%s: %d сравнений — меньше n-1, счетчик не работает", name, count)
+			}
+		}
+	}
+}
+
+func TestComparisonCountWithDuplicatesIsNLogN(t *testing.T) {
+	const n = 4096
+	nLogN := float64(n) * math.Log2(n)
+
+	rnd := rand.New(rand.NewSource(3))
+	fewDistinct := make([]int, n)
+	for i := range fewDistinct {
+		fewDistinct[i] = rnd.Intn(4)
+	}
+	inputs := map[string][]int{
+		"все равны":       make([]int, n),
+		"четыре значения": fewDistinct,
+	}
+
+	for name, sortFn := range sorters {
+		for input, data := range inputs {
+			data := slices.Clone(data)
+			count := 0
+			sortFn(data, CountComparisons(intLess, &count))
+
+			// Наивное разбиение на повторах дает ~n^2/2 сравнений, то есть ~170 * n*log2(n).
+			if ratio := float64(count) / nLogN; ratio > 2 {
+				t.Errorf("%s (%s): %d сравнений = %.2f * n*log2(n), ожидалось O(n log n)", name, input, count, ratio)
+			}
+			if !slices.IsSorted(data) {
+				t.Errorf("%s (%s): результат не отсортирован", name, input)
+			}
+		}
+	}
+}
+
+func sortedInts(n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	return s
+}
@@ -12,12 +12,20 @@
 //
 // После этого, запуск `go generate ./...` в вашем проекте автоматически создаст
 // файлы `*_gen.go` с кодом репозиториев.
+//
+// Флаг -backend выбирает реализацию: gorm (по умолчанию) или memory — потокобезопасный
+// репозиторий в памяти поверх memstore.MemStore с методами Get/Create/Set/Del/List.
+// Для полей с тегом `gorm:"unique_index"` генерируется поиск FindBy<Поле>.
+// Для memory поведение Create при совпадении первичного ключа задается политикой
+// CollisionPolicy: ошибка ErrDuplicateKey (CollisionError) или перезапись (CollisionUpsert).
 package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"go/ast"
+	"go/format"
 	"go/parser"
 	"go/printer"
 	"go/token"
@@ -27,20 +35,31 @@ import (
 	"regexp"
 	"strings"
 	"text/template"
+	"unicode"
 
 	"golang.org/x/tools/go/ast/inspector"
 )
 
-// repositoryTemplate — это шаблон text/template, на основе которого генерируется код репозитория.
+// fileTemplate — шаблон всего генерируемого файла. Репозиторий каждой сущности
+// подставляется из шаблона выбранного бэкенда ("gorm" или "memory").
 // template.Must используется для того, чтобы паниковать при запуске, если шаблон некорректен.
-var repositoryTemplate = template.Must(template.New("").Parse(`
-// Code generated by repogen. DO NOT EDIT.
-package main
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by repogen. DO NOT EDIT.
+
+package {{ .Package }}
 
 import (
-    "github.com/jinzhu/gorm"
+{{- range .Imports }}
+	"{{ . }}"
+{{- end }}
 )
+{{ if eq .Backend "memory" }}{{ template "memoryCommon" }}{{ end }}
+{{- range .Entities }}
+{{ if eq $.Backend "memory" }}{{ template "memory" . }}{{ else }}{{ template "gorm" . }}{{ end }}
+{{- end }}
+`))
 
+// gormRepositoryTemplate — репозиторий поверх GORM.
+var gormRepositoryTemplate = template.Must(fileTemplate.New("gorm").Parse(`
 type {{ .EntityName }}Repository struct {
     db *gorm.DB
 }
@@ -51,10 +70,16 @@ func New{{ .EntityName }}Repository(db *gorm.DB) {{ .EntityName }}Repository {
 
 func (r {{ .EntityName }}Repository) Get({{ .PrimaryName }} {{ .PrimaryType}}) (*{{ .EntityName }}, error) {
     entity := new({{ .EntityName }})
-    err := r.db.Limit(1).Where("{{ .PrimarySQLName }} = ?", {{ .PrimaryName }}).Find(entity).Error()
+    err := r.db.Limit(1).Where("{{ .PrimarySQLName }} = ?", {{ .PrimaryName }}).Find(entity).Error
     return entity, err
 }
-
+{{ range .Indexes }}
+func (r {{ $.EntityName }}Repository) FindBy{{ .Name }}({{ .ParamName }} {{ .Type }}) (*{{ $.EntityName }}, error) {
+    entity := new({{ $.EntityName }})
+    err := r.db.Limit(1).Where("{{ .SQLName }} = ?", {{ .ParamName }}).Find(entity).Error
+    return entity, err
+}
+{{ end }}
 
 func (r {{ .EntityName }}Repository) Create(entity *{{ .EntityName }}) error {
     return r.db.Create(entity).Error
@@ -69,6 +94,125 @@ func (r {{ .EntityName }}Repository) Delete(entity *{{ .EntityName }}) error {
 }
 `))
 
+// memoryCommonTemplate — общие для всех in-memory репозиториев ошибки и политика коллизий.
+// Генерируется один раз на файл. Ошибки — синонимы ошибок memstore, чтобы errors.Is
+// работал и с ошибками репозитория, и с ошибками хранилища.
+var memoryCommonTemplate = template.Must(fileTemplate.New("memoryCommon").Parse(`
+var (
+	// ErrNotFound возвращается, если записи с таким первичным ключом нет.
+	ErrNotFound = memstore.ErrNotFound
+	// ErrDuplicateKey возвращается из Create при политике CollisionError,
+	// если запись с таким первичным ключом уже есть.
+	ErrDuplicateKey = memstore.ErrDuplicateKey
+	// ErrUniqueViolation возвращается, если значение поля с тегом gorm:"unique_index"
+	// уже занято другой записью.
+	ErrUniqueViolation = memstore.ErrUniqueViolation
+)
+
+// CollisionPolicy определяет поведение Create при совпадении первичного ключа.
+type CollisionPolicy int
+
+const (
+	// CollisionError — Create возвращает ErrDuplicateKey, как INSERT в таблицу с PRIMARY KEY.
+	CollisionError CollisionPolicy = iota
+	// CollisionUpsert — Create перезаписывает существующую запись, как INSERT ... ON CONFLICT DO UPDATE.
+	CollisionUpsert
+)
+`))
+
+// memoryRepositoryTemplate — репозиторий в памяти поверх memstore.MemStore.
+// Не требует базы данных, поэтому подходит для тестов и примеров.
+var memoryRepositoryTemplate = template.Must(fileTemplate.New("memory").Parse(`
+// {{ .EntityName }}Repository хранит сущности {{ .EntityName }} в памяти по ключу {{ .PrimaryName }}.
+// Get возвращает копию, поэтому изменения полученной сущности не влияют на хранилище без Set.
+type {{ .EntityName }}Repository struct {
+	store       *memstore.MemStore[{{ .PrimaryType }}, {{ .EntityName }}]
+	onCollision CollisionPolicy
+{{- range .Indexes }}
+	by{{ .Name }} *memstore.UniqueIndex[{{ .Type }}, {{ $.PrimaryType }}, {{ $.EntityName }}]
+{{- end }}
+}
+
+// New{{ .EntityName }}Repository создает пустой репозиторий с политикой коллизий onCollision для Create.
+func New{{ .EntityName }}Repository(onCollision CollisionPolicy) *{{ .EntityName }}Repository {
+	store := memstore.NewMemStore(func(e {{ .EntityName }}) {{ .PrimaryType }} { return e.{{ .PrimaryName }} })
+	return &{{ .EntityName }}Repository{
+		store:       store,
+		onCollision: onCollision,
+{{- range .Indexes }}
+		by{{ .Name }}: memstore.NewUniqueIndex(store, "{{ .SQLName }}", func(e {{ $.EntityName }}) {{ .Type }} { return e.{{ .Name }} }),
+{{- end }}
+	}
+}
+
+// Get возвращает сущность по первичному ключу или ErrNotFound.
+func (r *{{ .EntityName }}Repository) Get({{ .ParamName }} {{ .PrimaryType }}) (*{{ .EntityName }}, error) {
+	entity, err := r.store.Read({{ .ParamName }})
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+{{ range .Indexes }}
+// FindBy{{ .Name }} возвращает сущность по уникальному полю {{ .Name }} или ErrNotFound.
+func (r *{{ $.EntityName }}Repository) FindBy{{ .Name }}({{ .ParamName }} {{ .Type }}) (*{{ $.EntityName }}, error) {
+	entity, err := r.by{{ .Name }}.Find({{ .ParamName }})
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+{{ end }}
+// Create добавляет сущность. При совпадении первичного ключа поведение задает CollisionPolicy;
+// при совпадении уникального поля возвращается ErrUniqueViolation.
+func (r *{{ .EntityName }}Repository) Create(entity *{{ .EntityName }}) error {
+	if r.onCollision == CollisionUpsert {
+		return r.store.Put(*entity)
+	}
+	return r.store.Create(*entity)
+}
+
+// Set сохраняет сущность, перезаписывая существующую с тем же ключом (upsert).
+func (r *{{ .EntityName }}Repository) Set(entity *{{ .EntityName }}) error {
+	return r.store.Put(*entity)
+}
+
+// Del удаляет сущность по первичному ключу или возвращает ErrNotFound.
+func (r *{{ .EntityName }}Repository) Del({{ .ParamName }} {{ .PrimaryType }}) error {
+	return r.store.Delete({{ .ParamName }})
+}
+
+// List возвращает все сущности в порядке создания.
+func (r *{{ .EntityName }}Repository) List() []{{ .EntityName }} {
+	return r.store.List()
+}
+`))
+
+// backendImports — импорты генерируемого файла для каждого бэкенда.
+var backendImports = map[string][]string{
+	"gorm":   {"github.com/jinzhu/gorm"},
+	"memory": {"repogen/memstore"},
+}
+
+// entityParams — параметры шаблона репозитория одной сущности.
+type entityParams struct {
+	EntityName     string
+	PrimaryName    string
+	PrimarySQLName string
+	PrimaryType    string
+	ParamName      string // Имя параметра-ключа в методах: UserID -> userID.
+	Indexes        []indexParams
+}
+
+// indexParams — параметры уникального индекса по полю с тегом `gorm:"unique_index"`.
+type indexParams struct {
+	Name      string // Имя поля: Email.
+	SQLName   string // Имя колонки: email.
+	Type      string
+	ParamName string // Имя параметра в FindBy<Name>: email.
+}
+
 // repositoryGenerator хранит информацию, необходимую для генерации одного репозитория.
 type repositoryGenerator struct {
 	typeSpec   *ast.TypeSpec
@@ -102,6 +246,40 @@ func (r repositoryGenerator) primaryField() (*ast.Field, error) {
 	return nil, fmt.Errorf("не найден первичный ключ (gorm:\"primary_key\") в структуре %s", r.typeSpec.Name.Name)
 }
 
+// uniqueFields возвращает поля структуры с тегом `gorm:"unique_index"`.
+// Поля, объявленные списком (`A, B string`), порождают индекс для каждого имени.
+func (r repositoryGenerator) uniqueFields() []indexParams {
+	var res []indexParams
+	for _, field := range r.structType.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		if !hasTagPart(tag.Get("gorm"), "unique_index") {
+			continue
+		}
+		for _, name := range field.Names {
+			res = append(res, indexParams{
+				Name:      name.Name,
+				SQLName:   getColumnName(&ast.Field{Names: []*ast.Ident{name}, Tag: field.Tag}),
+				Type:      expr2string(field.Type),
+				ParamName: lowerFirstWord(name.Name),
+			})
+		}
+	}
+	return res
+}
+
+// hasTagPart сообщает, есть ли в gorm-теге вида "a;b:c" часть part (с параметром или без).
+func hasTagPart(gormTag, part string) bool {
+	for _, p := range strings.Split(gormTag, ";") {
+		if p == part || strings.HasPrefix(p, part+":") {
+			return true
+		}
+	}
+	return false
+}
+
 // getColumnName извлекает имя колонки из тега `gorm:"column:..."`.
 // Если тег отсутствует, используется имя поля структуры в snake_case.
 func getColumnName(field *ast.Field) string {
@@ -118,67 +296,26 @@ func getColumnName(field *ast.Field) string {
 	return toSnakeCase(field.Names[0].Name)
 }
 
-// Generate выполняет основную логику генерации кода для одного репозитория.
-func (r repositoryGenerator) Generate(outFile *ast.File) error {
+// params собирает параметры шаблона для сущности.
+func (r repositoryGenerator) params() (entityParams, error) {
 	// Находим поле, которое является первичным ключом.
 	primary, err := r.primaryField()
 	if err != nil {
-		return err
+		return entityParams{}, err
 	}
-
-	// Готовим параметры для передачи в шаблон.
-	params := struct {
-		EntityName     string
-		PrimaryName    string
-		PrimarySQLName string
-		PrimaryType    string
-	}{
+	name := primary.Names[0].Name
+	return entityParams{
 		EntityName:     r.typeSpec.Name.Name,
-		PrimaryName:    primary.Names[0].Name,
+		PrimaryName:    name,
 		PrimarySQLName: getColumnName(primary), // Получаем имя колонки из тега.
 		PrimaryType:    expr2string(primary.Type),
-	}
-
-	// 1. Выполняем шаблон с параметрами и записываем результат в буфер.
-	var buf bytes.Buffer
-	err = repositoryTemplate.Execute(&buf, params)
-	if err != nil {
-		return fmt.Errorf("ошибка выполнения шаблона: %v", err)
-	}
-
-	// 2. Парсим сгенерированный код обратно в AST.
-	templateAst, err := parser.ParseFile(
-		token.NewFileSet(),
-		"", // Имя файла не нужно, т.к. источник - буфер.
-		buf.Bytes(),
-		parser.ParseComments,
-	)
-	if err != nil {
-		return fmt.Errorf("ошибка парсинга сгенерированного шаблона: %v", err)
-	}
-
-	// 3. Добавляем все сгенерированные объявления (imports, types, funcs) в выходной файл.
-	for _, decl := range templateAst.Decls {
-		outFile.Decls = append(outFile.Decls, decl)
-	}
-	return nil
+		ParamName:      lowerFirstWord(name),
+		Indexes:        r.uniqueFields(),
+	}, nil
 }
 
-func main() {
-	// `go generate` устанавливает несколько переменных окружения. GOFILE - одна из них.
-	path := os.Getenv("GOFILE")
-	if path == "" {
-		log.Fatal("Переменная окружения GOFILE должна быть установлена. Запустите через `go generate`.")
-	}
-
-	// 1. Парсим исходный файл в AST.
-	fset := token.NewFileSet()
-	astInFile, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
-	if err != nil {
-		log.Fatalf("ошибка парсинга файла %s: %v", path, err)
-	}
-
-	// 2. Ищем задания для генерации.
+// findEntities ищет в файле структуры, помеченные комментарием `//repogen:entity`.
+func findEntities(astInFile *ast.File) []repositoryGenerator {
 	// Используем inspector для удобного обхода AST.
 	i := inspector.New([]*ast.File{astInFile})
 	filter := []ast.Node{
@@ -221,47 +358,116 @@ func main() {
 		}
 		return true
 	})
+	return genTasks
+}
 
-	if len(genTasks) == 0 {
-		log.Println("Не найдено структур с комментарием //repogen:entity. Генерация не требуется.")
-		return
+// generate строит отформатированный код репозиториев для всех сущностей файла.
+// Возвращает nil без ошибки, если сущностей нет.
+func generate(astInFile *ast.File, backend string) ([]byte, error) {
+	imports, ok := backendImports[backend]
+	if !ok {
+		return nil, fmt.Errorf("неизвестный бэкенд %q (ожидается gorm или memory)", backend)
 	}
 
-	// 3. Генерируем код для каждого найденного задания.
-	astOutFile := &ast.File{
-		Name: astInFile.Name, // Имя пакета должно совпадать с исходным.
+	genTasks := findEntities(astInFile)
+	if len(genTasks) == 0 {
+		return nil, nil
 	}
 
+	entities := make([]entityParams, 0, len(genTasks))
 	for _, task := range genTasks {
-		err = task.Generate(astOutFile)
+		params, err := task.params()
 		if err != nil {
-			log.Fatalf("ошибка генерации для %s: %v", task.typeSpec.Name.Name, err)
+			return nil, fmt.Errorf("ошибка генерации для %s: %w", task.typeSpec.Name.Name, err)
 		}
+		entities = append(entities, params)
 	}
 
-	// 4. Сохраняем результат в файл.
-	outFileName := strings.TrimSuffix(path, ".go") + "_gen.go"
-	outFile, err := os.Create(outFileName)
+	// 1. Выполняем шаблон с параметрами и записываем результат в буфер.
+	var buf bytes.Buffer
+	err := fileTemplate.Execute(&buf, struct {
+		Package  string
+		Backend  string
+		Imports  []string
+		Entities []entityParams
+	}{
+		Package:  astInFile.Name.Name, // Имя пакета должно совпадать с исходным.
+		Backend:  backend,
+		Imports:  imports,
+		Entities: entities,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения шаблона: %w", err)
+	}
+
+	// 2. Форматируем результат как gofmt. Заодно это проверка, что шаблон дал корректный Go-код.
+	out, err := format.Source(buf.Bytes())
 	if err != nil {
-		log.Fatalf("ошибка создания файла %s: %v", outFileName, err)
+		return nil, fmt.Errorf("ошибка форматирования сгенерированного кода: %w", err)
+	}
+	return out, nil
+}
+
+func main() {
+	backend := flag.String("backend", "gorm", "реализация репозитория: gorm или memory")
+	flag.Parse()
+
+	// `go generate` устанавливает несколько переменных окружения. GOFILE - одна из них.
+	path := os.Getenv("GOFILE")
+	if path == "" {
+		log.Fatal("Переменная окружения GOFILE должна быть установлена. Запустите через `go generate`.")
 	}
-	defer outFile.Close()
 
-	// Используем printer для форматирования и записи AST в файл.
-	// Это гарантирует, что сгенерированный код будет отформатирован согласно `gofmt`.
-	err = printer.Fprint(outFile, token.NewFileSet(), astOutFile)
+	// 1. Парсим исходный файл в AST.
+	fset := token.NewFileSet()
+	astInFile, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
 	if err != nil {
+		log.Fatalf("ошибка парсинга файла %s: %v", path, err)
+	}
+
+	// 2. Ищем сущности и генерируем для них код.
+	out, err := generate(astInFile, *backend)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if out == nil {
+		log.Println("Не найдено структур с комментарием //repogen:entity. Генерация не требуется.")
+		return
+	}
+
+	// 3. Сохраняем результат в файл.
+	outFileName := strings.TrimSuffix(path, ".go") + "_gen.go"
+	if err := os.WriteFile(outFileName, out, 0o644); err != nil {
 		log.Fatalf("ошибка записи в файл %s: %v", outFileName, err)
 	}
 
 	log.Printf("Успешно сгенерирован файл: %s", outFileName)
 }
 
+// lowerFirstWord переводит в нижний регистр первое слово идентификатора:
+// "UserID" -> "userID", "ID" -> "id", "Email" -> "email".
+func lowerFirstWord(name string) string {
+	runes := []rune(name)
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		i++
+	}
+	// В "UserID" после заглавной идет строчная — опускаем только первую букву.
+	// В "IDValue" опускаем "ID", но оставляем "V" — начало следующего слова.
+	if i > 1 && i < len(runes) {
+		i--
+	}
+	for j := 0; j < i; j++ {
+		runes[j] = unicode.ToLower(runes[j])
+	}
+	return string(runes)
+}
+
 // toSnakeCase преобразует строку из CamelCase в snake_case.
 // Например, "MyFieldName" -> "my_field_name".
 func toSnakeCase(str string) string {
 	var matchFirstCap = regexp.MustCompile("(.)([A-Z][a-z]+)")
-	var matchAllCap   = regexp.MustCompile("([a-z0-9])([A-Z])")
+	var matchAllCap = regexp.MustCompile("([a-z0-9])([A-Z])")
 
 	snake := matchFirstCap.ReplaceAllString(str, "${1}_${2}")
 	snake = matchAllCap.ReplaceAllString(snake, "${1}_${2}")
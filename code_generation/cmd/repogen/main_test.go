@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+	"testing"
+)
+
+// parse разбирает исходный код сущностей.
+func parse(t *testing.T, src string) []byte {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "entities.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := generate(file, "memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestGeneratedCodeIsUpToDate(t *testing.T) {
+	// gen_gen.go в корне модуля должен совпадать с тем, что генерирует текущая версия repogen.
+	src, err := os.ReadFile("../../gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("../../gen_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parse(t, string(src)); !bytes.Equal(got, want) {
+		t.Error("gen_gen.go устарел: запустите `go generate ./...` в code_generation")
+	}
+}
+
+func TestGenerateMemoryBackend(t *testing.T) {
+	out := string(parse(t, `package store
+
+//repogen:entity
+type Order struct {
+	ID    int64 `+"`gorm:\"primary_key\"`"+`
+	Total int
+}
+
+//repogen:entity
+type Item struct {
+	SKU  string `+"`gorm:\"primary_key;column:sku\"`"+`
+	Name string
+	// unique_index_extra — другой тег, индекс по нему не нужен.
+	Code, Barcode string `+"`gorm:\"unique_index\"`"+`
+	Note          string `+"`gorm:\"unique_index_extra\"`"+`
+}
+
+// Product не помечен и не должен попасть в вывод.
+type Product struct{ ID int }
+`))
+
+	for _, want := range []string{
+		"// Code generated by repogen. DO NOT EDIT.",
+		"package store",
+		"func NewOrderRepository(onCollision CollisionPolicy) *OrderRepository",
+		"func (r *OrderRepository) Get(id int64) (*Order, error)",
+		"func (r *ItemRepository) Del(sku string) error",
+		"func (r *ItemRepository) List() []Item",
+		"func (r *ItemRepository) FindByCode(code string) (*Item, error)",
+		"func (r *ItemRepository) FindByBarcode(barcode string) (*Item, error)",
+		"*memstore.MemStore[string, Item]",
+		`"repogen/memstore"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("в сгенерированном коде нет %q", want)
+		}
+	}
+	if strings.Contains(out, "FindByNote") || strings.Contains(out, "OrderRepository) FindBy") {
+		t.Error("сгенерирован поиск по полю без тега unique_index")
+	}
+	if strings.Contains(out, "ProductRepository") {
+		t.Error("сгенерирован репозиторий для непомеченной структуры")
+	}
+	// Общие объявления генерируются один раз на файл.
+	if n := strings.Count(out, "type CollisionPolicy int"); n != 1 {
+		t.Errorf("CollisionPolicy объявлен %d раз", n)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", `package p
+
+//repogen:entity
+type NoKey struct{ Name string }
+`, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := generate(file, "memory"); err == nil {
+		t.Error("ожидалась ошибка для структуры без первичного ключа")
+	}
+	if _, err := generate(file, "sqlite"); err == nil {
+		t.Error("ожидалась ошибка для неизвестного бэкенда")
+	}
+}
+
+func TestLowerFirstWord(t *testing.T) {
+	for in, want := range map[string]string{
+		"UserID":  "userID",
+		"ID":      "id",
+		"Email":   "email",
+		"IDValue": "idValue",
+		"SKU":     "sku",
+	} {
+		if got := lowerFirstWord(in); got != want {
+			t.Errorf("lowerFirstWord(%q) = %q, ожидалось %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerateGormBackend(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", `package p
+
+//repogen:entity
+type User struct {
+	UserID uint   `+"`gorm:\"primary_key\"`"+`
+	Login  string `+"`gorm:\"unique_index:idx_login;column:login_name\"`"+`
+}
+`, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := generate(file, "gorm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"github.com/jinzhu/gorm"`, "func NewUserRepository(db *gorm.DB) UserRepository", `Where("user_id = ?", UserID)`,
+		"func (r UserRepository) FindByLogin(login string) (*User, error)", `Where("login_name = ?", login)`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("в сгенерированном коде нет %q", want)
+		}
+	}
+}
@@ -1,10 +1,10 @@
 package main
 
-//go:generate repogen
+//go:generate go run ./cmd/repogen -backend=memory
 
 //repogen:entity
 type User struct {
-	UserID       uint `gorm:"primary_key"`
-	Email        string
+	UserID       uint   `gorm:"primary_key"`
+	Email        string `gorm:"unique_index"`
 	PasswordHash string
 }
@@ -0,0 +1,88 @@
+// Code generated by repogen. DO NOT EDIT.
+
+package main
+
+import (
+	"repogen/memstore"
+)
+
+var (
+	// ErrNotFound возвращается, если записи с таким первичным ключом нет.
+	ErrNotFound = memstore.ErrNotFound
+	// ErrDuplicateKey возвращается из Create при политике CollisionError,
+	// если запись с таким первичным ключом уже есть.
+	ErrDuplicateKey = memstore.ErrDuplicateKey
+	// ErrUniqueViolation возвращается, если значение поля с тегом gorm:"unique_index"
+	// уже занято другой записью.
+	ErrUniqueViolation = memstore.ErrUniqueViolation
+)
+
+// CollisionPolicy определяет поведение Create при совпадении первичного ключа.
+type CollisionPolicy int
+
+const (
+	// CollisionError — Create возвращает ErrDuplicateKey, как INSERT в таблицу с PRIMARY KEY.
+	CollisionError CollisionPolicy = iota
+	// CollisionUpsert — Create перезаписывает существующую запись, как INSERT ... ON CONFLICT DO UPDATE.
+	CollisionUpsert
+)
+
+// UserRepository хранит сущности User в памяти по ключу UserID.
+// Get возвращает копию, поэтому изменения полученной сущности не влияют на хранилище без Set.
+type UserRepository struct {
+	store       *memstore.MemStore[uint, User]
+	onCollision CollisionPolicy
+	byEmail     *memstore.UniqueIndex[string, uint, User]
+}
+
+// NewUserRepository создает пустой репозиторий с политикой коллизий onCollision для Create.
+func NewUserRepository(onCollision CollisionPolicy) *UserRepository {
+	store := memstore.NewMemStore(func(e User) uint { return e.UserID })
+	return &UserRepository{
+		store:       store,
+		onCollision: onCollision,
+		byEmail:     memstore.NewUniqueIndex(store, "email", func(e User) string { return e.Email }),
+	}
+}
+
+// Get возвращает сущность по первичному ключу или ErrNotFound.
+func (r *UserRepository) Get(userID uint) (*User, error) {
+	entity, err := r.store.Read(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// FindByEmail возвращает сущность по уникальному полю Email или ErrNotFound.
+func (r *UserRepository) FindByEmail(email string) (*User, error) {
+	entity, err := r.byEmail.Find(email)
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// Create добавляет сущность. При совпадении первичного ключа поведение задает CollisionPolicy;
+// при совпадении уникального поля возвращается ErrUniqueViolation.
+func (r *UserRepository) Create(entity *User) error {
+	if r.onCollision == CollisionUpsert {
+		return r.store.Put(*entity)
+	}
+	return r.store.Create(*entity)
+}
+
+// Set сохраняет сущность, перезаписывая существующую с тем же ключом (upsert).
+func (r *UserRepository) Set(entity *User) error {
+	return r.store.Put(*entity)
+}
+
+// Del удаляет сущность по первичному ключу или возвращает ErrNotFound.
+func (r *UserRepository) Del(userID uint) error {
+	return r.store.Delete(userID)
+}
+
+// List возвращает все сущности в порядке создания.
+func (r *UserRepository) List() []User {
+	return r.store.List()
+}
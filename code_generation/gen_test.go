@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// store — интерфейс в стиле Repository из design_patterns/cached_repo, но типизированный.
+type store interface {
+	Get(userID uint) (*User, error)
+	Set(entity *User) error
+	Del(userID uint) error
+}
+
+// Сгенерированный репозиторий должен удовлетворять интерфейсу Get/Set/Del.
+var _ store = (*UserRepository)(nil)
+
+func TestUserRepositoryCRUD(t *testing.T) {
+	repo := NewUserRepository(CollisionError)
+
+	if _, err := repo.Get(1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get из пустого репозитория: err = %v, ожидалось %v", err, ErrNotFound)
+	}
+
+	if err := repo.Set(&User{UserID: 1, Email: "a@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	u, err := repo.Get(1)
+	if err != nil || u.Email != "a@example.com" {
+		t.Fatalf("Get = (%+v, %v)", u, err)
+	}
+
+	// Get возвращает копию: изменения не попадают в хранилище без Set.
+	u.Email = "changed@example.com"
+	if again, _ := repo.Get(1); again.Email != "a@example.com" {
+		t.Errorf("изменение копии повлияло на хранилище: %q", again.Email)
+	}
+
+	if err := repo.Del(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Del(1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("повторный Del: err = %v, ожидалось %v", err, ErrNotFound)
+	}
+}
+
+func TestUserRepositoryCollisionPolicy(t *testing.T) {
+	tests := []struct {
+		policy    CollisionPolicy
+		wantErr   error
+		wantEmail string
+	}{
+		{CollisionError, ErrDuplicateKey, "first@example.com"},
+		{CollisionUpsert, nil, "second@example.com"},
+	}
+	for _, tt := range tests {
+		repo := NewUserRepository(tt.policy)
+		if err := repo.Create(&User{UserID: 7, Email: "first@example.com"}); err != nil {
+			t.Fatalf("политика %d: первый Create: %v", tt.policy, err)
+		}
+
+		err := repo.Create(&User{UserID: 7, Email: "second@example.com"})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("политика %d: повторный Create: err = %v, ожидалось %v", tt.policy, err, tt.wantErr)
+		}
+		if u, _ := repo.Get(7); u.Email != tt.wantEmail {
+			t.Errorf("политика %d: Email = %q, ожидалось %q", tt.policy, u.Email, tt.wantEmail)
+		}
+
+		// Set — всегда upsert, независимо от политики.
+		if err := repo.Set(&User{UserID: 7, Email: "set@example.com"}); err != nil {
+			t.Errorf("политика %d: Set: %v", tt.policy, err)
+		}
+	}
+}
+
+func TestUserRepositoryConcurrentCreate(t *testing.T) {
+	repo := NewUserRepository(CollisionError)
+
+	// Ровно один из конкурентных Create с одним ключом должен выиграть.
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = repo.Create(&User{UserID: 42})
+		}()
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, ErrDuplicateKey):
+			t.Errorf("неожиданная ошибка: %v", err)
+		}
+	}
+	if created != 1 {
+		t.Errorf("успешных Create: %d, ожидался 1", created)
+	}
+}
+
+func TestUserRepositoryList(t *testing.T) {
+	repo := NewUserRepository(CollisionUpsert)
+	for _, id := range []uint{3, 1, 2} {
+		_ = repo.Create(&User{UserID: id})
+	}
+	_ = repo.Set(&User{UserID: 3, Email: "upd@example.com"}) // Перезапись не меняет позицию.
+	_ = repo.Del(1)
+
+	users := repo.List()
+	if len(users) != 2 || users[0].UserID != 3 || users[1].UserID != 2 {
+		t.Fatalf("List = %+v, ожидались пользователи 3 и 2 в порядке создания", users)
+	}
+	if users[0].Email != "upd@example.com" {
+		t.Errorf("List вернул устаревшую запись: %+v", users[0])
+	}
+}
+
+func TestUserRepositoryFindByEmail(t *testing.T) {
+	repo := NewUserRepository(CollisionError)
+	_ = repo.Create(&User{UserID: 1, Email: "alice@example.com"})
+	_ = repo.Create(&User{UserID: 2, Email: "bob@example.com"})
+
+	u, err := repo.FindByEmail("bob@example.com")
+	if err != nil || u.UserID != 2 {
+		t.Fatalf("FindByEmail = (%+v, %v)", u, err)
+	}
+	if _, err := repo.FindByEmail("nobody@example.com"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindByEmail неизвестного адреса: err = %v, ожидалось %v", err, ErrNotFound)
+	}
+
+	// Смена адреса через Set обновляет индекс.
+	_ = repo.Set(&User{UserID: 2, Email: "bob@new.example.com"})
+	if _, err := repo.FindByEmail("bob@example.com"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("старый адрес остался в индексе: err = %v", err)
+	}
+	if u, _ := repo.FindByEmail("bob@new.example.com"); u == nil || u.UserID != 2 {
+		t.Errorf("новый адрес не найден: %+v", u)
+	}
+}
+
+func TestUserRepositoryDuplicateEmail(t *testing.T) {
+	for _, policy := range []CollisionPolicy{CollisionError, CollisionUpsert} {
+		repo := NewUserRepository(policy)
+		_ = repo.Create(&User{UserID: 1, Email: "alice@example.com"})
+
+		if err := repo.Create(&User{UserID: 2, Email: "alice@example.com"}); !errors.Is(err, ErrUniqueViolation) {
+			t.Errorf("политика %d: Create с занятым email: err = %v, ожидалось %v", policy, err, ErrUniqueViolation)
+		}
+		if err := repo.Set(&User{UserID: 3, Email: "alice@example.com"}); !errors.Is(err, ErrUniqueViolation) {
+			t.Errorf("политика %d: Set с занятым email: err = %v, ожидалось %v", policy, err, ErrUniqueViolation)
+		}
+		if _, err := repo.Get(2); !errors.Is(err, ErrNotFound) {
+			t.Errorf("политика %d: отклоненная запись сохранена", policy)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Package main демонстрирует использование репозитория, сгенерированного repogen
+// (см. gen.go и сгенерированный gen_gen.go). Перегенерировать код: `go generate ./...`.
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+func main() {
+	// Строгий репозиторий: повторный Create с тем же ключом — ошибка, как в БД.
+	users := NewUserRepository(CollisionError)
+	_ = users.Create(&User{UserID: 1, Email: "alice@example.com"})
+
+	err := users.Create(&User{UserID: 1, Email: "impostor@example.com"})
+	fmt.Printf("Повторный Create: %v (ErrDuplicateKey: %t)\n", err, errors.Is(err, ErrDuplicateKey))
+
+	// Set всегда перезаписывает запись.
+	_ = users.Set(&User{UserID: 1, Email: "alice@new.example.com"})
+	u, _ := users.Get(1)
+	fmt.Printf("После Set: %+v\n", *u)
+
+	// С политикой CollisionUpsert повторный Create тоже перезаписывает запись.
+	upserts := NewUserRepository(CollisionUpsert)
+	_ = upserts.Create(&User{UserID: 2, Email: "bob@example.com"})
+	_ = upserts.Create(&User{UserID: 2, Email: "bob@new.example.com"})
+	u, _ = upserts.Get(2)
+	fmt.Printf("Upsert через Create: %+v\n", *u)
+
+	_ = users.Create(&User{UserID: 3, Email: "carol@example.com"})
+	fmt.Printf("Все пользователи (в порядке создания): %+v\n", users.List())
+
+	u, _ = users.FindByEmail("carol@example.com")
+	fmt.Printf("FindByEmail: %+v\n", *u)
+	err = users.Create(&User{UserID: 4, Email: "carol@example.com"})
+	fmt.Printf("Create с занятым email: %v (ErrUniqueViolation: %t)\n", err, errors.Is(err, ErrUniqueViolation))
+
+	_ = users.Del(1)
+	_, err = users.Get(1)
+	fmt.Printf("После Del: %v\n", err)
+}
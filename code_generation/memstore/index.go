@@ -0,0 +1,84 @@
+package memstore
+
+import "fmt"
+
+// index — вторичный индекс, который MemStore обновляет при каждом изменении данных.
+// Все методы вызываются под мьютексом хранилища.
+type index[K comparable, T any] interface {
+	check(k K, v T) error // Конфликтует ли v (с ключом k) с записями других ключей.
+	add(k K, v T)
+	remove(v T)
+}
+
+// UniqueIndex — вторичный уникальный индекс MemStore по полю F, извлекаемому функцией
+// field (аналог UNIQUE INDEX в SQL). Create, Update и Put, которые заняли бы значение
+// поля, уже принадлежащее другой записи, возвращают ErrUniqueViolation.
+//
+// Нулевое значение поля не индексируется (как NULL в SQL): записей с пустым полем
+// может быть сколько угодно, и найти их через Find нельзя.
+type UniqueIndex[F comparable, K comparable, T any] struct {
+	name  string
+	store *MemStore[K, T]
+	field func(T) F
+	keys  map[F]K
+}
+
+// NewUniqueIndex создает уникальный индекс name по полю field и регистрирует его в s.
+// Уже сохраненные записи индексируются сразу; если они нарушают уникальность,
+// NewUniqueIndex паникует — индексы рассчитаны на объявление вместе с хранилищем.
+func NewUniqueIndex[F comparable, K comparable, T any](s *MemStore[K, T], name string, field func(T) F) *UniqueIndex[F, K, T] {
+	idx := &UniqueIndex[F, K, T]{
+		name:  name,
+		store: s,
+		field: field,
+		keys:  make(map[F]K),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, k := range s.order {
+		v := s.items[k]
+		if err := idx.check(k, v); err != nil {
+			panic(err)
+		}
+		idx.add(k, v)
+	}
+	s.indexes = append(s.indexes, idx)
+	return idx
+}
+
+// Find возвращает запись с заданным значением поля или ErrNotFound.
+func (idx *UniqueIndex[F, K, T]) Find(value F) (T, error) {
+	idx.store.mu.RLock()
+	defer idx.store.mu.RUnlock()
+	var zero F
+	k, ok := idx.keys[value]
+	if !ok || value == zero {
+		var none T
+		return none, ErrNotFound
+	}
+	return idx.store.items[k], nil
+}
+
+func (idx *UniqueIndex[F, K, T]) check(k K, v T) error {
+	f := idx.field(v)
+	var zero F
+	if f == zero {
+		return nil
+	}
+	if owner, ok := idx.keys[f]; ok && owner != k {
+		return fmt.Errorf("индекс %s, значение %v: %w", idx.name, f, ErrUniqueViolation)
+	}
+	return nil
+}
+
+func (idx *UniqueIndex[F, K, T]) add(k K, v T) {
+	var zero F
+	if f := idx.field(v); f != zero {
+		idx.keys[f] = k
+	}
+}
+
+func (idx *UniqueIndex[F, K, T]) remove(v T) {
+	delete(idx.keys, idx.field(v))
+}
@@ -0,0 +1,105 @@
+package memstore
+
+import (
+	"errors"
+	"testing"
+)
+
+func newIndexedStore() (*MemStore[int, item], *UniqueIndex[string, int, item]) {
+	s := newItemStore()
+	return s, NewUniqueIndex(s, "name", func(it item) string { return it.Name })
+}
+
+func TestUniqueIndexFind(t *testing.T) {
+	s, byName := newIndexedStore()
+	_ = s.Create(item{ID: 1, Name: "a"})
+	_ = s.Create(item{ID: 2, Name: "b"})
+
+	got, err := byName.Find("b")
+	if err != nil || got.ID != 2 {
+		t.Fatalf("Find(b) = (%+v, %v)", got, err)
+	}
+	if _, err := byName.Find("zzz"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find отсутствующего значения: err = %v, ожидалось %v", err, ErrNotFound)
+	}
+}
+
+func TestUniqueIndexViolation(t *testing.T) {
+	s, byName := newIndexedStore()
+	_ = s.Create(item{ID: 1, Name: "a"})
+	_ = s.Create(item{ID: 2, Name: "b"})
+
+	if err := s.Create(item{ID: 3, Name: "a"}); !errors.Is(err, ErrUniqueViolation) {
+		t.Errorf("Create с занятым значением: err = %v, ожидалось %v", err, ErrUniqueViolation)
+	}
+	if err := s.Update(item{ID: 2, Name: "a"}); !errors.Is(err, ErrUniqueViolation) {
+		t.Errorf("Update на занятое значение: err = %v", err)
+	}
+	if err := s.Put(item{ID: 3, Name: "b"}); !errors.Is(err, ErrUniqueViolation) {
+		t.Errorf("Put с занятым значением: err = %v", err)
+	}
+	// Отклоненные операции не меняют ни данные, ни индекс.
+	if s.Len() != 2 {
+		t.Errorf("Len = %d, ожидалось 2", s.Len())
+	}
+	if got, _ := byName.Find("b"); got.ID != 2 {
+		t.Errorf("Find(b) = %+v после отклоненного Update", got)
+	}
+	// Повторное сохранение записи со своим же значением — не конфликт.
+	if err := s.Put(item{ID: 1, Name: "a"}); err != nil {
+		t.Errorf("Put той же записи: %v", err)
+	}
+}
+
+func TestUniqueIndexFollowsUpdatesAndDeletes(t *testing.T) {
+	s, byName := newIndexedStore()
+	_ = s.Create(item{ID: 1, Name: "old"})
+
+	_ = s.Update(item{ID: 1, Name: "new"})
+	if _, err := byName.Find("old"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("старое значение осталось в индексе: err = %v", err)
+	}
+	if got, err := byName.Find("new"); err != nil || got.ID != 1 {
+		t.Errorf("Find(new) = (%+v, %v)", got, err)
+	}
+	// Освободившееся значение можно занять другой записью.
+	if err := s.Create(item{ID: 2, Name: "old"}); err != nil {
+		t.Errorf("Create с освободившимся значением: %v", err)
+	}
+
+	_ = s.Delete(1)
+	if _, err := byName.Find("new"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("значение удаленной записи осталось в индексе: err = %v", err)
+	}
+}
+
+func TestUniqueIndexSkipsZeroValue(t *testing.T) {
+	s, byName := newIndexedStore()
+	for id := 1; id <= 3; id++ {
+		if err := s.Create(item{ID: id}); err != nil {
+			t.Fatalf("записи с пустым полем не должны конфликтовать: %v", err)
+		}
+	}
+	if _, err := byName.Find(""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find пустого значения: err = %v, ожидалось %v", err, ErrNotFound)
+	}
+}
+
+func TestNewUniqueIndexOnExistingData(t *testing.T) {
+	s := newItemStore()
+	_ = s.Create(item{ID: 1, Name: "a"})
+	byName := NewUniqueIndex(s, "name", func(it item) string { return it.Name })
+	if got, err := byName.Find("a"); err != nil || got.ID != 1 {
+		t.Errorf("существующая запись не проиндексирована: (%+v, %v)", got, err)
+	}
+
+	dups := newItemStore()
+	_ = dups.Create(item{ID: 1, Name: "a"})
+	_ = dups.Create(item{ID: 2, Name: "a"})
+	defer func() {
+		if recover() == nil {
+			t.Error("ожидалась паника при индексации данных с дубликатами")
+		}
+	}()
+	NewUniqueIndex(dups, "name", func(it item) string { return it.Name })
+}
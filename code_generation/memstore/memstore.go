@@ -0,0 +1,167 @@
+// Package memstore содержит обобщенное CRUD-хранилище в памяти, на котором
+// строятся репозитории, сгенерированные repogen с флагом -backend=memory.
+package memstore
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrNotFound возвращается, если записи с таким ключом нет.
+	ErrNotFound = errors.New("запись не найдена")
+	// ErrDuplicateKey возвращается из Create, если запись с таким ключом уже есть.
+	ErrDuplicateKey = errors.New("запись с таким первичным ключом уже существует")
+	// ErrUniqueViolation возвращается, если значение поля уже занято другой записью
+	// в уникальном индексе (см. UniqueIndex).
+	ErrUniqueViolation = errors.New("нарушение уникального индекса")
+)
+
+// MemStore — потокобезопасное хранилище значений T с ключом K, который извлекается
+// из самого значения функцией key (как первичный ключ из строки таблицы).
+//
+// Значения хранятся по значению: Read возвращает копию, и изменить запись можно
+// только через Update или Put. List возвращает записи в порядке их создания,
+// поэтому вывод стабилен между вызовами (в отличие от обхода map).
+type MemStore[K comparable, T any] struct {
+	mu      sync.RWMutex
+	key     func(T) K
+	items   map[K]T
+	order   []K // Ключи в порядке создания.
+	indexes []index[K, T]
+}
+
+// NewMemStore создает пустое хранилище с функцией извлечения ключа key.
+func NewMemStore[K comparable, T any](key func(T) K) *MemStore[K, T] {
+	return &MemStore[K, T]{
+		key:   key,
+		items: make(map[K]T),
+	}
+}
+
+// Create добавляет новую запись или возвращает ErrDuplicateKey, если ключ занят.
+func (s *MemStore[K, T]) Create(v T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := s.key(v)
+	if _, ok := s.items[k]; ok {
+		return ErrDuplicateKey
+	}
+	if err := s.checkIndexes(k, v); err != nil {
+		return err
+	}
+	s.insert(k, v)
+	return nil
+}
+
+// Read возвращает запись по ключу или ErrNotFound.
+func (s *MemStore[K, T]) Read(k K) (T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.items[k]
+	if !ok {
+		var zero T
+		return zero, ErrNotFound
+	}
+	return v, nil
+}
+
+// Update заменяет существующую запись или возвращает ErrNotFound.
+// Позиция записи в List не меняется.
+func (s *MemStore[K, T]) Update(v T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := s.key(v)
+	old, ok := s.items[k]
+	if !ok {
+		return ErrNotFound
+	}
+	if err := s.checkIndexes(k, v); err != nil {
+		return err
+	}
+	s.replace(k, old, v)
+	return nil
+}
+
+// Put создает или заменяет запись (upsert). Ошибка возможна только при нарушении
+// уникального индекса.
+func (s *MemStore[K, T]) Put(v T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := s.key(v)
+	if err := s.checkIndexes(k, v); err != nil {
+		return err
+	}
+	if old, ok := s.items[k]; ok {
+		s.replace(k, old, v)
+		return nil
+	}
+	s.insert(k, v)
+	return nil
+}
+
+// Delete удаляет запись по ключу или возвращает ErrNotFound.
+func (s *MemStore[K, T]) Delete(k K) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.items[k]
+	if !ok {
+		return ErrNotFound
+	}
+	for _, idx := range s.indexes {
+		idx.remove(old)
+	}
+	delete(s.items, k)
+	// Линейное удаление из order: хранилище рассчитано на примеры и тесты, а не на миллионы записей.
+	s.order = slices.DeleteFunc(s.order, func(o K) bool { return o == k })
+	return nil
+}
+
+// List возвращает копии всех записей в порядке создания.
+func (s *MemStore[K, T]) List() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]T, 0, len(s.order))
+	for _, k := range s.order {
+		res = append(res, s.items[k])
+	}
+	return res
+}
+
+// Len возвращает количество записей.
+func (s *MemStore[K, T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.items)
+}
+
+// insert добавляет новую запись; вызывается под s.mu после checkIndexes.
+func (s *MemStore[K, T]) insert(k K, v T) {
+	s.items[k] = v
+	s.order = append(s.order, k)
+	for _, idx := range s.indexes {
+		idx.add(k, v)
+	}
+}
+
+// replace заменяет запись old с ключом k на v; вызывается под s.mu после checkIndexes.
+func (s *MemStore[K, T]) replace(k K, old, v T) {
+	for _, idx := range s.indexes {
+		idx.remove(old)
+		idx.add(k, v)
+	}
+	s.items[k] = v
+}
+
+// checkIndexes проверяет, что запись v с ключом k не конфликтует с другими записями
+// ни в одном индексе. Вызывается под s.mu до изменения данных, чтобы операция
+// либо применялась ко всем индексам, либо не применялась вовсе.
+func (s *MemStore[K, T]) checkIndexes(k K, v T) error {
+	for _, idx := range s.indexes {
+		if err := idx.check(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
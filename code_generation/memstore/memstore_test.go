@@ -0,0 +1,126 @@
+package memstore
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+type item struct {
+	ID   int
+	Name string
+}
+
+func newItemStore() *MemStore[int, item] {
+	return NewMemStore(func(it item) int { return it.ID })
+}
+
+// names возвращает имена записей из List по порядку.
+func names(items []item) []string {
+	res := make([]string, len(items))
+	for i, it := range items {
+		res[i] = it.Name
+	}
+	return res
+}
+
+func TestMemStoreCreateThenRead(t *testing.T) {
+	s := newItemStore()
+	if err := s.Create(item{ID: 1, Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Read(1)
+	if err != nil || got.Name != "a" {
+		t.Fatalf("Read = (%+v, %v)", got, err)
+	}
+	if err := s.Create(item{ID: 1, Name: "dup"}); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("повторный Create: err = %v, ожидалось %v", err, ErrDuplicateKey)
+	}
+	if _, err := s.Read(2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Read отсутствующего ключа: err = %v, ожидалось %v", err, ErrNotFound)
+	}
+}
+
+func TestMemStoreUpdate(t *testing.T) {
+	s := newItemStore()
+	if err := s.Update(item{ID: 1, Name: "x"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update отсутствующей записи: err = %v, ожидалось %v", err, ErrNotFound)
+	}
+
+	_ = s.Create(item{ID: 1, Name: "old"})
+	if err := s.Update(item{ID: 1, Name: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.Read(1); got.Name != "new" {
+		t.Errorf("после Update Name = %q", got.Name)
+	}
+
+	s.Put(item{ID: 1, Name: "put"})
+	s.Put(item{ID: 2, Name: "created by put"})
+	if got, _ := s.Read(1); got.Name != "put" {
+		t.Errorf("Put не заменил запись: %q", got.Name)
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len = %d, ожидалось 2", s.Len())
+	}
+}
+
+func TestMemStoreDelete(t *testing.T) {
+	s := newItemStore()
+	_ = s.Create(item{ID: 1, Name: "a"})
+
+	if err := s.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Read(1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Read после Delete: err = %v", err)
+	}
+	if err := s.Delete(1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("повторный Delete: err = %v, ожидалось %v", err, ErrNotFound)
+	}
+	// После удаления ключ снова свободен.
+	if err := s.Create(item{ID: 1, Name: "again"}); err != nil {
+		t.Errorf("Create после Delete: %v", err)
+	}
+}
+
+func TestMemStoreListStableOrder(t *testing.T) {
+	s := newItemStore()
+	for _, it := range []item{{3, "c"}, {1, "a"}, {2, "b"}, {5, "e"}} {
+		_ = s.Create(it)
+	}
+	_ = s.Update(item{ID: 1, Name: "a2"}) // Update не меняет позицию.
+	_ = s.Delete(2)
+	s.Put(item{ID: 4, Name: "d"}) // Новая запись — в конец.
+
+	want := []string{"c", "a2", "e", "d"}
+	for i := 0; i < 3; i++ {
+		if got := names(s.List()); !slices.Equal(got, want) {
+			t.Fatalf("List = %v, ожидалось %v", got, want)
+		}
+	}
+}
+
+func TestMemStoreConcurrent(t *testing.T) {
+	s := newItemStore()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = s.Create(item{ID: i % 10})
+			_, _ = s.Read(i % 10)
+			_ = s.List()
+			if i%3 == 0 {
+				_ = s.Delete(i % 10)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(s.List()); got != s.Len() {
+		t.Errorf("List вернул %d записей, Len = %d", got, s.Len())
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"golang.org/x/sync/errgroup"
@@ -13,9 +14,42 @@ type Resp struct {
 	// но можно оставить, если это часть бизнес-логики.
 }
 
+// Client — функция обращения к одному адресу.
+type Client func(ctx context.Context, addr string) (Resp, error)
+
+// AddrError связывает ошибку с адресом, на котором она произошла.
+type AddrError struct {
+	Addr string
+	Err  error
+}
+
+func (e *AddrError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Addr, e.Err)
+}
+
+// Unwrap позволяет проверять исходную ошибку через errors.Is / errors.As.
+func (e *AddrError) Unwrap() error {
+	return e.Err
+}
+
 func main() {
 	// Пример вызова
 	MyChanGroup(context.Background(), []string{"192.168.0.1", "127.0.0.1", "google.com"})
+
+	// Режим "собрать все ошибки": ни одна ошибка не отменяет остальные запросы.
+	flaky := func(ctx context.Context, addr string) (Resp, error) {
+		if addr == "127.0.0.1" {
+			return Resp{}, errors.New("connection refused")
+		}
+		return Resp{Response: []byte("data from " + addr)}, nil
+	}
+	resps, errs := MyChanGroupCollectAll(context.Background(), []string{"192.168.0.1", "127.0.0.1", "google.com"}, flaky)
+	for _, resp := range resps {
+		fmt.Printf("CollectAll received: %s\n", resp.Response)
+	}
+	for _, err := range errs {
+		fmt.Printf("CollectAll error: %v\n", err)
+	}
 }
 
 func MyChanGroup(ctx context.Context, addrs []string) error {
@@ -75,3 +109,44 @@ func MyChanGroup(ctx context.Context, addrs []string) error {
 	fmt.Println("Finished successfully")
 	return nil
 }
+
+// MyChanGroupCollectAll — вариант MyChanGroup без fail-fast: запросы выполняются ко всем
+// адресам, даже если часть из них завершилась ошибкой.
+//
+// Используется обычный errgroup.Group без WithContext, а горутины всегда возвращают nil,
+// поэтому ошибка одного адреса не отменяет остальные. Отменить операцию можно только
+// через родительский ctx.
+//
+// Возвращает успешные ответы и ошибки (типа *AddrError) в порядке адресов во входном срезе.
+func MyChanGroupCollectAll(ctx context.Context, addrs []string, client Client) ([]Resp, []error) {
+	var g errgroup.Group
+	g.SetLimit(10)
+
+	// Каждая горутина пишет только в свою ячейку — синхронизация не нужна.
+	resps := make([]Resp, len(addrs))
+	errs := make([]error, len(addrs))
+
+	for i, addr := range addrs {
+		g.Go(func() error {
+			resp, err := client(ctx, addr)
+			if err != nil {
+				errs[i] = &AddrError{Addr: addr, Err: err}
+				return nil // Не прерываем группу.
+			}
+			resps[i] = resp
+			return nil
+		})
+	}
+	_ = g.Wait() // Всегда nil: ошибки собраны в errs.
+
+	var okResps []Resp
+	var allErrs []error
+	for i := range addrs {
+		if errs[i] != nil {
+			allErrs = append(allErrs, errs[i])
+		} else {
+			okResps = append(okResps, resps[i])
+		}
+	}
+	return okResps, allErrs
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestMyChanGroupCollectAllAttemptsEveryAddr(t *testing.T) {
+	errRefused := errors.New("connection refused")
+	failing := map[string]bool{"b": true, "d": true}
+
+	var mu sync.Mutex
+	attempted := map[string]int{}
+	client := func(ctx context.Context, addr string) (Resp, error) {
+		mu.Lock()
+		attempted[addr]++
+		mu.Unlock()
+		if failing[addr] {
+			return Resp{}, errRefused
+		}
+		return Resp{Response: []byte(addr)}, nil
+	}
+
+	addrs := []string{"a", "b", "c", "d", "e"}
+	resps, errs := MyChanGroupCollectAll(context.Background(), addrs, client)
+
+	for _, addr := range addrs {
+		if attempted[addr] != 1 {
+			t.Errorf("адрес %q опрошен %d раз, ожидался 1", addr, attempted[addr])
+		}
+	}
+
+	if len(resps) != 3 {
+		t.Errorf("получено %d ответов, ожидалось 3", len(resps))
+	}
+	for i, want := range []string{"a", "c", "e"} {
+		if i < len(resps) && string(resps[i].Response) != want {
+			t.Errorf("resps[%d] = %q, ожидалось %q", i, resps[i].Response, want)
+		}
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("получено %d ошибок, ожидалось 2: %v", len(errs), errs)
+	}
+	for i, want := range []string{"b", "d"} {
+		var addrErr *AddrError
+		if !errors.As(errs[i], &addrErr) {
+			t.Fatalf("errs[%d] = %v не является *AddrError", i, errs[i])
+		}
+		if addrErr.Addr != want {
+			t.Errorf("errs[%d].Addr = %q, ожидалось %q", i, addrErr.Addr, want)
+		}
+		if !errors.Is(errs[i], errRefused) {
+			t.Errorf("errs[%d] не оборачивает исходную ошибку", i)
+		}
+	}
+}
+
+func TestMyChanGroupCollectAllNoErrors(t *testing.T) {
+	client := func(ctx context.Context, addr string) (Resp, error) {
+		return Resp{Response: []byte(addr)}, nil
+	}
+	resps, errs := MyChanGroupCollectAll(context.Background(), []string{"x", "y"}, client)
+	if len(resps) != 2 || errs != nil {
+		t.Errorf("resps = %d, errs = %v", len(resps), errs)
+	}
+}
@@ -0,0 +1,131 @@
+// Package main демонстрирует обобщенную потокобезопасную карту ConcurrentMap
+// с возможностью сохранить ее состояние (снимок) и восстановить его при перезапуске.
+//
+// Карта защищена sync.RWMutex (см. maps/reads_writes): чтения идут параллельно,
+// записи — эксклюзивно. Снимок делается под блокировкой на чтение, поэтому он
+// согласован: в него не попадет "половина" пакетного обновления.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+)
+
+// ConcurrentMap — потокобезопасная карта с произвольными ключами и значениями.
+// Нулевое значение не готово к использованию, создавайте через NewConcurrentMap.
+type ConcurrentMap[K comparable, V any] struct {
+	mu   sync.RWMutex
+	data map[K]V
+}
+
+// NewConcurrentMap создает пустую карту.
+func NewConcurrentMap[K comparable, V any]() *ConcurrentMap[K, V] {
+	return &ConcurrentMap[K, V]{data: make(map[K]V)}
+}
+
+// Get возвращает значение по ключу и признак его наличия.
+func (m *ConcurrentMap[K, V]) Get(key K) (V, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data[key]
+	return v, ok
+}
+
+// Set сохраняет значение по ключу.
+func (m *ConcurrentMap[K, V]) Set(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = value
+}
+
+// SetAll атомарно сохраняет несколько значений: читатели и снимки увидят
+// либо все изменения, либо ни одного.
+func (m *ConcurrentMap[K, V]) SetAll(entries map[K]V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	maps.Copy(m.data, entries)
+}
+
+// Delete удаляет ключ.
+func (m *ConcurrentMap[K, V]) Delete(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+}
+
+// Len возвращает количество элементов.
+func (m *ConcurrentMap[K, V]) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.data)
+}
+
+// Snapshot возвращает копию содержимого карты, сделанную под блокировкой на чтение.
+//
+// Копируется сама карта, а значения — по значению. Если V содержит указатели,
+// срезы или карты, они разделяются со снимком ("неглубокая" копия значений),
+// поэтому такие значения нужно считать неизменяемыми.
+func (m *ConcurrentMap[K, V]) Snapshot() map[K]V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return maps.Clone(m.data)
+}
+
+// Restore заменяет все содержимое карты копией snapshot.
+// Переданная карта не сохраняется внутри, поэтому ее можно дальше изменять.
+func (m *ConcurrentMap[K, V]) Restore(snapshot map[K]V) {
+	data := make(map[K]V, len(snapshot))
+	maps.Copy(data, snapshot)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data = data
+}
+
+// MarshalJSON сериализует согласованный снимок карты.
+// Ключи должны поддерживаться encoding/json (строки, целые числа или encoding.TextMarshaler).
+func (m *ConcurrentMap[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Snapshot())
+}
+
+// UnmarshalJSON восстанавливает карту из JSON, заменяя текущее содержимое.
+func (m *ConcurrentMap[K, V]) UnmarshalJSON(data []byte) error {
+	var snapshot map[K]V
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	m.Restore(snapshot)
+	return nil
+}
+
+func main() {
+	cache := NewConcurrentMap[string, int]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.Set(fmt.Sprintf("user:%d", i), i*10)
+		}()
+	}
+	wg.Wait()
+
+	// "Теплый" перезапуск: сохраняем состояние в JSON и поднимаем новую карту из него.
+	data, err := json.Marshal(cache)
+	if err != nil {
+		fmt.Println("Ошибка сериализации:", err)
+		return
+	}
+	fmt.Printf("Снимок: %s\n", data)
+
+	restored := NewConcurrentMap[string, int]()
+	if err := json.Unmarshal(data, restored); err != nil {
+		fmt.Println("Ошибка восстановления:", err)
+		return
+	}
+	v, _ := restored.Get("user:3")
+	fmt.Printf("После восстановления: %d элементов, user:3 = %d\n", restored.Len(), v)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"sync"
+	"testing"
+)
+
+func TestSnapshotIsCopy(t *testing.T) {
+	m := NewConcurrentMap[string, int]()
+	m.Set("a", 1)
+
+	snap := m.Snapshot()
+	snap["a"] = 100
+	snap["b"] = 2
+
+	if v, _ := m.Get("a"); v != 1 {
+		t.Errorf("изменение снимка повлияло на карту: a = %d", v)
+	}
+	if m.Len() != 1 {
+		t.Errorf("Len = %d, ожидалось 1", m.Len())
+	}
+}
+
+func TestRestoreReplacesContent(t *testing.T) {
+	m := NewConcurrentMap[string, int]()
+	m.Set("old", 1)
+
+	src := map[string]int{"x": 1, "y": 2}
+	m.Restore(src)
+	src["z"] = 3 // Исходная карта не должна быть связана с ConcurrentMap.
+
+	if got := m.Snapshot(); !maps.Equal(got, map[string]int{"x": 1, "y": 2}) {
+		t.Errorf("после Restore: %v", got)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	m := NewConcurrentMap[int, string]()
+	m.SetAll(map[int]string{1: "one", 2: "two"})
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"1":"one","2":"two"}`; string(data) != want {
+		t.Errorf("JSON = %s, ожидалось %s", data, want)
+	}
+
+	restored := NewConcurrentMap[int, string]()
+	restored.Set(99, "будет удалено")
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.Snapshot(); !maps.Equal(got, m.Snapshot()) {
+		t.Errorf("после UnmarshalJSON: %v", got)
+	}
+}
+
+func TestUnmarshalJSONInvalidKeepsContent(t *testing.T) {
+	m := NewConcurrentMap[string, int]()
+	m.Set("a", 1)
+	if err := json.Unmarshal([]byte(`{"a": "not a number"}`), m); err == nil {
+		t.Fatal("ожидалась ошибка")
+	}
+	if v, _ := m.Get("a"); v != 1 {
+		t.Errorf("при ошибке содержимое изменилось: a = %d", v)
+	}
+}
+
+// TestSnapshotConsistentUnderWrites проверяет, что снимок не видит "половину" пакетного
+// обновления: писатели всегда меняют ключи a и b вместе, и в любом снимке они равны.
+func TestSnapshotConsistentUnderWrites(t *testing.T) {
+	m := NewConcurrentMap[string, int]()
+	m.SetAll(map[string]int{"a": 0, "b": 0})
+
+	stop := make(chan struct{})
+	var writers sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		writers.Add(1)
+		go func() {
+			defer writers.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				m.SetAll(map[string]int{"a": i, "b": i})
+				m.Set("noise", i)
+				m.Delete("noise")
+			}
+		}()
+	}
+
+	for i := 0; i < 2000; i++ {
+		snap := m.Snapshot()
+		if snap["a"] != snap["b"] {
+			t.Fatalf("несогласованный снимок: a = %d, b = %d", snap["a"], snap["b"])
+		}
+		if i%100 == 0 {
+			data, err := json.Marshal(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded map[string]int
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if decoded["a"] != decoded["b"] {
+				t.Fatalf("несогласованный JSON: %s", data)
+			}
+		}
+	}
+	close(stop)
+	writers.Wait()
+}
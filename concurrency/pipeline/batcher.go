@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// errBatcherClosed возвращается при добавлении элемента в закрытый Batcher.
+var errBatcherClosed = errors.New("batcher закрыт")
+
+// Batcher накапливает элементы и передает их пачками в функцию flush.
+//
+// Пачка отправляется, как только наберется maxSize элементов или пройдет maxDelay
+// с момента добавления первого элемента пачки — смотря что наступит раньше.
+// Так запись в медленный бэкенд идет крупными порциями, но задержка одного
+// элемента все равно ограничена maxDelay.
+//
+// Вызовы flush никогда не пересекаются и идут в порядке формирования пачек.
+// flush не должна вызывать Add или Close того же Batcher — это приведет к взаимной блокировке.
+type Batcher[T any] struct {
+	maxSize  int
+	maxDelay time.Duration
+	flush    func([]T)
+	// afterFunc запускает f через d и возвращает функцию остановки таймера;
+	// подменяется в тестах.
+	afterFunc func(d time.Duration, f func()) (stop func() bool)
+
+	mu        sync.Mutex
+	batch     []T
+	stopTimer func() bool // Остановка таймера текущей пачки (nil — таймер не запущен).
+	gen       uint64      // Номер текущей пачки: таймер старой пачки не должен сбросить новую.
+	closed    bool
+
+	// flushMu упорядочивает вызовы flush. Захватывается до освобождения mu,
+	// поэтому пачки отправляются строго в порядке их формирования.
+	flushMu sync.Mutex
+}
+
+// NewBatcher создает Batcher. Паникует, если maxSize или maxDelay не положительны.
+func NewBatcher[T any](maxSize int, maxDelay time.Duration, flush func([]T)) *Batcher[T] {
+	if maxSize <= 0 || maxDelay <= 0 {
+		panic("NewBatcher: maxSize и maxDelay должны быть положительными")
+	}
+	return &Batcher[T]{
+		maxSize:  maxSize,
+		maxDelay: maxDelay,
+		flush:    flush,
+		afterFunc: func(d time.Duration, f func()) func() bool {
+			return time.AfterFunc(d, f).Stop
+		},
+	}
+}
+
+// Add добавляет элемент в текущую пачку. Если пачка заполнилась, Add синхронно
+// отправляет ее в flush. После Close возвращает errBatcherClosed.
+func (b *Batcher[T]) Add(item T) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return errBatcherClosed
+	}
+
+	b.batch = append(b.batch, item)
+	if len(b.batch) == 1 {
+		// Первый элемент пачки запускает отсчет maxDelay.
+		gen := b.gen
+		b.stopTimer = b.afterFunc(b.maxDelay, func() { b.flushByTimer(gen) })
+	}
+	if len(b.batch) < b.maxSize {
+		b.mu.Unlock()
+		return nil
+	}
+	b.flushLocked()
+	return nil
+}
+
+// Close отправляет накопленный остаток и запрещает дальнейшие Add.
+// После возврата из Close вызовов flush больше не будет.
+func (b *Batcher[T]) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	// flushLocked дожидается и flush, начатого таймером до закрытия (через flushMu).
+	b.flushLocked()
+}
+
+// flushByTimer отправляет пачку номер gen, если она еще не была отправлена.
+func (b *Batcher[T]) flushByTimer(gen uint64) {
+	b.mu.Lock()
+	if gen != b.gen {
+		// Пачка уже ушла по размеру или при Close.
+		b.mu.Unlock()
+		return
+	}
+	b.flushLocked()
+}
+
+// flushLocked забирает текущую пачку и передает ее в flush.
+// Вызывается с захваченным mu и освобождает его.
+func (b *Batcher[T]) flushLocked() {
+	batch := b.batch
+	b.batch = nil
+	b.gen++
+	if b.stopTimer != nil {
+		b.stopTimer()
+		b.stopTimer = nil
+	}
+
+	b.flushMu.Lock()
+	b.mu.Unlock()
+	defer b.flushMu.Unlock()
+
+	if len(batch) > 0 {
+		b.flush(batch)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTimers — управляемая замена time.AfterFunc: таймеры срабатывают только в Advance.
+type fakeTimers struct {
+	mu     sync.Mutex
+	now    time.Duration
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at      time.Duration
+	f       func()
+	stopped bool
+}
+
+func (c *fakeTimers) AfterFunc(d time.Duration, f func()) func() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{at: c.now + d, f: f}
+	c.timers = append(c.timers, t)
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		wasActive := !t.stopped
+		t.stopped = true
+		return wasActive
+	}
+}
+
+// Advance сдвигает время и синхронно вызывает все наступившие таймеры.
+func (c *fakeTimers) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now += d
+	var due []*fakeTimer
+	for _, t := range c.timers {
+		if !t.stopped && t.at <= c.now {
+			t.stopped = true
+			due = append(due, t)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, t := range due {
+		t.f()
+	}
+}
+
+// flushRecorder запоминает все пачки, переданные во flush.
+type flushRecorder struct {
+	mu      sync.Mutex
+	batches [][]int
+}
+
+func (r *flushRecorder) flush(batch []int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, batch)
+}
+
+func (r *flushRecorder) get() [][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.batches)
+}
+
+func newTestBatcher(maxSize int, maxDelay time.Duration) (*Batcher[int], *flushRecorder, *fakeTimers) {
+	rec := &flushRecorder{}
+	clock := &fakeTimers{}
+	b := NewBatcher(maxSize, maxDelay, rec.flush)
+	b.afterFunc = clock.AfterFunc
+	return b, rec, clock
+}
+
+func equalBatches(a, b [][]int) bool {
+	return slices.EqualFunc(a, b, slices.Equal[[]int])
+}
+
+func TestBatcherFlushesBySize(t *testing.T) {
+	b, rec, _ := newTestBatcher(3, time.Second)
+	for i := 1; i <= 7; i++ {
+		if err := b.Add(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := [][]int{{1, 2, 3}, {4, 5, 6}}
+	if got := rec.get(); !equalBatches(got, want) {
+		t.Errorf("пачки = %v, ожидалось %v", got, want)
+	}
+}
+
+func TestBatcherFlushesByTime(t *testing.T) {
+	b, rec, clock := newTestBatcher(10, time.Second)
+	b.Add(1)
+	clock.Advance(500 * time.Millisecond)
+	b.Add(2)
+
+	clock.Advance(499 * time.Millisecond)
+	if got := rec.get(); len(got) != 0 {
+		t.Fatalf("до истечения maxDelay отправлено %v", got)
+	}
+
+	// maxDelay отсчитывается от первого элемента пачки.
+	clock.Advance(time.Millisecond)
+	if got, want := rec.get(), [][]int{{1, 2}}; !equalBatches(got, want) {
+		t.Fatalf("пачки = %v, ожидалось %v", got, want)
+	}
+
+	// Следующая пачка получает свой отсчет.
+	b.Add(3)
+	clock.Advance(time.Second)
+	if got, want := rec.get(), [][]int{{1, 2}, {3}}; !equalBatches(got, want) {
+		t.Errorf("пачки = %v, ожидалось %v", got, want)
+	}
+}
+
+func TestBatcherStaleTimerDoesNotFlushNewBatch(t *testing.T) {
+	b, rec, clock := newTestBatcher(2, time.Second)
+	b.Add(1)
+	b.Add(2) // Пачка ушла по размеру, ее таймер остановлен.
+	clock.Advance(500 * time.Millisecond)
+	b.Add(3)
+
+	clock.Advance(500 * time.Millisecond)
+	if got, want := rec.get(), [][]int{{1, 2}}; !equalBatches(got, want) {
+		t.Fatalf("таймер старой пачки отправил новую: %v", got)
+	}
+	clock.Advance(500 * time.Millisecond)
+	if got, want := rec.get(), [][]int{{1, 2}, {3}}; !equalBatches(got, want) {
+		t.Errorf("пачки = %v, ожидалось %v", got, want)
+	}
+}
+
+func TestBatcherCloseFlushesPartialBatch(t *testing.T) {
+	b, rec, clock := newTestBatcher(10, time.Second)
+	b.Add(1)
+	b.Add(2)
+	b.Close()
+
+	if got, want := rec.get(), [][]int{{1, 2}}; !equalBatches(got, want) {
+		t.Fatalf("после Close пачки = %v, ожидалось %v", got, want)
+	}
+	if err := b.Add(3); !errors.Is(err, errBatcherClosed) {
+		t.Errorf("Add после Close = %v, ожидалась errBatcherClosed", err)
+	}
+	b.Close() // Повторный Close безопасен.
+	clock.Advance(time.Hour)
+	if got := rec.get(); len(got) != 1 {
+		t.Errorf("после Close были лишние отправки: %v", got)
+	}
+}
+
+func TestBatcherConcurrentAddRealClock(t *testing.T) {
+	var mu sync.Mutex
+	total := 0
+	b := NewBatcher(7, 5*time.Millisecond, func(batch []int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(batch) > 7 {
+			t.Errorf("пачка из %d элементов превышает maxSize", len(batch))
+		}
+		total += len(batch)
+	})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				b.Add(i)
+				if i%25 == 0 {
+					time.Sleep(time.Millisecond)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	b.Close()
+
+	if total != 800 {
+		t.Errorf("отправлено %d элементов, ожидалось 800", total)
+	}
+}
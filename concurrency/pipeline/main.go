@@ -0,0 +1,98 @@
+// Package main демонстрирует обобщенные строительные блоки конвейеров на каналах.
+//
+// Tee раздваивает поток: каждый элемент входного канала доставляется в оба выходных.
+// Это удобно, когда нужно, например, параллельно с основной обработкой писать аудит.
+//
+// Batcher группирует элементы в пачки по размеру или по времени для эффективной записи в бэкенд.
+//
+// Stage — ступень конвейера с пулом воркеров; ступени соединяются в цепочку через каналы.
+//
+// WindowByCount и WindowByTime нарезают поток на окна по количеству элементов или по времени.
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Tee возвращает два канала, в каждый из которых попадают все элементы in в исходном порядке.
+// Оба выходных канала закрываются после закрытия in.
+//
+// Tee блокирующий и ничего не теряет: следующий элемент читается из in только после того,
+// как текущий принят обоими потребителями. Поэтому медленный потребитель задает темп
+// и второму — быстрый ждет, пока медленный примет элемент. Внутри текущего элемента
+// порядок отправки не фиксирован: кто первым готов принять, тот и получает первым.
+//
+// Оба выхода нужно дочитывать до конца, иначе горутина Tee (и источник) заблокируются.
+func Tee[T any](in <-chan T) (<-chan T, <-chan T) {
+	out1 := make(chan T)
+	out2 := make(chan T)
+
+	go func() {
+		defer close(out1)
+		defer close(out2)
+
+		for v := range in {
+			// Локальные копии каналов: после отправки в один из них обнуляем его,
+			// чтобы select больше его не выбирал (отправка в nil-канал никогда не готова).
+			o1, o2 := out1, out2
+			for i := 0; i < 2; i++ {
+				select {
+				case o1 <- v:
+					o1 = nil
+				case o2 <- v:
+					o2 = nil
+				}
+			}
+		}
+	}()
+
+	return out1, out2
+}
+
+func main() {
+	events := make(chan string)
+	go func() {
+		defer close(events)
+		for i := 1; i <= 3; i++ {
+			events <- fmt.Sprintf("событие #%d", i)
+		}
+	}()
+
+	processing, audit := Tee(events)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for e := range audit {
+			fmt.Println("[аудит]", e)
+		}
+	}()
+
+	for e := range processing {
+		fmt.Println("[обработка]", e)
+	}
+	wg.Wait()
+
+	batcher := NewBatcher(3, 100*time.Millisecond, func(batch []int) {
+		fmt.Println("[запись пачки]", batch)
+	})
+	for i := 1; i <= 7; i++ {
+		batcher.Add(i) // Пачки {1,2,3} и {4,5,6} уйдут по размеру.
+	}
+	time.Sleep(150 * time.Millisecond) // {7} уйдет по таймеру.
+	batcher.Close()
+
+	numbers := make(chan int)
+	go func() {
+		defer close(numbers)
+		for i := 1; i <= 5; i++ {
+			numbers <- i
+		}
+	}()
+	for window := range WindowByCount(numbers, 2) {
+		fmt.Println("[окно]", window) // [1 2], [3 4], [5]
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// generate возвращает канал с числами 0..n-1.
+func generate(n int) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 0; i < n; i++ {
+			ch <- i
+		}
+	}()
+	return ch
+}
+
+// collect вычитывает канал до закрытия.
+func collect[T any](ch <-chan T) []T {
+	var res []T
+	for v := range ch {
+		res = append(res, v)
+	}
+	return res
+}
+
+func assertSequence(t *testing.T, name string, got []int, n int) {
+	t.Helper()
+	if len(got) != n {
+		t.Fatalf("%s: получено %d элементов, ожидалось %d", name, len(got), n)
+	}
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("%s: got[%d] = %d, ожидалось %d", name, i, v, i)
+		}
+	}
+}
+
+func TestTeeDeliversEveryItemToBoth(t *testing.T) {
+	const n = 1000
+	a, b := Tee(generate(n))
+
+	var gotA, gotB []int
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); gotA = collect(a) }()
+	go func() { defer wg.Done(); gotB = collect(b) }()
+	wg.Wait()
+
+	assertSequence(t, "первый выход", gotA, n)
+	assertSequence(t, "второй выход", gotB, n)
+}
+
+func TestTeeSlowConsumerPacesTheOther(t *testing.T) {
+	const n = 5
+	const delay = 10 * time.Millisecond
+	fast, slow := Tee(generate(n))
+
+	var gotSlow []int
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for v := range slow {
+			time.Sleep(delay)
+			gotSlow = append(gotSlow, v)
+		}
+	}()
+
+	start := time.Now()
+	gotFast := collect(fast)
+	elapsed := time.Since(start)
+	wg.Wait()
+
+	// Ничего не теряется, но быстрый потребитель ждет медленного.
+	assertSequence(t, "быстрый", gotFast, n)
+	assertSequence(t, "медленный", gotSlow, n)
+	if minElapsed := (n - 1) * delay; elapsed < minElapsed {
+		t.Errorf("быстрый потребитель завершился за %v, ожидалось не меньше %v", elapsed, minElapsed)
+	}
+}
+
+func TestTeeClosesOnEmptyInput(t *testing.T) {
+	in := make(chan int)
+	close(in)
+	a, b := Tee(in)
+
+	if got := collect(a); len(got) != 0 {
+		t.Errorf("первый выход: %v", got)
+	}
+	if got := collect(b); len(got) != 0 {
+		t.Errorf("второй выход: %v", got)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import "sync"
+
+// Stage — одна ступень конвейера: workers горутин читают элементы из in,
+// обрабатывают их функцией fn и отправляют результаты в out, а ошибки — в errs.
+// Оба канала закрываются, когда in закрыт и все воркеры завершились.
+//
+// Выходные каналы буферизованы на workers элементов, чтобы кратковременные паузы
+// потребителя не останавливали воркеров. Порядок результатов не сохраняется.
+//
+// Потребитель обязан читать оба канала до закрытия (например, в разных горутинах):
+// если ошибки никто не читает, воркеры заблокируются на отправке в errs.
+// Выход одной ступени можно сразу подать на вход следующей.
+func Stage[In, Out any](in <-chan In, workers int, fn func(In) (Out, error)) (<-chan Out, <-chan error) {
+	if workers <= 0 {
+		workers = 1
+	}
+	out := make(chan Out, workers)
+	errs := make(chan error, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for v := range in {
+				res, err := fn(v)
+				if err != nil {
+					errs <- err
+					continue
+				}
+				out <- res
+			}
+		}()
+	}
+
+	// Каналы закрывает отдельная горутина: только она знает, что все воркеры завершились.
+	go func() {
+		wg.Wait()
+		close(out)
+		close(errs)
+	}()
+
+	return out, errs
+}
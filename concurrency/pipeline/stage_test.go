@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mergeErrors собирает ошибки из нескольких каналов в фоне. Результат доступен после wait().
+func mergeErrors(chans ...<-chan error) (wait func() []error) {
+	var mu sync.Mutex
+	var all []error
+	var wg sync.WaitGroup
+	for _, ch := range chans {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for err := range ch {
+				mu.Lock()
+				all = append(all, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	return func() []error {
+		wg.Wait()
+		return all
+	}
+}
+
+func TestStageChainAllItemsFlow(t *testing.T) {
+	const n = 200
+	parse, parseErrs := Stage(generate(n), 4, func(v int) (string, error) {
+		return strconv.Itoa(v), nil
+	})
+	square, squareErrs := Stage(parse, 3, func(s string) (int, error) {
+		v, err := strconv.Atoi(s)
+		return v * v, err
+	})
+	waitErrs := mergeErrors(parseErrs, squareErrs)
+
+	got := collect(square)
+	if errs := waitErrs(); len(errs) != 0 {
+		t.Fatalf("неожиданные ошибки: %v", errs)
+	}
+
+	slices.Sort(got)
+	if len(got) != n {
+		t.Fatalf("получено %d элементов, ожидалось %d", len(got), n)
+	}
+	for i, v := range got {
+		if v != i*i {
+			t.Fatalf("got[%d] = %d, ожидалось %d", i, v, i*i)
+		}
+	}
+}
+
+func TestStageSurfacesErrors(t *testing.T) {
+	errOdd := errors.New("нечетное число")
+	const n = 50
+
+	first, firstErrs := Stage(generate(n), 4, func(v int) (int, error) {
+		if v%2 == 1 {
+			return 0, fmt.Errorf("%d: %w", v, errOdd)
+		}
+		return v, nil
+	})
+	second, secondErrs := Stage(first, 2, func(v int) (int, error) {
+		if v%10 == 0 {
+			return 0, fmt.Errorf("%d делится на 10", v)
+		}
+		return v, nil
+	})
+	waitErrs := mergeErrors(firstErrs, secondErrs)
+
+	got := collect(second)
+	errs := waitErrs()
+
+	// 25 нечетных отсеяны первой ступенью, 5 кратных 10 — второй.
+	if len(got) != 20 {
+		t.Errorf("прошло %d элементов, ожидалось 20", len(got))
+	}
+	if len(errs) != 30 {
+		t.Errorf("получено %d ошибок, ожидалось 30", len(errs))
+	}
+	odd := 0
+	for _, err := range errs {
+		if errors.Is(err, errOdd) {
+			odd++
+		}
+	}
+	if odd != 25 {
+		t.Errorf("ошибок первой ступени %d, ожидалось 25", odd)
+	}
+}
+
+func TestStageUsesWorkersConcurrently(t *testing.T) {
+	const workers = 4
+	var current, maxSeen atomic.Int32
+
+	out, errs := Stage(generate(20), workers, func(v int) (int, error) {
+		n := current.Add(1)
+		defer current.Add(-1)
+		for {
+			m := maxSeen.Load()
+			if n <= m || maxSeen.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return v, nil
+	})
+	waitErrs := mergeErrors(errs)
+	collect(out)
+	waitErrs()
+
+	if got := maxSeen.Load(); got < 2 || got > workers {
+		t.Errorf("одновременно работало %d воркеров, ожидалось от 2 до %d", got, workers)
+	}
+}
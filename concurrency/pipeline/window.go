@@ -0,0 +1,79 @@
+package main
+
+import "time"
+
+// WindowByCount группирует поток в окна по n элементов. Последнее окно может быть
+// неполным: остаток отправляется при закрытии in. Выходной канал закрывается после этого.
+// Паникует, если n не положительно.
+func WindowByCount[T any](in <-chan T, n int) <-chan []T {
+	if n <= 0 {
+		panic("WindowByCount: n должно быть положительным")
+	}
+	out := make(chan []T)
+
+	go func() {
+		defer close(out)
+
+		window := make([]T, 0, n)
+		for v := range in {
+			window = append(window, v)
+			if len(window) == n {
+				out <- window
+				// Новый срез, а не window[:0]: отправленное окно принадлежит потребителю.
+				window = make([]T, 0, n)
+			}
+		}
+		if len(window) > 0 {
+			out <- window
+		}
+	}()
+
+	return out
+}
+
+// WindowByTime группирует поток в окна по времени: каждые d отправляется все, что пришло
+// с прошлого тика (окно может быть неполным по любому счету). Пустые окна не отправляются.
+// При закрытии in остаток отправляется сразу, не дожидаясь тика, и выходной канал закрывается.
+// Паникует, если d не положительно.
+//
+// В отличие от Batcher, окна выровнены по тикам, а не по первому элементу, и размер
+// окна не ограничен.
+func WindowByTime[T any](in <-chan T, d time.Duration) <-chan []T {
+	if d <= 0 {
+		panic("WindowByTime: d должно быть положительным")
+	}
+	ticker := time.NewTicker(d)
+	return windowByTicks(in, ticker.C, ticker.Stop)
+}
+
+// windowByTicks — реализация WindowByTime с внешним источником тиков; stop вызывается
+// при завершении. В тестах тики подаются вручную.
+func windowByTicks[T any](in <-chan T, ticks <-chan time.Time, stop func()) <-chan []T {
+	out := make(chan []T)
+
+	go func() {
+		defer close(out)
+		defer stop()
+
+		var window []T
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if len(window) > 0 {
+						out <- window
+					}
+					return
+				}
+				window = append(window, v)
+			case <-ticks:
+				if len(window) > 0 {
+					out <- window
+					window = nil
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// collectWindows дочитывает все окна из ch.
+func collectWindows(ch <-chan []int) [][]int {
+	var windows [][]int
+	for w := range ch {
+		windows = append(windows, w)
+	}
+	return windows
+}
+
+// source возвращает закрытый после отправки items канал.
+func source(items ...int) <-chan int {
+	ch := make(chan int, len(items))
+	for _, v := range items {
+		ch <- v
+	}
+	close(ch)
+	return ch
+}
+
+func TestWindowByCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []int
+		n     int
+		want  [][]int
+	}{
+		{"кратное количество", []int{1, 2, 3, 4, 5, 6}, 3, [][]int{{1, 2, 3}, {4, 5, 6}}},
+		{"с остатком", []int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{"меньше окна", []int{1}, 5, [][]int{{1}}},
+		{"пустой поток", nil, 3, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := collectWindows(WindowByCount(source(tt.items...), tt.n))
+			if !equalBatches(got, tt.want) {
+				t.Errorf("окна = %v, ожидалось %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// manualTicks — управляемый источник тиков для windowByTicks.
+type manualTicks struct {
+	ch      chan time.Time
+	stopped chan struct{}
+}
+
+func newManualTicks() *manualTicks {
+	return &manualTicks{ch: make(chan time.Time), stopped: make(chan struct{})}
+}
+
+// Tick доставляет тик; возвращается, когда горутина окон его приняла.
+func (m *manualTicks) Tick() { m.ch <- time.Time{} }
+
+func (m *manualTicks) Stop() { close(m.stopped) }
+
+func TestWindowByTimeEmitsOnTick(t *testing.T) {
+	in := make(chan int)
+	ticks := newManualTicks()
+	out := windowByTicks(in, ticks.ch, ticks.Stop)
+
+	// Каналы небуферизованы, и их читает одна горутина: после возврата из отправки
+	// элемент уже в окне, поэтому порядок элементов и тиков детерминирован.
+	in <- 1
+	in <- 2
+	in <- 3
+	ticks.Tick()
+	if got := <-out; !equalBatches([][]int{got}, [][]int{{1, 2, 3}}) {
+		t.Fatalf("окно = %v, ожидалось [1 2 3]", got)
+	}
+
+	// Тик без новых элементов не порождает пустое окно.
+	ticks.Tick()
+	in <- 4
+	ticks.Tick()
+	if got := <-out; !equalBatches([][]int{got}, [][]int{{4}}) {
+		t.Fatalf("окно = %v, ожидалось [4]", got)
+	}
+
+	// Остаток отправляется при закрытии входа, без тика.
+	in <- 5
+	in <- 6
+	close(in)
+	if got := collectWindows(out); !equalBatches(got, [][]int{{5, 6}}) {
+		t.Errorf("остаток = %v, ожидалось [[5 6]]", got)
+	}
+	select {
+	case <-ticks.stopped:
+	case <-time.After(time.Second):
+		t.Error("тикер не остановлен после закрытия входа")
+	}
+}
+
+func TestWindowByTimeRealTicker(t *testing.T) {
+	in := make(chan int)
+	out := WindowByTime(in, 10*time.Millisecond)
+
+	in <- 1
+	in <- 2
+	select {
+	case got := <-out:
+		if !equalBatches([][]int{got}, [][]int{{1, 2}}) {
+			t.Errorf("окно = %v, ожидалось [1 2]", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("окно не отправлено по тику")
+	}
+	close(in)
+	if got := collectWindows(out); len(got) != 0 {
+		t.Errorf("после закрытия пустого окна получено %v", got)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DropPolicy определяет, что делать с сообщением, если буфер подписчика переполнен.
+type DropPolicy int
+
+const (
+	// DropNewest пропускает новое сообщение: подписчик получит то, что уже лежит в буфере.
+	DropNewest DropPolicy = iota
+	// DropOldest вытесняет самое старое сообщение из буфера, освобождая место для нового:
+	// подписчик всегда видит последние данные.
+	DropOldest
+)
+
+// dropOldestAttempts — сколько раз DropOldest пытается освободить место, прежде чем
+// сдаться и пропустить сообщение (буфер могут одновременно заполнять другие рассылки).
+const dropOldestAttempts = 3
+
+// Broadcaster — обобщенный рассыльщик для одного топика (Fan-Out).
+//
+// Это типизированное ядро PubSubManager: без топиков и `chan any`. Подписчики
+// подключаются и отключаются в любой момент, в том числе во время рассылки.
+// Broadcast никогда не блокируется на медленном подписчике — при переполнении буфера
+// срабатывает DropPolicy.
+//
+// Рассылка выполняется под блокировкой на чтение, а закрытие каналов — под блокировкой
+// на запись, поэтому отправка в закрытый канал невозможна.
+type Broadcaster[T any] struct {
+	mu     sync.RWMutex
+	subs   map[<-chan T]chan T
+	closed bool
+
+	buffer  int
+	policy  DropPolicy
+	dropped atomic.Uint64
+}
+
+// NewBroadcaster создает рассыльщика с буфером buffer сообщений на подписчика.
+func NewBroadcaster[T any](buffer int, policy DropPolicy) *Broadcaster[T] {
+	return &Broadcaster[T]{
+		subs:   make(map[<-chan T]chan T),
+		buffer: buffer,
+		policy: policy,
+	}
+}
+
+// Subscribe регистрирует нового подписчика. После Close возвращается уже закрытый канал.
+func (b *Broadcaster[T]) Subscribe() <-chan T {
+	ch := make(chan T, b.buffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch
+	}
+	b.subs[ch] = ch
+	return ch
+}
+
+// Unsubscribe отключает подписчика и закрывает его канал.
+// Повторный вызов и вызов с неизвестным каналом ничего не делают.
+func (b *Broadcaster[T]) Unsubscribe(ch <-chan T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if sub, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(sub)
+	}
+}
+
+// Broadcast отправляет msg всем текущим подписчикам и возвращает количество тех,
+// кому сообщение доставить не удалось. После Close сообщения игнорируются.
+func (b *Broadcaster[T]) Broadcast(msg T) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	drops := 0
+	for _, sub := range b.subs {
+		if !b.send(sub, msg) {
+			drops++
+		}
+	}
+	b.dropped.Add(uint64(drops))
+	return drops
+}
+
+// send выполняет неблокирующую отправку с учетом политики переполнения.
+func (b *Broadcaster[T]) send(sub chan T, msg T) bool {
+	select {
+	case sub <- msg:
+		return true
+	default:
+	}
+	if b.policy != DropOldest {
+		return false
+	}
+
+	for range dropOldestAttempts {
+		// Вытесняем самое старое сообщение; буфер мог уже опустеть — тогда просто пробуем снова.
+		select {
+		case <-sub:
+		default:
+		}
+		select {
+		case sub <- msg:
+			return true
+		default:
+		}
+	}
+	return false
+}
+
+// Len возвращает количество активных подписчиков.
+func (b *Broadcaster[T]) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Dropped возвращает общее количество недоставленных сообщений.
+func (b *Broadcaster[T]) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+// Close отключает всех подписчиков и закрывает их каналы. Повторный вызов безопасен.
+func (b *Broadcaster[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for ch, sub := range b.subs {
+		delete(b.subs, ch)
+		close(sub)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// drain вычитывает все сообщения, уже лежащие в буфере канала.
+func drain[T any](ch <-chan T) []T {
+	var got []T
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return got
+			}
+			got = append(got, v)
+		default:
+			return got
+		}
+	}
+}
+
+func TestBroadcasterDeliversToAllSubscribers(t *testing.T) {
+	b := NewBroadcaster[int](4, DropNewest)
+	defer b.Close()
+
+	subs := []<-chan int{b.Subscribe(), b.Subscribe(), b.Subscribe()}
+	for i := 1; i <= 3; i++ {
+		if n := b.Broadcast(i); n != 0 {
+			t.Fatalf("Broadcast(%d): %d пропусков при свободных буферах", i, n)
+		}
+	}
+	for i, sub := range subs {
+		if got := drain(sub); !slices.Equal(got, []int{1, 2, 3}) {
+			t.Errorf("подписчик %d получил %v", i, got)
+		}
+	}
+}
+
+func TestBroadcasterDropPolicies(t *testing.T) {
+	tests := []struct {
+		policy DropPolicy
+		want   []int
+	}{
+		{DropNewest, []int{1, 2}},
+		{DropOldest, []int{4, 5}},
+	}
+	for _, tt := range tests {
+		b := NewBroadcaster[int](2, tt.policy)
+		sub := b.Subscribe()
+		drops := 0
+		for i := 1; i <= 5; i++ {
+			drops += b.Broadcast(i)
+		}
+
+		if got := drain(sub); !slices.Equal(got, tt.want) {
+			t.Errorf("политика %d: получено %v, ожидалось %v", tt.policy, got, tt.want)
+		}
+		if tt.policy == DropNewest && (drops != 3 || b.Dropped() != 3) {
+			t.Errorf("DropNewest: пропусков %d (Dropped %d), ожидалось 3", drops, b.Dropped())
+		}
+		if tt.policy == DropOldest && drops != 0 {
+			t.Errorf("DropOldest: новые сообщения не должны пропускаться, пропусков %d", drops)
+		}
+		b.Close()
+	}
+}
+
+func TestBroadcasterUnsubscribeClosesChannel(t *testing.T) {
+	b := NewBroadcaster[string](1, DropNewest)
+	sub := b.Subscribe()
+	other := b.Subscribe()
+
+	b.Unsubscribe(sub)
+	b.Unsubscribe(sub) // Повторная отписка не должна паниковать.
+	if _, ok := <-sub; ok {
+		t.Error("канал отписанного подписчика не закрыт")
+	}
+	if b.Len() != 1 {
+		t.Errorf("Len = %d, ожидался 1", b.Len())
+	}
+
+	b.Broadcast("msg")
+	if got := drain(other); !slices.Equal(got, []string{"msg"}) {
+		t.Errorf("оставшийся подписчик получил %v", got)
+	}
+
+	b.Close()
+	b.Close()
+	if _, ok := <-b.Subscribe(); ok {
+		t.Error("Subscribe после Close должен возвращать закрытый канал")
+	}
+	b.Broadcast("после Close") // Не должно паниковать.
+}
+
+func TestBroadcasterConcurrentSubscribeUnsubscribe(t *testing.T) {
+	for _, policy := range []DropPolicy{DropNewest, DropOldest} {
+		b := NewBroadcaster[int](2, policy)
+
+		var wg sync.WaitGroup
+		stop := make(chan struct{})
+
+		// Издатели непрерывно рассылают сообщения.
+		for p := 0; p < 4; p++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; ; i++ {
+					select {
+					case <-stop:
+						return
+					default:
+						b.Broadcast(i)
+						runtime.Gosched() // Даем подписчикам шанс захватить блокировку на запись.
+					}
+				}
+			}()
+		}
+
+		// Подписчики подключаются, читают немного и отключаются, пока идет рассылка.
+		var subWG sync.WaitGroup
+		for s := 0; s < 8; s++ {
+			subWG.Add(1)
+			go func() {
+				defer subWG.Done()
+				for range 200 {
+					ch := b.Subscribe()
+					drain(ch)
+					b.Unsubscribe(ch)
+					// После отписки канал закрыт: чтение завершается, а не висит.
+					for range ch {
+					}
+				}
+			}()
+		}
+		subWG.Wait()
+		close(stop)
+		wg.Wait()
+
+		if b.Len() != 0 {
+			t.Errorf("политика %d: Len = %d после отписки всех", policy, b.Len())
+		}
+		b.Close()
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock — управляемый источник времени для тестов.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestManager() (*PubSubManager, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1_000_000, 0)}
+	m := NewPubSubManager()
+	m.now = clock.Now
+	return m, clock
+}
+
+func TestStaleSubscribersReportsStalledSubscriber(t *testing.T) {
+	m, clock := newTestManager()
+	defer m.Close()
+
+	stalled := m.Subscribe("news")
+	active := m.Subscribe("news")
+	// Переполняем буфер зависшего подписчика; активный читает все.
+	for i := 0; i <= cap(stalled); i++ {
+		m.PublishTimeout("news", i, time.Millisecond)
+		<-active
+	}
+
+	const threshold = time.Minute
+	if got := m.StaleSubscribers(threshold); len(got) != 0 {
+		t.Fatalf("до истечения порога StaleSubscribers = %v, ожидалось пусто", got)
+	}
+
+	clock.Advance(threshold)
+	m.PublishTimeout("news", "после паузы", time.Millisecond)
+	<-active
+
+	got := m.StaleSubscribers(threshold)
+	if len(got) != 1 || got["news"] != 1 {
+		t.Errorf("StaleSubscribers = %v, ожидалось map[news:1]", got)
+	}
+}
+
+func TestStaleSubscribersIgnoresQuietTopic(t *testing.T) {
+	m, clock := newTestManager()
+	defer m.Close()
+
+	m.Subscribe("quiet")
+	clock.Advance(time.Hour)
+
+	if got := m.StaleSubscribers(time.Minute); len(got) != 0 {
+		t.Errorf("подписчик без сообщений не должен считаться зависшим: %v", got)
+	}
+}
+
+func TestStaleSubscriberRecoversAfterDelivery(t *testing.T) {
+	m, clock := newTestManager()
+	defer m.Close()
+
+	sub := m.Subscribe("t")
+	for i := 0; i <= cap(sub); i++ {
+		m.PublishTimeout("t", i, time.Millisecond)
+	}
+	clock.Advance(time.Minute)
+	if got := m.StaleSubscribers(time.Minute); got["t"] != 1 {
+		t.Fatalf("StaleSubscribers = %v, ожидалось map[t:1]", got)
+	}
+
+	// Подписчик "ожил": освободил буфер и принял новое сообщение.
+	for len(sub) > 0 {
+		<-sub
+	}
+	m.PublishTimeout("t", "снова на связи", time.Second)
+	if got := m.StaleSubscribers(time.Minute); len(got) != 0 {
+		t.Errorf("после успешной доставки StaleSubscribers = %v, ожидалось пусто", got)
+	}
+}
+
+func TestUnsubscribeStale(t *testing.T) {
+	m, clock := newTestManager()
+	defer m.Close()
+
+	stalled := m.Subscribe("news")
+	active := m.Subscribe("news")
+	for i := 0; i <= cap(stalled); i++ {
+		m.PublishTimeout("news", i, time.Millisecond)
+		<-active
+	}
+	clock.Advance(time.Minute)
+
+	removed := m.UnsubscribeStale(time.Minute)
+	if removed["news"] != 1 {
+		t.Fatalf("UnsubscribeStale = %v, ожидалось map[news:1]", removed)
+	}
+
+	// Канал отписанного подписчика закрыт (после вычитывания буфера).
+	for range stalled {
+	}
+
+	if n := m.PublishTimeout("news", "только активному", time.Second); n != 0 {
+		t.Errorf("таймаутов = %d после отписки зависшего, ожидалось 0", n)
+	}
+	if msg := <-active; msg != "только активному" {
+		t.Errorf("активный подписчик получил %v", msg)
+	}
+}
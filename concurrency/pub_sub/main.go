@@ -6,27 +6,91 @@
 package main
 
 import (
+	"context"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/andrewhigh08/exp/internal/chanutil"
+	"github.com/andrewhigh08/exp/internal/lifecycle"
 )
 
+// subscriber — канал подписчика и сведения о его "живости".
+// Время хранится в UnixNano в атомиках, так как обновляется из горутин рассылки
+// под блокировкой на чтение.
+type subscriber struct {
+	ch chan any
+	// closer закрывает ch не более одного раза, даже если подписчика отписывают
+	// с нескольких путей (Unsubscribe, UnsubscribeStale, Close).
+	closer *chanutil.CloseOnce[any]
+	// lastDelivery — время последней успешной доставки (или подписки, если доставок еще не было).
+	lastDelivery atomic.Int64
+	// lastDrop — время последнего пропущенного сообщения (0 — пропусков не было).
+	lastDrop atomic.Int64
+	// ordered — упорядоченная доставка без пропусков (nil для обычных подписчиков).
+	ordered *orderedDelivery
+	// closed — подписчик отписан; защищено PubSubManager.mu (запись — под Lock).
+	closed bool
+}
+
+// close прекращает доставку подписчику и закрывает его канал.
+// Канал упорядоченного подписчика закрывает горутина доставки. Повторный вызов безопасен.
+func (s *subscriber) close() {
+	s.closed = true
+	if s.ordered != nil {
+		s.ordered.stop()
+		return
+	}
+	s.closer.Close()
+}
+
+// newSubscriber создает подписчика с буферизованным каналом; ordered == nil — обычный подписчик.
+// Буферизация помогает справиться с кратковременными пиками сообщений.
+func newSubscriber(ordered *orderedDelivery) *subscriber {
+	ch := make(chan any, 10)
+	return &subscriber{ch: ch, closer: chanutil.NewCloseOnce(ch), ordered: ordered}
+}
+
+// stale сообщает, что подписчик пропустил сообщение не раньше последней успешной доставки
+// и не принимал сообщения дольше threshold.
+func (s *subscriber) stale(now time.Time, threshold time.Duration) bool {
+	last := s.lastDelivery.Load()
+	return s.lastDrop.Load() >= last && now.Sub(time.Unix(0, last)) >= threshold
+}
+
 // PubSubManager управляет подписками и рассылкой сообщений.
 type PubSubManager struct {
 	// mu защищает доступ к `topics`. RWMutex выбран потому, что публикаций
 	// (чтение списка подписчиков) обычно гораздо больше, чем изменений в подписках.
 	mu sync.RWMutex
-	// topics хранит для каждого ID топика срез каналов его подписчиков.
-	topics map[string][]chan any
+	// topics хранит для каждого ID топика срез его подписчиков.
+	topics map[string][]*subscriber
+	// dropped считает сообщения, которые не удалось доставить подписчикам.
+	dropped atomic.Uint64
+	// now — источник времени; подменяется в тестах.
+	now func() time.Time
 }
 
 // NewPubSubManager создает новый экземпляр менеджера.
 func NewPubSubManager() *PubSubManager {
 	return &PubSubManager{
-		topics: make(map[string][]chan any),
+		topics: make(map[string][]*subscriber),
+		now:    time.Now,
 	}
 }
 
+// delivered отмечает успешную доставку сообщения подписчику.
+func (p *PubSubManager) delivered(sub *subscriber) {
+	sub.lastDelivery.Store(p.now().UnixNano())
+}
+
+// drop учитывает пропущенное для подписчика сообщение.
+func (p *PubSubManager) drop(sub *subscriber) {
+	p.dropped.Add(1)
+	sub.lastDrop.Store(p.now().UnixNano())
+}
+
 // Publish отправляет сообщение всем подписчикам указанного топика.
 // Рассылка происходит по принципу Fan-Out.
 func (p *PubSubManager) Publish(topicID string, msg any) {
@@ -37,39 +101,195 @@ func (p *PubSubManager) Publish(topicID string, msg any) {
 	if subscribers, found := p.topics[topicID]; found {
 		// Клонируем срез подписчиков, чтобы не блокировать мьютекс надолго.
 		// Это быстрая операция, после которой можно отпустить мьютекс.
-		subsCopy := make([]chan any, len(subscribers))
+		subsCopy := make([]*subscriber, len(subscribers))
 		copy(subsCopy, subscribers)
+		// Номера для упорядоченных подписчиков выдаются синхронно, в порядке вызовов Publish.
+		seqs := assignSeqs(subsCopy)
 
 		go func() {
-			// Отправляем сообщение всем подписчикам в отдельной горутине.
-			for _, subChan := range subsCopy {
-				// Используем неблокирующую отправку, чтобы медленный или неактивный
-				// подписчик не мог заблокировать рассылку для остальных.
-				select {
-				case subChan <- msg:
-				default:
-					// Если канал подписчика переполнен или заблокирован,
-					// мы просто пропускаем отправку ему этого сообщения.
-					log.Printf("Канал подписчика для топика '%s' заблокирован. Сообщение пропущено.", topicID)
-				}
-			}
+			// Отправки неблокирующие, поэтому блокировку на чтение можно держать всю рассылку.
+			// Она не дает Unsubscribe закрыть канал во время отправки, а отписавшихся
+			// после копирования среза подписчиков пропускаем по флагу closed.
+			p.mu.RLock()
+			defer p.mu.RUnlock()
+
+			p.fanOut(topicID, subsCopy, seqs, msg)
 		}()
 	}
 }
 
+// publishSync рассылает сообщение синхронно, в вызывающей горутине. Последовательные
+// вызовы из одной горутины доставляются обычным подписчикам в порядке вызовов.
+func (p *PubSubManager) publishSync(topicID string, msg any) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	subscribers := p.topics[topicID]
+	p.fanOut(topicID, subscribers, assignSeqs(subscribers), msg)
+}
+
+// fanOut неблокирующе отправляет сообщение подписчикам; seqs — номера от assignSeqs.
+// Вызывается под блокировкой p.mu на чтение.
+func (p *PubSubManager) fanOut(topicID string, subscribers []*subscriber, seqs []uint64, msg any) {
+	for i, sub := range subscribers {
+		if sub.closed {
+			continue
+		}
+		if sub.ordered != nil {
+			sub.ordered.push(seqs[i], msg)
+			continue
+		}
+		// Используем неблокирующую отправку, чтобы медленный или неактивный
+		// подписчик не мог заблокировать рассылку для остальных.
+		select {
+		case sub.ch <- msg:
+			p.delivered(sub)
+		default:
+			// Если канал подписчика переполнен или заблокирован,
+			// мы просто пропускаем отправку ему этого сообщения.
+			p.drop(sub)
+			log.Printf("Канал подписчика для топика '%s' заблокирован. Сообщение пропущено.", topicID)
+		}
+	}
+}
+
+// PublishTimeout — компромисс между неблокирующим Publish (мгновенный пропуск) и полностью
+// блокирующей отправкой. Каждому подписчику дается до d на прием сообщения; по истечении
+// времени сообщение для него пропускается и учитывается в Dropped.
+//
+// Отправка каждому подписчику идет в своей горутине со своим таймером, поэтому метод
+// возвращается не позже чем через d, сколько бы медленных подписчиков ни было.
+// Возвращает количество подписчиков, которым сообщение не было доставлено.
+//
+// На время рассылки удерживается блокировка на чтение: это гарантирует, что Unsubscribe
+// не закроет канал, пока в него идет отправка, но задерживает подписку/отписку до d.
+func (p *PubSubManager) PublishTimeout(topicID string, msg any, d time.Duration) int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	subscribers := p.topics[topicID]
+	seqs := assignSeqs(subscribers)
+	var timeouts atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(len(subscribers))
+	for i, sub := range subscribers {
+		if sub.ordered != nil {
+			// Упорядоченный подписчик не теряет сообщений, таймаут к нему не применяется.
+			sub.ordered.push(seqs[i], msg)
+			wg.Done()
+			continue
+		}
+		go func(sub *subscriber) {
+			defer wg.Done()
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+
+			select {
+			case sub.ch <- msg:
+				p.delivered(sub)
+			case <-timer.C:
+				timeouts.Add(1)
+				p.drop(sub)
+				log.Printf("Подписчик топика '%s' не принял сообщение за %s. Сообщение пропущено.", topicID, d)
+			}
+		}(sub)
+	}
+	wg.Wait()
+
+	return int(timeouts.Load())
+}
+
+// Dropped возвращает общее количество сообщений, пропущенных из-за медленных подписчиков.
+func (p *PubSubManager) Dropped() uint64 {
+	return p.dropped.Load()
+}
+
+// StaleSubscribers возвращает для каждого топика количество "зависших" подписчиков:
+// тех, кто пропустил сообщение и не принимал сообщений дольше threshold.
+// Подписчик на тихом топике, которому просто нечего доставлять, зависшим не считается.
+// Топики без зависших подписчиков в результат не попадают.
+func (p *PubSubManager) StaleSubscribers(threshold time.Duration) map[string]int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	now := p.now()
+	stale := make(map[string]int)
+	for topicID, subscribers := range p.topics {
+		for _, sub := range subscribers {
+			if sub.stale(now, threshold) {
+				stale[topicID]++
+			}
+		}
+	}
+	return stale
+}
+
+// UnsubscribeStale отписывает зависших подписчиков (см. StaleSubscribers) и закрывает их каналы.
+// Возвращает количество отписанных подписчиков по топикам.
+func (p *PubSubManager) UnsubscribeStale(threshold time.Duration) map[string]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	removed := make(map[string]int)
+	for topicID, subscribers := range p.topics {
+		alive := make([]*subscriber, 0, len(subscribers))
+		for _, sub := range subscribers {
+			if sub.stale(now, threshold) {
+				sub.close()
+				removed[topicID]++
+				continue
+			}
+			alive = append(alive, sub)
+		}
+		p.topics[topicID] = alive
+	}
+	return removed
+}
+
 // Subscribe подписывает нового клиента на топик и возвращает канал для получения сообщений.
 func (p *PubSubManager) Subscribe(topicID string) chan any {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	// Создаем канал для нового подписчика.
-	// Буферизация помогает справиться с кратковременными пиками сообщений.
-	ch := make(chan any, 10)
+	sub := newSubscriber(nil)
+	// Отсчет "живости" начинается с момента подписки.
+	p.delivered(sub)
 
-	// Добавляем канал в список подписчиков топика.
-	p.topics[topicID] = append(p.topics[topicID], ch)
+	// Добавляем подписчика в список подписчиков топика.
+	p.topics[topicID] = append(p.topics[topicID], sub)
 
-	return ch
+	return sub.ch
+}
+
+// SubscribeOrdered подписывает клиента с гарантией упорядоченной доставки: сообщения
+// приходят строго в порядке вызовов Publish/PublishTimeout, без пропусков и дубликатов,
+// даже при асинхронной рассылке. Такой подписчик никогда не теряет сообщения из-за
+// переполнения буфера — они накапливаются в памяти до прочтения (см. orderedDelivery).
+// Отписка — обычным Unsubscribe.
+func (p *PubSubManager) SubscribeOrdered(topicID string) chan any {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sub := newSubscriber(newOrderedDelivery())
+	p.delivered(sub)
+	go sub.ordered.run(sub.closer, func() { p.delivered(sub) })
+
+	p.topics[topicID] = append(p.topics[topicID], sub)
+	return sub.ch
+}
+
+// assignSeqs выдает номера очередному сообщению для всех упорядоченных подписчиков.
+// seqs[i] соответствует subscribers[i]; для обычных подписчиков значение не используется.
+func assignSeqs(subscribers []*subscriber) []uint64 {
+	seqs := make([]uint64, len(subscribers))
+	for i, sub := range subscribers {
+		if sub.ordered != nil {
+			seqs[i] = sub.ordered.assign()
+		}
+	}
+	return seqs
 }
 
 // Unsubscribe отписывает клиента от топика.
@@ -80,16 +300,20 @@ func (p *PubSubManager) Unsubscribe(topicID string, subChan chan any) {
 
 	if subscribers, found := p.topics[topicID]; found {
 		// Создаем новый срез, исключая из него отписавшийся канал.
-		newSubscribers := make([]chan any, 0, len(subscribers)-1)
+		newSubscribers := make([]*subscriber, 0, len(subscribers))
 		for _, sub := range subscribers {
-			if sub != subChan {
+			if sub.ch != subChan {
 				newSubscribers = append(newSubscribers, sub)
 			}
 		}
 		// Обновляем список подписчиков.
 		p.topics[topicID] = newSubscribers
 		// Закрываем канал, чтобы потребитель знал, что подписка прекращена.
-		close(subChan)
+		for _, sub := range subscribers {
+			if sub.ch == subChan {
+				sub.close()
+			}
+		}
 	}
 }
 
@@ -99,8 +323,8 @@ func (p *PubSubManager) Close() {
 	defer p.mu.Unlock()
 
 	for topicID, subscribers := range p.topics {
-		for _, subChan := range subscribers {
-			close(subChan)
+		for _, sub := range subscribers {
+			sub.close()
 		}
 		// Очищаем карту топиков.
 		delete(p.topics, topicID)
@@ -108,8 +332,20 @@ func (p *PubSubManager) Close() {
 }
 
 func main() {
+	// Все компоненты регистрируют хуки завершения в одной группе и
+	// останавливаются в обратном порядке при выходе из main.
+	shutdown := lifecycle.NewShutdownGroup(lifecycle.ReverseOrder)
+	defer func() {
+		if err := shutdown.Shutdown(context.Background()); err != nil {
+			log.Printf("Ошибки при завершении: %v", err)
+		}
+	}()
+
 	m := NewPubSubManager()
-	defer m.Close() // Гарантируем корректное завершение работы.
+	shutdown.OnShutdown(func(context.Context) error {
+		m.Close()
+		return nil
+	})
 
 	// Подписчик 1
 	sub1Chan := m.Subscribe("news")
@@ -134,6 +370,8 @@ func main() {
 	m.Publish("news", "Привет, мир!")
 	m.Publish("news", "Вторая новость")
 	m.Publish("other_topic", "Это сообщение никто не получит")
+	// Медленному подписчику даем немного времени вместо мгновенного пропуска.
+	m.PublishTimeout("news", "Новость с таймаутом доставки", 100*time.Millisecond)
 
 	time.Sleep(1 * time.Second)
 
@@ -145,5 +383,52 @@ func main() {
 	m.Publish("news", "Третья новость для оставшихся")
 
 	time.Sleep(2 * time.Second)
+	log.Printf("Пропущено сообщений: %d", m.Dropped())
+	log.Printf("Зависшие подписчики (порог 1s): %v", m.StaleSubscribers(time.Second))
+
+	// Упорядоченный подписчик получает все сообщения строго в порядке публикации,
+	// хотя Publish рассылает их асинхронно.
+	audit := m.SubscribeOrdered("audit")
+	for i := 1; i <= 3; i++ {
+		m.Publish("audit", i)
+	}
+	log.Printf("Аудит по порядку: %v, %v, %v", <-audit, <-audit, <-audit)
+
+	// Очередь публикации: издатель ждет только места в очереди, а рассылка идет по порядку.
+	queue := NewPublishQueue(m, 16)
+	shutdown.OnShutdown(func(context.Context) error {
+		queue.Close()
+		return nil
+	})
+	for i := 1; i <= 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		if err := queue.Publish(ctx, "audit", i*10); err != nil {
+			log.Printf("Публикация в очередь не удалась: %v", err)
+		}
+		cancel()
+	}
+	log.Printf("Аудит из очереди: %v, %v, %v", <-audit, <-audit, <-audit)
+
+	// Дедупликация повторных доставок: ID сообщения помнится в скользящем окне.
+	recent := NewRecentSet[string](1000, time.Minute)
+	for _, id := range []string{"msg-1", "msg-2", "msg-1"} {
+		if recent.Seen(id) {
+			log.Printf("Сообщение %s уже обработано, пропускаем повтор.", id)
+			continue
+		}
+		log.Printf("Обрабатываем сообщение %s.", id)
+	}
+
+	// Типизированный рассыльщик для одного топика: медленный подписчик видит только свежие данные.
+	prices := NewBroadcaster[float64](1, DropOldest)
+	shutdown.OnShutdown(func(context.Context) error {
+		prices.Close()
+		return nil
+	})
+	ticker := prices.Subscribe()
+	for _, price := range []float64{100.5, 101.2, 99.8} {
+		prices.Broadcast(price)
+	}
+	log.Printf("Последняя цена у медленного подписчика: %v", <-ticker)
 	log.Println("Завершение работы main.")
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPublishTimeoutDropsForSlowSubscriber(t *testing.T) {
+	m := NewPubSubManager()
+	defer m.Close()
+
+	slow := m.Subscribe("news")
+	fast := m.Subscribe("news")
+
+	// Заполняем буфер медленного подписчика, никто его не читает.
+	for i := 0; i < cap(slow); i++ {
+		if n := m.PublishTimeout("news", i, time.Second); n != 0 {
+			t.Fatalf("сообщение %d: %d таймаутов при свободном буфере", i, n)
+		}
+		<-fast
+	}
+
+	const timeout = 50 * time.Millisecond
+	start := time.Now()
+	timeouts := m.PublishTimeout("news", "overflow", timeout)
+	elapsed := time.Since(start)
+
+	if timeouts != 1 {
+		t.Errorf("timeouts = %d, ожидался 1", timeouts)
+	}
+	if m.Dropped() != 1 {
+		t.Errorf("Dropped = %d, ожидался 1", m.Dropped())
+	}
+	if elapsed < timeout || elapsed > timeout+time.Second {
+		t.Errorf("PublishTimeout вернулся через %v, ожидалось около %v", elapsed, timeout)
+	}
+	// Быстрый подписчик все равно получил сообщение.
+	select {
+	case msg := <-fast:
+		if msg != "overflow" {
+			t.Errorf("быстрый подписчик получил %v", msg)
+		}
+	default:
+		t.Error("быстрый подписчик не получил сообщение")
+	}
+}
+
+func TestPublishTimeoutBoundedByTimeoutNotSubscriberCount(t *testing.T) {
+	m := NewPubSubManager()
+	defer m.Close()
+
+	// Несколько заблокированных подписчиков: таймеры идут параллельно, а не по очереди.
+	for i := 0; i < 5; i++ {
+		sub := m.Subscribe("t")
+		for j := 0; j < cap(sub); j++ {
+			sub <- j
+		}
+	}
+
+	const timeout = 50 * time.Millisecond
+	start := time.Now()
+	if n := m.PublishTimeout("t", "msg", timeout); n != 5 {
+		t.Errorf("timeouts = %d, ожидалось 5", n)
+	}
+	if elapsed := time.Since(start); elapsed > 4*timeout {
+		t.Errorf("рассылка заняла %v — таймауты суммируются вместо параллельного ожидания", elapsed)
+	}
+}
+
+func TestConcurrentUnsubscribeAndCloseDoNotPanic(t *testing.T) {
+	m := NewPubSubManager()
+	subs := []chan any{m.Subscribe("t"), m.Subscribe("t"), m.SubscribeOrdered("t")}
+
+	// Отписка одного и того же канала и завершение менеджера конкурируют между собой:
+	// каждый канал должен быть закрыт ровно один раз.
+	var wg sync.WaitGroup
+	for range 10 {
+		for _, sub := range subs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				m.Unsubscribe("t", sub)
+			}()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.Close()
+		}()
+	}
+	wg.Wait()
+
+	for i, sub := range subs {
+		select {
+		case _, ok := <-sub:
+			if ok {
+				t.Errorf("подписчик %d: канал не закрыт", i)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("подписчик %d: канал не закрыт за секунду", i)
+		}
+	}
+}
+
+func TestUnsubscribeLastSubscriberTwice(t *testing.T) {
+	m := NewPubSubManager()
+	defer m.Close()
+
+	sub := m.Subscribe("t")
+	m.Unsubscribe("t", sub)
+	// Повторная отписка от топика без подписчиков не должна паниковать.
+	m.Unsubscribe("t", sub)
+
+	if _, ok := <-sub; ok {
+		t.Error("канал отписавшегося подписчика не закрыт")
+	}
+}
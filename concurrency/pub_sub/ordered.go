@@ -0,0 +1,95 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/andrewhigh08/exp/internal/chanutil"
+)
+
+// orderedDelivery — упорядоченная доставка для подписчика, созданного SubscribeOrdered.
+//
+// Publish рассылает сообщения асинхронно (своя горутина на каждый вызов), поэтому
+// сообщения двух последовательных Publish могут прийти в любом порядке. Чтобы этого
+// избежать, Publish синхронно присваивает сообщению номер (seq) в порядке публикации,
+// а доставка идет через буфер переупорядочивания: пришедшее раньше времени сообщение
+// ждет, пока не будут выданы все предыдущие номера.
+//
+// Сообщения не пропускаются и не дублируются: буфер не ограничен, а отдельная горутина
+// передает их в канал подписчика блокирующей отправкой. Медленный упорядоченный
+// подписчик поэтому накапливает сообщения в памяти, а не теряет их.
+type orderedDelivery struct {
+	mu      sync.Mutex
+	nextSeq uint64         // Номер для следующего опубликованного сообщения.
+	next    uint64         // Номер следующего сообщения к выдаче подписчику.
+	pending map[uint64]any // Сообщения, пришедшие раньше своей очереди.
+
+	notify chan struct{} // Сигнал горутине доставки о новых сообщениях.
+	done   chan struct{} // Закрывается при отписке.
+	once   sync.Once
+}
+
+func newOrderedDelivery() *orderedDelivery {
+	return &orderedDelivery{
+		pending: make(map[uint64]any),
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// assign выдает номер очередному сообщению. Вызывается синхронно в Publish,
+// поэтому номера соответствуют порядку публикации.
+func (o *orderedDelivery) assign() uint64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	seq := o.nextSeq
+	o.nextSeq++
+	return seq
+}
+
+// push кладет сообщение с номером seq в буфер переупорядочивания.
+func (o *orderedDelivery) push(seq uint64, msg any) {
+	o.mu.Lock()
+	o.pending[seq] = msg
+	o.mu.Unlock()
+
+	select {
+	case o.notify <- struct{}{}:
+	default: // Сигнал уже ожидает обработки.
+	}
+}
+
+// run выдает сообщения в канал closer строго по порядку номеров и закрывает его после stop.
+// Только эта горутина пишет в канал, поэтому отправка в закрытый канал невозможна.
+func (o *orderedDelivery) run(closer *chanutil.CloseOnce[any], delivered func()) {
+	defer closer.Close()
+	out := closer.C()
+	for {
+		o.mu.Lock()
+		msg, ok := o.pending[o.next]
+		if ok {
+			delete(o.pending, o.next)
+			o.next++
+		}
+		o.mu.Unlock()
+
+		if !ok {
+			select {
+			case <-o.notify:
+				continue
+			case <-o.done:
+				return
+			}
+		}
+		select {
+		case out <- msg:
+			delivered()
+		case <-o.done:
+			return
+		}
+	}
+}
+
+// stop останавливает доставку; недоставленные сообщения отбрасываются.
+func (o *orderedDelivery) stop() {
+	o.once.Do(func() { close(o.done) })
+}
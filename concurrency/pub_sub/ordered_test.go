@@ -0,0 +1,114 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// receive читает n сообщений из ch, падая по таймауту.
+func receive(t *testing.T, ch <-chan any, n int) []any {
+	t.Helper()
+	got := make([]any, 0, n)
+	for len(got) < n {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				t.Fatalf("канал закрыт после %d сообщений из %d", len(got), n)
+			}
+			got = append(got, msg)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("получено %d сообщений из %d", len(got), n)
+		}
+	}
+	return got
+}
+
+func TestSubscribeOrderedAsyncPublish(t *testing.T) {
+	m := NewPubSubManager()
+	defer m.Close()
+
+	ordered := m.SubscribeOrdered("events")
+
+	// Асинхронный Publish запускает горутину на каждое сообщение — без упорядочивания
+	// подписчик видел бы их в произвольном порядке и терял бы при переполнении буфера.
+	const n = 500
+	for i := 0; i < n; i++ {
+		m.Publish("events", i)
+	}
+
+	for i, msg := range receive(t, ordered, n) {
+		if msg != i {
+			t.Fatalf("сообщение %d: получено %v — нарушен порядок, пропуск или дубликат", i, msg)
+		}
+	}
+	select {
+	case msg := <-ordered:
+		t.Errorf("лишнее сообщение: %v", msg)
+	case <-time.After(20 * time.Millisecond):
+	}
+	if m.Dropped() != 0 {
+		t.Errorf("Dropped = %d: упорядоченный подписчик не должен терять сообщения", m.Dropped())
+	}
+}
+
+func TestSubscribeOrderedMixedWithPublishTimeout(t *testing.T) {
+	m := NewPubSubManager()
+	defer m.Close()
+
+	ordered := m.SubscribeOrdered("t")
+	plain := m.Subscribe("t")
+
+	// Обычный подписчик читает в фоне, чтобы PublishTimeout не ждал таймаутов.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range plain {
+		}
+	}()
+
+	const n = 100
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			m.Publish("t", i)
+		} else {
+			m.PublishTimeout("t", i, time.Second)
+		}
+	}
+
+	for i, msg := range receive(t, ordered, n) {
+		if msg != i {
+			t.Fatalf("сообщение %d: получено %v", i, msg)
+		}
+	}
+
+	m.Unsubscribe("t", plain)
+	wg.Wait()
+}
+
+func TestSubscribeOrderedUnsubscribeClosesChannel(t *testing.T) {
+	m := NewPubSubManager()
+	defer m.Close()
+
+	ordered := m.SubscribeOrdered("t")
+	m.Publish("t", "first")
+	if got := receive(t, ordered, 1); got[0] != "first" {
+		t.Fatalf("получено %v", got[0])
+	}
+
+	m.Unsubscribe("t", ordered)
+	m.Publish("t", "after") // Не должно паниковать.
+
+	select {
+	case _, ok := <-ordered:
+		if ok {
+			// В буфере канала могло остаться сообщение; после него канал должен закрыться.
+			if _, ok := <-ordered; ok {
+				t.Error("канал не закрыт после отписки")
+			}
+		}
+	case <-time.After(time.Second):
+		t.Error("канал не закрыт после отписки")
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrQueueClosed возвращается PublishQueue.Publish после Close.
+var ErrQueueClosed = errors.New("pubsub: очередь публикации закрыта")
+
+// queuedMessage — сообщение, ожидающее рассылки.
+type queuedMessage struct {
+	topicID string
+	msg     any
+}
+
+// PublishQueue развязывает издателей и рассылку: Publish кладет сообщение в ограниченную
+// очередь, а единственная горутина-диспетчер рассылает сообщения подписчикам менеджера.
+//
+// Очередь сглаживает всплески публикаций: издатель ждет только места в очереди, а не
+// рассылки. Поскольку диспетчер один и рассылает синхронно, подписчики получают сообщения
+// в порядке постановки в очередь (в отличие от PubSubManager.Publish, который запускает
+// горутину на каждое сообщение). Медленные подписчики по-прежнему теряют сообщения при
+// переполнении своего буфера (см. Dropped).
+type PublishQueue struct {
+	manager *PubSubManager
+	queue   chan queuedMessage
+	done    chan struct{} // Закрывается, когда диспетчер разослал все и завершился.
+
+	// mu защищает closed и не дает Close закрыть queue, пока в нее идет отправка.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewPublishQueue создает очередь на size сообщений для менеджера m и запускает диспетчер.
+func NewPublishQueue(m *PubSubManager, size int) *PublishQueue {
+	q := &PublishQueue{
+		manager: m,
+		queue:   make(chan queuedMessage, size),
+		done:    make(chan struct{}),
+	}
+	go q.dispatch()
+	return q
+}
+
+// dispatch рассылает сообщения из очереди, пока она не будет закрыта и вычитана.
+func (q *PublishQueue) dispatch() {
+	defer close(q.done)
+	for m := range q.queue {
+		q.manager.publishSync(m.topicID, m.msg)
+	}
+}
+
+// Publish ставит сообщение в очередь. Если очередь заполнена, ждет освобождения места
+// до отмены ctx и тогда возвращает ошибку, оборачивающую ctx.Err(). После Close
+// возвращает ErrQueueClosed.
+func (q *PublishQueue) Publish(ctx context.Context, topicID string, msg any) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+	select {
+	case q.queue <- queuedMessage{topicID: topicID, msg: msg}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("очередь публикации заполнена: %w", ctx.Err())
+	}
+}
+
+// Close перестает принимать сообщения и дожидается, пока диспетчер разошлет все,
+// что уже стоит в очереди. Менеджер при этом не закрывается. Повторный вызов безопасен.
+func (q *PublishQueue) Close() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.queue)
+	}
+	q.mu.Unlock()
+
+	<-q.done
+}
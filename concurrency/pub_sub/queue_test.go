@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPublishQueuePreservesOrderUnderBurst(t *testing.T) {
+	m := NewPubSubManager()
+	defer m.Close()
+	q := NewPublishQueue(m, 8)
+	defer q.Close()
+
+	sub := m.Subscribe("events")
+	// Читаем параллельно с публикацией: буфер подписчика меньше всплеска.
+	const n = 200
+	gotCh := make(chan []any, 1)
+	go func() { gotCh <- receive(t, sub, n) }()
+
+	for i := 0; i < n; i++ {
+		if err := q.Publish(context.Background(), "events", i); err != nil {
+			t.Fatalf("Publish(%d): %v", i, err)
+		}
+		// Даем подписчику успевать за диспетчером, чтобы сообщения не пропускались.
+		if i%5 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	got := <-gotCh
+	if m.Dropped() != 0 {
+		t.Fatalf("пропущено %d сообщений", m.Dropped())
+	}
+	for i, msg := range got {
+		if msg != i {
+			t.Fatalf("сообщение %d: получено %v — порядок нарушен", i, msg)
+		}
+	}
+}
+
+func TestPublishQueueFullReturnsOnCanceledContext(t *testing.T) {
+	m := NewPubSubManager()
+	defer m.Close()
+	// Диспетчер не запускаем, чтобы очередь гарантированно осталась заполненной.
+	q := &PublishQueue{manager: m, queue: make(chan queuedMessage, 1), done: make(chan struct{})}
+	if err := q.Publish(context.Background(), "t", 1); err != nil {
+		t.Fatalf("Publish в пустую очередь: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := q.Publish(ctx, "t", 2)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, ожидалось %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Publish вернулся через %v", elapsed)
+	}
+}
+
+func TestPublishQueueCloseDrains(t *testing.T) {
+	m := NewPubSubManager()
+	defer m.Close()
+	sub := m.SubscribeOrdered("t")
+	q := NewPublishQueue(m, 100)
+
+	const n = 50
+	for i := 0; i < n; i++ {
+		if err := q.Publish(context.Background(), "t", i); err != nil {
+			t.Fatalf("Publish(%d): %v", i, err)
+		}
+	}
+	q.Close()
+
+	// После Close все поставленные в очередь сообщения уже разосланы.
+	for i, msg := range receive(t, sub, n) {
+		if msg != i {
+			t.Fatalf("сообщение %d: получено %v", i, msg)
+		}
+	}
+	if err := q.Publish(context.Background(), "t", n); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Publish после Close: err = %v, ожидалось %v", err, ErrQueueClosed)
+	}
+	q.Close() // Повторный вызов безопасен.
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// RecentSet запоминает недавно встреченные ключи в скользящем окне — по количеству
+// ключей, по времени или по обоим ограничениям сразу. Нужен для дедупликации потоков
+// с доставкой "хотя бы один раз" (at-least-once): повторно доставленное сообщение
+// распознается по ID, пока оно не выпало из окна, а память остается ограниченной.
+//
+// Окно ограничивает, насколько давно ключ встречался в последний раз: повторное
+// появление ключа продлевает его пребывание в окне. Безопасен для конкурентного использования.
+type RecentSet[K comparable] struct {
+	maxKeys int           // Сколько последних ключей помнить (0 — без ограничения).
+	maxAge  time.Duration // Сколько помнить ключ после последнего появления (0 — без ограничения).
+	now     func() time.Time
+
+	mu    sync.Mutex
+	order *list.List          // Ключи от самого старого (фронт) к самому свежему.
+	items map[K]*list.Element // Ключ -> элемент order.
+}
+
+// recentEntry — ключ и время его последнего появления.
+type recentEntry[K comparable] struct {
+	key  K
+	seen time.Time
+}
+
+// NewRecentSet создает окно из не более maxKeys последних ключей, каждый из которых
+// помнится maxAge после последнего появления. Нулевое значение снимает ограничение,
+// но хотя бы одно должно быть задано, иначе память не ограничена — тогда NewRecentSet паникует.
+func NewRecentSet[K comparable](maxKeys int, maxAge time.Duration) *RecentSet[K] {
+	if maxKeys < 0 || maxAge < 0 || (maxKeys == 0 && maxAge == 0) {
+		panic("NewRecentSet: нужно задать положительное maxKeys или maxAge")
+	}
+	return &RecentSet[K]{
+		maxKeys: maxKeys,
+		maxAge:  maxAge,
+		now:     time.Now,
+		order:   list.New(),
+		items:   make(map[K]*list.Element),
+	}
+}
+
+// Seen отмечает появление key и сообщает, встречался ли он уже в пределах окна.
+// Типичное использование: if set.Seen(msg.ID) { continue } // дубликат.
+func (s *RecentSet[K]) Seen(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.expire(now)
+
+	if el, ok := s.items[key]; ok {
+		el.Value.(*recentEntry[K]).seen = now
+		s.order.MoveToBack(el)
+		return true
+	}
+	s.items[key] = s.order.PushBack(&recentEntry[K]{key: key, seen: now})
+	if s.maxKeys > 0 && s.order.Len() > s.maxKeys {
+		s.remove(s.order.Front())
+	}
+	return false
+}
+
+// Contains сообщает, находится ли key в окне, не отмечая его появление.
+func (s *RecentSet[K]) Contains(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire(s.now())
+	_, ok := s.items[key]
+	return ok
+}
+
+// Len возвращает количество ключей в окне.
+func (s *RecentSet[K]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire(s.now())
+	return s.order.Len()
+}
+
+// expire удаляет ключи, выпавшие из окна по времени. Вызывается под s.mu.
+// Ключи в order упорядочены по времени появления, поэтому достаточно идти с фронта.
+func (s *RecentSet[K]) expire(now time.Time) {
+	if s.maxAge == 0 {
+		return
+	}
+	for el := s.order.Front(); el != nil; el = s.order.Front() {
+		if now.Sub(el.Value.(*recentEntry[K]).seen) < s.maxAge {
+			return
+		}
+		s.remove(el)
+	}
+}
+
+// remove удаляет элемент из списка и индекса. Вызывается под s.mu.
+func (s *RecentSet[K]) remove(el *list.Element) {
+	s.order.Remove(el)
+	delete(s.items, el.Value.(*recentEntry[K]).key)
+}
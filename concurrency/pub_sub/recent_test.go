@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestRecentSet(maxKeys int, maxAge time.Duration) (*RecentSet[string], *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1_000_000, 0)}
+	s := NewRecentSet[string](maxKeys, maxAge)
+	s.now = clock.Now
+	return s, clock
+}
+
+func TestRecentSetCountWindow(t *testing.T) {
+	s, _ := newTestRecentSet(3, 0)
+
+	for _, id := range []string{"a", "b", "c"} {
+		if s.Seen(id) {
+			t.Fatalf("Seen(%q) = true при первом появлении", id)
+		}
+	}
+	// Внутри окна — дубликат; повторное появление делает "a" самым свежим.
+	if !s.Seen("a") {
+		t.Error("Seen(a) = false внутри окна")
+	}
+
+	s.Seen("d") // Окно переполнено: вытесняется самый старый ключ "b".
+	if s.Contains("b") {
+		t.Error("b осталась в окне после вытеснения")
+	}
+	if s.Seen("b") {
+		t.Error("Seen(b) = true: ключ вне окна должен считаться новым")
+	}
+	if s.Len() != 3 {
+		t.Errorf("Len = %d, ожидалось 3", s.Len())
+	}
+}
+
+func TestRecentSetTimeWindow(t *testing.T) {
+	s, clock := newTestRecentSet(0, time.Minute)
+
+	s.Seen("msg-1")
+	clock.Advance(30 * time.Second)
+	if !s.Seen("msg-1") {
+		t.Error("повторная доставка внутри окна не распознана")
+	}
+
+	// Окно отсчитывается от последнего появления: 30с + 59с после первого — все еще дубликат.
+	clock.Advance(59 * time.Second)
+	if !s.Contains("msg-1") {
+		t.Error("msg-1 выпала из окна раньше срока")
+	}
+
+	clock.Advance(time.Second)
+	if s.Seen("msg-1") {
+		t.Error("ключ вне окна по времени должен считаться новым")
+	}
+}
+
+func TestRecentSetBothLimits(t *testing.T) {
+	s, clock := newTestRecentSet(2, time.Minute)
+	s.Seen("a")
+	s.Seen("b")
+	s.Seen("c") // Вытесняет "a" по количеству.
+	if s.Contains("a") {
+		t.Error("a не вытеснена по количеству")
+	}
+
+	clock.Advance(time.Minute) // "b" и "c" истекают по времени.
+	if n := s.Len(); n != 0 {
+		t.Errorf("Len = %d после истечения окна, ожидалось 0", n)
+	}
+}
+
+func TestNewRecentSetRequiresLimit(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewRecentSet без ограничений не запаниковал")
+		}
+	}()
+	NewRecentSet[int](0, 0)
+}
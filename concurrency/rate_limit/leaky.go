@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBucketFull возвращается из LeakyBucket.Add с политикой DropOnOverflow,
+// когда очередь заполнена и элемент отброшен.
+var ErrBucketFull = errors.New("leaky bucket: очередь заполнена")
+
+// OverflowPolicy задает, что LeakyBucket делает с элементом, для которого нет места.
+type OverflowPolicy int
+
+const (
+	// DropOnOverflow отбрасывает новый элемент: Add сразу возвращает ErrBucketFull.
+	DropOnOverflow OverflowPolicy = iota
+	// BlockOnOverflow ставит отправителя в очередь: Add ждет, пока освободится место.
+	BlockOnOverflow
+)
+
+// LeakyBucket — "дырявое ведро": принимает элементы с любой скоростью в очередь на
+// capacity элементов, а выпускает их с постоянной частотой, не чаще одного за 1/rate.
+//
+// В отличие от token bucket, который копит токены за время простоя и потом пропускает
+// пачку, дырявое ведро сглаживает поток (shaping): сколько бы элементов ни пришло разом
+// и сколько бы оно ни простаивало, на выходе интервал между элементами не меньше 1/rate.
+// Всплеск на входе превращается в очередь, а не во всплеск на выходе; что делать при
+// переполнении очереди, задает OverflowPolicy.
+//
+// Безопасен для конкурентного использования.
+type LeakyBucket[T any] struct {
+	interval time.Duration
+	capacity int
+	policy   OverflowPolicy
+	now      func() time.Time // Источник времени; подменяется в тестах.
+
+	mu      sync.Mutex
+	queue   []T
+	next    time.Time     // Раньше этого момента следующий элемент не выпускается.
+	dropped int           // Элементы, отброшенные при переполнении.
+	changed chan struct{} // Закрывается и заменяется при каждом изменении очереди.
+}
+
+// NewLeakyBucket создает ведро, выпускающее perSecond элементов в секунду из очереди
+// на capacity элементов. Паникует, если perSecond или capacity не положительны.
+func NewLeakyBucket[T any](perSecond float64, capacity int, policy OverflowPolicy) *LeakyBucket[T] {
+	if perSecond <= 0 || capacity <= 0 {
+		panic("NewLeakyBucket: частота и емкость должны быть положительными")
+	}
+	return &LeakyBucket[T]{
+		interval: time.Duration(float64(time.Second) / perSecond),
+		capacity: capacity,
+		policy:   policy,
+		now:      time.Now,
+		changed:  make(chan struct{}),
+	}
+}
+
+// Add ставит элемент в очередь. Если места нет, при DropOnOverflow элемент отбрасывается
+// и возвращается ErrBucketFull, а при BlockOnOverflow Add ждет места или отмены ctx.
+func (b *LeakyBucket[T]) Add(ctx context.Context, item T) error {
+	for {
+		b.mu.Lock()
+		if len(b.queue) < b.capacity {
+			b.queue = append(b.queue, item)
+			b.notifyLocked()
+			b.mu.Unlock()
+			return nil
+		}
+		if b.policy == DropOnOverflow {
+			b.dropped++
+			b.mu.Unlock()
+			return ErrBucketFull
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		select {
+		case <-changed:
+			// Очередь изменилась — проверяем, появилось ли место.
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// TryTake выпускает очередной элемент, если очередь не пуста и с прошлого выпуска
+// прошло не меньше 1/rate. Иначе возвращает false.
+func (b *LeakyBucket[T]) TryTake() (T, bool) {
+	item, ok, _ := b.take()
+	return item, ok
+}
+
+// Take ждет очередной элемент и момент, когда его можно выпустить, или отмены ctx.
+func (b *LeakyBucket[T]) Take(ctx context.Context) (T, error) {
+	for {
+		item, ok, changed := b.take()
+		if ok {
+			return item, nil
+		}
+
+		// Очередь пуста — ждем только изменений; иначе еще и наступления срока.
+		var timer *time.Timer
+		var timerC <-chan time.Time
+		b.mu.Lock()
+		if len(b.queue) > 0 {
+			timer = time.NewTimer(b.next.Sub(b.now()))
+			timerC = timer.C
+		}
+		b.mu.Unlock()
+
+		select {
+		case <-timerC:
+		case <-changed:
+		case <-ctx.Done():
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err := ctx.Err(); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+}
+
+// take выпускает элемент, если можно. При неудаче возвращает канал, который закроется
+// при следующем изменении очереди.
+func (b *LeakyBucket[T]) take() (T, bool, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var zero T
+	now := b.now()
+	if len(b.queue) == 0 || now.Before(b.next) {
+		return zero, false, b.changed
+	}
+	item := b.queue[0]
+	b.queue[0] = zero // Не держим ссылку на выпущенный элемент.
+	b.queue = b.queue[1:]
+	// Простой не дает права на пачку: следующий выпуск — не раньше чем через интервал
+	// от текущего, даже если предыдущий был давно.
+	b.next = now.Add(b.interval)
+	b.notifyLocked()
+	return item, true, nil
+}
+
+// notifyLocked будит всех, кто ждет изменения очереди. Вызывается под b.mu.
+func (b *LeakyBucket[T]) notifyLocked() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// Len возвращает число элементов, ожидающих выпуска.
+func (b *LeakyBucket[T]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.queue)
+}
+
+// Dropped возвращает число элементов, отброшенных при переполнении (DropOnOverflow).
+func (b *LeakyBucket[T]) Dropped() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestLeaky[T any](perSecond float64, capacity int, policy OverflowPolicy, clock *fakeClock) *LeakyBucket[T] {
+	b := NewLeakyBucket[T](perSecond, capacity, policy)
+	b.now = clock.Now
+	return b
+}
+
+// drainFor опрашивает ведро каждую миллисекунду фейкового времени в течение d
+// и возвращает выпущенные элементы.
+func drainFor[T any](b *LeakyBucket[T], clock *fakeClock, d time.Duration) []T {
+	var out []T
+	for elapsed := time.Duration(0); elapsed < d; elapsed += time.Millisecond {
+		for {
+			item, ok := b.TryTake()
+			if !ok {
+				break
+			}
+			out = append(out, item)
+		}
+		clock.Advance(time.Millisecond)
+	}
+	return out
+}
+
+func TestLeakyBucketCapsOutputRate(t *testing.T) {
+	// 10 элементов в секунду, то есть не чаще одного за 100ms, как бы ни приходили элементы.
+	for _, tt := range []struct {
+		name string
+		feed func(b *LeakyBucket[int], clock *fakeClock) []int
+	}{
+		{"всплеск", func(b *LeakyBucket[int], clock *fakeClock) []int {
+			for i := range 50 {
+				b.Add(context.Background(), i)
+			}
+			return drainFor(b, clock, time.Second)
+		}},
+		{"всплеск после простоя", func(b *LeakyBucket[int], clock *fakeClock) []int {
+			// Простой не накапливает права на пачку, в отличие от token bucket.
+			b.Add(context.Background(), -1)
+			b.TryTake()
+			clock.Advance(10 * time.Second)
+			for i := range 50 {
+				b.Add(context.Background(), i)
+			}
+			return drainFor(b, clock, time.Second)
+		}},
+		{"равномерный поток", func(b *LeakyBucket[int], clock *fakeClock) []int {
+			var out []int
+			for i := range 100 {
+				b.Add(context.Background(), i)
+				out = append(out, drainFor(b, clock, 10*time.Millisecond)...)
+			}
+			return out
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			b := newTestLeaky[int](10, 100, DropOnOverflow, clock)
+			out := tt.feed(b, clock)
+			if len(out) != 10 {
+				t.Errorf("за секунду выпущено %d элементов, ожидалось 10", len(out))
+			}
+			for i, v := range out {
+				if v != i {
+					t.Fatalf("порядок нарушен: %v", out)
+				}
+			}
+		})
+	}
+}
+
+func TestLeakyBucketSpacing(t *testing.T) {
+	clock := newFakeClock()
+	b := newTestLeaky[string](10, 10, DropOnOverflow, clock)
+	b.Add(context.Background(), "a")
+	b.Add(context.Background(), "b")
+
+	if v, ok := b.TryTake(); !ok || v != "a" {
+		t.Fatalf("первый элемент выпускается сразу, получено (%q, %v)", v, ok)
+	}
+	clock.Advance(99 * time.Millisecond)
+	if _, ok := b.TryTake(); ok {
+		t.Fatal("второй элемент выпущен раньше интервала")
+	}
+	clock.Advance(time.Millisecond)
+	if v, ok := b.TryTake(); !ok || v != "b" {
+		t.Errorf("через интервал получено (%q, %v), ожидалось (b, true)", v, ok)
+	}
+}
+
+func TestLeakyBucketDropOnOverflow(t *testing.T) {
+	clock := newFakeClock()
+	b := newTestLeaky[int](10, 3, DropOnOverflow, clock)
+	for i := range 5 {
+		err := b.Add(context.Background(), i)
+		if i < 3 && err != nil {
+			t.Fatalf("Add(%d) = %v", i, err)
+		}
+		if i >= 3 && !errors.Is(err, ErrBucketFull) {
+			t.Errorf("Add(%d) = %v, ожидалась ErrBucketFull", i, err)
+		}
+	}
+	if b.Len() != 3 || b.Dropped() != 2 {
+		t.Errorf("Len = %d, Dropped = %d; ожидалось 3 и 2", b.Len(), b.Dropped())
+	}
+	// Отброшены новые элементы, в очереди остались первые.
+	if out := drainFor(b, clock, time.Second); len(out) != 3 || out[0] != 0 || out[2] != 2 {
+		t.Errorf("выпущено %v, ожидалось [0 1 2]", out)
+	}
+}
+
+func TestLeakyBucketBlockOnOverflow(t *testing.T) {
+	clock := newFakeClock()
+	b := newTestLeaky[int](10, 2, BlockOnOverflow, clock)
+	b.Add(context.Background(), 0)
+	b.Add(context.Background(), 1)
+
+	// Очередь полна: Add ждет места и сдается по таймауту.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := b.Add(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Add в полное ведро = %v, ожидалась context.DeadlineExceeded", err)
+	}
+
+	added := make(chan error, 1)
+	go func() { added <- b.Add(context.Background(), 2) }()
+	select {
+	case err := <-added:
+		t.Fatalf("Add вернулся до освобождения места: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	if _, ok := b.TryTake(); !ok {
+		t.Fatal("TryTake не выпустил элемент")
+	}
+	select {
+	case err := <-added:
+		if err != nil {
+			t.Fatalf("Add = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Add не дождался освободившегося места")
+	}
+	if b.Dropped() != 0 || b.Len() != 2 {
+		t.Errorf("Dropped = %d, Len = %d; ожидалось 0 и 2", b.Dropped(), b.Len())
+	}
+}
+
+func TestLeakyBucketTakeWaits(t *testing.T) {
+	const interval = 10 * time.Millisecond
+	b := NewLeakyBucket[int](float64(time.Second/interval), 10, DropOnOverflow)
+
+	// Take ждет появления элемента.
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		for i := range 5 {
+			b.Add(context.Background(), i)
+		}
+	}()
+	start := time.Now()
+	for i := range 5 {
+		v, err := b.Take(context.Background())
+		if err != nil || v != i {
+			t.Fatalf("Take = (%d, %v), ожидалось (%d, nil)", v, err, i)
+		}
+	}
+	// Пять элементов — четыре интервала между ними.
+	if elapsed := time.Since(start); elapsed < 4*interval {
+		t.Errorf("5 элементов выпущены за %v, ожидалось не меньше %v", elapsed, 4*interval)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := b.Take(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Take из пустого ведра = %v, ожидалась context.DeadlineExceeded", err)
+	}
+}
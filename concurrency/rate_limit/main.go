@@ -0,0 +1,100 @@
+// Package main демонстрирует ограничение скорости (rate limiting) обработки данных.
+//
+// RateLimited пропускает элементы из канала не быстрее заданной частоты.
+// В основе — идея "ведра токенов" (token bucket) емкостью в один токен:
+// токен пополняется раз в 1/rate секунды, и каждый элемент расходует один токен.
+// Если токена нет, отправка ждет; если потребитель не успевает, блокируется источник.
+//
+// FixedWindowLimiter и SlidingWindowLimiter — ограничители для запросов (например, HTTP):
+// они отвечают на вопрос "можно ли сейчас?" (Allow) или ждут разрешения (Wait).
+//
+// LeakyBucket сглаживает поток: принимает элементы пачками в ограниченную очередь
+// и выпускает их с постоянной частотой, отбрасывая или придерживая лишние.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RateLimited возвращает канал, в который элементы из in пересылаются не чаще
+// perSecond раз в секунду. Выходной канал закрывается, когда закрывается in.
+//
+// Буферизации нет: пока элемент ждет своей очереди, следующий не читается из in,
+// так что медленный темп естественно передается источнику как обратное давление.
+// Потребитель обязан дочитать выходной канал до конца, иначе горутина пересылки не завершится.
+func RateLimited[T any](in <-chan T, perSecond float64) <-chan T {
+	if perSecond <= 0 {
+		panic("RateLimited: частота должна быть положительной")
+	}
+	interval := time.Duration(float64(time.Second) / perSecond)
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+		// next — момент, когда появится следующий токен. Первый элемент проходит сразу.
+		next := time.Now()
+		for v := range in {
+			if wait := time.Until(next); wait > 0 {
+				time.Sleep(wait)
+			}
+			out <- v
+			// Если источник долго молчал, токены не копятся: ведро вмещает только один.
+			now := time.Now()
+			if next.Before(now) {
+				next = now
+			}
+			next = next.Add(interval)
+		}
+	}()
+
+	return out
+}
+
+func main() {
+	logs := make(chan string)
+	go func() {
+		defer close(logs)
+		for i := 1; i <= 5; i++ {
+			logs <- fmt.Sprintf("запись лога #%d", i)
+		}
+	}()
+
+	fmt.Println("Воспроизводим логи со скоростью 4 записи в секунду...")
+	start := time.Now()
+	for line := range RateLimited(logs, 4) {
+		fmt.Printf("[%6s] %s\n", time.Since(start).Round(10*time.Millisecond), line)
+	}
+
+	fmt.Println("\nОграничители с окном: 3 запроса в секунду, пачка из 5 запросов...")
+	for _, l := range []struct {
+		name    string
+		limiter Limiter
+	}{
+		{"фиксированное окно", NewFixedWindowLimiter(3, time.Second)},
+		{"скользящее окно", NewSlidingWindowLimiter(3, time.Second)},
+	} {
+		allowed := 0
+		for i := 0; i < 5; i++ {
+			if l.limiter.Allow() {
+				allowed++
+			}
+		}
+		fmt.Printf("%s: разрешено %d из 5\n", l.name, allowed)
+	}
+
+	fmt.Println("\nДырявое ведро: 10 элементов в секунду, очередь на 3, пачка из 5...")
+	bucket := NewLeakyBucket[int](10, 3, DropOnOverflow)
+	for i := 1; i <= 5; i++ {
+		if err := bucket.Add(context.Background(), i); errors.Is(err, ErrBucketFull) {
+			fmt.Printf("элемент %d отброшен: очередь полна\n", i)
+		}
+	}
+	start = time.Now()
+	for bucket.Len() > 0 {
+		v, _ := bucket.Take(context.Background())
+		fmt.Printf("[%6s] элемент %d\n", time.Since(start).Round(10*time.Millisecond), v)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimitedPace(t *testing.T) {
+	const (
+		n    = 11
+		rate = 100.0 // 10ms между элементами
+	)
+	in := make(chan int, n)
+	for i := 0; i < n; i++ {
+		in <- i
+	}
+	close(in)
+
+	start := time.Now()
+	var got []int
+	for v := range RateLimited(in, rate) {
+		got = append(got, v)
+	}
+	elapsed := time.Since(start)
+
+	if len(got) != n {
+		t.Fatalf("получено %d элементов, ожидалось %d", len(got), n)
+	}
+	for i, v := range got {
+		if v != i {
+			t.Errorf("got[%d] = %d: порядок нарушен", i, v)
+		}
+	}
+
+	// Первый элемент проходит сразу, затем n-1 интервалов.
+	want := time.Duration(float64(n-1) / rate * float64(time.Second))
+	if elapsed < want*9/10 || elapsed > want*3 {
+		t.Errorf("пересылка %d элементов заняла %v, ожидалось около %v", n, elapsed, want)
+	}
+}
+
+func TestRateLimitedClosesOutput(t *testing.T) {
+	in := make(chan string)
+	close(in)
+
+	select {
+	case _, ok := <-RateLimited(in, 1):
+		if ok {
+			t.Error("из пустого закрытого входа пришел элемент")
+		}
+	case <-time.After(time.Second):
+		t.Error("выходной канал не закрылся")
+	}
+}
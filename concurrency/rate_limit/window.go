@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter — общий интерфейс ограничителей частоты запросов.
+type Limiter interface {
+	// Allow сообщает, можно ли выполнить запрос прямо сейчас, и если да — учитывает его.
+	Allow() bool
+	// Wait блокируется, пока запрос не будет разрешен, или возвращает ошибку контекста.
+	Wait(ctx context.Context) error
+}
+
+// waitFor реализует Wait поверх функции reserve, которая либо разрешает запрос,
+// либо сообщает, через сколько стоит попробовать снова.
+func waitFor(ctx context.Context, reserve func() (bool, time.Duration)) error {
+	for {
+		ok, delay := reserve()
+		if ok {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+			// Окно сдвинулось — пробуем снова (место мог занять другой ожидающий).
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// FixedWindowLimiter разрешает не более limit запросов в каждом окне длиной window.
+// Окна идут встык от момента создания; в начале нового окна счетчик сбрасывается.
+//
+// Простой и дешевый, но на стыке окон пропускает всплеск до 2*limit запросов
+// (limit в конце одного окна и limit в начале следующего).
+type FixedWindowLimiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	start time.Time // Начало текущего окна.
+	count int       // Запросов в текущем окне.
+}
+
+// NewFixedWindowLimiter создает ограничитель с фиксированным окном.
+// Паникует, если limit или window не положительны.
+func NewFixedWindowLimiter(limit int, window time.Duration) *FixedWindowLimiter {
+	if limit <= 0 || window <= 0 {
+		panic("NewFixedWindowLimiter: limit и window должны быть положительными")
+	}
+	l := &FixedWindowLimiter{limit: limit, window: window, now: time.Now}
+	l.start = l.now()
+	return l
+}
+
+func (l *FixedWindowLimiter) Allow() bool {
+	ok, _ := l.reserve()
+	return ok
+}
+
+func (l *FixedWindowLimiter) Wait(ctx context.Context) error {
+	return waitFor(ctx, l.reserve)
+}
+
+func (l *FixedWindowLimiter) reserve() (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if elapsed := now.Sub(l.start); elapsed >= l.window {
+		// Перескакиваем сразу на окно, содержащее now, сохраняя выравнивание границ.
+		l.start = l.start.Add(elapsed - elapsed%l.window)
+		l.count = 0
+	}
+	if l.count < l.limit {
+		l.count++
+		return true, 0
+	}
+	return false, l.start.Add(l.window).Sub(now)
+}
+
+// SlidingWindowLimiter разрешает не более limit запросов за любые window подряд.
+//
+// Хранит время последних limit разрешенных запросов (sliding log), поэтому точен и не
+// пропускает всплесков на стыке окон, ценой O(limit) памяти.
+type SlidingWindowLimiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	times []time.Time // Моменты разрешенных запросов за последнее окно, по возрастанию.
+}
+
+// NewSlidingWindowLimiter создает ограничитель со скользящим окном.
+// Паникует, если limit или window не положительны.
+func NewSlidingWindowLimiter(limit int, window time.Duration) *SlidingWindowLimiter {
+	if limit <= 0 || window <= 0 {
+		panic("NewSlidingWindowLimiter: limit и window должны быть положительными")
+	}
+	return &SlidingWindowLimiter{
+		limit:  limit,
+		window: window,
+		now:    time.Now,
+		times:  make([]time.Time, 0, limit),
+	}
+}
+
+func (l *SlidingWindowLimiter) Allow() bool {
+	ok, _ := l.reserve()
+	return ok
+}
+
+func (l *SlidingWindowLimiter) Wait(ctx context.Context) error {
+	return waitFor(ctx, l.reserve)
+}
+
+func (l *SlidingWindowLimiter) reserve() (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	// Отбрасываем запросы, вышедшие за пределы окна.
+	cutoff := now.Add(-l.window)
+	i := 0
+	for i < len(l.times) && !l.times[i].After(cutoff) {
+		i++
+	}
+	l.times = append(l.times[:0], l.times[i:]...)
+
+	if len(l.times) < l.limit {
+		l.times = append(l.times, now)
+		return true, 0
+	}
+	// Место освободится, когда самый старый запрос выйдет из окна.
+	return false, l.times[0].Add(l.window).Sub(now)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock — управляемый источник времени для тестов.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1_000_000, 0)}
+}
+
+func newTestFixed(limit int, window time.Duration, clock *fakeClock) *FixedWindowLimiter {
+	l := NewFixedWindowLimiter(limit, window)
+	l.now = clock.Now
+	l.start = clock.Now()
+	return l
+}
+
+func newTestSliding(limit int, window time.Duration, clock *fakeClock) *SlidingWindowLimiter {
+	l := NewSlidingWindowLimiter(limit, window)
+	l.now = clock.Now
+	return l
+}
+
+// allowN вызывает Allow n раз и возвращает количество разрешенных запросов.
+func allowN(l Limiter, n int) int {
+	allowed := 0
+	for i := 0; i < n; i++ {
+		if l.Allow() {
+			allowed++
+		}
+	}
+	return allowed
+}
+
+func TestFixedWindowResets(t *testing.T) {
+	clock := newFakeClock()
+	l := newTestFixed(3, time.Second, clock)
+
+	if got := allowN(l, 5); got != 3 {
+		t.Fatalf("в первом окне разрешено %d, ожидалось 3", got)
+	}
+
+	clock.Advance(999 * time.Millisecond)
+	if l.Allow() {
+		t.Fatal("до конца окна запрос не должен проходить")
+	}
+
+	clock.Advance(time.Millisecond)
+	if got := allowN(l, 5); got != 3 {
+		t.Errorf("после сброса окна разрешено %d, ожидалось 3", got)
+	}
+
+	// Пропуск нескольких окон: границы остаются выровненными.
+	clock.Advance(2*time.Second + 500*time.Millisecond)
+	if got := allowN(l, 5); got != 3 {
+		t.Errorf("после простоя разрешено %d, ожидалось 3", got)
+	}
+	clock.Advance(500 * time.Millisecond)
+	if !l.Allow() {
+		t.Error("граница окна должна быть выровнена по моменту создания")
+	}
+}
+
+func TestFixedWindowAllowsBurstAtBoundary(t *testing.T) {
+	clock := newFakeClock()
+	l := newTestFixed(3, time.Second, clock)
+
+	clock.Advance(900 * time.Millisecond)
+	first := allowN(l, 3)
+	clock.Advance(200 * time.Millisecond)
+	second := allowN(l, 3)
+
+	// Известный недостаток фиксированного окна: 6 запросов за 200 мс.
+	if first+second != 6 {
+		t.Errorf("на стыке окон разрешено %d, ожидалось 6", first+second)
+	}
+}
+
+func TestSlidingWindowSmoothsBoundaryBurst(t *testing.T) {
+	clock := newFakeClock()
+	l := newTestSliding(3, time.Second, clock)
+
+	clock.Advance(900 * time.Millisecond)
+	if got := allowN(l, 3); got != 3 {
+		t.Fatalf("разрешено %d, ожидалось 3", got)
+	}
+	clock.Advance(200 * time.Millisecond)
+	if got := allowN(l, 3); got != 0 {
+		t.Errorf("через 200 мс разрешено %d, ожидалось 0: окно еще не сдвинулось", got)
+	}
+
+	// Ровно через окно после первой пачки места освобождаются.
+	clock.Advance(800 * time.Millisecond)
+	if got := allowN(l, 5); got != 3 {
+		t.Errorf("через окно разрешено %d, ожидалось 3", got)
+	}
+}
+
+func TestSlidingWindowReleasesGradually(t *testing.T) {
+	clock := newFakeClock()
+	l := newTestSliding(2, time.Second, clock)
+
+	l.Allow()
+	clock.Advance(500 * time.Millisecond)
+	l.Allow()
+	if l.Allow() {
+		t.Fatal("лимит исчерпан")
+	}
+
+	// Через секунду после первого запроса освобождается ровно одно место.
+	clock.Advance(500 * time.Millisecond)
+	if got := allowN(l, 2); got != 1 {
+		t.Errorf("разрешено %d, ожидалось 1", got)
+	}
+}
+
+func TestLimiterWaitHonoursContext(t *testing.T) {
+	limiters := map[string]Limiter{
+		"fixed":   NewFixedWindowLimiter(1, time.Hour),
+		"sliding": NewSlidingWindowLimiter(1, time.Hour),
+	}
+	for name, l := range limiters {
+		t.Run(name, func(t *testing.T) {
+			if err := l.Wait(context.Background()); err != nil {
+				t.Fatalf("первый Wait: %v", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Wait = %v, ожидалась context.DeadlineExceeded", err)
+			}
+		})
+	}
+}
+
+func TestLimiterWaitUnblocksWhenWindowMoves(t *testing.T) {
+	const window = 30 * time.Millisecond
+	limiters := map[string]Limiter{
+		"fixed":   NewFixedWindowLimiter(2, window),
+		"sliding": NewSlidingWindowLimiter(2, window),
+	}
+	for name, l := range limiters {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			for i := 0; i < 4; i++ {
+				if err := l.Wait(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// Третий и четвертый запросы попадают во второе окно.
+			if elapsed := time.Since(start); elapsed < window/2 || elapsed > window+time.Second {
+				t.Errorf("4 запроса заняли %v, ожидалось около %v", elapsed, window)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -75,6 +76,35 @@ func main() {
 		fmt.Printf("Получен результат: '%s'\n", resFail.Value)
 	}
 
+	fmt.Println("\n--- Сценарий 3: Ограничение времени выполнения ---")
+	_, err := WithTimeout(context.Background(), 100*time.Millisecond, func(ctx context.Context) (string, error) {
+		// Работа дольше таймаута, но с поддержкой отмены.
+		select {
+		case <-time.After(time.Second):
+			return "Задача выполнена", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})
+	fmt.Printf("Получена ошибка: %v\n", err)
+
+	fmt.Println("\n--- Сценарий 4: Откат на запасной источник ---")
+	value, err := WithFallback(context.Background(), 100*time.Millisecond,
+		func(ctx context.Context) (string, error) {
+			// Медленная реплика не укладывается в таймаут.
+			select {
+			case <-time.After(time.Second):
+				return "Данные с реплики", nil
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		},
+		func(context.Context) (string, error) {
+			return "Данные из кеша", nil
+		},
+	)
+	fmt.Printf("Получен результат: '%s', ошибка: %v\n", value, err)
+
 	// ЗАМЕТКА ОБ ОШИБКЕ В ИСХОДНОМ КОДЕ:
 	// В оригинальном примере использовался `select` с двумя каналами.
 	// Проблема была в том, что горутина-производитель закрывала оба канала
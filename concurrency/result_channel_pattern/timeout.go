@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// result — типизированный вариант Result для передачи значения и ошибки из горутины.
+type result[T any] struct {
+	value T
+	err   error
+}
+
+// WithTimeout выполняет fn с производным от ctx контекстом, ограниченным временем d,
+// и возвращает ее результат. Если fn не укладывается в d (или отменен родительский ctx),
+// WithTimeout возвращается сразу, не дожидаясь fn. Ошибка оборачивает
+// context.DeadlineExceeded, если истекло d, и ошибку родительского ctx, если
+// прерван он: отмена вызывающим не выдается за таймаут fn.
+//
+// fn запускается в отдельной горутине, а результат передается через канал — это
+// позволяет вернуть управление, даже если fn игнорирует отмену контекста.
+//
+// Утечка горутины: если fn не проверяет ctx, после таймаута она продолжает работать
+// до собственного завершения — Go не умеет принудительно останавливать горутины.
+// Канал результата буферизирован, поэтому завершившаяся fn не заблокируется на отправке,
+// но все ресурсы, которые она удерживает, освободятся только по ее окончании.
+// Для долгих или бесконечных fn таймаут нужно поддерживать внутри самой функции.
+func WithTimeout[T any](ctx context.Context, d time.Duration, fn func(context.Context) (T, error)) (T, error) {
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	resCh := make(chan result[T], 1)
+	go func() {
+		v, err := fn(ctx)
+		resCh <- result[T]{value: v, err: err}
+	}()
+
+	select {
+	case res := <-resCh:
+		return res.value, res.err
+	case <-ctx.Done():
+		// Если fn успела завершиться одновременно с таймаутом, отдаем ее результат.
+		select {
+		case res := <-resCh:
+			return res.value, res.err
+		default:
+		}
+		var zero T
+		if err := parent.Err(); err != nil {
+			return zero, fmt.Errorf("функция прервана: %w", err)
+		}
+		return zero, fmt.Errorf("функция не завершилась за %s: %w", d, ctx.Err())
+	}
+}
+
+// WithFallback выполняет primary с таймаутом d (см. WithTimeout), а если она вернула ошибку
+// или не уложилась в d — выполняет fallback с исходным ctx. Типичный случай — чтение
+// с медленной реплики с откатом на кеш.
+//
+// Если не удались обе функции, возвращается errors.Join обеих ошибок, так что
+// errors.Is находит любую из них (в том числе context.DeadlineExceeded для primary).
+// Если отменен сам ctx, fallback не вызывается.
+func WithFallback[T any](ctx context.Context, d time.Duration, primary, fallback func(context.Context) (T, error)) (T, error) {
+	v, primaryErr := WithTimeout(ctx, d, primary)
+	if primaryErr == nil {
+		return v, nil
+	}
+	if err := ctx.Err(); err != nil {
+		var zero T
+		return zero, errors.Join(primaryErr, err)
+	}
+
+	v, fallbackErr := fallback(ctx)
+	if fallbackErr != nil {
+		var zero T
+		return zero, errors.Join(
+			fmt.Errorf("основная функция: %w", primaryErr),
+			fmt.Errorf("запасная функция: %w", fallbackErr),
+		)
+	}
+	return v, nil
+}
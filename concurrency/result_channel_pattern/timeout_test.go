@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithTimeoutFastSuccess(t *testing.T) {
+	got, err := WithTimeout(context.Background(), time.Second, func(context.Context) (int, error) {
+		return 42, nil
+	})
+	if err != nil || got != 42 {
+		t.Errorf("WithTimeout = (%d, %v), ожидалось (42, nil)", got, err)
+	}
+}
+
+func TestWithTimeoutReturnsFnError(t *testing.T) {
+	errBoom := errors.New("boom")
+	_, err := WithTimeout(context.Background(), time.Second, func(context.Context) (string, error) {
+		return "", errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("err = %v, ожидалось %v", err, errBoom)
+	}
+}
+
+func TestWithTimeoutIgnoringCancellation(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release) // Отпускаем "зависшую" функцию, чтобы горутина не утекла из теста.
+
+	const d = 20 * time.Millisecond
+	start := time.Now()
+	got, err := WithTimeout(context.Background(), d, func(context.Context) (int, error) {
+		<-release // Функция не смотрит на ctx.
+		return 1, nil
+	})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, ожидалось %v", err, context.DeadlineExceeded)
+	}
+	if got != 0 {
+		t.Errorf("при таймауте ожидалось нулевое значение, получено %d", got)
+	}
+	if elapsed > d+time.Second {
+		t.Errorf("WithTimeout вернулся через %v, ожидалось около %v", elapsed, d)
+	}
+}
+
+func TestWithTimeoutPassesDeadlineToFn(t *testing.T) {
+	_, err := WithTimeout(context.Background(), 20*time.Millisecond, func(ctx context.Context) (int, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("у контекста fn нет дедлайна")
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, ожидалось %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestWithTimeoutParentCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	_, err := WithTimeout(ctx, time.Minute, func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, ожидалось %v", err, context.Canceled)
+	}
+}
+
+func TestWithTimeoutParentCancellationNotReportedAsTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	// fn игнорирует отмену, поэтому WithTimeout возвращается по отмене родителя.
+	_, err := WithTimeout(ctx, time.Minute, func(context.Context) (int, error) {
+		<-release
+		return 0, nil
+	})
+	if want := "функция прервана: context canceled"; err == nil || err.Error() != want {
+		t.Errorf("err = %v, ожидалось %q: отмена родителя не должна выдаваться за таймаут", err, want)
+	}
+}
+
+func TestWithFallbackPrimarySuccess(t *testing.T) {
+	fallbackCalled := false
+	got, err := WithFallback(context.Background(), time.Second,
+		func(context.Context) (string, error) { return "primary", nil },
+		func(context.Context) (string, error) {
+			fallbackCalled = true
+			return "fallback", nil
+		},
+	)
+	if err != nil || got != "primary" {
+		t.Errorf("WithFallback = (%q, %v), ожидалось (\"primary\", nil)", got, err)
+	}
+	if fallbackCalled {
+		t.Error("fallback вызван при успешной primary")
+	}
+}
+
+func TestWithFallbackPrimaryTimeout(t *testing.T) {
+	got, err := WithFallback(context.Background(), 20*time.Millisecond,
+		func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+		func(context.Context) (string, error) { return "fallback", nil },
+	)
+	if err != nil || got != "fallback" {
+		t.Errorf("WithFallback = (%q, %v), ожидалось (\"fallback\", nil)", got, err)
+	}
+}
+
+func TestWithFallbackPrimaryError(t *testing.T) {
+	got, err := WithFallback(context.Background(), time.Second,
+		func(context.Context) (int, error) { return 0, errors.New("replica down") },
+		func(context.Context) (int, error) { return 7, nil },
+	)
+	if err != nil || got != 7 {
+		t.Errorf("WithFallback = (%d, %v), ожидалось (7, nil)", got, err)
+	}
+}
+
+func TestWithFallbackBothFail(t *testing.T) {
+	errCache := errors.New("cache miss")
+	got, err := WithFallback(context.Background(), 20*time.Millisecond,
+		func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 1, ctx.Err()
+		},
+		func(context.Context) (int, error) { return 2, errCache },
+	)
+	if got != 0 {
+		t.Errorf("при ошибке ожидалось нулевое значение, получено %d", got)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errCache) {
+		t.Errorf("err = %v, ожидалось объединение %v и %v", err, context.DeadlineExceeded, errCache)
+	}
+}
+
+func TestWithFallbackSkipsFallbackWhenParentCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := WithFallback(ctx, time.Second,
+		func(ctx context.Context) (int, error) { return 0, ctx.Err() },
+		func(context.Context) (int, error) {
+			t.Error("fallback вызван после отмены родительского контекста")
+			return 0, nil
+		},
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, ожидалось %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// autoTuneMaxWorkers — верхняя граница перебора числа воркеров.
+	autoTuneMaxWorkers = 256
+	// autoTuneMinGain — минимальный относительный прирост пропускной способности (10%),
+	// ради которого стоит удвоить число воркеров.
+	autoTuneMinGain = 0.10
+)
+
+// AutoTuneWorkers подбирает число воркеров по замерам пропускной способности.
+//
+// sample(workers) должен прогнать пробную нагрузку с заданным числом воркеров и вернуть
+// пропускную способность (например, задач в секунду). Перебираются 1, 2, 4, 8, ...
+// воркеров; как только удвоение дает прирост меньше autoTuneMinGain (плато или падение
+// из-за конкуренции за ресурсы), возвращается последнее число, которое еще давало прирост.
+//
+// Удвоение выбрано потому, что для I/O-нагрузки оптимум может быть и 4, и 200 воркеров:
+// так нужно O(log n) замеров вместо n. Результат — оценка с точностью до степени двойки.
+func AutoTuneWorkers(sample func(workers int) float64) int {
+	best := 1
+	bestThroughput := sample(best)
+	for w := 2; w <= autoTuneMaxWorkers; w *= 2 {
+		throughput := sample(w)
+		if throughput < bestThroughput*(1+autoTuneMinGain) {
+			break
+		}
+		best, bestThroughput = w, throughput
+	}
+	return best
+}
+
+// MeasureThroughput выполняет tasks вызовов work силами workers воркеров и возвращает
+// пропускную способность в задачах в секунду. Удобен как основа для sample в AutoTuneWorkers.
+func MeasureThroughput(workers, tasks int, work func()) float64 {
+	jobs := make(chan struct{}, tasks)
+	for range tasks {
+		jobs <- struct{}{}
+	}
+	close(jobs)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				work()
+			}
+		}()
+	}
+	wg.Wait()
+	return float64(tasks) / time.Since(start).Seconds()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAutoTuneWorkersPicksPlateau(t *testing.T) {
+	tests := []struct {
+		name  string
+		curve func(workers int) float64
+		want  int
+	}{
+		{
+			// Линейный рост до 8 воркеров, дальше ограничение внешнего ресурса.
+			name:  "плато",
+			curve: func(w int) float64 { return 100 * float64(min(w, 8)) },
+			want:  8,
+		},
+		{
+			// После 16 воркеров конкуренция за блокировки снижает пропускную способность.
+			name: "деградация",
+			curve: func(w int) float64 {
+				if w <= 16 {
+					return float64(w) * 50
+				}
+				return 800 - float64(w)
+			},
+			want: 16,
+		},
+		{
+			// Рост меньше порога (5% на удвоение) не оправдывает лишних воркеров.
+			name:  "слабый прирост",
+			curve: func(w int) float64 { return 1000 + float64(w) },
+			want:  1,
+		},
+		{
+			name:  "рост без плато",
+			curve: func(w int) float64 { return float64(w) },
+			want:  autoTuneMaxWorkers,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AutoTuneWorkers(tt.curve); got != tt.want {
+				t.Errorf("AutoTuneWorkers = %d, ожидалось %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoTuneWorkersProbesPowersOfTwo(t *testing.T) {
+	var probed []int
+	AutoTuneWorkers(func(w int) float64 {
+		probed = append(probed, w)
+		return float64(min(w, 4))
+	})
+	// Замер на 8 воркерах показывает плато — дальше не идем.
+	if want := []int{1, 2, 4, 8}; !slices.Equal(probed, want) {
+		t.Errorf("замеры на %v, ожидалось %v", probed, want)
+	}
+}
+
+func TestMeasureThroughputScalesWithWorkers(t *testing.T) {
+	work := func() { time.Sleep(5 * time.Millisecond) }
+	one := MeasureThroughput(1, 8, work)
+	four := MeasureThroughput(4, 8, work)
+	// Задачи ждут, а не считают: 4 воркера должны быть заметно быстрее одного.
+	if four < 2*one {
+		t.Errorf("пропускная способность 4 воркеров %.0f/с, одного — %.0f/с", four, one)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Deduplicator — декоратор над ProcessFunc, который выполняет задачу с данным
+// ключом идемпотентности только один раз.
+//
+// Повторные задачи с тем же ключом не запускают обработку заново и получают
+// сохраненный Result. Если дубликат приходит, пока первая задача еще выполняется,
+// он дожидается ее результата (как singleflight), а не стартует параллельный запрос.
+// Полезно для краулеров, где один и тот же URL может встретиться много раз.
+type Deduplicator struct {
+	process ProcessFunc
+	key     func(Task) string
+
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+}
+
+// dedupEntry хранит результат задачи. Канал done закрывается, когда result готов.
+type dedupEntry struct {
+	done   chan struct{}
+	result Result
+}
+
+// NewDeduplicator создает дедупликатор. key вычисляет ключ идемпотентности задачи.
+func NewDeduplicator(process ProcessFunc, key func(Task) string) *Deduplicator {
+	return &Deduplicator{
+		process: process,
+		key:     key,
+		entries: make(map[string]*dedupEntry),
+	}
+}
+
+// Process выполняет задачу или возвращает результат ранее выполненной задачи с тем же ключом.
+// Метод безопасен для вызова из нескольких воркеров одновременно.
+//
+// Если обработка паникует, паника передается вызвавшему, ожидающие дубликаты получают
+// Result с ошибкой, а ключ забывается: следующая задача с ним выполнится заново.
+func (d *Deduplicator) Process(t Task) Result {
+	k := d.key(t)
+
+	d.mu.Lock()
+	if e, ok := d.entries[k]; ok {
+		d.mu.Unlock()
+		<-e.done
+		return e.result
+	}
+	e := &dedupEntry{done: make(chan struct{})}
+	d.entries[k] = e
+	d.mu.Unlock()
+
+	// Ожидающих нужно отпустить в любом случае, иначе паника обработки
+	// навсегда заблокирует все дубликаты этого ключа.
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		r := recover()
+		d.mu.Lock()
+		delete(d.entries, k)
+		d.mu.Unlock()
+		e.result = Result{URL: t.URL, Error: fmt.Errorf("обработка задачи запаниковала: %v", r)}
+		close(e.done)
+		panic(r)
+	}()
+
+	// Сама обработка идет без блокировки, чтобы задачи с разными ключами не мешали друг другу.
+	e.result = d.process(t)
+	completed = true
+	close(e.done)
+	return e.result
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultLatencyBuckets — границы корзин по умолчанию для задержек HTTP-запросов.
+var DefaultLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Histogram — гистограмма задержек в стиле Prometheus.
+//
+// Каждое наблюдение попадает в первую корзину, верхняя граница которой не меньше
+// его значения; значения больше всех границ попадают в корзину +Inf.
+// Счетчики атомарные, поэтому Observe можно вызывать из многих горутин без мьютекса.
+type Histogram struct {
+	bounds []time.Duration // Верхние границы корзин по возрастанию.
+	counts []atomic.Uint64 // Некумулятивные счетчики; последний — корзина +Inf.
+	count  atomic.Uint64   // Общее число наблюдений.
+	sum    atomic.Int64    // Сумма наблюдений в наносекундах.
+}
+
+// Bucket — кумулятивный счетчик корзины: сколько наблюдений было <= UpperBound.
+type Bucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// NewHistogram создает гистограмму с заданными границами корзин.
+// Границы сортируются, дубликаты и неположительные значения отбрасываются.
+// Пустой список заменяется на DefaultLatencyBuckets.
+func NewHistogram(buckets []time.Duration) *Histogram {
+	bounds := slices.Clone(buckets)
+	slices.Sort(bounds)
+	bounds = slices.Compact(bounds)
+	bounds = slices.DeleteFunc(bounds, func(b time.Duration) bool { return b <= 0 })
+	if len(bounds) == 0 {
+		bounds = slices.Clone(DefaultLatencyBuckets)
+	}
+	return &Histogram{
+		bounds: bounds,
+		counts: make([]atomic.Uint64, len(bounds)+1),
+	}
+}
+
+// Observe учитывает одно наблюдение.
+func (h *Histogram) Observe(d time.Duration) {
+	// Индекс первой границы >= d; если таких нет — корзина +Inf (len(bounds)).
+	i, _ := slices.BinarySearch(h.bounds, d)
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+}
+
+// Count возвращает общее число наблюдений.
+func (h *Histogram) Count() uint64 {
+	return h.count.Load()
+}
+
+// Sum возвращает сумму всех наблюдений.
+func (h *Histogram) Sum() time.Duration {
+	return time.Duration(h.sum.Load())
+}
+
+// Buckets возвращает кумулятивные счетчики конечных корзин (как `le` в Prometheus).
+// Наблюдения выше последней границы учитываются только в Count.
+func (h *Histogram) Buckets() []Bucket {
+	res := make([]Bucket, len(h.bounds))
+	var cumulative uint64
+	for i, b := range h.bounds {
+		cumulative += h.counts[i].Load()
+		res[i] = Bucket{UpperBound: b, Count: cumulative}
+	}
+	return res
+}
+
+// Quantile возвращает приближенное значение квантиля q (0 <= q <= 1) так же, как
+// histogram_quantile в Prometheus: находит корзину, в которую попадает ранг q*count,
+// и линейно интерполирует внутри нее, считая наблюдения распределенными равномерно.
+//
+// Точность ограничена шириной корзин. Если квантиль попадает в корзину +Inf,
+// возвращается последняя конечная граница. Без наблюдений результат равен 0.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	q = min(max(q, 0), 1)
+
+	// Снимаем счетчики один раз, чтобы расчет шел по согласованным данным.
+	counts := make([]uint64, len(h.counts))
+	var total uint64
+	for i := range h.counts {
+		counts[i] = h.counts[i].Load()
+		total += counts[i]
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := q * float64(total)
+	var cumulative uint64
+	for i, c := range counts {
+		if c == 0 || float64(cumulative+c) < rank {
+			cumulative += c
+			continue
+		}
+		if i == len(h.bounds) {
+			return h.bounds[len(h.bounds)-1]
+		}
+		lower := time.Duration(0)
+		if i > 0 {
+			lower = h.bounds[i-1]
+		}
+		upper := h.bounds[i]
+		frac := (rank - float64(cumulative)) / float64(c)
+		return lower + time.Duration(frac*float64(upper-lower))
+	}
+	return h.bounds[len(h.bounds)-1]
+}
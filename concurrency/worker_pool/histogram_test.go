@@ -0,0 +1,113 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHistogramBucketCounts(t *testing.T) {
+	h := NewHistogram([]time.Duration{100 * time.Millisecond, 10 * time.Millisecond, 50 * time.Millisecond})
+
+	for _, d := range []time.Duration{
+		1 * time.Millisecond,
+		10 * time.Millisecond, // Граница включается в корзину (le).
+		20 * time.Millisecond,
+		60 * time.Millisecond,
+		70 * time.Millisecond,
+		time.Second, // Выше всех границ — корзина +Inf.
+	} {
+		h.Observe(d)
+	}
+
+	want := []Bucket{
+		{UpperBound: 10 * time.Millisecond, Count: 2},
+		{UpperBound: 50 * time.Millisecond, Count: 3},
+		{UpperBound: 100 * time.Millisecond, Count: 5},
+	}
+	got := h.Buckets()
+	if len(got) != len(want) {
+		t.Fatalf("Buckets = %v, ожидалось %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Buckets[%d] = %+v, ожидалось %+v", i, got[i], want[i])
+		}
+	}
+	if h.Count() != 6 {
+		t.Errorf("Count = %d, ожидалось 6", h.Count())
+	}
+	if want := 1161 * time.Millisecond; h.Sum() != want {
+		t.Errorf("Sum = %v, ожидалось %v", h.Sum(), want)
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	h := NewHistogram([]time.Duration{
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+	})
+	// 100 наблюдений: 1ms, 2ms, ..., 100ms — равномерное распределение.
+	for i := 1; i <= 100; i++ {
+		h.Observe(time.Duration(i) * time.Millisecond)
+	}
+
+	tests := []struct {
+		q    float64
+		want time.Duration
+	}{
+		// Ранг 50 попадает в корзину (20ms, 50ms] с 30 наблюдениями: 20 + 30*(30/30).
+		{0.5, 50 * time.Millisecond},
+		// Ранг 95 — корзина (50ms, 100ms]: 50 + 50*(45/50) = 95ms.
+		{0.95, 95 * time.Millisecond},
+		{0.1, 10 * time.Millisecond},
+		{0, 0},
+		{1, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := h.Quantile(tt.q); got != tt.want {
+			t.Errorf("Quantile(%v) = %v, ожидалось %v", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestHistogramQuantileEdgeCases(t *testing.T) {
+	h := NewHistogram([]time.Duration{time.Millisecond})
+	if got := h.Quantile(0.5); got != 0 {
+		t.Errorf("Quantile без наблюдений = %v, ожидалось 0", got)
+	}
+
+	h.Observe(time.Hour)
+	if got := h.Quantile(0.99); got != time.Millisecond {
+		t.Errorf("Quantile в корзине +Inf = %v, ожидалась последняя граница", got)
+	}
+}
+
+func TestHistogramDefaultBuckets(t *testing.T) {
+	h := NewHistogram(nil)
+	if got := len(h.Buckets()); got != len(DefaultLatencyBuckets) {
+		t.Errorf("корзин %d, ожидалось %d", got, len(DefaultLatencyBuckets))
+	}
+}
+
+func TestHistogramConcurrentObserve(t *testing.T) {
+	h := NewHistogram(nil)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				h.Observe(time.Duration(i) * time.Millisecond)
+				_ = h.Quantile(0.95)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if h.Count() != 8000 {
+		t.Errorf("Count = %d, ожидалось 8000", h.Count())
+	}
+}
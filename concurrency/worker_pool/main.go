@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"sync"
@@ -20,24 +21,24 @@ type Result struct {
 	Duration   time.Duration
 }
 
-// worker — функция, которая читает из канала jobs и делает HTTP-запрос по URL
-func worker(id int, jobs <-chan Task, results chan<- Result, wg *sync.WaitGroup) {
-	defer wg.Done()
+// ProcessFunc — функция, выполняющая одну задачу.
+// Воркеры не знают, что именно она делает, поэтому ее легко обернуть декоратором.
+type ProcessFunc func(Task) Result
 
-	// Настроим HTTP-клиент с таймаутом
+// newURLChecker возвращает ProcessFunc, который делает HTTP-запрос по URL задачи.
+func newURLChecker(timeout time.Duration) ProcessFunc {
+	// Настроим HTTP-клиент с таймаутом. Клиент потокобезопасен и переиспользуется всеми воркерами.
 	client := &http.Client{
-		Timeout: 5 * time.Second,
+		Timeout: timeout,
 	}
 
-	for j := range jobs {
-		fmt.Printf("Воркер %d: начал обработку %s\n", id, j.URL)
-
+	return func(t Task) Result {
 		start := time.Now()
-		resp, err := client.Get(j.URL)
+		resp, err := client.Get(t.URL)
 		duration := time.Since(start)
 
 		result := Result{
-			URL:      j.URL,
+			URL:      t.URL,
 			Duration: duration,
 			Error:    err,
 		}
@@ -46,7 +47,17 @@ func worker(id int, jobs <-chan Task, results chan<- Result, wg *sync.WaitGroup)
 			result.StatusCode = resp.StatusCode
 			resp.Body.Close() // Обязательно закрываем тело ответа
 		}
+		return result
+	}
+}
 
+// worker — функция, которая читает из канала jobs и обрабатывает каждую задачу через process
+func worker(id int, jobs <-chan Task, results chan<- Result, wg *sync.WaitGroup, process ProcessFunc) {
+	defer wg.Done()
+
+	for j := range jobs {
+		fmt.Printf("Воркер %d: начал обработку %s\n", id, j.URL)
+		result := process(j)
 		fmt.Printf("Воркер %d: закончил обработку %s\n", id, j.URL)
 		results <- result
 	}
@@ -61,6 +72,7 @@ func main() {
 		"https://stackoverflow.com",
 		"https://pkg.go.dev",
 		"https://invalid-url.com.example", // Пример нерабочего URL
+		"https://golang.org",              // Дубликат: будет обработан только один раз
 	}
 
 	numJobs := len(urls)
@@ -73,10 +85,14 @@ func main() {
 	// WaitGroup для синхронизации завершения всех воркеров
 	var wg sync.WaitGroup
 
+	// Оборачиваем проверку URL в дедупликатор: повторные задачи с тем же URL
+	// не делают лишних запросов, а получают уже готовый результат.
+	dedup := NewDeduplicator(newURLChecker(5*time.Second), func(t Task) string { return t.URL })
+
 	// Запускаем воркеров
 	for w := 1; w <= numWorkers; w++ {
 		wg.Add(1)
-		go worker(w, jobs, results, &wg)
+		go worker(w, jobs, results, &wg, dedup.Process)
 	}
 
 	// Отправляем задачи в канал.
@@ -94,14 +110,43 @@ func main() {
 	}()
 
 	fmt.Println("\n--- Вывод результатов ---")
-	// Читаем результаты по мере их поступления
-	for res := range results {
-		if res.Error != nil {
-			fmt.Printf("❌ ОШИБКА  \t %s: %v (заняло %v)\n", res.URL, res.Error, res.Duration)
-		} else {
-			fmt.Printf("✅ %d \t %s (заняло %v)\n", res.StatusCode, res.URL, res.Duration)
+	latency := NewHistogram(DefaultLatencyBuckets)
+	// Читаем результаты по мере их поступления, но не дольше общего дедлайна:
+	// при его истечении оставшиеся результаты не ждем.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+read:
+	for {
+		select {
+		case res, ok := <-results:
+			if !ok {
+				fmt.Println("Все URL обработаны.")
+				break read
+			}
+			latency.Observe(res.Duration)
+			if res.Error != nil {
+				fmt.Printf("❌ ОШИБКА  \t %s: %v (заняло %v)\n", res.URL, res.Error, res.Duration)
+			} else {
+				fmt.Printf("✅ %d \t %s (заняло %v)\n", res.StatusCode, res.URL, res.Duration)
+			}
+		case <-ctx.Done():
+			fmt.Printf("Не все URL успели обработаться: %v\n", ctx.Err())
+			break read
 		}
 	}
 
-	fmt.Println("Все URL обработаны.")
+	fmt.Printf("Задержки: p50 ≈ %v, p95 ≈ %v (по %d запросам)\n",
+		latency.Quantile(0.5), latency.Quantile(0.95), latency.Count())
+
+	// Подбор числа воркеров вместо константы: имитируем I/O-задачи к сервису,
+	// который обслуживает не больше 6 запросов одновременно.
+	backend := make(chan struct{}, 6)
+	recommended := AutoTuneWorkers(func(workers int) float64 {
+		return MeasureThroughput(workers, 48, func() {
+			backend <- struct{}{}
+			time.Sleep(5 * time.Millisecond)
+			<-backend
+		})
+	})
+	fmt.Printf("Рекомендуемое число воркеров: %d\n", recommended)
 }
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andrewhigh08/exp/internal/chanutil"
+)
+
+// runPool прогоняет задачи через пул воркеров и собирает все результаты.
+func runPool(tasks []Task, numWorkers int, process ProcessFunc) []Result {
+	jobs := make(chan Task, len(tasks))
+	results := make(chan Result, len(tasks))
+	var wg sync.WaitGroup
+
+	for w := 1; w <= numWorkers; w++ {
+		wg.Add(1)
+		go worker(w, jobs, results, &wg, process)
+	}
+	for _, t := range tasks {
+		jobs <- t
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	collected, _ := chanutil.Drain(context.Background(), results) // Без отмены ошибки не бывает.
+	return collected
+}
+
+func TestDeduplicatorRunsDuplicateOnce(t *testing.T) {
+	var calls atomic.Int32
+	process := func(t Task) Result {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond) // Дубликат успевает прийти, пока задача выполняется.
+		return Result{URL: t.URL, StatusCode: 200}
+	}
+	dedup := NewDeduplicator(process, func(t Task) string { return t.URL })
+
+	tasks := []Task{{URL: "https://a.example"}, {URL: "https://a.example"}}
+	results := runPool(tasks, 2, dedup.Process)
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("обработка запускалась %d раз, ожидался 1", n)
+	}
+	if len(results) != 2 {
+		t.Fatalf("получено %d результатов, ожидалось 2", len(results))
+	}
+	for _, r := range results {
+		if r.URL != "https://a.example" || r.StatusCode != 200 {
+			t.Errorf("неожиданный результат: %+v", r)
+		}
+	}
+}
+
+func TestDeduplicatorPanicReleasesWaiters(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	process := func(t Task) Result {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-release
+			panic("boom")
+		}
+		return Result{URL: t.URL, StatusCode: 200}
+	}
+	dedup := NewDeduplicator(process, func(t Task) string { return t.URL })
+
+	panicked := make(chan any, 1)
+	go func() {
+		defer func() { panicked <- recover() }()
+		dedup.Process(Task{URL: "a"})
+	}()
+	<-started
+
+	waiter := make(chan Result, 1)
+	go func() { waiter <- dedup.Process(Task{URL: "a"}) }()
+	time.Sleep(10 * time.Millisecond) // Дубликат успевает встать в ожидание.
+	close(release)
+
+	if r := <-panicked; r != "boom" {
+		t.Errorf("паника %v, ожидалась boom", r)
+	}
+	select {
+	case r := <-waiter:
+		// Дубликат либо дождался ошибки упавшей обработки, либо пришел после нее и выполнился заново.
+		if r.Error == nil && r.StatusCode != 200 {
+			t.Errorf("неожиданный результат дубликата: %+v", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("дубликат заблокирован после паники обработки")
+	}
+
+	// Ключ забыт: следующая задача выполняется заново и успешно.
+	if r := dedup.Process(Task{URL: "a"}); r.Error != nil || r.StatusCode != 200 {
+		t.Errorf("повторная задача: %+v, ожидался успех", r)
+	}
+}
+
+func TestDeduplicatorDistinctKeys(t *testing.T) {
+	var calls atomic.Int32
+	process := func(t Task) Result {
+		calls.Add(1)
+		return Result{URL: t.URL}
+	}
+	dedup := NewDeduplicator(process, func(t Task) string { return t.URL })
+
+	tasks := []Task{{URL: "a"}, {URL: "b"}, {URL: "a"}, {URL: "c"}, {URL: "b"}}
+	results := runPool(tasks, 3, dedup.Process)
+
+	if n := calls.Load(); n != 3 {
+		t.Errorf("обработка запускалась %d раз, ожидалось 3", n)
+	}
+	if len(results) != len(tasks) {
+		t.Errorf("получено %d результатов, ожидалось %d", len(results), len(tasks))
+	}
+}
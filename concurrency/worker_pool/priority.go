@@ -0,0 +1,136 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/andrewhigh08/exp/internal/syncutil"
+)
+
+// errPoolClosed возвращается при попытке отправить задачу в закрытый пул.
+var errPoolClosed = errors.New("пул воркеров закрыт")
+
+// PriorityPool — пул воркеров, который раздает задачи по приоритету, а не в порядке поступления.
+//
+// Вместо FIFO-канала задачи лежат в куче (container/heap), защищенной мьютексом.
+// Свободные воркеры ждут на условной переменной и, проснувшись, забирают задачу
+// с наибольшим приоритетом. Задачи с равным приоритетом выполняются в порядке отправки.
+type PriorityPool[T any] struct {
+	handle func(T)
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  priorityQueue[T]
+	seq    uint64 // Счетчик отправленных задач: сохраняет FIFO внутри одного приоритета.
+	closed bool
+
+	wg sync.WaitGroup
+}
+
+// NewPriorityPool запускает workers воркеров, каждый из которых обрабатывает задачи функцией handle.
+func NewPriorityPool[T any](workers int, handle func(T)) *PriorityPool[T] {
+	p := &PriorityPool[T]{handle: handle}
+	p.cond = sync.NewCond(&p.mu)
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+// SubmitPriority ставит задачу в очередь. Чем больше priority, тем раньше задача будет взята в работу.
+// После Close возвращает errPoolClosed.
+func (p *PriorityPool[T]) SubmitPriority(task T, priority int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return errPoolClosed
+	}
+	heap.Push(&p.queue, priorityItem[T]{task: task, priority: priority, seq: p.seq})
+	p.seq++
+	// Будим одного свободного воркера: задача одна, остальным просыпаться незачем.
+	p.cond.Signal()
+	return nil
+}
+
+// Close прекращает прием задач, дожидается выполнения уже поставленных и завершения воркеров.
+func (p *PriorityPool[T]) Close() {
+	p.stop()
+	p.wg.Wait()
+}
+
+// CloseContext работает как Close, но ждет воркеров только до отмены ctx и тогда
+// возвращает ctx.Err(). Воркеры при этом не прерываются: они доделают очередь в фоне.
+func (p *PriorityPool[T]) CloseContext(ctx context.Context) error {
+	p.stop()
+	return syncutil.WaitContext(ctx, &p.wg)
+}
+
+// stop прекращает прием задач и будит всех воркеров, чтобы они увидели closed
+// и завершились после опустошения очереди.
+func (p *PriorityPool[T]) stop() {
+	p.mu.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.mu.Unlock()
+}
+
+func (p *PriorityPool[T]) worker() {
+	defer p.wg.Done()
+
+	for {
+		p.mu.Lock()
+		// Wait в цикле: после пробуждения очередь может быть уже пуста (задачу забрал другой воркер).
+		for p.queue.Len() == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		if p.queue.Len() == 0 {
+			// Пул закрыт и задач больше нет.
+			p.mu.Unlock()
+			return
+		}
+		item := heap.Pop(&p.queue).(priorityItem[T])
+		p.mu.Unlock()
+
+		// Обработка идет без блокировки, чтобы не мешать отправке и другим воркерам.
+		p.handle(item.task)
+	}
+}
+
+// priorityItem — задача в очереди вместе с приоритетом и порядковым номером отправки.
+type priorityItem[T any] struct {
+	task     T
+	priority int
+	seq      uint64
+}
+
+// priorityQueue реализует heap.Interface: наверху задача с наибольшим приоритетом,
+// а при равенстве — отправленная раньше.
+type priorityQueue[T any] []priorityItem[T]
+
+func (q priorityQueue[T]) Len() int { return len(q) }
+
+func (q priorityQueue[T]) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q priorityQueue[T]) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *priorityQueue[T]) Push(x any) { *q = append(*q, x.(priorityItem[T])) }
+
+func (q *priorityQueue[T]) Pop() any {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	var zero priorityItem[T]
+	old[n-1] = zero // Не держим ссылку на задачу в хвосте среза.
+	*q = old[:n-1]
+	return item
+}
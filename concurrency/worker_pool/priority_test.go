@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPriorityPoolSingleWorkerOrder(t *testing.T) {
+	gate := make(chan struct{})
+	started := make(chan struct{})
+	var mu sync.Mutex
+	var order []string
+
+	pool := NewPriorityPool(1, func(task string) {
+		if task == "blocker" {
+			close(started)
+			<-gate
+			return
+		}
+		mu.Lock()
+		order = append(order, task)
+		mu.Unlock()
+	})
+
+	// Занимаем единственного воркера, чтобы вся пачка успела накопиться в очереди.
+	if err := pool.SubmitPriority("blocker", 0); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	burst := []struct {
+		task     string
+		priority int
+	}{
+		{"low-1", 1},
+		{"high-1", 10},
+		{"mid-1", 5},
+		{"low-2", 1},
+		{"high-2", 10},
+		{"mid-2", 5},
+		{"negative", -1},
+	}
+	for _, b := range burst {
+		if err := pool.SubmitPriority(b.task, b.priority); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(gate)
+	pool.Close()
+
+	want := []string{"high-1", "high-2", "mid-1", "mid-2", "low-1", "low-2", "negative"}
+	if !slices.Equal(order, want) {
+		t.Errorf("порядок обработки %v, ожидался %v", order, want)
+	}
+}
+
+func TestPriorityPoolProcessesAllTasks(t *testing.T) {
+	var processed atomic.Int32
+	pool := NewPriorityPool(4, func(int) { processed.Add(1) })
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 250; i++ {
+				if err := pool.SubmitPriority(i, i%7); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	pool.Close()
+
+	if n := processed.Load(); n != 1000 {
+		t.Errorf("обработано %d задач, ожидалось 1000", n)
+	}
+}
+
+func TestPriorityPoolSubmitAfterClose(t *testing.T) {
+	pool := NewPriorityPool(1, func(int) {})
+	pool.Close()
+	if err := pool.SubmitPriority(1, 1); !errors.Is(err, errPoolClosed) {
+		t.Errorf("SubmitPriority после Close = %v, ожидалась errPoolClosed", err)
+	}
+}
+
+func TestPriorityPoolCloseContext(t *testing.T) {
+	release := make(chan struct{})
+	pool := NewPriorityPool(1, func(int) { <-release })
+	if err := pool.SubmitPriority(1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Задача не завершается, поэтому ожидание ограничено контекстом.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.CloseContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("CloseContext = %v, ожидалось %v", err, context.DeadlineExceeded)
+	}
+
+	close(release)
+	if err := pool.CloseContext(context.Background()); err != nil {
+		t.Errorf("CloseContext после завершения задачи = %v", err)
+	}
+}
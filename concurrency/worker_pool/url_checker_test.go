@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/andrewhigh08/exp/internal/fakehttp"
+)
+
+func TestURLCheckerWithFakeServer(t *testing.T) {
+	srv := fakehttp.New(t, map[string]fakehttp.Route{
+		"/ok":      {Status: http.StatusOK},
+		"/created": {Status: http.StatusCreated, Delay: 10 * time.Millisecond},
+		"/broken":  {Status: http.StatusInternalServerError},
+		"/slow":    {Delay: time.Second},
+	})
+
+	urls := srv.URLs("/ok", "/created", "/broken", "/slow", "/missing")
+	tasks := make([]Task, len(urls))
+	for i, u := range urls {
+		tasks[i] = Task{URL: u}
+	}
+
+	results := runPool(tasks, 3, newURLChecker(100*time.Millisecond))
+	if len(results) != len(tasks) {
+		t.Fatalf("получено %d результатов, ожидалось %d", len(results), len(tasks))
+	}
+
+	byURL := make(map[string]Result, len(results))
+	for _, r := range results {
+		byURL[r.URL] = r
+	}
+	wantStatus := map[string]int{
+		srv.URLFor("/ok"):      http.StatusOK,
+		srv.URLFor("/created"): http.StatusCreated,
+		srv.URLFor("/broken"):  http.StatusInternalServerError,
+		srv.URLFor("/missing"): http.StatusNotFound,
+	}
+	for u, status := range wantStatus {
+		r := byURL[u]
+		if r.Error != nil || r.StatusCode != status {
+			t.Errorf("%s: статус %d, ошибка %v; ожидался %d", u, r.StatusCode, r.Error, status)
+		}
+	}
+	if r := byURL[srv.URLFor("/slow")]; r.Error == nil {
+		t.Errorf("медленный URL должен завершиться таймаутом, получено %+v", r)
+	}
+	if got := byURL[srv.URLFor("/created")].Duration; got < 10*time.Millisecond {
+		t.Errorf("Duration = %v, ожидалось не меньше задержки сервера", got)
+	}
+}
+
+func TestDeduplicatorHitsServerOnce(t *testing.T) {
+	srv := fakehttp.New(t, map[string]fakehttp.Route{"/page": {Delay: 20 * time.Millisecond}})
+	dedup := NewDeduplicator(newURLChecker(time.Second), func(t Task) string { return t.URL })
+
+	u := srv.URLFor("/page")
+	results := runPool([]Task{{URL: u}, {URL: u}, {URL: u}}, 3, dedup.Process)
+
+	if len(results) != 3 {
+		t.Fatalf("получено %d результатов, ожидалось 3", len(results))
+	}
+	if hits := srv.Hits("/page"); hits != 1 {
+		t.Errorf("сервер получил %d запросов, ожидался 1", hits)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Op — тип операции, попавшей в журнал аудита.
+type Op string
+
+const (
+	OpGet  Op = "GET"
+	OpMGet Op = "MGET"
+	OpSet  Op = "SET"
+	OpDel  Op = "DEL"
+	OpCAS  Op = "CAS"
+)
+
+// Event — одна запись журнала аудита кэша.
+//
+// Для GET/MGET поле Hit означает попадание в кэш. Для SET/DEL — что ключ
+// уже был в кэше на момент операции (т.е. значение перезаписано или удалено из кэша).
+// Для CAS — что текущее значение для сравнения взято из кэша, а не загружено из бэкенда.
+type Event struct {
+	Op        Op
+	Key       string
+	Hit       bool
+	Timestamp time.Time
+}
+
+// auditLog — потокобезопасный кольцевой буфер событий фиксированного размера.
+// При переполнении самые старые события перезаписываются новыми.
+type auditLog struct {
+	mu     sync.Mutex
+	events []Event
+	next   int  // Позиция для следующей записи.
+	full   bool // Буфер хотя бы раз заполнился целиком.
+}
+
+func newAuditLog(capacity int) *auditLog {
+	return &auditLog{events: make([]Event, capacity)}
+}
+
+func (a *auditLog) record(e Event) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.events[a.next] = e
+	a.next = (a.next + 1) % len(a.events)
+	if a.next == 0 {
+		a.full = true
+	}
+}
+
+// snapshot возвращает копию событий в хронологическом порядке (от старых к новым).
+func (a *auditLog) snapshot() []Event {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.full {
+		return append([]Event(nil), a.events[:a.next]...)
+	}
+	result := make([]Event, 0, len(a.events))
+	result = append(result, a.events[a.next:]...)
+	return append(result, a.events[:a.next]...)
+}
+
+// EnableAudit включает журнал аудита, хранящий не более capacity последних событий.
+// Аудит выключен по умолчанию, чтобы не платить за него в обычном режиме.
+// Повторный вызов начинает новый пустой журнал.
+func (c *CachedRepository) EnableAudit(capacity int) {
+	if capacity <= 0 {
+		c.audit.Store(nil)
+		return
+	}
+	c.audit.Store(newAuditLog(capacity))
+}
+
+// AuditLog возвращает копию журнала аудита или nil, если аудит не включен.
+func (c *CachedRepository) AuditLog() []Event {
+	if a := c.audit.Load(); a != nil {
+		return a.snapshot()
+	}
+	return nil
+}
+
+// recordEvent добавляет событие в журнал, если аудит включен.
+func (c *CachedRepository) recordEvent(op Op, key string, hit bool) {
+	if a := c.audit.Load(); a != nil {
+		a.record(Event{Op: op, Key: key, Hit: hit, Timestamp: time.Now()})
+	}
+}
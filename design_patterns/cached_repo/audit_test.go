@@ -0,0 +1,91 @@
+package main
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAuditLogRecordsOperations(t *testing.T) {
+	c := NewCachedRepository(newMemRepo(map[string]string{"a": "1", "b": "2"}))
+	c.EnableAudit(16)
+
+	_, _ = c.Get("a")       // miss
+	_, _ = c.Get("a")       // hit
+	_, _ = c.MGet("a", "b") // hit a, miss b
+	_ = c.Set("c", "3")     // c не было в кэше
+	_ = c.Set("a", "10")    // a было в кэше
+	_ = c.Del("b")          // b было в кэше после MGet
+
+	want := []Event{
+		{Op: OpGet, Key: "a", Hit: false},
+		{Op: OpGet, Key: "a", Hit: true},
+		{Op: OpMGet, Key: "a", Hit: true},
+		{Op: OpMGet, Key: "b", Hit: false},
+		{Op: OpSet, Key: "c", Hit: false},
+		{Op: OpSet, Key: "a", Hit: true},
+		{Op: OpDel, Key: "b", Hit: true},
+	}
+
+	got := c.AuditLog()
+	if len(got) != len(want) {
+		t.Fatalf("записано %d событий, ожидалось %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].Op != want[i].Op || got[i].Key != want[i].Key || got[i].Hit != want[i].Hit {
+			t.Errorf("событие %d = %+v, ожидалось %+v", i, got[i], want[i])
+		}
+		if got[i].Timestamp.IsZero() {
+			t.Errorf("событие %d без временной метки", i)
+		}
+	}
+}
+
+func TestAuditLogIsBounded(t *testing.T) {
+	c := NewCachedRepository(newMemRepo(map[string]string{}))
+	c.EnableAudit(3)
+
+	for _, key := range []string{"k1", "k2", "k3", "k4", "k5"} {
+		_ = c.Set(key, "v")
+	}
+
+	got := c.AuditLog()
+	if len(got) != 3 {
+		t.Fatalf("len = %d, ожидалось 3", len(got))
+	}
+	// Остаются три последних события в хронологическом порядке.
+	for i, key := range []string{"k3", "k4", "k5"} {
+		if got[i].Key != key {
+			t.Errorf("событие %d: ключ %q, ожидался %q", i, got[i].Key, key)
+		}
+	}
+}
+
+func TestAuditLogDisabledByDefault(t *testing.T) {
+	c := NewCachedRepository(newMemRepo(map[string]string{"a": "1"}))
+	_, _ = c.Get("a")
+	if log := c.AuditLog(); log != nil {
+		t.Errorf("аудит выключен, но журнал не пуст: %+v", log)
+	}
+}
+
+func TestAuditLogConcurrent(t *testing.T) {
+	c := NewCachedRepository(newMemRepo(map[string]string{"a": "1"}))
+	c.EnableAudit(64)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_, _ = c.Get("a")
+				_ = c.AuditLog()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(c.AuditLog()); got != 64 {
+		t.Errorf("len = %d, ожидалось 64 (буфер заполнен)", got)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ChainedRepository обобщает декоратор CachedRepository на N уровней хранения,
+// например память -> Redis -> база данных. Уровни перечисляются от самого быстрого
+// к самому медленному; последний считается источником истины.
+//
+// Get опрашивает уровни по порядку до первого попадания и заполняет им все более
+// ранние уровни (read-through), так что следующее чтение обслужит самый быстрый.
+// Set и Del применяются ко всем уровням.
+//
+// Сам ChainedRepository состояния не хранит; безопасность для конкурентного
+// использования определяется уровнями.
+type ChainedRepository struct {
+	layers []Repository
+}
+
+// NewChainedRepository создает цепочку из уровней layers (от быстрого к медленному).
+// Паникует, если уровней нет.
+func NewChainedRepository(layers ...Repository) *ChainedRepository {
+	if len(layers) == 0 {
+		panic("NewChainedRepository: нужен хотя бы один уровень")
+	}
+	return &ChainedRepository{layers: layers}
+}
+
+// Get возвращает значение с первого уровня, где оно нашлось, и записывает его во все
+// предыдущие уровни. Ошибки такой дозаписи не возвращаются: значение уже получено,
+// а недозаполненный уровень лишь даст промах при следующем чтении.
+// Если ключа нет ни на одном уровне, возвращается ошибка последнего уровня.
+func (c *ChainedRepository) Get(key string) (string, error) {
+	var err error
+	for i, layer := range c.layers {
+		var value string
+		if value, err = layer.Get(key); err != nil {
+			continue
+		}
+		c.backfill(i, key, value)
+		return value, nil
+	}
+	return "", err
+}
+
+// MGet опрашивает каждый следующий уровень только по ключам, не найденным на предыдущих
+// (см. TierMerge), и дозаписывает найденное в более ранние уровни.
+//
+// Интерфейс Repository не различает пустое значение и отсутствие ключа в ответе MGet,
+// поэтому пустая строка от любого уровня, кроме последнего, считается промахом.
+// Ответ последнего уровня принимается как есть.
+func (c *ChainedRepository) MGet(keys ...string) ([]string, error) {
+	merge := NewTierMerge[string, string](keys)
+	last := len(c.layers) - 1
+	for i, layer := range c.layers {
+		missing := merge.Missing()
+		if len(missing) == 0 {
+			break
+		}
+		values, err := layer.MGet(missing...)
+		if err != nil {
+			return nil, fmt.Errorf("уровень %d: %w", i, err)
+		}
+		for j, value := range values[:min(len(missing), len(values))] {
+			if value == "" && i != last {
+				continue
+			}
+			merge.Set(missing[j], value)
+			c.backfill(i, missing[j], value)
+		}
+	}
+	return merge.Values(), nil
+}
+
+// Set записывает значение во все уровни по порядку. Ошибка одного уровня не мешает
+// записи в остальные; все ошибки объединяются через errors.Join.
+func (c *ChainedRepository) Set(key, value string) error {
+	var errs []error
+	for i, layer := range c.layers {
+		if err := layer.Set(key, value); err != nil {
+			errs = append(errs, fmt.Errorf("уровень %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Del удаляет ключ со всех уровней по порядку; ошибки объединяются, как в Set.
+func (c *ChainedRepository) Del(key string) error {
+	var errs []error
+	for i, layer := range c.layers {
+		if err := layer.Del(key); err != nil {
+			errs = append(errs, fmt.Errorf("уровень %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// backfill записывает значение, найденное на уровне found, во все более ранние уровни.
+func (c *ChainedRepository) backfill(found int, key, value string) {
+	for _, layer := range c.layers[:found] {
+		_ = layer.Set(key, value)
+	}
+}
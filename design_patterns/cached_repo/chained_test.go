@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// newThreeLayers возвращает уровни "память", "redis" и "бд"; данные есть только в последнем.
+func newThreeLayers(data map[string]string) (l1, l2, l3 *countingRepo) {
+	return newCountingRepo(map[string]string{}), newCountingRepo(map[string]string{}), newCountingRepo(data)
+}
+
+// valueIn возвращает значение ключа прямо из данных уровня, минуя счетчики.
+func valueIn(r *countingRepo, key string) (string, bool) {
+	r.memRepo.mu.Lock()
+	defer r.memRepo.mu.Unlock()
+	v, ok := r.data[key]
+	return v, ok
+}
+
+func TestChainedGetBackfillsEarlierLayers(t *testing.T) {
+	l1, l2, l3 := newThreeLayers(map[string]string{"user:1": "John"})
+	chain := NewChainedRepository(l1, l2, l3)
+
+	got, err := chain.Get("user:1")
+	if err != nil || got != "John" {
+		t.Fatalf("Get = %q, %v; ожидалось John", got, err)
+	}
+	for i, layer := range []*countingRepo{l1, l2} {
+		if v, ok := valueIn(layer, "user:1"); !ok || v != "John" {
+			t.Errorf("уровень %d не дозаполнен: %q, %t", i+1, v, ok)
+		}
+	}
+
+	// Второе чтение обслуживает первый уровень, до остальных оно не доходит.
+	if got, _ := chain.Get("user:1"); got != "John" {
+		t.Errorf("повторный Get = %q", got)
+	}
+	if n := l1.loadsOf("user:1"); n != 2 {
+		t.Errorf("обращений к уровню 1: %d, ожидалось 2", n)
+	}
+	if n2, n3 := l2.loadsOf("user:1"), l3.loadsOf("user:1"); n2 != 1 || n3 != 1 {
+		t.Errorf("обращений к уровням 2 и 3: %d и %d, ожидалось по 1", n2, n3)
+	}
+}
+
+func TestChainedGetHitInMiddleLayer(t *testing.T) {
+	l1, l2, l3 := newThreeLayers(map[string]string{})
+	l2.data["k"] = "v"
+	chain := NewChainedRepository(l1, l2, l3)
+
+	if got, err := chain.Get("k"); err != nil || got != "v" {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+	if _, ok := valueIn(l1, "k"); !ok {
+		t.Error("уровень 1 не дозаполнен")
+	}
+	if _, ok := valueIn(l3, "k"); ok {
+		t.Error("значение записано в более медленный уровень")
+	}
+	if n := l3.loadsOf("k"); n != 0 {
+		t.Errorf("обращений к уровню 3: %d, ожидалось 0", n)
+	}
+}
+
+func TestChainedGetMissReturnsLastError(t *testing.T) {
+	chain := NewChainedRepository(newThreeLayers(map[string]string{}))
+	if _, err := chain.Get("nope"); err == nil {
+		t.Error("ожидалась ошибка для отсутствующего ключа")
+	}
+}
+
+func TestChainedMGetBackfills(t *testing.T) {
+	l1, l2, l3 := newThreeLayers(map[string]string{"a": "A", "b": "B"})
+	l1.data["a"] = "A1" // Более ранний уровень важнее.
+	chain := NewChainedRepository(l1, l2, l3)
+
+	got, err := chain.MGet("a", "b", "c", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"A1", "B", "", "B"}; !slices.Equal(got, want) {
+		t.Errorf("MGet = %q, ожидалось %q", got, want)
+	}
+	for i, layer := range []*countingRepo{l1, l2} {
+		if v, _ := valueIn(layer, "b"); v != "B" {
+			t.Errorf("уровень %d не дозаполнен ключом b: %q", i+1, v)
+		}
+	}
+	if n := l3.loadsOf("a"); n != 0 {
+		t.Errorf("ключ a, найденный на уровне 1, запрошен у уровня 3 %d раз", n)
+	}
+}
+
+func TestChainedSetAndDelApplyToAllLayers(t *testing.T) {
+	l1, l2, l3 := newThreeLayers(map[string]string{})
+	chain := NewChainedRepository(l1, l2, l3)
+
+	if err := chain.Set("k", "v"); err != nil {
+		t.Fatal(err)
+	}
+	for i, layer := range []*countingRepo{l1, l2, l3} {
+		if v, _ := valueIn(layer, "k"); v != "v" {
+			t.Errorf("после Set уровень %d содержит %q", i+1, v)
+		}
+	}
+
+	if err := chain.Del("k"); err != nil {
+		t.Fatal(err)
+	}
+	for i, layer := range []*countingRepo{l1, l2, l3} {
+		if _, ok := valueIn(layer, "k"); ok {
+			t.Errorf("после Del ключ остался на уровне %d", i+1)
+		}
+	}
+}
+
+// failingSetRepo — уровень, отклоняющий запись.
+type failingSetRepo struct{ *memRepo }
+
+var errReadOnly = errors.New("read-only")
+
+func (r failingSetRepo) Set(string, string) error { return errReadOnly }
+
+func TestChainedSetContinuesPastFailingLayer(t *testing.T) {
+	l1, l3 := newMemRepo(map[string]string{}), newMemRepo(map[string]string{})
+	chain := NewChainedRepository(l1, failingSetRepo{newMemRepo(map[string]string{})}, l3)
+
+	err := chain.Set("k", "v")
+	if !errors.Is(err, errReadOnly) {
+		t.Errorf("ошибка %v, ожидалась %v", err, errReadOnly)
+	}
+	if l1.data["k"] != "v" || l3.data["k"] != "v" {
+		t.Error("остальные уровни не получили запись")
+	}
+}
+
+func TestNewChainedRepositoryPanicsWithoutLayers(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ожидалась паника без уровней")
+		}
+	}()
+	NewChainedRepository()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Обобщенные кэши (LRUTTLCache, KeyedMutex, TierMerge) принимают любой comparable-ключ,
+// поэтому составной ключ удобнее всего задать структурой:
+//
+//	type regionKey struct {
+//		UserID int
+//		Region string
+//	}
+//	cache := NewLRUTTLCache[regionKey, string](100, time.Minute)
+//
+// Структуры сравниваются по всем полям, так что разные кортежи не совпадут никогда.
+// Поля должны быть сравнимыми по значению: указатель сравнивается по адресу, а поле-интерфейс
+// с несравнимым значением внутри (срезом, картой) вызовет панику при обращении к карте.
+
+// Key2 — готовый составной ключ из двух компонентов для случаев, когда заводить
+// отдельный тип ради ключа не хочется.
+type Key2[A, B comparable] struct {
+	First  A
+	Second B
+}
+
+// MakeKey2 создает составной ключ из двух компонентов.
+func MakeKey2[A, B comparable](a A, b B) Key2[A, B] {
+	return Key2[A, B]{First: a, Second: b}
+}
+
+// CompositeKey строит строковый ключ из нескольких частей для хранилищ со строковыми
+// ключами (CachedRepository, Redis). Каждой части предшествует ее длина, поэтому
+// кодирование однозначно: наивное склеивание через разделитель дало бы одинаковый ключ
+// для ("a:b", "c") и ("a", "b:c"), а CompositeKey — нет.
+func CompositeKey(parts ...string) string {
+	var b strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			b.WriteByte(':')
+		}
+		b.WriteString(strconv.Itoa(len(part)))
+		b.WriteByte('#')
+		b.WriteString(part)
+	}
+	return b.String()
+}
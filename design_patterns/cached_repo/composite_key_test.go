@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// userRegion — составной ключ кэша.
+type userRegion struct {
+	UserID int
+	Region string
+}
+
+func TestLRUTTLStructKey(t *testing.T) {
+	c := NewLRUTTLCache[userRegion, string](10, time.Hour)
+	c.Set(userRegion{UserID: 1, Region: "eu"}, "1-eu")
+	c.Set(userRegion{UserID: 1, Region: "us"}, "1-us")
+	c.Set(userRegion{UserID: 2, Region: "eu"}, "2-eu")
+
+	// Равный кортеж, собранный заново, попадает в кэш.
+	tests := []struct {
+		key  userRegion
+		want string
+	}{
+		{userRegion{1, "eu"}, "1-eu"},
+		{userRegion{1, "us"}, "1-us"},
+		{userRegion{2, "eu"}, "2-eu"},
+	}
+	for _, tt := range tests {
+		if got, ok := c.Get(tt.key); !ok || got != tt.want {
+			t.Errorf("Get(%+v) = %q, %t; ожидалось %q", tt.key, got, ok, tt.want)
+		}
+	}
+	if _, ok := c.Get(userRegion{2, "us"}); ok {
+		t.Error("Get(2, us) нашел запись, которой не было")
+	}
+	if c.Len() != 3 {
+		t.Errorf("Len = %d, ожидалось 3: кортежи не должны совпадать", c.Len())
+	}
+}
+
+func TestKey2(t *testing.T) {
+	c := NewLRUTTLCache[Key2[int, string], int](10, time.Hour)
+	c.Set(MakeKey2(1, "eu"), 1)
+	c.Set(MakeKey2(1, "us"), 2)
+
+	if v, ok := c.Get(MakeKey2(1, "eu")); !ok || v != 1 {
+		t.Errorf("Get(1, eu) = %d, %t", v, ok)
+	}
+	if v, ok := c.Get(MakeKey2(1, "us")); !ok || v != 2 {
+		t.Errorf("Get(1, us) = %d, %t", v, ok)
+	}
+}
+
+func TestCompositeKeyIsUnambiguous(t *testing.T) {
+	tests := []struct {
+		a, b []string
+	}{
+		{[]string{"a:b", "c"}, []string{"a", "b:c"}},
+		{[]string{"1#a"}, []string{"1", "a"}},
+		{[]string{""}, []string{}},
+		{[]string{"", ""}, []string{""}},
+		{[]string{"ab"}, []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		if ka, kb := CompositeKey(tt.a...), CompositeKey(tt.b...); ka == kb {
+			t.Errorf("CompositeKey(%q) == CompositeKey(%q) == %q", tt.a, tt.b, ka)
+		}
+	}
+	if CompositeKey("user", "42", "eu") != CompositeKey("user", "42", "eu") {
+		t.Error("одинаковые части дали разные ключи")
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// HotTierRepository — кэширующий декоратор с ограниченной памятью, который не дает
+// потоку редких ключей вытеснить по-настоящему горячие данные.
+//
+// Кэш состоит из двух уровней (упрощенный гибрид LFU и 2Q):
+//   - основной — LRU на mainCapacity записей; сюда попадает все, что загружено из бэкенда;
+//   - горячий — отдельный небольшой LRU на hotCapacity записей.
+//
+// Отдельно ведется история обращений: счетчик по ключу без значения, тоже в LRU, но
+// в historyFactor раз длиннее обоих уровней вместе. Благодаря ей ключ "помнится" и после
+// вытеснения из основного уровня. Набрав promoteAfter обращений, ключ переезжает в горячий
+// уровень, и дальше поток новых ключей через основной уровень его не вытесняет.
+// Разовое сканирование большого числа ключей ничего не продвигает и вымывает только
+// основной уровень и историю.
+//
+// Горячий уровень тоже ограничен: при переполнении из него выпадает наименее недавно
+// использованный ключ и при следующем обращении снова проходит через основной уровень.
+// TTL действует на обоих уровнях и на историю, так что давние обращения забываются;
+// при переводе в горячий уровень срок жизни значения отсчитывается заново.
+type HotTierRepository struct {
+	repo         Repository
+	promoteAfter int
+
+	// mu делает атомарными составные операции над уровнями (подсчет обращений
+	// и перевод между уровнями). Обращения к бэкенду выполняются без нее.
+	mu      sync.Mutex
+	main    *LRUTTLCache[string, string]
+	hot     *LRUTTLCache[string, string]
+	history *LRUTTLCache[string, int] // Число обращений к ключу, в том числе уже вытесненному.
+}
+
+// historyFactor — во сколько раз история обращений длиннее обоих уровней кэша вместе.
+const historyFactor = 4
+
+// NewHotTierRepository оборачивает repo кэшем из основного уровня на mainCapacity записей
+// и горячего уровня на hotCapacity записей. Ключ попадает в горячий уровень после
+// promoteAfter обращений (включая загрузки из бэкенда). Записи и история живут ttl.
+// Паникует, если емкости или promoteAfter меньше 1.
+func NewHotTierRepository(repo Repository, mainCapacity, hotCapacity, promoteAfter int, ttl time.Duration) *HotTierRepository {
+	if mainCapacity < 1 || hotCapacity < 1 || promoteAfter < 1 {
+		panic("NewHotTierRepository: емкости уровней и promoteAfter должны быть не меньше 1")
+	}
+	return &HotTierRepository{
+		repo:         repo,
+		promoteAfter: promoteAfter,
+		main:         NewLRUTTLCache[string, string](mainCapacity, ttl),
+		hot:          NewLRUTTLCache[string, string](hotCapacity, ttl),
+		history:      NewLRUTTLCache[string, int](historyFactor*(mainCapacity+hotCapacity), ttl),
+	}
+}
+
+// Get возвращает значение из кэша или загружает его из бэкенда.
+func (h *HotTierRepository) Get(key string) (string, error) {
+	h.mu.Lock()
+	value, ok := h.lookupLocked(key)
+	h.mu.Unlock()
+	if ok {
+		return value, nil
+	}
+
+	value, err := h.repo.Get(key)
+	if err != nil {
+		return "", err
+	}
+	h.mu.Lock()
+	h.fillLocked(key, value)
+	h.mu.Unlock()
+	return value, nil
+}
+
+// MGet выполняет пакетное получение: каждый ключ учитывается как обращение (и может быть
+// переведен в горячий уровень), а недостающие в кэше загружаются одним запросом к бэкенду.
+func (h *HotTierRepository) MGet(keys ...string) ([]string, error) {
+	merge := NewTierMerge[string, string](keys)
+
+	h.mu.Lock()
+	for _, key := range merge.Missing() {
+		if value, ok := h.lookupLocked(key); ok {
+			merge.Set(key, value)
+		}
+	}
+	h.mu.Unlock()
+
+	if missingKeys := merge.Missing(); len(missingKeys) > 0 {
+		missingValues, err := h.repo.MGet(missingKeys...)
+		if err != nil {
+			return nil, err
+		}
+		merge.Fill(missingKeys, missingValues)
+
+		h.mu.Lock()
+		for i, value := range missingValues {
+			h.fillLocked(missingKeys[i], value)
+		}
+		h.mu.Unlock()
+	}
+
+	return merge.Values(), nil
+}
+
+// Set обновляет значение в том уровне, где лежит ключ (новый ключ попадает в основной),
+// затем записывает его в бэкенд. Запись не считается обращением.
+func (h *HotTierRepository) Set(key, value string) error {
+	h.mu.Lock()
+	if _, ok := h.hot.Get(key); ok {
+		h.hot.Set(key, value)
+	} else {
+		h.main.Set(key, value)
+	}
+	h.mu.Unlock()
+
+	return h.repo.Set(key, value)
+}
+
+// Del удаляет ключ из обоих уровней кэша и из истории, затем из бэкенда.
+func (h *HotTierRepository) Del(key string) error {
+	h.mu.Lock()
+	h.hot.Delete(key)
+	h.main.Delete(key)
+	h.history.Delete(key)
+	h.mu.Unlock()
+
+	return h.repo.Del(key)
+}
+
+// HotKeys возвращает отсортированный список ключей горячего уровня.
+func (h *HotTierRepository) HotKeys() []string {
+	keys := h.hot.Keys()
+	slices.Sort(keys)
+	return keys
+}
+
+// lookupLocked ищет ключ сначала в горячем, затем в основном уровне. Попадание в основной
+// уровень учитывается как обращение и может перевести ключ в горячий. Промах не учитывается:
+// это сделает fillLocked после загрузки. Вызывается под h.mu.
+func (h *HotTierRepository) lookupLocked(key string) (string, bool) {
+	if value, ok := h.hot.Get(key); ok {
+		return value, true
+	}
+	value, ok := h.main.Get(key)
+	if !ok {
+		return "", false
+	}
+	if h.touchLocked(key) {
+		h.main.Delete(key)
+		h.hot.Set(key, value)
+	}
+	return value, true
+}
+
+// fillLocked кладет загруженное из бэкенда значение в кэш; загрузка учитывается как
+// обращение. Если ключ уже горячий (его перевел параллельный запрос) или стал горячим
+// сейчас, значение попадает в горячий уровень. Вызывается под h.mu.
+func (h *HotTierRepository) fillLocked(key, value string) {
+	_, hot := h.hot.Get(key)
+	if h.touchLocked(key) || hot {
+		h.main.Delete(key)
+		h.hot.Set(key, value)
+		return
+	}
+	h.main.Set(key, value)
+}
+
+// touchLocked учитывает обращение к ключу и сообщает, набрал ли он promoteAfter
+// обращений. Вызывается под h.mu.
+func (h *HotTierRepository) touchLocked(key string) bool {
+	hits, _ := h.history.Get(key)
+	hits++
+	h.history.Set(key, hits)
+	return hits >= h.promoteAfter
+}
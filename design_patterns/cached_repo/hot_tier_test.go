@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingRepo считает обращения к бэкенду по ключам.
+type countingRepo struct {
+	*memRepo
+	mu    sync.Mutex
+	loads map[string]int
+}
+
+func newCountingRepo(data map[string]string) *countingRepo {
+	return &countingRepo{memRepo: newMemRepo(data), loads: make(map[string]int)}
+}
+
+func (r *countingRepo) Get(key string) (string, error) {
+	r.count(key)
+	return r.memRepo.Get(key)
+}
+
+func (r *countingRepo) MGet(keys ...string) ([]string, error) {
+	for _, key := range keys {
+		r.count(key)
+	}
+	return r.memRepo.MGet(keys...)
+}
+
+func (r *countingRepo) count(key string) {
+	r.mu.Lock()
+	r.loads[key]++
+	r.mu.Unlock()
+}
+
+func (r *countingRepo) loadsOf(key string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.loads[key]
+}
+
+// skewedData возвращает hot горячих и cold холодных ключей со значениями.
+func skewedData(hot, cold int) (hotKeys, coldKeys []string, data map[string]string) {
+	data = make(map[string]string)
+	for i := 0; i < hot; i++ {
+		key := fmt.Sprintf("hot:%d", i)
+		hotKeys = append(hotKeys, key)
+		data[key] = "v-" + key
+	}
+	for i := 0; i < cold; i++ {
+		key := fmt.Sprintf("cold:%d", i)
+		coldKeys = append(coldKeys, key)
+		data[key] = "v-" + key
+	}
+	return hotKeys, coldKeys, data
+}
+
+// runSkewed выполняет серию пакетных запросов: в каждом все горячие ключи и по perRound
+// новых холодных ключей, которых больше, чем помещается в основной уровень.
+func runSkewed(t *testing.T, repo *HotTierRepository, hotKeys, coldKeys []string, perRound int) {
+	t.Helper()
+	for i := 0; i+perRound <= len(coldKeys); i += perRound {
+		batch := append(slices.Clone(hotKeys), coldKeys[i:i+perRound]...)
+		if _, err := repo.MGet(batch...); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHotTierSurvivesColdChurn(t *testing.T) {
+	hotKeys, coldKeys, data := skewedData(3, 160)
+	backend := newCountingRepo(data)
+	repo := NewHotTierRepository(backend, 4, 4, 3, time.Hour)
+
+	runSkewed(t, repo, hotKeys, coldKeys, 8) // 20 раундов.
+
+	if got := repo.HotKeys(); !slices.Equal(got, hotKeys) {
+		t.Fatalf("HotKeys = %v, ожидалось %v", got, hotKeys)
+	}
+	// Горячий ключ загружается, пока не наберет promoteAfter обращений, а дальше
+	// читается только из горячего уровня.
+	for _, key := range hotKeys {
+		if n := backend.loadsOf(key); n > 3 {
+			t.Errorf("%s загружен из бэкенда %d раз, ожидалось не больше 3", key, n)
+		}
+	}
+
+	// Холодные ключи вытеснены из основного уровня и загружаются заново.
+	before := backend.loadsOf(coldKeys[0])
+	if _, err := repo.Get(coldKeys[0]); err != nil {
+		t.Fatal(err)
+	}
+	if backend.loadsOf(coldKeys[0]) != before+1 {
+		t.Errorf("холодный ключ %s не был вытеснен", coldKeys[0])
+	}
+}
+
+func TestHotTierWithoutPromotionChurns(t *testing.T) {
+	// Для сравнения: при недостижимом пороге горячий уровень пуст, и те же
+	// горячие ключи вымываются холодными в каждом раунде.
+	hotKeys, coldKeys, data := skewedData(3, 160)
+	backend := newCountingRepo(data)
+	repo := NewHotTierRepository(backend, 4, 4, 1000, time.Hour)
+
+	runSkewed(t, repo, hotKeys, coldKeys, 8)
+
+	if got := repo.HotKeys(); len(got) != 0 {
+		t.Fatalf("HotKeys = %v, ожидался пустой список", got)
+	}
+	if n := backend.loadsOf(hotKeys[0]); n != 20 {
+		t.Errorf("%s загружен %d раз, ожидалась загрузка в каждом из 20 раундов", hotKeys[0], n)
+	}
+}
+
+func TestHotTierScanDoesNotPromote(t *testing.T) {
+	_, coldKeys, data := skewedData(0, 50)
+	repo := NewHotTierRepository(newCountingRepo(data), 10, 4, 2, time.Hour)
+
+	// Однократный проход по всем ключам ничего не переводит в горячий уровень.
+	for _, key := range coldKeys {
+		if _, err := repo.Get(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := repo.HotKeys(); len(got) != 0 {
+		t.Errorf("после сканирования HotKeys = %v, ожидался пустой список", got)
+	}
+}
+
+func TestHotTierSetAndDel(t *testing.T) {
+	backend := newCountingRepo(map[string]string{"k": "v1"})
+	repo := NewHotTierRepository(backend, 4, 2, 2, time.Hour)
+
+	repo.Get("k")
+	repo.Get("k") // Второе обращение переводит ключ в горячий уровень.
+	if got := repo.HotKeys(); !slices.Equal(got, []string{"k"}) {
+		t.Fatalf("HotKeys = %v, ожидалось [k]", got)
+	}
+
+	if err := repo.Set("k", "v2"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := repo.Get("k"); v != "v2" {
+		t.Errorf("после Set Get = %q, ожидалось v2", v)
+	}
+	if n := backend.loadsOf("k"); n != 1 {
+		t.Errorf("обращений к бэкенду %d, горячий ключ должен читаться из кэша", n)
+	}
+
+	if err := repo.Del("k"); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.HotKeys(); len(got) != 0 {
+		t.Errorf("после Del HotKeys = %v", got)
+	}
+	if _, err := repo.Get("k"); err == nil {
+		t.Error("после Del ключ должен отсутствовать и в кэше, и в бэкенде")
+	}
+}
+
+func TestHotTierIsBounded(t *testing.T) {
+	hotKeys, _, data := skewedData(5, 0)
+	repo := NewHotTierRepository(newCountingRepo(data), 8, 2, 1, time.Hour)
+
+	for _, key := range hotKeys {
+		repo.Get(key)
+	}
+	// При promoteAfter == 1 каждый ключ сразу горячий, но уровень держит только два последних.
+	if got, want := repo.HotKeys(), []string{"hot:3", "hot:4"}; !slices.Equal(got, want) {
+		t.Errorf("HotKeys = %v, ожидалось %v", got, want)
+	}
+}
+
+func TestHotTierConcurrent(t *testing.T) {
+	hotKeys, coldKeys, data := skewedData(4, 100)
+	repo := NewHotTierRepository(newCountingRepo(data), 8, 8, 3, time.Hour)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := hotKeys[i%len(hotKeys)]
+				if i%3 == 0 {
+					key = coldKeys[(g*31+i)%len(coldKeys)]
+				}
+				v, err := repo.Get(key)
+				if err != nil || v != "v-"+key {
+					t.Errorf("Get(%s) = (%q, %v)", key, v, err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for _, key := range hotKeys {
+		if !slices.Contains(repo.HotKeys(), key) {
+			t.Errorf("%s не попал в горячий уровень: %v", key, repo.HotKeys())
+		}
+	}
+}
+
+func TestNewHotTierRepositoryPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ожидалась паника при нулевой емкости горячего уровня")
+		}
+	}()
+	NewHotTierRepository(newMemRepo(nil), 4, 0, 2, time.Hour)
+}
@@ -0,0 +1,66 @@
+package main
+
+import "sync"
+
+// KeyedMutex — набор независимых блокировок по ключу: операции над разными ключами
+// не конкурируют друг с другом, а над одним ключом — выполняются по очереди.
+//
+// Типичное применение — read-modify-write поверх Repository, где общий мьютекс
+// сериализовал бы все ключи сразу. Мьютексы создаются по требованию и удаляются,
+// когда их никто не держит и не ждет (счетчик ссылок), поэтому память не растет
+// с числом когда-либо использованных ключей.
+//
+// Нулевое значение готово к использованию. KeyedMutex нельзя копировать после первого использования.
+type KeyedMutex[K comparable] struct {
+	mu    sync.Mutex
+	locks map[K]*keyedLock
+}
+
+// keyedLock — мьютекс ключа и число горутин, которые его держат или ждут.
+type keyedLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// Lock захватывает блокировку ключа k, ожидая, если она занята.
+func (km *KeyedMutex[K]) Lock(k K) {
+	km.mu.Lock()
+	if km.locks == nil {
+		km.locks = make(map[K]*keyedLock)
+	}
+	l, ok := km.locks[k]
+	if !ok {
+		l = &keyedLock{}
+		km.locks[k] = l
+	}
+	// Ссылку учитываем до ожидания: иначе Unlock текущего владельца мог бы удалить запись.
+	l.refs++
+	km.mu.Unlock()
+
+	l.mu.Lock()
+}
+
+// Unlock освобождает блокировку ключа k. Как и у sync.Mutex, вызов для
+// незахваченного ключа — ошибка программы и приводит к панике.
+func (km *KeyedMutex[K]) Unlock(k K) {
+	km.mu.Lock()
+	l, ok := km.locks[k]
+	if !ok {
+		km.mu.Unlock()
+		panic("KeyedMutex: Unlock незахваченного ключа")
+	}
+	l.refs--
+	if l.refs == 0 {
+		delete(km.locks, k)
+	}
+	km.mu.Unlock()
+
+	l.mu.Unlock()
+}
+
+// Len возвращает количество ключей, блокировки которых сейчас удерживаются или ожидаются.
+func (km *KeyedMutex[K]) Len() int {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	return len(km.locks)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeyedMutexSameKeySerializes(t *testing.T) {
+	var km KeyedMutex[string]
+	var inside, maxInside atomic.Int32
+	counter := 0 // Защищен только km: гонку поймает -race, если блокировка не работает.
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			km.Lock("user:1")
+			defer km.Unlock("user:1")
+
+			n := inside.Add(1)
+			if n > maxInside.Load() {
+				maxInside.Store(n)
+			}
+			counter++
+			time.Sleep(time.Millisecond)
+			inside.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	if counter != 20 {
+		t.Errorf("counter = %d, ожидалось 20", counter)
+	}
+	if got := maxInside.Load(); got != 1 {
+		t.Errorf("одновременно в критической секции было %d горутин, ожидалась 1", got)
+	}
+}
+
+func TestKeyedMutexDistinctKeysDoNotContend(t *testing.T) {
+	var km KeyedMutex[int]
+
+	// Держим блокировку ключа 1 — операции над другими ключами не должны ее ждать.
+	km.Lock(1)
+	defer km.Unlock(1)
+
+	const keys = 10
+	var wg sync.WaitGroup
+	all := make(chan struct{})
+	var entered atomic.Int32
+	for k := 2; k < 2+keys; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			km.Lock(k)
+			defer km.Unlock(k)
+			// Каждая горутина ждет, пока все остальные тоже окажутся в своих секциях:
+			// это возможно, только если разные ключи блокируются независимо.
+			if entered.Add(1) == keys {
+				close(all)
+			}
+			<-all
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("операции над разными ключами блокируют друг друга")
+	}
+}
+
+func TestKeyedMutexReclaimsUnusedKeys(t *testing.T) {
+	var km KeyedMutex[string]
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := []string{"a", "b", "c"}[i%3]
+			km.Lock(key)
+			km.Unlock(key)
+		}()
+	}
+	wg.Wait()
+
+	if km.Len() != 0 {
+		t.Errorf("Len = %d после освобождения всех блокировок, ожидалось 0", km.Len())
+	}
+
+	km.Lock("x")
+	if km.Len() != 1 {
+		t.Errorf("Len = %d при удерживаемой блокировке, ожидался 1", km.Len())
+	}
+	km.Unlock("x")
+}
+
+func TestKeyedMutexUnlockUnlockedPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Unlock незахваченного ключа должен паниковать")
+		}
+	}()
+	var km KeyedMutex[string]
+	km.Unlock("nope")
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRUTTLCache — потокобезопасный кэш, совмещающий две стратегии вытеснения:
+//   - LRU: при превышении capacity удаляется запись, к которой дольше всего не обращались;
+//   - TTL: запись считается отсутствующей, если с момента Set прошло больше ttl.
+//
+// Срабатывает то условие, которое наступит раньше. Просроченные записи удаляются
+// лениво — при обращении к ним, а также в Len.
+//
+// Это базовый примитив, на котором можно построить ограниченную по памяти версию CachedRepository.
+type LRUTTLCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	ll       *list.List          // Фронт списка — самая "свежая" запись, хвост — кандидат на вытеснение.
+	items    map[K]*list.Element // Быстрый поиск элемента списка по ключу.
+	now      func() time.Time    // Источник времени; подменяется в тестах.
+
+	onEvict func(key K, value V) // Колбэк удаления записи (nil — не задан).
+	removed []*lruEntry[K, V]    // Удаленные под c.mu записи, ожидающие вызова onEvict.
+}
+
+// lruEntry — значение, хранимое в элементе списка.
+type lruEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// NewLRUTTLCache создает кэш не более чем на capacity записей, каждая из которых живет ttl.
+func NewLRUTTLCache[K comparable, V any](capacity int, ttl time.Duration) *LRUTTLCache[K, V] {
+	return &LRUTTLCache[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		ll:       list.New(),
+		items:    make(map[K]*list.Element),
+		now:      time.Now,
+	}
+}
+
+// OnEvict задает колбэк, который вызывается для каждой удаленной записи: при вытеснении
+// по LRU, удалении просроченной записи и явном Delete. Это позволяет освободить ресурс,
+// хранящийся в значении (соединение, файл). При перезаписи ключа через Set колбэк
+// получает прежнее значение: оно тоже покидает кэш.
+//
+// Колбэк вызывается после снятия блокировки кэша, поэтому может снова обращаться к кэшу.
+// fn == nil отключает колбэк.
+func (c *LRUTTLCache[K, V]) OnEvict(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onEvict = fn
+}
+
+// unlock снимает c.mu и вызывает onEvict для записей, удаленных под блокировкой.
+// Публичные методы снимают блокировку через него, а не через c.mu.Unlock.
+func (c *LRUTTLCache[K, V]) unlock() {
+	removed, onEvict := c.removed, c.onEvict
+	c.removed = nil
+	c.mu.Unlock()
+
+	if onEvict == nil {
+		return
+	}
+	for _, entry := range removed {
+		onEvict(entry.key, entry.value)
+	}
+}
+
+// Get возвращает значение по ключу и отмечает запись как недавно использованную.
+// Обращение не продлевает TTL: срок жизни отсчитывается от последнего Set.
+func (c *LRUTTLCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var zero V
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	entry := el.Value.(*lruEntry[K, V])
+	if c.expired(entry) {
+		c.removeElement(el)
+		return zero, false
+	}
+	c.ll.MoveToFront(el)
+	return entry.value, true
+}
+
+// Set добавляет или обновляет запись, заново запуская ее TTL.
+// Если кэш переполнен, вытесняется наименее недавно использованная запись.
+func (c *LRUTTLCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*lruEntry[K, V])
+		if c.onEvict != nil {
+			// Запись в списке переиспользуется, поэтому колбэку отдаем копию со старым значением.
+			c.removed = append(c.removed, &lruEntry[K, V]{key: key, value: entry.value})
+		}
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&lruEntry[K, V]{key: key, value: value, expiresAt: expiresAt})
+	if c.ll.Len() > c.capacity {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// Delete удаляет запись. Возвращает true, если запись была в кэше.
+func (c *LRUTTLCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.removeElement(el)
+	return true
+}
+
+// Len возвращает количество непросроченных записей, попутно удаляя просроченные.
+func (c *LRUTTLCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for el := c.ll.Back(); el != nil; {
+		prev := el.Prev()
+		if c.expired(el.Value.(*lruEntry[K, V])) {
+			c.removeElement(el)
+		}
+		el = prev
+	}
+	return c.ll.Len()
+}
+
+// Keys возвращает ключи непросроченных записей от самой недавно использованной
+// к наименее недавно использованной, попутно удаляя просроченные.
+func (c *LRUTTLCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := make([]K, 0, c.ll.Len())
+	for el := c.ll.Front(); el != nil; {
+		next := el.Next()
+		entry := el.Value.(*lruEntry[K, V])
+		if c.expired(entry) {
+			c.removeElement(el)
+		} else {
+			keys = append(keys, entry.key)
+		}
+		el = next
+	}
+	return keys
+}
+
+// expired сообщает, истек ли срок жизни записи. Вызывается под c.mu.
+func (c *LRUTTLCache[K, V]) expired(entry *lruEntry[K, V]) bool {
+	return !c.now().Before(entry.expiresAt)
+}
+
+// removeElement удаляет элемент из списка и индекса и откладывает вызов onEvict
+// до снятия блокировки (см. unlock). Вызывается под c.mu.
+func (c *LRUTTLCache[K, V]) removeElement(el *list.Element) {
+	entry := el.Value.(*lruEntry[K, V])
+	c.ll.Remove(el)
+	delete(c.items, entry.key)
+	if c.onEvict != nil {
+		c.removed = append(c.removed, entry)
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock — управляемые часы для детерминированных тестов TTL.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.t
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.t = f.t.Add(d)
+}
+
+func newTestLRU[K comparable, V any](capacity int, ttl time.Duration, clock *fakeClock) *LRUTTLCache[K, V] {
+	c := NewLRUTTLCache[K, V](capacity, ttl)
+	c.now = clock.Now
+	return c
+}
+
+func TestLRUTTLExpiresBeforeEviction(t *testing.T) {
+	clock := newFakeClock()
+	c := newTestLRU[string, int](10, time.Minute, clock)
+
+	c.Set("a", 1)
+	clock.Advance(30 * time.Second)
+	c.Set("b", 2)
+
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %v, %v до истечения TTL", v, ok)
+	}
+
+	clock.Advance(45 * time.Second) // a прожила 75с > ttl, b — 45с.
+	if _, ok := c.Get("a"); ok {
+		t.Error("a должна была истечь, хотя место в кэше есть")
+	}
+	if v, ok := c.Get("b"); !ok || v != 2 {
+		t.Errorf("Get(b) = %v, %v: b еще не истекла", v, ok)
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len = %d, ожидалось 1", c.Len())
+	}
+
+	// Повторный Set продлевает TTL.
+	c.Set("b", 3)
+	clock.Advance(50 * time.Second)
+	if v, ok := c.Get("b"); !ok || v != 3 {
+		t.Errorf("Get(b) = %v, %v после продления TTL", v, ok)
+	}
+}
+
+func TestLRUTTLEvictsBeforeExpiry(t *testing.T) {
+	clock := newFakeClock()
+	c := newTestLRU[string, int](2, time.Hour, clock)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")    // a становится самой свежей, b — кандидат на вытеснение.
+	c.Set("c", 3) // Переполнение: вытесняется b, хотя ее TTL еще далеко.
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b должна была быть вытеснена по LRU")
+	}
+	for _, k := range []string{"a", "c"} {
+		if _, ok := c.Get(k); !ok {
+			t.Errorf("%s не должна была быть вытеснена", k)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, ожидалось 2", c.Len())
+	}
+}
+
+func TestLRUTTLDelete(t *testing.T) {
+	c := NewLRUTTLCache[int, string](2, time.Hour)
+	c.Set(1, "one")
+	if !c.Delete(1) {
+		t.Error("Delete(1) = false для существующего ключа")
+	}
+	if c.Delete(1) {
+		t.Error("повторный Delete(1) = true")
+	}
+	if _, ok := c.Get(1); ok {
+		t.Error("ключ найден после Delete")
+	}
+}
+
+func TestLRUTTLConcurrent(t *testing.T) {
+	c := NewLRUTTLCache[string, int](50, time.Second)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := fmt.Sprintf("k%d", (g*31+i)%100)
+				c.Set(key, i)
+				c.Get(key)
+				if i%10 == 0 {
+					c.Delete(key)
+					c.Len()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if n := c.Len(); n > 50 {
+		t.Errorf("Len = %d превышает емкость 50", n)
+	}
+}
+
+// evictRecorder запоминает вызовы колбэка OnEvict.
+type evictRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *evictRecorder) record(key string, value int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, fmt.Sprintf("%s=%d", key, value))
+}
+
+func (r *evictRecorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := r.calls
+	r.calls = nil
+	return calls
+}
+
+func TestLRUTTLOnEvict(t *testing.T) {
+	clock := newFakeClock()
+	c := newTestLRU[string, int](2, time.Minute, clock)
+	var rec evictRecorder
+	c.OnEvict(rec.record)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("b", 20) // Перезапись освобождает прежнее значение.
+	if calls := rec.take(); !slices.Equal(calls, []string{"b=2"}) {
+		t.Errorf("перезапись: вызовы %v, ожидалось [b=2]", calls)
+	}
+
+	c.Set("c", 3) // Вытеснение по LRU.
+	if calls := rec.take(); !slices.Equal(calls, []string{"a=1"}) {
+		t.Errorf("вытеснение: вызовы %v, ожидалось [a=1]", calls)
+	}
+
+	c.Delete("b")
+	c.Delete("b") // Повторное удаление колбэк не вызывает.
+	if calls := rec.take(); !slices.Equal(calls, []string{"b=20"}) {
+		t.Errorf("Delete: вызовы %v, ожидалось [b=20]", calls)
+	}
+
+	clock.Advance(time.Minute) // Истечение TTL.
+	c.Get("c")
+	c.Get("c")
+	c.Len()
+	if calls := rec.take(); !slices.Equal(calls, []string{"c=3"}) {
+		t.Errorf("истечение TTL: вызовы %v, ожидалось [c=3]", calls)
+	}
+}
+
+func TestLRUTTLOnEvictCanReenterCache(t *testing.T) {
+	c := NewLRUTTLCache[string, int](1, time.Hour)
+	// Колбэк обращается к кэшу: под блокировкой это был бы дедлок.
+	c.OnEvict(func(key string, value int) {
+		c.Len()
+		c.Get(key)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Set("a", 1)
+		c.Set("b", 2)
+		c.Delete("b")
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("дедлок при обращении к кэшу из колбэка")
+	}
+}
+
+func TestLRUTTLKeys(t *testing.T) {
+	clock := newFakeClock()
+	c := newTestLRU[string, int](3, time.Minute, clock)
+	c.Set("a", 1)
+	clock.Advance(30 * time.Second)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("b")
+
+	if got, want := c.Keys(), []string{"b", "c", "a"}; !slices.Equal(got, want) {
+		t.Fatalf("Keys = %v, ожидалось %v", got, want)
+	}
+
+	clock.Advance(40 * time.Second) // "a" просрочена.
+	if got, want := c.Keys(), []string{"b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("Keys после истечения TTL = %v, ожидалось %v", got, want)
+	}
+}
@@ -9,9 +9,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -24,6 +26,9 @@ type Repository interface {
 	Del(key string) error
 }
 
+// errNilBackend возвращается при попытке подменить бэкенд на nil.
+var errNilBackend = errors.New("новый бэкенд репозитория не может быть nil")
+
 // --- Декоратор: Кэширующий репозиторий ---
 
 // CachedRepository — это декоратор, который добавляет кэширование.
@@ -32,6 +37,10 @@ type CachedRepository struct {
 	repo  Repository        // Оборачиваемый репозиторий (например, БД)
 	cache map[string]string // In-memory кэш
 	mu    sync.RWMutex      // Мьютекс для потокобезопасного доступа к кэшу
+	gen   uint64            // Поколение бэкенда: растет при каждом SwapBackend
+
+	keys  KeyedMutex[string]       // Упорядочивает записи (Set, Del, CompareAndSwap) по одному ключу
+	audit atomic.Pointer[auditLog] // Журнал аудита (nil — аудит выключен)
 }
 
 // NewCachedRepository создает новый экземпляр кэширующего репозитория.
@@ -54,21 +63,30 @@ func (c *CachedRepository) Get(key string) (string, error) {
 	if value, ok := c.cache[key]; ok {
 		c.mu.RUnlock()
 		fmt.Printf("[CACHE HIT] Get key: %s\n", key)
+		c.recordEvent(OpGet, key, true)
 		return value, nil
 	}
+	// Запоминаем текущий бэкенд под той же блокировкой: если параллельно произойдет
+	// SwapBackend, мы корректно дочитаем из старого, но никогда не получим nil.
+	repo, gen := c.repo, c.gen
 	// Важно отпустить блокировку чтения перед тем, как делать что-то еще.
 	c.mu.RUnlock()
 
 	fmt.Printf("[CACHE MISS] Get key: %s -> fetching from DB\n", key)
+	c.recordEvent(OpGet, key, false)
 	// Если в кэше нет, загружаем из основного репозитория.
-	value, err := c.repo.Get(key)
+	value, err := repo.Get(key)
 	if err != nil {
 		return "", err
 	}
 
-	// Сохраняем значение в кэше с эксклюзивной блокировкой на запись.
+	// Сохраняем значение в кэше с эксклюзивной блокировкой на запись, но только если
+	// бэкенд не сменился за время загрузки: иначе значение старого бэкенда вернулось бы
+	// в кэш, который SwapBackend мог очистить.
 	c.mu.Lock()
-	c.cache[key] = value
+	if c.gen == gen {
+		c.cache[key] = value
+	}
 	c.mu.Unlock()
 
 	return value, nil
@@ -76,67 +94,157 @@ func (c *CachedRepository) Get(key string) (string, error) {
 
 // MGet выполняет пакетное получение данных.
 // Он эффективно находит ключи, которых нет в кэше, и запрашивает только их.
+// Кэш и основной репозиторий — два уровня, результаты которых объединяет TierMerge.
 func (c *CachedRepository) MGet(keys ...string) ([]string, error) {
-	results := make([]string, len(keys))
-	missingKeys := make([]string, 0)
-	// Создаем карту для быстрого поиска индекса ключа, чтобы избежать вложенного цикла.
-	keyIndexMap := make(map[string]int, len(keys))
-	for i, key := range keys {
-		keyIndexMap[key] = i
-	}
+	merge := NewTierMerge[string, string](keys)
 
 	c.mu.RLock()
-	for _, key := range keys {
+	for _, key := range merge.Missing() {
 		if value, ok := c.cache[key]; ok {
 			fmt.Printf("[CACHE HIT] MGet key: %s\n", key)
-			results[keyIndexMap[key]] = value
+			c.recordEvent(OpMGet, key, true)
+			merge.Set(key, value)
 		} else {
 			fmt.Printf("[CACHE MISS] MGet key: %s\n", key)
-			missingKeys = append(missingKeys, key)
+			c.recordEvent(OpMGet, key, false)
 		}
 	}
+	repo, gen := c.repo, c.gen
 	c.mu.RUnlock()
 
-	if len(missingKeys) > 0 {
+	if missingKeys := merge.Missing(); len(missingKeys) > 0 {
 		fmt.Printf("MGet fetching %d missing keys from DB: %v\n", len(missingKeys), missingKeys)
-		missingValues, err := c.repo.MGet(missingKeys...)
+		missingValues, err := repo.MGet(missingKeys...)
 		if err != nil {
 			return nil, err
 		}
+		merge.Fill(missingKeys, missingValues)
 
+		// Как и в Get, не кэшируем ответ бэкенда, смененного за время загрузки.
 		c.mu.Lock()
-		for i, value := range missingValues {
-			key := missingKeys[i]
-			c.cache[key] = value
-			results[keyIndexMap[key]] = value
+		if c.gen == gen {
+			for i, value := range missingValues {
+				c.cache[missingKeys[i]] = value
+			}
 		}
 		c.mu.Unlock()
 	}
 
-	return results, nil
+	return merge.Values(), nil
 }
 
 // Set реализует стратегию "Write-Through" (с некоторыми упрощениями).
 // Сначала обновляем кэш, затем основное хранилище.
 func (c *CachedRepository) Set(key, value string) error {
 	fmt.Printf("Set key: %s. Updating cache and DB.\n", key)
+	c.keys.Lock(key)
+	defer c.keys.Unlock(key)
 	c.mu.Lock()
+	_, existed := c.cache[key]
 	c.cache[key] = value
+	repo := c.repo
 	c.mu.Unlock()
+	c.recordEvent(OpSet, key, existed)
 
 	// Передаем вызов дальше, в основной репозиторий.
-	return c.repo.Set(key, value)
+	return repo.Set(key, value)
 }
 
 // Del реализует стратегию "Write-Through" для удаления.
 // Сначала удаляем из кэша, затем из основного хранилища.
 func (c *CachedRepository) Del(key string) error {
 	fmt.Printf("Del key: %s. Deleting from cache and DB.\n", key)
+	c.keys.Lock(key)
+	defer c.keys.Unlock(key)
 	c.mu.Lock()
+	_, existed := c.cache[key]
 	delete(c.cache, key)
+	repo := c.repo
 	c.mu.Unlock()
+	c.recordEvent(OpDel, key, existed)
 
-	return c.repo.Del(key)
+	return repo.Del(key)
+}
+
+// CompareAndSwap записывает new, только если текущее значение ключа равно old, и сообщает,
+// произошла ли замена. Это позволяет клиентам реализовать оптимистичную блокировку:
+// прочитать значение, вычислить новое и записать его, только если никто не успел изменить ключ.
+//
+// Если ключа нет в кэше, значение сначала загружается из бэкенда (как в Get) и кэшируется;
+// ошибка загрузки возвращается как есть. Совпадение с кэшем перепроверяется по бэкенду,
+// ведь его могли изменить в обход кэша; при расхождении кэш обновляется значением из бэкенда
+// и замена не выполняется.
+//
+// Операция держит блокировку своего ключа (см. KeyedMutex): Set, Del и другие
+// CompareAndSwap по тому же ключу ждут ее завершения и не могут вклиниться между
+// сравнением и записью. Общий мьютекс кэша на время обращений к бэкенду не удерживается,
+// поэтому чтения и записи других ключей не ждут. Если запись в бэкенд не удалась, ключ
+// удаляется из кэша, чтобы следующий Get перечитал актуальное значение.
+func (c *CachedRepository) CompareAndSwap(key, old, new string) (bool, error) {
+	c.keys.Lock(key)
+	defer c.keys.Unlock(key)
+
+	c.mu.RLock()
+	current, cached := c.cache[key]
+	repo, gen := c.repo, c.gen
+	c.mu.RUnlock()
+	c.recordEvent(OpCAS, key, cached)
+	if cached && current != old {
+		return false, nil
+	}
+
+	// Промах кэша или совпадение с кэшем: значение определяет бэкенд.
+	current, err := repo.Get(key)
+	if err != nil {
+		return false, err
+	}
+	if current != old {
+		c.store(gen, key, current)
+		return false, nil
+	}
+
+	fmt.Printf("CAS key: %s. Updating cache and DB.\n", key)
+	if err := repo.Set(key, new); err != nil {
+		c.mu.Lock()
+		delete(c.cache, key)
+		c.mu.Unlock()
+		return false, err
+	}
+	c.store(gen, key, new)
+	return true, nil
+}
+
+// store кэширует значение, если бэкенд не сменился с поколения gen (см. Get).
+func (c *CachedRepository) store(gen uint64, key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen == gen {
+		c.cache[key] = value
+	}
+}
+
+// SwapBackend атомарно подменяет оборачиваемый репозиторий, например,
+// при blue/green-миграции на новую базу данных.
+//
+// Замена происходит под блокировкой на запись, поэтому конкурентные вызовы
+// видят либо старый, либо новый бэкенд. По умолчанию кэш сохраняется ("теплый" кэш
+// переживает переключение); если clearCache == true, кэш очищается вместе с заменой.
+// Значения, загруженные из старого бэкенда уже после замены, в кэш не попадают.
+func (c *CachedRepository) SwapBackend(newRepo Repository, clearCache bool) error {
+	if newRepo == nil {
+		return errNilBackend
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.repo = newRepo
+	c.gen++ // Загрузки, начатые до замены, не попадут в кэш.
+	if clearCache {
+		c.cache = make(map[string]string)
+	}
+	fmt.Printf("Backend swapped (cache cleared: %t)\n", clearCache)
+	return nil
 }
 
 // --- Mock-реализация для демонстрации ---
@@ -224,5 +332,72 @@ func main() {
 	fmt.Println("--- Запрос Del ---")
 	_ = cachedRepo.Del("user:1")
 	_, err := cachedRepo.Get("user:1") // Должен быть промах кэша и ошибка БД
-	fmt.Printf("Проверка после Del: %v\n", err)
+	fmt.Printf("Проверка после Del: %v\n\n", err)
+
+	fmt.Println("--- Переключение бэкенда (blue/green) ---")
+	_ = cachedRepo.SwapBackend(newMockDB(), false)
+	val, _ = cachedRepo.Get("user:4") // Значение осталось в кэше, хотя в новой БД его нет
+	fmt.Printf("После SwapBackend из кэша: %s\n", val)
+
+	fmt.Println("\n--- Оптимистичная блокировка (CompareAndSwap) ---")
+	swapped, _ := cachedRepo.CompareAndSwap("user:2", "Jane", "Janet")
+	fmt.Printf("Jane -> Janet: %t\n", swapped)
+	swapped, _ = cachedRepo.CompareAndSwap("user:2", "Jane", "Joan") // Значение уже изменилось
+	fmt.Printf("Jane -> Joan: %t\n", swapped)
+
+	fmt.Println("\n--- Типизированный репозиторий поверх кэша ---")
+	type profile struct {
+		Name string
+		Age  int
+	}
+	profiles := NewTypedRepository[profile](cachedRepo, JSONCodec[profile]{})
+	_ = profiles.Set("profile:1", profile{Name: "Alice", Age: 30})
+	p, _ := profiles.Get("profile:1")
+	fmt.Printf("Профиль из кэша: %+v\n", p)
+
+	fmt.Println("\n--- Read-modify-write с блокировкой по ключу ---")
+	var locks KeyedMutex[string]
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Блокируется только "profile:1": операции над другими ключами не ждут.
+			locks.Lock("profile:1")
+			defer locks.Unlock("profile:1")
+			p, _ := profiles.Get("profile:1")
+			p.Age++
+			_ = profiles.Set("profile:1", p)
+		}()
+	}
+	wg.Wait()
+	p, _ = profiles.Get("profile:1")
+	fmt.Printf("Возраст после трех инкрементов: %d\n", p.Age)
+
+	fmt.Println("\n--- Составной ключ кэша ---")
+	type userRegion struct {
+		UserID int
+		Region string
+	}
+	prices := NewLRUTTLCache[userRegion, string](100, time.Minute)
+	prices.Set(userRegion{UserID: 1, Region: "eu"}, "EUR")
+	prices.Set(userRegion{UserID: 1, Region: "us"}, "USD")
+	currency, _ := prices.Get(userRegion{UserID: 1, Region: "us"})
+	fmt.Printf("Валюта пользователя 1 в регионе us: %s\n", currency)
+	fmt.Printf("Строковый составной ключ: %s\n", CompositeKey("price", "1", "us"))
+
+	fmt.Println("\n--- Горячий уровень: частые ключи переживают поток редких ---")
+	hotRepo := NewHotTierRepository(newMockDB(), 2, 2, 3, time.Minute)
+	for i := 0; i < 4; i++ {
+		// "user:1" читается в каждом пакете, остальные ключи — по одному разу.
+		_, _ = hotRepo.MGet("user:1", fmt.Sprintf("item:%d", 2*i), fmt.Sprintf("item:%d", 2*i+1))
+	}
+	fmt.Printf("Горячие ключи: %v\n", hotRepo.HotKeys())
+
+	fmt.Println("\n--- Цепочка уровней: память -> redis -> БД ---")
+	memory := &mockDBRepository{data: map[string]string{}}
+	redis := &mockDBRepository{data: map[string]string{}}
+	chain := NewChainedRepository(memory, redis, newMockDB())
+	val, _ = chain.Get("user:2") // Попадание только в БД дозаполняет память и redis.
+	fmt.Printf("Из БД: %s; в памяти: %q, в redis: %q\n", val, memory.data["user:2"], redis.data["user:2"])
 }
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memRepo — простая реализация Repository без задержек для тестов.
+type memRepo struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newMemRepo(data map[string]string) *memRepo {
+	return &memRepo{data: data}
+}
+
+func (m *memRepo) Get(key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.data[key]; ok {
+		return v, nil
+	}
+	return "", errors.New("key not found")
+}
+
+func (m *memRepo) MGet(keys ...string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res := make([]string, len(keys))
+	for i, k := range keys {
+		res[i] = m.data[k]
+	}
+	return res, nil
+}
+
+func (m *memRepo) Set(key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = value
+	return nil
+}
+
+func (m *memRepo) Del(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+	return nil
+}
+
+func TestSwapBackendKeepsCache(t *testing.T) {
+	c := NewCachedRepository(newMemRepo(map[string]string{"k": "old"}))
+	if _, err := c.Get("k"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.SwapBackend(newMemRepo(map[string]string{"k": "new"}), false); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.Get("k"); v != "old" {
+		t.Errorf("без очистки ожидалось значение из кэша %q, получено %q", "old", v)
+	}
+
+	if err := c.SwapBackend(newMemRepo(map[string]string{"k": "newest"}), true); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.Get("k"); v != "newest" {
+		t.Errorf("после очистки ожидалось значение из нового бэкенда %q, получено %q", "newest", v)
+	}
+}
+
+// blockingRepo — бэкенд, чтения которого ждут release; entered сообщает о начале чтения.
+type blockingRepo struct {
+	*memRepo
+	entered chan struct{}
+	release chan struct{}
+}
+
+func newBlockingRepo(data map[string]string) *blockingRepo {
+	return &blockingRepo{memRepo: newMemRepo(data), entered: make(chan struct{}, 1), release: make(chan struct{})}
+}
+
+func (r *blockingRepo) Get(key string) (string, error) {
+	r.entered <- struct{}{}
+	<-r.release
+	return r.memRepo.Get(key)
+}
+
+func (r *blockingRepo) MGet(keys ...string) ([]string, error) {
+	r.entered <- struct{}{}
+	<-r.release
+	return r.memRepo.MGet(keys...)
+}
+
+func TestSwapBackendDuringFetchDoesNotCacheOldValue(t *testing.T) {
+	for _, op := range []string{"Get", "MGet"} {
+		t.Run(op, func(t *testing.T) {
+			old := newBlockingRepo(map[string]string{"k": "old"})
+			c := NewCachedRepository(old)
+
+			done := make(chan string)
+			go func() {
+				if op == "Get" {
+					v, _ := c.Get("k")
+					done <- v
+				} else {
+					vs, _ := c.MGet("k")
+					done <- vs[0]
+				}
+			}()
+			<-old.entered // Загрузка из старого бэкенда началась.
+
+			if err := c.SwapBackend(newMemRepo(map[string]string{"k": "new"}), true); err != nil {
+				t.Fatal(err)
+			}
+			close(old.release)
+			if v := <-done; v != "old" {
+				t.Errorf("начатая загрузка вернула %q, ожидалось old", v)
+			}
+
+			if v, _ := c.Get("k"); v != "new" {
+				t.Errorf("после очистки кэша получено %q из старого бэкенда, ожидалось new", v)
+			}
+		})
+	}
+}
+
+func TestSwapBackendRejectsNil(t *testing.T) {
+	c := NewCachedRepository(newMemRepo(map[string]string{}))
+	if err := c.SwapBackend(nil, false); !errors.Is(err, errNilBackend) {
+		t.Errorf("err = %v, ожидалось %v", err, errNilBackend)
+	}
+}
+
+// TestSwapBackendConcurrent запускается с -race: читатели постоянно вызывают Get,
+// пока бэкенд переключается между двумя реализациями.
+func TestSwapBackendConcurrent(t *testing.T) {
+	blue := newMemRepo(map[string]string{"a": "blue", "b": "blue"})
+	green := newMemRepo(map[string]string{"a": "green", "b": "green"})
+	c := NewCachedRepository(blue)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				for _, key := range []string{"a", "b"} {
+					v, err := c.Get(key)
+					if err != nil {
+						t.Errorf("Get(%q): %v", key, err)
+						return
+					}
+					if v != "blue" && v != "green" {
+						t.Errorf("Get(%q) = %q: значение не из известного бэкенда", key, v)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	// Периодическая очистка кэша заставляет читателей снова ходить в бэкенд
+	// прямо во время переключений.
+	for i := 0; i < 50; i++ {
+		next := Repository(green)
+		if i%2 == 1 {
+			next = blue
+		}
+		if err := c.SwapBackend(next, i%4 == 0); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(100 * time.Microsecond)
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestCompareAndSwapSucceeds(t *testing.T) {
+	db := newMemRepo(map[string]string{"k": "v1"})
+	c := NewCachedRepository(db)
+	if _, err := c.Get("k"); err != nil {
+		t.Fatal(err)
+	}
+
+	swapped, err := c.CompareAndSwap("k", "v1", "v2")
+	if err != nil || !swapped {
+		t.Fatalf("CompareAndSwap = (%t, %v), ожидалось (true, nil)", swapped, err)
+	}
+	if got, _ := c.Get("k"); got != "v2" {
+		t.Errorf("кэш: %q, ожидалось v2", got)
+	}
+	if got, _ := db.Get("k"); got != "v2" {
+		t.Errorf("бэкенд: %q, ожидалось v2", got)
+	}
+}
+
+func TestCompareAndSwapFailsOnChangedValue(t *testing.T) {
+	db := newMemRepo(map[string]string{"k": "v1"})
+	c := NewCachedRepository(db)
+	_ = c.Set("k", "v2") // Кто-то успел изменить значение.
+
+	swapped, err := c.CompareAndSwap("k", "v1", "v3")
+	if err != nil || swapped {
+		t.Fatalf("CompareAndSwap = (%t, %v), ожидалось (false, nil)", swapped, err)
+	}
+	if got, _ := db.Get("k"); got != "v2" {
+		t.Errorf("бэкенд: %q, ожидалось неизменное v2", got)
+	}
+}
+
+func TestCompareAndSwapDetectsBackendChange(t *testing.T) {
+	db := newMemRepo(map[string]string{"k": "v1"})
+	c := NewCachedRepository(db)
+	if _, err := c.Get("k"); err != nil {
+		t.Fatal(err)
+	}
+	_ = db.Set("k", "v2") // Запись в обход кэша: кэш устарел.
+
+	swapped, err := c.CompareAndSwap("k", "v1", "v3")
+	if err != nil || swapped {
+		t.Fatalf("CompareAndSwap = (%t, %v), ожидалось (false, nil)", swapped, err)
+	}
+	// Кэш обновлен значением из бэкенда, повторная попытка с ним проходит.
+	if swapped, _ := c.CompareAndSwap("k", "v2", "v3"); !swapped {
+		t.Error("CompareAndSwap с актуальным значением не выполнил замену")
+	}
+}
+
+// slowSetRepo — бэкенд, записи которого ждут release; entered сообщает о начале записи.
+type slowSetRepo struct {
+	*memRepo
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (r *slowSetRepo) Set(key, value string) error {
+	r.entered <- struct{}{}
+	<-r.release
+	return r.memRepo.Set(key, value)
+}
+
+func TestCompareAndSwapWaitsForConcurrentSet(t *testing.T) {
+	db := &slowSetRepo{memRepo: newMemRepo(map[string]string{"k": "v1"}), entered: make(chan struct{}, 2), release: make(chan struct{})}
+	c := NewCachedRepository(db)
+
+	setDone := make(chan error)
+	go func() { setDone <- c.Set("k", "X") }()
+	<-db.entered // Кэш уже обновлен, запись в бэкенд еще идет.
+
+	casDone := make(chan bool)
+	go func() {
+		swapped, _ := c.CompareAndSwap("k", "X", "Y")
+		casDone <- swapped
+	}()
+	time.Sleep(20 * time.Millisecond) // Даем CompareAndSwap вклиниться, если он может.
+	close(db.release)
+	if err := <-setDone; err != nil {
+		t.Fatal(err)
+	}
+	<-casDone
+
+	cached, _ := c.Get("k")
+	stored, _ := db.memRepo.Get("k")
+	if cached != stored {
+		t.Errorf("кэш %q расходится с бэкендом %q", cached, stored)
+	}
+}
+
+func TestCompareAndSwapDoesNotBlockOtherKeys(t *testing.T) {
+	db := newBlockingRepo(map[string]string{"k": "v1"})
+	c := NewCachedRepository(db)
+	_ = c.Set("other", "x") // Запись не читает бэкенд, значение попадает в кэш.
+
+	go func() { _, _ = c.CompareAndSwap("k", "v1", "v2") }()
+	<-db.entered // CompareAndSwap ждет ответа бэкенда.
+	defer close(db.release)
+
+	got := make(chan string)
+	go func() {
+		v, _ := c.Get("other")
+		got <- v
+	}()
+	select {
+	case v := <-got:
+		if v != "x" {
+			t.Errorf("Get(other) = %q, ожидалось x", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("чтение другого ключа ждет обращения CompareAndSwap к бэкенду")
+	}
+}
+
+func TestCompareAndSwapMissFetchesFromBackend(t *testing.T) {
+	db := newMemRepo(map[string]string{"k": "v1"})
+	c := NewCachedRepository(db)
+	c.EnableAudit(10)
+
+	swapped, err := c.CompareAndSwap("k", "v1", "v2")
+	if err != nil || !swapped {
+		t.Fatalf("CompareAndSwap = (%t, %v), ожидалось (true, nil)", swapped, err)
+	}
+	if events := c.AuditLog(); len(events) != 1 || events[0].Op != OpCAS || events[0].Hit {
+		t.Errorf("журнал аудита: %+v, ожидался один CAS-промах", events)
+	}
+
+	// Отсутствующий ключ: ошибка бэкенда возвращается вызывающему.
+	if swapped, err := c.CompareAndSwap("missing", "", "v"); err == nil || swapped {
+		t.Errorf("CompareAndSwap(missing) = (%t, %v), ожидалась ошибка", swapped, err)
+	}
+}
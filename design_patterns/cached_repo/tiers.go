@@ -0,0 +1,89 @@
+package main
+
+// TierMerge собирает результат пакетного запроса (MGet) из нескольких уровней кэша.
+//
+// Типичный сценарий — L1 (in-process), затем L2 (общий кэш), затем база данных:
+// каждый следующий уровень опрашивается только по ключам, не найденным на предыдущих
+// (Missing), а найденные значения раскладываются по позициям исходного запроса (Fill).
+// Значение, найденное на более раннем уровне, не перезаписывается более поздним.
+//
+// Повторяющиеся ключи в запросе допустимы: все их позиции заполняются одним значением,
+// а в Missing ключ попадает один раз. TierMerge не потокобезопасен.
+type TierMerge[K comparable, V any] struct {
+	keys      []K
+	values    []V
+	resolved  []bool
+	positions map[K][]int // Позиции каждого ключа в исходном запросе.
+	remaining int         // Сколько уникальных ключей еще не найдено.
+}
+
+// NewTierMerge начинает сборку результата для ключей keys (в порядке запроса).
+func NewTierMerge[K comparable, V any](keys []K) *TierMerge[K, V] {
+	m := &TierMerge[K, V]{
+		keys:      keys,
+		values:    make([]V, len(keys)),
+		resolved:  make([]bool, len(keys)),
+		positions: make(map[K][]int, len(keys)),
+	}
+	for i, k := range keys {
+		m.positions[k] = append(m.positions[k], i)
+	}
+	m.remaining = len(m.positions)
+	return m
+}
+
+// Missing возвращает еще не найденные ключи без повторов, в порядке первого появления в запросе.
+// Именно их нужно запросить у следующего уровня.
+func (m *TierMerge[K, V]) Missing() []K {
+	missing := make([]K, 0, m.remaining)
+	for i, k := range m.keys {
+		if !m.resolved[i] && m.positions[k][0] == i {
+			missing = append(missing, k)
+		}
+	}
+	return missing
+}
+
+// Set сохраняет значение ключа, найденное на текущем уровне. Возвращает false, если ключ
+// не входит в запрос или уже найден на более раннем уровне (тогда значение игнорируется).
+func (m *TierMerge[K, V]) Set(key K, value V) bool {
+	pos, ok := m.positions[key]
+	if !ok || m.resolved[pos[0]] {
+		return false
+	}
+	for _, i := range pos {
+		m.values[i] = value
+		m.resolved[i] = true
+	}
+	m.remaining--
+	return true
+}
+
+// Fill сохраняет позиционный ответ уровня: values[i] — значение для keys[i].
+// Обычно keys — это результат Missing, а values — ответ MGet следующего уровня.
+// Возвращает количество впервые найденных ключей.
+func (m *TierMerge[K, V]) Fill(keys []K, values []V) int {
+	n := 0
+	for i := range min(len(keys), len(values)) {
+		if m.Set(keys[i], values[i]) {
+			n++
+		}
+	}
+	return n
+}
+
+// Done сообщает, что все ключи найдены и опрашивать следующие уровни не нужно.
+func (m *TierMerge[K, V]) Done() bool {
+	return m.remaining == 0
+}
+
+// Values возвращает значения в порядке исходного запроса.
+// Для ненайденных ключей на соответствующих позициях — нулевое значение V.
+func (m *TierMerge[K, V]) Values() []V {
+	return m.values
+}
+
+// Resolved сообщает для каждой позиции запроса, было ли найдено значение.
+func (m *TierMerge[K, V]) Resolved() []bool {
+	return m.resolved
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTierMergeTwoTiers(t *testing.T) {
+	l1 := map[string]string{"a": "a@L1", "c": "c@L1"}
+	l2 := map[string]string{"a": "a@L2", "b": "b@L2", "d": "d@L2"}
+
+	merge := NewTierMerge[string, string]([]string{"d", "a", "b", "c", "e"})
+
+	// L1: значения из in-process кэша.
+	for _, k := range merge.Missing() {
+		if v, ok := l1[k]; ok {
+			merge.Set(k, v)
+		}
+	}
+	if got, want := merge.Missing(), []string{"d", "b", "e"}; !slices.Equal(got, want) {
+		t.Fatalf("после L1 Missing = %v, ожидалось %v", got, want)
+	}
+
+	// L2 отвечает позиционно, как MGet: пустая строка — промах.
+	missing := merge.Missing()
+	values := make([]string, len(missing))
+	for i, k := range missing {
+		values[i] = l2[k]
+	}
+	var hitKeys []string
+	var hitValues []string
+	for i, v := range values {
+		if v != "" {
+			hitKeys = append(hitKeys, missing[i])
+			hitValues = append(hitValues, v)
+		}
+	}
+	if n := merge.Fill(hitKeys, hitValues); n != 2 {
+		t.Errorf("Fill нашел %d ключей, ожидалось 2", n)
+	}
+
+	wantValues := []string{"d@L2", "a@L1", "b@L2", "c@L1", ""}
+	if got := merge.Values(); !slices.Equal(got, wantValues) {
+		t.Errorf("Values = %v, ожидалось %v", got, wantValues)
+	}
+	if got, want := merge.Resolved(), []bool{true, true, true, true, false}; !slices.Equal(got, want) {
+		t.Errorf("Resolved = %v, ожидалось %v", got, want)
+	}
+	if got := merge.Missing(); !slices.Equal(got, []string{"e"}) {
+		t.Errorf("Missing = %v, ожидалось [e]", got)
+	}
+	if merge.Done() {
+		t.Error("Done = true при ненайденном ключе")
+	}
+}
+
+func TestTierMergeEarlierTierWins(t *testing.T) {
+	merge := NewTierMerge[string, int]([]string{"x"})
+	if !merge.Set("x", 1) {
+		t.Fatal("первое значение не сохранено")
+	}
+	if merge.Set("x", 2) {
+		t.Error("значение более позднего уровня перезаписало раннее")
+	}
+	if merge.Set("unknown", 3) {
+		t.Error("сохранен ключ, которого нет в запросе")
+	}
+	if got := merge.Values(); got[0] != 1 {
+		t.Errorf("Values = %v, ожидалось [1]", got)
+	}
+	if !merge.Done() {
+		t.Error("Done = false, хотя все ключи найдены")
+	}
+}
+
+func TestTierMergeDuplicateKeys(t *testing.T) {
+	merge := NewTierMerge[string, int]([]string{"a", "b", "a"})
+	if got := merge.Missing(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("Missing = %v, ожидалось [a b] без повторов", got)
+	}
+	merge.Fill([]string{"a", "b"}, []int{1, 2})
+	if got := merge.Values(); !slices.Equal(got, []int{1, 2, 1}) {
+		t.Errorf("Values = %v, ожидалось [1 2 1]", got)
+	}
+	if !merge.Done() {
+		t.Error("Done = false после заполнения всех ключей")
+	}
+}
+
+func TestCachedRepositoryMGetDuplicateKeys(t *testing.T) {
+	c := NewCachedRepository(newMemRepo(map[string]string{"a": "1", "b": "2"}))
+	_, _ = c.Get("a") // "a" в кэше, "b" — нет.
+
+	got, err := c.MGet("b", "a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"2", "1", "2"}; !slices.Equal(got, want) {
+		t.Errorf("MGet = %v, ожидалось %v", got, want)
+	}
+}
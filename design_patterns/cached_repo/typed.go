@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Codec преобразует значения типа V в строку для хранения в Repository и обратно.
+type Codec[V any] interface {
+	Encode(v V) (string, error)
+	Decode(s string) (V, error)
+}
+
+// JSONCodec — Codec на основе encoding/json.
+type JSONCodec[V any] struct{}
+
+func (JSONCodec[V]) Encode(v V) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (JSONCodec[V]) Decode(s string) (V, error) {
+	var v V
+	err := json.Unmarshal([]byte(s), &v)
+	return v, err
+}
+
+// GobCodec — Codec на основе encoding/gob. Результат бинарный, но строка Go
+// может хранить произвольные байты, поэтому для Repository это не проблема.
+type GobCodec[V any] struct{}
+
+func (GobCodec[V]) Encode(v V) (string, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (GobCodec[V]) Decode(s string) (V, error) {
+	var v V
+	err := gob.NewDecoder(strings.NewReader(s)).Decode(&v)
+	return v, err
+}
+
+// TypedRepository — типизированная обертка над Repository.
+// Значения автоматически кодируются при записи и декодируются при чтении,
+// поэтому под ней можно использовать любой Repository, в том числе CachedRepository.
+type TypedRepository[V any] struct {
+	repo  Repository
+	codec Codec[V]
+}
+
+// NewTypedRepository создает типизированный репозиторий поверх repo с кодеком codec.
+func NewTypedRepository[V any](repo Repository, codec Codec[V]) *TypedRepository[V] {
+	return &TypedRepository[V]{repo: repo, codec: codec}
+}
+
+// Get получает и декодирует значение по ключу.
+func (t *TypedRepository[V]) Get(key string) (V, error) {
+	var zero V
+	raw, err := t.repo.Get(key)
+	if err != nil {
+		return zero, err
+	}
+	v, err := t.codec.Decode(raw)
+	if err != nil {
+		return zero, fmt.Errorf("декодирование значения ключа %q: %w", key, err)
+	}
+	return v, nil
+}
+
+// MGet получает и декодирует значения по нескольким ключам.
+func (t *TypedRepository[V]) MGet(keys ...string) ([]V, error) {
+	raws, err := t.repo.MGet(keys...)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]V, len(raws))
+	for i, raw := range raws {
+		if res[i], err = t.codec.Decode(raw); err != nil {
+			return nil, fmt.Errorf("декодирование значения ключа %q: %w", keys[i], err)
+		}
+	}
+	return res, nil
+}
+
+// Set кодирует и сохраняет значение.
+func (t *TypedRepository[V]) Set(key string, v V) error {
+	raw, err := t.codec.Encode(v)
+	if err != nil {
+		return fmt.Errorf("кодирование значения ключа %q: %w", key, err)
+	}
+	return t.repo.Set(key, raw)
+}
+
+// Del удаляет значение по ключу.
+func (t *TypedRepository[V]) Del(key string) error {
+	return t.repo.Del(key)
+}
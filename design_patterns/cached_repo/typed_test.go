@@ -0,0 +1,109 @@
+package main
+
+import (
+	"testing"
+)
+
+type testUser struct {
+	Name  string
+	Age   int
+	Roles []string
+}
+
+func equalUsers(a, b testUser) bool {
+	if a.Name != b.Name || a.Age != b.Age || len(a.Roles) != len(b.Roles) {
+		return false
+	}
+	for i := range a.Roles {
+		if a.Roles[i] != b.Roles[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTypedRepositoryRoundTrip(t *testing.T) {
+	codecs := map[string]Codec[testUser]{
+		"json": JSONCodec[testUser]{},
+		"gob":  GobCodec[testUser]{},
+	}
+	want := testUser{Name: "Alice", Age: 30, Roles: []string{"admin", "dev"}}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			repo := NewTypedRepository(newMemRepo(map[string]string{}), codec)
+			if err := repo.Set("user:1", want); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			got, err := repo.Get("user:1")
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if !equalUsers(got, want) {
+				t.Errorf("Get = %+v, ожидалось %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestTypedRepositoryStoresJSON(t *testing.T) {
+	backend := newMemRepo(map[string]string{})
+	repo := NewTypedRepository[testUser](backend, JSONCodec[testUser]{})
+	if err := repo.Set("u", testUser{Name: "Bob", Age: 7}); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := backend.Get("u")
+	if want := `{"Name":"Bob","Age":7,"Roles":null}`; raw != want {
+		t.Errorf("в бэкенде %q, ожидалось %q", raw, want)
+	}
+}
+
+func TestTypedRepositoryThroughCache(t *testing.T) {
+	backend := newMemRepo(map[string]string{})
+	cached := NewCachedRepository(backend)
+	cached.EnableAudit(16)
+	repo := NewTypedRepository[testUser](cached, JSONCodec[testUser]{})
+
+	users := []testUser{{Name: "A", Age: 1}, {Name: "B", Age: 2}}
+	for i, u := range users {
+		if err := repo.Set([]string{"a", "b"}[i], u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Удаляем значения из бэкенда: если чтение идет из кэша, оно все равно успешно.
+	_ = backend.Del("a")
+	_ = backend.Del("b")
+
+	got, err := repo.MGet("a", "b")
+	if err != nil {
+		t.Fatalf("MGet: %v", err)
+	}
+	for i := range users {
+		if !equalUsers(got[i], users[i]) {
+			t.Errorf("MGet[%d] = %+v, ожидалось %+v", i, got[i], users[i])
+		}
+	}
+
+	u, err := repo.Get("a")
+	if err != nil || !equalUsers(u, users[0]) {
+		t.Errorf("Get = %+v, %v; ожидалось %+v", u, err, users[0])
+	}
+
+	for _, e := range cached.AuditLog() {
+		if (e.Op == OpGet || e.Op == OpMGet) && !e.Hit {
+			t.Errorf("ожидалось попадание в кэш, событие %+v", e)
+		}
+	}
+}
+
+func TestTypedRepositoryDecodeError(t *testing.T) {
+	backend := newMemRepo(map[string]string{"bad": "not json"})
+	repo := NewTypedRepository[testUser](backend, JSONCodec[testUser]{})
+	if _, err := repo.Get("bad"); err == nil {
+		t.Error("ожидалась ошибка декодирования")
+	}
+	if _, err := repo.MGet("bad"); err == nil {
+		t.Error("ожидалась ошибка декодирования в MGet")
+	}
+}
@@ -0,0 +1,222 @@
+// Package main демонстрирует исполнитель конвейера в виде направленного ациклического графа (DAG).
+//
+// В линейном конвейере (см. algorithms/bizone) каждый шаг получает данные только
+// от предыдущего. В DAG у стадии может быть несколько входов и несколько потребителей:
+// например, данные загружаются один раз, затем параллельно очищаются и обогащаются,
+// а финальная стадия объединяет оба результата ("ромб").
+//
+// Исполнитель:
+// 1. Проверяет граф: неизвестные зависимости и циклы.
+// 2. Запускает каждую стадию, как только готовы все ее зависимости.
+// 3. Ограничивает количество одновременно выполняющихся стадий.
+// 4. При первой ошибке отменяет контекст и останавливает остальные стадии.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	errDuplicateNode     = errors.New("стадия с таким именем уже существует")
+	errUnknownDependency = errors.New("неизвестная зависимость")
+	errCycle             = errors.New("граф содержит цикл")
+)
+
+// NodeFunc — функция стадии. Она получает результаты своих зависимостей,
+// проиндексированные по имени стадии, и возвращает собственный результат.
+type NodeFunc[T any] func(ctx context.Context, inputs map[string]T) (T, error)
+
+// node — стадия графа.
+type node[T any] struct {
+	name string
+	fn   NodeFunc[T]
+	deps []string
+}
+
+// DAG — граф стадий обработки данных типа T.
+type DAG[T any] struct {
+	nodes map[string]*node[T]
+	order []string // Порядок добавления — для детерминированных сообщений об ошибках.
+}
+
+// NewDAG создает пустой граф.
+func NewDAG[T any]() *DAG[T] {
+	return &DAG[T]{nodes: make(map[string]*node[T])}
+}
+
+// AddNode добавляет стадию name, которая выполнится после всех стадий deps.
+// Зависимости можно объявлять до того, как они сами добавлены в граф:
+// проверка выполняется в Run.
+func (d *DAG[T]) AddNode(name string, fn NodeFunc[T], deps ...string) error {
+	if _, exists := d.nodes[name]; exists {
+		return fmt.Errorf("%w: %q", errDuplicateNode, name)
+	}
+	d.nodes[name] = &node[T]{name: name, fn: fn, deps: deps}
+	d.order = append(d.order, name)
+	return nil
+}
+
+// validate проверяет, что все зависимости существуют и граф не содержит циклов
+// (алгоритм Кана: если топологическая сортировка не охватила все вершины — есть цикл).
+func (d *DAG[T]) validate() error {
+	inDegree := make(map[string]int, len(d.nodes))
+	dependents := make(map[string][]string, len(d.nodes))
+	for _, name := range d.order {
+		n := d.nodes[name]
+		for _, dep := range n.deps {
+			if _, ok := d.nodes[dep]; !ok {
+				return fmt.Errorf("%w: стадия %q зависит от %q", errUnknownDependency, name, dep)
+			}
+			inDegree[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	var queue []string
+	for _, name := range d.order {
+		if inDegree[name] == 0 {
+			queue = append(queue, name)
+		}
+	}
+	visited := 0
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, dependent := range dependents[name] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	if visited != len(d.nodes) {
+		return errCycle
+	}
+	return nil
+}
+
+// Run выполняет граф и возвращает результаты всех стадий по их именам.
+//
+// Одновременно выполняется не более limit стадий (limit <= 0 — без ограничения).
+// Горутина запускается для каждой стадии сразу, но слот семафора занимается только
+// после готовности зависимостей. Если бы ограничение делалось через errgroup.SetLimit,
+// ожидающие стадии занимали бы слоты и граф мог бы заблокироваться.
+func (d *DAG[T]) Run(ctx context.Context, limit int) (map[string]T, error) {
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	// done[name] закрывается, когда результат стадии записан в results.
+	// Закрытие канала — это широковещательный сигнал для всех зависимых стадий.
+	done := make(map[string]chan struct{}, len(d.nodes))
+	for name := range d.nodes {
+		done[name] = make(chan struct{})
+	}
+
+	var mu sync.Mutex
+	results := make(map[string]T, len(d.nodes))
+
+	var sem chan struct{}
+	if limit > 0 {
+		sem = make(chan struct{}, limit)
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	for _, name := range d.order {
+		n := d.nodes[name]
+		g.Go(func() error {
+			// Ждем все зависимости или отмену графа.
+			for _, dep := range n.deps {
+				select {
+				case <-done[dep]:
+				case <-gctx.Done():
+					return gctx.Err()
+				}
+			}
+
+			if sem != nil {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-gctx.Done():
+					return gctx.Err()
+				}
+			}
+
+			mu.Lock()
+			inputs := make(map[string]T, len(n.deps))
+			for _, dep := range n.deps {
+				inputs[dep] = results[dep]
+			}
+			mu.Unlock()
+
+			out, err := n.fn(gctx, inputs)
+			if err != nil {
+				return fmt.Errorf("стадия %q: %w", n.name, err)
+			}
+
+			mu.Lock()
+			results[n.name] = out
+			mu.Unlock()
+			close(done[n.name])
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func main() {
+	// Ромбовидный граф:
+	//          extract
+	//         /       \
+	//     clean      enrich
+	//         \       /
+	//           merge
+	dag := NewDAG[string]()
+
+	step := func(name string, f func(inputs map[string]string) string) NodeFunc[string] {
+		return func(ctx context.Context, inputs map[string]string) (string, error) {
+			log.Printf("Стадия %s: старт", name)
+			select {
+			case <-time.After(100 * time.Millisecond): // Имитация работы
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+			out := f(inputs)
+			log.Printf("Стадия %s: готово -> %q", name, out)
+			return out, nil
+		}
+	}
+
+	_ = dag.AddNode("extract", step("extract", func(map[string]string) string {
+		return "  Hello, DAG  "
+	}))
+	_ = dag.AddNode("clean", step("clean", func(in map[string]string) string {
+		return strings.TrimSpace(in["extract"])
+	}), "extract")
+	_ = dag.AddNode("enrich", step("enrich", func(in map[string]string) string {
+		return fmt.Sprintf("len=%d", len(in["extract"]))
+	}), "extract")
+	_ = dag.AddNode("merge", step("merge", func(in map[string]string) string {
+		return in["clean"] + " (" + in["enrich"] + ")"
+	}), "clean", "enrich")
+
+	results, err := dag.Run(context.Background(), 2)
+	if err != nil {
+		log.Fatalf("Ошибка выполнения графа: %v", err)
+	}
+	fmt.Printf("Итоговый результат: %s\n", results["merge"])
+}
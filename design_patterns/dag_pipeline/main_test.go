@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDAGDiamond(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		seq      int
+		started  = map[string]int{}
+		finished = map[string]int{}
+	)
+	tick := func(m map[string]int, name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		seq++
+		m[name] = seq
+	}
+
+	node := func(name string, value int) NodeFunc[int] {
+		return func(_ context.Context, inputs map[string]int) (int, error) {
+			tick(started, name)
+			defer tick(finished, name)
+			time.Sleep(5 * time.Millisecond)
+			sum := value
+			for _, v := range inputs {
+				sum += v
+			}
+			return sum, nil
+		}
+	}
+
+	dag := NewDAG[int]()
+	// Стадии добавлены не в топологическом порядке — исполнитель должен разобраться сам.
+	mustAdd(t, dag, "merge", node("merge", 1000), "left", "right")
+	mustAdd(t, dag, "left", node("left", 10), "source")
+	mustAdd(t, dag, "right", node("right", 100), "source")
+	mustAdd(t, dag, "source", node("source", 1))
+
+	results, err := dag.Run(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	want := map[string]int{"source": 1, "left": 11, "right": 101, "merge": 1112}
+	for name, v := range want {
+		if results[name] != v {
+			t.Errorf("results[%q] = %d, ожидалось %d", name, results[name], v)
+		}
+	}
+
+	deps := map[string][]string{"left": {"source"}, "right": {"source"}, "merge": {"left", "right"}}
+	for name, ds := range deps {
+		for _, dep := range ds {
+			if started[name] < finished[dep] {
+				t.Errorf("стадия %q стартовала раньше завершения зависимости %q", name, dep)
+			}
+		}
+	}
+}
+
+func TestDAGRespectsLimit(t *testing.T) {
+	var current, maxSeen atomic.Int32
+	fn := func(context.Context, map[string]int) (int, error) {
+		n := current.Add(1)
+		defer current.Add(-1)
+		for {
+			m := maxSeen.Load()
+			if n <= m || maxSeen.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return 0, nil
+	}
+
+	dag := NewDAG[int]()
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		mustAdd(t, dag, name, fn)
+	}
+	if _, err := dag.Run(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+	if maxSeen.Load() > 2 {
+		t.Errorf("одновременно выполнялось %d стадий при лимите 2", maxSeen.Load())
+	}
+}
+
+func TestDAGValidation(t *testing.T) {
+	noop := func(context.Context, map[string]int) (int, error) { return 0, nil }
+
+	dag := NewDAG[int]()
+	mustAdd(t, dag, "a", noop)
+	if err := dag.AddNode("a", noop); !errors.Is(err, errDuplicateNode) {
+		t.Errorf("дубликат: err = %v", err)
+	}
+
+	unknown := NewDAG[int]()
+	mustAdd(t, unknown, "a", noop, "missing")
+	if _, err := unknown.Run(context.Background(), 0); !errors.Is(err, errUnknownDependency) {
+		t.Errorf("неизвестная зависимость: err = %v", err)
+	}
+
+	cyclic := NewDAG[int]()
+	mustAdd(t, cyclic, "a", noop, "c")
+	mustAdd(t, cyclic, "b", noop, "a")
+	mustAdd(t, cyclic, "c", noop, "b")
+	if _, err := cyclic.Run(context.Background(), 0); !errors.Is(err, errCycle) {
+		t.Errorf("цикл: err = %v", err)
+	}
+}
+
+func TestDAGStopsOnError(t *testing.T) {
+	errBoom := errors.New("boom")
+	var downstreamRan atomic.Bool
+
+	dag := NewDAG[int]()
+	mustAdd(t, dag, "fail", func(context.Context, map[string]int) (int, error) { return 0, errBoom })
+	mustAdd(t, dag, "after", func(context.Context, map[string]int) (int, error) {
+		downstreamRan.Store(true)
+		return 0, nil
+	}, "fail")
+
+	if _, err := dag.Run(context.Background(), 0); !errors.Is(err, errBoom) {
+		t.Fatalf("err = %v, ожидалось %v", err, errBoom)
+	}
+	if downstreamRan.Load() {
+		t.Error("зависимая стадия выполнилась после ошибки")
+	}
+}
+
+func mustAdd(t *testing.T, d *DAG[int], name string, fn NodeFunc[int], deps ...string) {
+	t.Helper()
+	if err := d.AddNode(name, fn, deps...); err != nil {
+		t.Fatal(err)
+	}
+}
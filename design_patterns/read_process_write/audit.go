@@ -0,0 +1,30 @@
+package main
+
+// AuditProcessor — шаг конвейера, который пропускает элемент без изменений и вызывает
+// для него колбэк с побочным эффектом: логирование, метрики, выборку в отладочное хранилище.
+// Работает как io.TeeReader для конвейера: наблюдение можно вставить в любое место
+// цепочки процессоров, не меняя данные.
+//
+// DataManager обрабатывает элементы конкурентно, поэтому колбэк должен быть безопасен
+// для вызова из нескольких горутин. Колбэк получает тот же элемент, что уйдет дальше,
+// и не должен его изменять.
+//
+// Как и у Dedup, метод Process имеет сигнатуру Process(T) ([]T, error), поэтому
+// AuditProcessor[*Data] удовлетворяет интерфейсу Processor.
+type AuditProcessor[T any] struct {
+	fn func(T)
+}
+
+// Проверка на этапе компиляции, что AuditProcessor подходит для конвейера.
+var _ Processor = (*AuditProcessor[*Data])(nil)
+
+// NewAuditProcessor создает процессор, вызывающий fn для каждого элемента.
+func NewAuditProcessor[T any](fn func(T)) *AuditProcessor[T] {
+	return &AuditProcessor[T]{fn: fn}
+}
+
+// Process вызывает колбэк и возвращает []T{item}.
+func (a *AuditProcessor[T]) Process(item T) ([]T, error) {
+	a.fn(item)
+	return []T{item}, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestAuditProcessorPassesItemThrough(t *testing.T) {
+	var seen []string
+	a := NewAuditProcessor(func(s string) { seen = append(seen, s) })
+
+	for _, s := range []string{"a", "b", "a"} {
+		out, err := a.Process(s)
+		if err != nil {
+			t.Fatalf("неожиданная ошибка: %v", err)
+		}
+		if len(out) != 1 || out[0] != s {
+			t.Errorf("Process(%q) = %v, ожидалось [%s]", s, out, s)
+		}
+	}
+	if want := []string{"a", "b", "a"}; !slices.Equal(seen, want) {
+		t.Errorf("колбэк получил %v, ожидалось %v", seen, want)
+	}
+}
+
+func TestAuditProcessorInPipeline(t *testing.T) {
+	var mu sync.Mutex
+	var before, after []Data
+	audit := func(dst *[]Data) *AuditProcessor[*Data] {
+		return NewAuditProcessor(func(d *Data) {
+			mu.Lock()
+			*dst = append(*dst, *d)
+			mu.Unlock()
+		})
+	}
+
+	input := []*Data{{ID: 1, Payload: "a"}, {ID: 2, Payload: "b"}, {ID: 3, Payload: "c"}}
+	writer := &mockWriter{sortByID: true}
+	processors := []Processor{audit(&before), &duplicatorProcessor{}, audit(&after)}
+	NewDataManager(&sliceReader{data: input}, processors, writer).Manage()
+
+	// Колбэк срабатывает для каждого элемента на своем месте цепочки.
+	if len(before) != 3 {
+		t.Errorf("аудит до дублирования увидел %d элементов, ожидалось 3", len(before))
+	}
+	if len(after) != 6 {
+		t.Errorf("аудит после дублирования увидел %d элементов, ожидалось 6", len(after))
+	}
+
+	// Аудит не меняет данные: результат совпадает с конвейером без него.
+	want := &mockWriter{sortByID: true}
+	plainInput := []*Data{{ID: 1, Payload: "a"}, {ID: 2, Payload: "b"}, {ID: 3, Payload: "c"}}
+	NewDataManager(&sliceReader{data: plainInput}, []Processor{&duplicatorProcessor{}}, want).Manage()
+
+	got := snapshot(writer.data)
+	slices.SortFunc(got, compareData)
+	expected := snapshot(want.data)
+	slices.SortFunc(expected, compareData)
+	if !slices.Equal(got, expected) {
+		t.Errorf("результат с аудитом %v, без аудита %v", got, expected)
+	}
+}
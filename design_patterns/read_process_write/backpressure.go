@@ -0,0 +1,113 @@
+package main
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// BackpressureWriter — необязательное расширение Writer для приемника, который умеет
+// сообщить о своей перегрузке. Если Writer его реализует, Manage в режиме LimitBuffered
+// не передает очередной пакет, пока CanAccept не вернет true.
+//
+// Приемник без CanAccept тоже может притормозить конвейер — просто блокируясь в Write.
+type BackpressureWriter interface {
+	Writer
+	CanAccept() bool
+}
+
+// backpressurePoll — период повторной проверки CanAccept перегруженного приемника.
+const backpressurePoll = time.Millisecond
+
+// LimitBuffered ограничивает число результатов, ожидающих записи, значением n.
+//
+// По умолчанию Manage собирает все результаты в памяти и записывает их одним пакетом
+// в конце, поэтому медленный или большой приемник означает неограниченный рост памяти.
+// С лимитом результаты уходят в Writer пакетами по мере готовности, а обработка
+// останавливается, когда n результатов уже ждут записи: процессор ждет освобождения
+// места, прежде чем отдать следующий результат. Одновременно обрабатывается не больше
+// n элементов (при UseWorkStealing — не больше числа воркеров).
+//
+// n <= 0 возвращает режим по умолчанию. Возвращает dm для цепочки вызовов.
+func (dm *DataManager) LimitBuffered(n int) *DataManager {
+	dm.maxBuffered = max(n, 0)
+	return dm
+}
+
+// boundedSink передает результаты в Writer отдельной горутиной, держа в памяти
+// не больше max результатов, ожидающих записи.
+type boundedSink struct {
+	writer Writer
+	slots  chan struct{} // Семафор: одно место на каждый результат, еще не переданный в Write.
+	out    chan *Data
+	done   chan struct{}
+
+	buffered atomic.Int64
+	peak     atomic.Int64 // Наибольшее значение buffered.
+}
+
+func newBoundedSink(writer Writer, max int) *boundedSink {
+	s := &boundedSink{
+		writer: writer,
+		slots:  make(chan struct{}, max),
+		out:    make(chan *Data, max),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// collect передает результаты на запись, блокируясь, пока для них нет места.
+func (s *boundedSink) collect(results []*Data) {
+	for _, d := range results {
+		s.slots <- struct{}{}
+		n := s.buffered.Add(1)
+		for {
+			peak := s.peak.Load()
+			if n <= peak || s.peak.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		s.out <- d
+	}
+}
+
+// close дожидается записи всех переданных результатов. После close вызывать collect нельзя.
+func (s *boundedSink) close() {
+	close(s.out)
+	<-s.done
+}
+
+// run собирает в пакет все результаты, готовые к моменту записи, и передает его в Writer.
+func (s *boundedSink) run() {
+	defer close(s.done)
+	for d := range s.out {
+		batch := []*Data{d}
+	fill:
+		for {
+			select {
+			case next, ok := <-s.out:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, next)
+			default:
+				break fill
+			}
+		}
+		s.flush(batch)
+	}
+}
+
+// flush ждет готовности приемника, записывает пакет и освобождает места в буфере.
+func (s *boundedSink) flush(batch []*Data) {
+	if bw, ok := s.writer.(BackpressureWriter); ok {
+		for !bw.CanAccept() {
+			time.Sleep(backpressurePoll)
+		}
+	}
+	s.writer.Write(batch)
+	s.buffered.Add(-int64(len(batch)))
+	for range batch {
+		<-s.slots
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowWriter — медленный приемник: каждый Write занимает delay.
+// Если gated, CanAccept возвращает false, пока не вызван open.
+type slowWriter struct {
+	delay  time.Duration
+	gated  bool
+	opened atomic.Bool
+
+	mu       sync.Mutex
+	written  int
+	maxBatch int
+}
+
+func (w *slowWriter) Write(data []*Data) {
+	time.Sleep(w.delay)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.written += len(data)
+	w.maxBatch = max(w.maxBatch, len(data))
+}
+
+func (w *slowWriter) CanAccept() bool { return !w.gated || w.opened.Load() }
+
+func (w *slowWriter) open() { w.opened.Store(true) }
+
+func (w *slowWriter) stats() (written, maxBatch int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.written, w.maxBatch
+}
+
+func numberedData(n int) []*Data {
+	data := make([]*Data, n)
+	for i := range data {
+		data[i] = &Data{ID: i}
+	}
+	return data
+}
+
+func TestLimitBufferedWithSlowWriter(t *testing.T) {
+	const items, limit = 300, 8
+	for _, workers := range []int{0, 4} {
+		writer := &slowWriter{delay: time.Millisecond}
+		dm := NewDataManager(&sliceReader{data: numberedData(items)}, []Processor{&duplicatorProcessor{}}, writer).
+			UseWorkStealing(workers).
+			LimitBuffered(limit)
+		dm.Manage()
+
+		written, maxBatch := writer.stats()
+		if written != 2*items {
+			t.Errorf("workers=%d: записано %d результатов, ожидалось %d", workers, written, 2*items)
+		}
+		if maxBatch > limit {
+			t.Errorf("workers=%d: пакет из %d результатов превышает лимит %d", workers, maxBatch, limit)
+		}
+	}
+}
+
+func TestBoundedSinkPeakWithinLimit(t *testing.T) {
+	const producers, perProducer, limit = 16, 20, 8
+	writer := &slowWriter{delay: time.Millisecond}
+	sink := newBoundedSink(writer, limit)
+
+	var wg sync.WaitGroup
+	wg.Add(producers)
+	for range producers {
+		go func() {
+			defer wg.Done()
+			for range perProducer {
+				sink.collect(numberedData(1))
+			}
+		}()
+	}
+	wg.Wait()
+	sink.close()
+
+	if written, _ := writer.stats(); written != producers*perProducer {
+		t.Errorf("записано %d результатов, ожидалось %d", written, producers*perProducer)
+	}
+	if peak := sink.peak.Load(); peak > limit || peak == 0 {
+		t.Errorf("в буфере было до %d результатов, лимит %d", peak, limit)
+	}
+}
+
+func TestLimitBufferedWaitsForCanAccept(t *testing.T) {
+	const items, limit = 100, 4
+	var processed atomic.Int32
+	count := funcProcessor(func(d *Data) ([]*Data, error) {
+		processed.Add(1)
+		return []*Data{d}, nil
+	})
+	writer := &slowWriter{gated: true}
+	dm := NewDataManager(&sliceReader{data: numberedData(items)}, []Processor{count}, NewSortedWriter(writer, ByID)).
+		LimitBuffered(limit)
+
+	done := make(chan struct{})
+	go func() {
+		dm.Manage()
+		close(done)
+	}()
+
+	// Приемник перегружен: ничего не записано, а обработка остановилась на заполненном
+	// буфере (limit результатов) плюс не больше limit элементов, ждущих места в нем.
+	time.Sleep(50 * time.Millisecond)
+	if written, _ := writer.stats(); written != 0 {
+		t.Fatalf("записано %d результатов, пока CanAccept == false", written)
+	}
+	if n := processed.Load(); n > 2*limit {
+		t.Fatalf("обработано %d элементов при перегруженном приемнике, ожидалось не больше %d", n, 2*limit)
+	}
+
+	writer.open()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Manage не завершился после снятия перегрузки")
+	}
+	if written, _ := writer.stats(); written != items {
+		t.Errorf("записано %d результатов, ожидалось %d", written, items)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import "sync"
+
+// Dedup — шаг конвейера, пропускающий элемент только при первом появлении его ключа.
+// Ключ вычисляется функцией keyFn. Множество уже встреченных ключей защищено мьютексом,
+// поэтому один экземпляр Dedup можно безопасно использовать из нескольких горутин
+// (DataManager обрабатывает элементы конкурентно).
+//
+// Метод Process имеет сигнатуру Process(T) ([]T, error), поэтому Dedup[*Data, K]
+// удовлетворяет интерфейсу Processor.
+type Dedup[T any, K comparable] struct {
+	keyFn func(T) K
+
+	mu   sync.Mutex
+	seen map[K]struct{}
+}
+
+// Проверка на этапе компиляции, что Dedup подходит для конвейера.
+var _ Processor = (*Dedup[*Data, int])(nil)
+
+// NewDedup создает дедупликатор с функцией получения ключа keyFn.
+func NewDedup[T any, K comparable](keyFn func(T) K) *Dedup[T, K] {
+	return &Dedup[T, K]{
+		keyFn: keyFn,
+		seen:  make(map[K]struct{}),
+	}
+}
+
+// First сообщает, встретился ли ключ item впервые, и запоминает его.
+func (d *Dedup[T, K]) First(item T) bool {
+	key := d.keyFn(item)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.seen[key]; ok {
+		return false
+	}
+	d.seen[key] = struct{}{}
+	return true
+}
+
+// Process возвращает []T{item}, если ключ встретился впервые, и пустой результат для дубликатов.
+func (d *Dedup[T, K]) Process(item T) ([]T, error) {
+	if !d.First(item) {
+		return nil, nil
+	}
+	return []T{item}, nil
+}
+
+// Len возвращает количество уникальных ключей, встреченных на данный момент.
+func (d *Dedup[T, K]) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.seen)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDedupPassesOnlyFirstOccurrence(t *testing.T) {
+	d := NewDedup(func(d *Data) string { return d.Payload })
+
+	stream := []*Data{
+		{ID: 1, Payload: "a"},
+		{ID: 2, Payload: "b"},
+		{ID: 3, Payload: "a"},
+		{ID: 4, Payload: "c"},
+		{ID: 5, Payload: "b"},
+		{ID: 6, Payload: "a"},
+	}
+
+	var got []int
+	for _, item := range stream {
+		out, err := d.Process(item)
+		if err != nil {
+			t.Fatalf("неожиданная ошибка: %v", err)
+		}
+		for _, o := range out {
+			got = append(got, o.ID)
+		}
+	}
+
+	want := []int{1, 2, 4}
+	if len(got) != len(want) {
+		t.Fatalf("пропущены ID %v, ожидались %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got[%d] = %d, ожидалось %d", i, got[i], want[i])
+		}
+	}
+	if d.Len() != 3 {
+		t.Errorf("Len() = %d, ожидалось 3", d.Len())
+	}
+}
+
+func TestDedupNonPointerType(t *testing.T) {
+	d := NewDedup(func(s string) int { return len(s) })
+
+	var got []string
+	for _, s := range []string{"a", "bb", "c", "dd", "eee"} {
+		if d.First(s) {
+			got = append(got, s)
+		}
+	}
+	if len(got) != 3 || got[0] != "a" || got[1] != "bb" || got[2] != "eee" {
+		t.Errorf("got = %v, ожидалось [a bb eee]", got)
+	}
+}
+
+func TestDedupConcurrent(t *testing.T) {
+	const (
+		goroutines = 8
+		keys       = 100
+	)
+	d := NewDedup(func(d *Data) int { return d.ID })
+
+	// Каждая горутина пытается пропустить все ключи; каждый ключ должен пройти ровно один раз.
+	var passed [keys]atomic.Int32
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := 0; id < keys; id++ {
+				out, _ := d.Process(&Data{ID: id})
+				passed[id].Add(int32(len(out)))
+			}
+		}()
+	}
+	wg.Wait()
+
+	for id := range passed {
+		if n := passed[id].Load(); n != 1 {
+			t.Errorf("ключ %d пропущен %d раз, ожидался 1", id, n)
+		}
+	}
+}
+
+func TestDedupInManager(t *testing.T) {
+	writer := &mockWriter{}
+	reader := &sliceReader{data: []*Data{
+		{ID: 1, Payload: "x"},
+		{ID: 2, Payload: "x"},
+		{ID: 3, Payload: "y"},
+	}}
+	dedup := NewDedup(func(d *Data) string { return d.Payload })
+
+	NewDataManager(reader, []Processor{dedup}, writer).Manage()
+
+	if len(writer.data) != 2 {
+		t.Errorf("записано %d элементов, ожидалось 2", len(writer.data))
+	}
+}
+
+// sliceReader — Reader, возвращающий заранее заданные данные.
+type sliceReader struct {
+	data []*Data
+}
+
+func (r *sliceReader) Read() []*Data {
+	return r.data
+}
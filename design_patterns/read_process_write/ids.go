@@ -0,0 +1,51 @@
+package main
+
+import "sync/atomic"
+
+// IDGenerator выдает уникальные монотонно возрастающие ID для элементов, порожденных
+// процессорами. Без него процессор, разбивающий элемент на несколько, вынужден повторять
+// ID родителя, и потомков нельзя отличить друг от друга.
+//
+// ID источника назначаются извне, поэтому генератор должен их "увидеть" (Observe):
+// после этого он выдает только большие ID и не пересекается с ними. DataManager
+// делает это сам, если генератор подключен через UseIDGenerator.
+//
+// Генератор детерминирован — без случайности и без привязки ко времени: при
+// последовательной обработке одни и те же входные данные получают одни и те же ID.
+// Безопасен для конкурентного использования.
+type IDGenerator struct {
+	last atomic.Int64
+}
+
+// NewIDGenerator создает генератор, первый ID которого — 1 (или больше, после Observe).
+func NewIDGenerator() *IDGenerator {
+	return &IDGenerator{}
+}
+
+// Next возвращает новый ID, больший всех выданных и всех переданных в Observe.
+func (g *IDGenerator) Next() int {
+	return int(g.last.Add(1))
+}
+
+// Observe сообщает генератору о внешнем ID, чтобы последующие Next его не повторили.
+func (g *IDGenerator) Observe(id int) {
+	for {
+		last := g.last.Load()
+		if int64(id) <= last || g.last.CompareAndSwap(last, int64(id)) {
+			return
+		}
+	}
+}
+
+// Child создает потомка parent с новым ID, ссылкой на родителя и полезной нагрузкой payload.
+func (g *IDGenerator) Child(parent *Data, payload string) *Data {
+	return &Data{ID: g.Next(), ParentID: parent.ID, Payload: payload}
+}
+
+// UseIDGenerator подключает генератор ID: перед обработкой Manage передает ему ID всех
+// прочитанных элементов (см. IDGenerator.Observe). Тот же генератор нужно передать
+// процессорам, которые порождают новые элементы. Возвращает dm для цепочки вызовов.
+func (dm *DataManager) UseIDGenerator(g *IDGenerator) *DataManager {
+	dm.ids = g
+	return dm
+}
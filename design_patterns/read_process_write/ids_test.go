@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestIDGeneratorMonotonic(t *testing.T) {
+	g := NewIDGenerator()
+	if got := g.Next(); got != 1 {
+		t.Errorf("первый ID = %d, ожидался 1", got)
+	}
+	g.Observe(10)
+	g.Observe(5) // Меньший ID не уменьшает счетчик.
+	if got := g.Next(); got != 11 {
+		t.Errorf("после Observe(10) Next = %d, ожидалось 11", got)
+	}
+}
+
+func TestIDGeneratorConcurrentUnique(t *testing.T) {
+	g := NewIDGenerator()
+	const goroutines, perGoroutine = 8, 1000
+
+	var mu sync.Mutex
+	seen := make(map[int]bool)
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			local := make([]int, 0, perGoroutine)
+			for i := range perGoroutine {
+				g.Observe(i) // Внешние ID вперемешку с выдачей не должны ломать уникальность.
+				local = append(local, g.Next())
+			}
+			mu.Lock()
+			for _, id := range local {
+				if seen[id] {
+					t.Errorf("ID %d выдан дважды", id)
+				}
+				seen[id] = true
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if len(seen) != goroutines*perGoroutine {
+		t.Errorf("уникальных ID %d, ожидалось %d", len(seen), goroutines*perGoroutine)
+	}
+}
+
+func TestSplitAssignsFreshIDsWithParent(t *testing.T) {
+	var mu sync.Mutex
+	var middle []Data
+	ids := NewIDGenerator()
+	input := []*Data{{ID: 3, Payload: "a"}, {ID: 7, Payload: "b"}, {ID: 5, Payload: "c"}}
+	writer := &mockWriter{sortByID: true}
+	processors := []Processor{
+		&duplicatorProcessor{ids: ids},
+		NewAuditProcessor(func(d *Data) {
+			mu.Lock()
+			middle = append(middle, *d)
+			mu.Unlock()
+		}),
+		&duplicatorProcessor{ids: ids},
+	}
+	NewDataManager(&sliceReader{data: input}, processors, writer).UseIDGenerator(ids).Manage()
+
+	if len(middle) != 6 || len(writer.data) != 12 {
+		t.Fatalf("после первого разбиения %d элементов, после второго %d; ожидалось 6 и 12", len(middle), len(writer.data))
+	}
+
+	// Все ID уникальны и не совпадают с ID источника.
+	parents := make(map[int]Data)
+	for _, d := range input {
+		parents[d.ID] = *d
+	}
+	for _, d := range middle {
+		if _, dup := parents[d.ID]; dup {
+			t.Fatalf("ID %d промежуточного элемента уже занят", d.ID)
+		}
+		parents[d.ID] = d
+	}
+	for _, d := range writer.data {
+		if _, dup := parents[d.ID]; dup {
+			t.Fatalf("ID %d итогового элемента уже занят", d.ID)
+		}
+	}
+
+	// Цепочка ParentID от каждого итогового элемента ведет к исходному через промежуточный.
+	for _, d := range writer.data {
+		mid, ok := parents[d.ParentID]
+		if !ok || mid.ParentID == 0 {
+			t.Errorf("элемент %d: родитель %d не промежуточный элемент", d.ID, d.ParentID)
+			continue
+		}
+		root, ok := parents[mid.ParentID]
+		if !ok || root.ParentID != 0 {
+			t.Errorf("элемент %d: промежуточный %d ссылается не на исходный %d", d.ID, mid.ID, mid.ParentID)
+			continue
+		}
+		if !strings.HasPrefix(d.Payload, root.Payload+" (копия ") {
+			t.Errorf("элемент %d: payload %q не получен из %q", d.ID, d.Payload, root.Payload)
+		}
+	}
+}
@@ -7,19 +7,29 @@
 package main
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 
+	"github.com/andrewhigh08/exp/internal/workstealing"
 	"golang.org/x/sync/errgroup"
 )
 
+// ErrStopPipeline — сигнал процессора "остановить весь конвейер": нужное уже найдено.
+// Это не ошибка: Manage прекращает обработку и записывает результаты, собранные
+// к этому моменту. Процессор может вернуть ее обернутой (проверяется через errors.Is).
+var ErrStopPipeline = errors.New("конвейер остановлен процессором")
+
 // Data — структура данных, которую мы обрабатываем.
 type Data struct {
-	ID      int
-	Payload string
+	ID       int
+	ParentID int `json:",omitempty"` // ID элемента, из которого этот получен процессором (0 — прочитан из источника).
+	Payload  string
 }
 
 // Reader — интерфейс для источника данных.
@@ -48,6 +58,11 @@ type DataManager struct {
 	reader     Reader
 	processors []Processor
 	writer     Writer
+
+	workers     int              // Число воркеров планировщика с кражей работы; 0 — горутина на каждый элемент.
+	maxBuffered int              // Лимит результатов, ожидающих записи (см. LimitBuffered); 0 — без лимита.
+	ids         *IDGenerator     // Генератор ID для порожденных элементов (см. UseIDGenerator).
+	metrics     *pipelineMetrics // Статистика процессоров (см. CollectMetrics); nil — не собирается.
 }
 
 // NewDataManager — конструктор для DataManager.
@@ -59,62 +74,103 @@ func NewDataManager(reader Reader, processors []Processor, writer Writer) *DataM
 	}
 }
 
+// UseWorkStealing переключает Manage на фиксированный пул из workers воркеров с кражей
+// работы (см. workstealing.Scheduler) вместо отдельной горутины на каждый элемент.
+// Это выгодно для CPU-нагруженных процессоров и большого числа элементов: горутин не
+// больше, чем ядер, а неравномерно тяжелые элементы перераспределяются между воркерами.
+// workers <= 0 возвращает режим по умолчанию. Возвращает dm для цепочки вызовов.
+func (dm *DataManager) UseWorkStealing(workers int) *DataManager {
+	dm.workers = max(workers, 0)
+	return dm
+}
+
 // Manage управляет потоком данных: читает, конкурентно обрабатывает и записывает.
+//
+// Если процессор возвращает ErrStopPipeline, новые элементы больше не запускаются, а уже
+// запущенные прекращают обработку перед следующим шагом. Элементы, прошедшие все
+// процессоры до остановки, записываются как обычно.
 func (dm *DataManager) Manage() {
+	// Признак остановки принадлежит вызову: ErrStopPipeline в одном Manage
+	// не должен останавливать другой, идущий параллельно на том же DataManager.
+	var stopped atomic.Bool
+	if dm.metrics != nil {
+		dm.metrics.reset()
+	}
 	initialData := dm.reader.Read()
 	log.Printf("Прочитано %d элементов из источника.", len(initialData))
+	if dm.ids != nil {
+		for _, item := range initialData {
+			dm.ids.Observe(item.ID)
+		}
+	}
 
 	var finalResults []*Data
 	var finalMu sync.Mutex // Мьютекс для безопасного добавления в общий срез результатов
-	var eg errgroup.Group
-
-	// Обрабатываем каждый элемент из начального набора в отдельной горутине.
-	for _, item := range initialData {
-		item := item // Создаем локальную копию для безопасного использования в замыкании.
-		eg.Go(func() error {
-			// `currentData` представляет собой набор данных на входе для цепочки процессоров.
-			// Начинаем с одного элемента.
-			currentData := []*Data{item}
-
-			// Последовательно пропускаем данные через все процессоры.
-			for _, processor := range dm.processors {
-				// `nextData` будет содержать результаты работы текущего процессора.
-				var nextData []*Data
-				for _, dataItem := range currentData {
-					processed, err := processor.Process(dataItem)
-					if err != nil {
-						// Если процессор вернул ошибку, пропускаем этот элемент
-						// и не передаем его дальше по цепочке.
-						log.Printf("Ошибка обработки элемента ID %d: %v. Элемент пропущен.", dataItem.ID, err)
-						continue // Пропускаем только `dataItem`, а не весь `item`
-					}
-					nextData = append(nextData, processed...)
-				}
-				// Результат этого шага становится входом для следующего.
-				currentData = nextData
-
-				// Если на каком-то шаге все данные были отфильтрованы,
-				// нет смысла продолжать обработку.
-				if len(currentData) == 0 {
-					break
-				}
+	collect := func(results []*Data) {
+		// Если после всех процессоров остались данные, добавляем их в общий результат.
+		if len(results) > 0 {
+			finalMu.Lock()
+			finalResults = append(finalResults, results...)
+			finalMu.Unlock()
+		}
+	}
+	// С лимитом буфера результаты сразу уходят в Writer, а не копятся до конца.
+	var sink *boundedSink
+	if dm.maxBuffered > 0 {
+		sink = newBoundedSink(dm.writer, dm.maxBuffered)
+		collect = sink.collect
+	}
+
+	if dm.workers > 0 {
+		// Фиксированный пул воркеров с кражей работы вместо горутины на элемент.
+		sched := workstealing.New(dm.workers, func(item *Data) { collect(dm.processItem(item, &stopped)) })
+		for _, item := range initialData {
+			if stopped.Load() {
+				break // Конвейер остановлен: оставшиеся элементы не отправляем.
 			}
+			_ = sched.Submit(item) // Ошибка возможна только после Close.
+		}
+		sched.Close()
+	} else {
+		var eg errgroup.Group
+		if dm.maxBuffered > 0 {
+			// Горутина, ждущая места в буфере, держит свой результат в памяти:
+			// ограничиваем и число одновременно обрабатываемых элементов.
+			eg.SetLimit(dm.maxBuffered)
+		}
+		// Обрабатываем каждый элемент из начального набора в отдельной горутине.
+		for _, item := range initialData {
+			if stopped.Load() {
+				break // Конвейер остановлен: оставшиеся элементы не запускаем.
+			}
+			item := item // Создаем локальную копию для безопасного использования в замыкании.
+			eg.Go(func() error {
+				collect(dm.processItem(item, &stopped))
+				return nil
+			})
+		}
 
-			// Если после всех процессоров остались данные, добавляем их в общий результат.
-			if len(currentData) > 0 {
-				finalMu.Lock()
-				finalResults = append(finalResults, currentData...)
-				finalMu.Unlock()
+		// Ожидаем завершения всех горутин. errgroup вернет первую возникшую ошибку.
+		if err := eg.Wait(); err != nil {
+			log.Printf("Произошла критическая ошибка в одной из горутин: %v", err)
+			if sink != nil {
+				sink.close()
 			}
-			return nil
-		})
+			return
+		}
 	}
-
-	// Ожидаем завершения всех горутин. errgroup вернет первую возникшую ошибку.
-	if err := eg.Wait(); err != nil {
-		log.Printf("Произошла критическая ошибка в одной из горутин: %v", err)
+	if sink != nil {
+		// Результаты уже переданы в Writer по мере готовности; дожидаемся последнего пакета.
+		sink.close()
+		log.Printf("Результатов в буфере записи одновременно: до %d из %d.", sink.peak.Load(), dm.maxBuffered)
+		if stopped.Load() {
+			log.Println("Конвейер остановлен досрочно.")
+		}
 		return
 	}
+	if stopped.Load() {
+		log.Printf("Конвейер остановлен досрочно, собрано %d элементов.", len(finalResults))
+	}
 
 	// Записываем все собранные результаты одним пакетом.
 	if len(finalResults) > 0 {
@@ -124,6 +180,49 @@ func (dm *DataManager) Manage() {
 	}
 }
 
+// processItem пропускает один элемент через всю цепочку процессоров и возвращает результат.
+// Если какой-то процессор остановил конвейер (см. stopped), незавершенный элемент
+// отбрасывается (nil).
+func (dm *DataManager) processItem(item *Data, stopped *atomic.Bool) []*Data {
+	// `currentData` представляет собой набор данных на входе для цепочки процессоров.
+	// Начинаем с одного элемента.
+	currentData := []*Data{item}
+
+	// Последовательно пропускаем данные через все процессоры.
+	for _, processor := range dm.processors {
+		if stopped.Load() {
+			return nil // Конвейер остановлен: незавершенный элемент отбрасывается.
+		}
+		// `nextData` будет содержать результаты работы текущего процессора.
+		var nextData []*Data
+		for _, dataItem := range currentData {
+			processed, err := processor.Process(dataItem)
+			if errors.Is(err, ErrStopPipeline) {
+				log.Printf("Процессор остановил конвейер на элементе ID %d.", dataItem.ID)
+				stopped.Store(true)
+				return nil
+			}
+			if err != nil {
+				// Если процессор вернул ошибку, пропускаем этот элемент
+				// и не передаем его дальше по цепочке.
+				log.Printf("Ошибка обработки элемента ID %d: %v. Элемент пропущен.", dataItem.ID, err)
+				continue // Пропускаем только `dataItem`, а не весь `item`
+			}
+			nextData = append(nextData, processed...)
+		}
+		// Результат этого шага становится входом для следующего.
+		currentData = nextData
+
+		// Если на каком-то шаге все данные были отфильтрованы,
+		// нет смысла продолжать обработку.
+		if len(currentData) == 0 {
+			break
+		}
+	}
+
+	return currentData
+}
+
 // --- Mock-реализации для демонстрации ---
 
 type mockReader struct{}
@@ -133,10 +232,15 @@ func (r *mockReader) Read() []*Data {
 		{ID: 1, Payload: "hello"},
 		{ID: 2, Payload: "world"},
 		{ID: 3, Payload: "error"}, // Этот элемент вызовет ошибку
+		{ID: 4, Payload: "hello"}, // Дубликат ID 1 по Payload: Dedup пропустит только один из них
 	}
 }
 
-type duplicatorProcessor struct{}
+type duplicatorProcessor struct {
+	// ids выдает копиям собственные ID со ссылкой на родителя.
+	// Если nil, копии повторяют ID исходного элемента.
+	ids *IDGenerator
+}
 
 // Process дублирует каждый элемент.
 func (p *duplicatorProcessor) Process(d *Data) ([]*Data, error) {
@@ -145,6 +249,12 @@ func (p *duplicatorProcessor) Process(d *Data) ([]*Data, error) {
 	if d.Payload == "error" {
 		return nil, errors.New("некорректный payload")
 	}
+	if p.ids != nil {
+		return []*Data{
+			p.ids.Child(d, d.Payload+" (копия 1)"),
+			p.ids.Child(d, d.Payload+" (копия 2)"),
+		}, nil
+	}
 	// Возвращаем два новых элемента
 	return []*Data{
 		{ID: d.ID, Payload: d.Payload + " (копия 1)"},
@@ -165,6 +275,8 @@ func (p *upperCaseProcessor) Process(d *Data) ([]*Data, error) {
 type mockWriter struct {
 	mu   sync.Mutex
 	data []*Data
+	// sortByID включает детерминированный порядок: накопленные данные упорядочиваются по ID.
+	sortByID bool
 }
 
 func (w *mockWriter) Write(data []*Data) {
@@ -172,21 +284,36 @@ func (w *mockWriter) Write(data []*Data) {
 	defer w.mu.Unlock()
 	log.Printf("Запись %d элементов...", len(data))
 	w.data = append(w.data, data...)
+	if w.sortByID {
+		slices.SortStableFunc(w.data, func(a, b *Data) int { return cmp.Compare(a.ID, b.ID) })
+	}
 }
 
 func main() {
 	reader := &mockReader{}
 	writer := &mockWriter{}
+	// Генератор дает копиям собственные ID, а ParentID указывает на исходный элемент.
+	ids := NewIDGenerator()
 	processors := []Processor{
-		&duplicatorProcessor{},
+		NewDedup(func(d *Data) string { return d.Payload }),
+		&duplicatorProcessor{ids: ids},
 		&upperCaseProcessor{},
+		// Аудит в конце цепочки: наблюдаем итоговые элементы, не меняя их.
+		NewAuditProcessor(func(d *Data) { log.Printf("Аудит: ID %d, Payload %q", d.ID, d.Payload) }),
 	}
 
-	manager := NewDataManager(reader, processors, writer)
+	// SortedWriter делает вывод детерминированным: горутины завершаются в произвольном порядке.
+	manager := NewDataManager(reader, processors, NewSortedWriter(writer, ByID)).UseIDGenerator(ids).CollectMetrics()
 	manager.Manage()
 
 	fmt.Println("\n--- Итоговые данные в Writer ---")
 	for _, d := range writer.data {
-		fmt.Printf("ID: %d, Payload: %s\n", d.ID, d.Payload)
+		fmt.Printf("ID: %d, ParentID: %d, Payload: %s\n", d.ID, d.ParentID, d.Payload)
+	}
+
+	fmt.Println("\n--- Статистика процессоров ---")
+	for _, st := range manager.Metrics() {
+		fmt.Printf("%d. %s: вызовов %d, ошибок %d, на выходе %d, p50 %v, max %v\n",
+			st.Stage, st.Name, st.Calls, st.Errors, st.Out, st.P50, st.Max)
 	}
 }
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// StageStats — статистика одного процессора за последний Manage.
+type StageStats struct {
+	Stage  int    // Позиция процессора в цепочке, с 0.
+	Name   string // Тип процессора, например "*main.duplicatorProcessor".
+	Calls  int    // Вызовы Process.
+	Errors int    // Вызовы, вернувшие ошибку (включая ErrStopPipeline).
+	Out    int    // Элементы, возвращенные процессором.
+
+	// Распределение длительности одного вызова Process.
+	Total, Min, Max, P50, P95, P99 time.Duration
+
+	// Throughput — вызовов в секунду от начала первого вызова до конца последнего.
+	// Вызовы идут конкурентно, поэтому это пропускная способность шага в целом,
+	// а не одного вызова (она может быть больше Calls/Total).
+	Throughput float64
+}
+
+// CollectMetrics включает сбор статистики по каждому процессору (см. Metrics):
+// процессоры оборачиваются измеряющими декораторами, сами они ничего не замечают.
+// Статистика сбрасывается в начале каждого Manage. Возвращает dm для цепочки вызовов.
+func (dm *DataManager) CollectMetrics() *DataManager {
+	if dm.metrics != nil {
+		return dm
+	}
+	dm.metrics = &pipelineMetrics{stages: make([]*stageMetrics, len(dm.processors))}
+	wrapped := make([]Processor, len(dm.processors))
+	for i, p := range dm.processors {
+		stage := &stageMetrics{name: fmt.Sprintf("%T", p)}
+		dm.metrics.stages[i] = stage
+		wrapped[i] = &timedProcessor{next: p, stats: stage}
+	}
+	dm.processors = wrapped
+	return dm
+}
+
+// Metrics возвращает статистику процессоров за последний Manage в порядке цепочки.
+// Без CollectMetrics возвращает nil.
+func (dm *DataManager) Metrics() []StageStats {
+	if dm.metrics == nil {
+		return nil
+	}
+	stats := make([]StageStats, len(dm.metrics.stages))
+	for i, stage := range dm.metrics.stages {
+		stats[i] = stage.stats()
+		stats[i].Stage = i
+	}
+	return stats
+}
+
+// pipelineMetrics — статистика всех процессоров конвейера.
+type pipelineMetrics struct {
+	stages []*stageMetrics
+}
+
+func (m *pipelineMetrics) reset() {
+	for _, stage := range m.stages {
+		stage.reset()
+	}
+}
+
+// stageMetrics накапливает измерения одного процессора. Длительности хранятся
+// целиком, чтобы считать точные перцентили: память растет с числом вызовов.
+type stageMetrics struct {
+	name string
+
+	mu          sync.Mutex
+	durations   []time.Duration
+	errors      int
+	out         int
+	first, last time.Time // Начало первого и конец последнего вызова.
+}
+
+func (s *stageMetrics) observe(start time.Time, took time.Duration, out int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.durations = append(s.durations, took)
+	s.out += out
+	if err != nil {
+		s.errors++
+	}
+	if s.first.IsZero() || start.Before(s.first) {
+		s.first = start
+	}
+	if end := start.Add(took); end.After(s.last) {
+		s.last = end
+	}
+}
+
+func (s *stageMetrics) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.durations = nil
+	s.errors, s.out = 0, 0
+	s.first, s.last = time.Time{}, time.Time{}
+}
+
+func (s *stageMetrics) stats() StageStats {
+	s.mu.Lock()
+	durations := slices.Clone(s.durations)
+	st := StageStats{Name: s.name, Calls: len(durations), Errors: s.errors, Out: s.out}
+	elapsed := s.last.Sub(s.first)
+	s.mu.Unlock()
+
+	if len(durations) == 0 {
+		return st
+	}
+	slices.Sort(durations)
+	for _, d := range durations {
+		st.Total += d
+	}
+	st.Min, st.Max = durations[0], durations[len(durations)-1]
+	st.P50 = percentile(durations, 0.50)
+	st.P95 = percentile(durations, 0.95)
+	st.P99 = percentile(durations, 0.99)
+	if elapsed > 0 {
+		st.Throughput = float64(len(durations)) / elapsed.Seconds()
+	}
+	return st
+}
+
+// percentile возвращает перцентиль p (от 0 до 1) отсортированного непустого среза
+// методом ближайшего ранга.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
+
+// timedProcessor измеряет вызовы Process обернутого процессора.
+type timedProcessor struct {
+	next  Processor
+	stats *stageMetrics
+}
+
+func (p *timedProcessor) Process(d *Data) ([]*Data, error) {
+	start := time.Now()
+	out, err := p.next.Process(d)
+	p.stats.observe(start, time.Since(start), len(out), err)
+	return out, err
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// sleepProcessor обрабатывает элемент за delay и пропускает его дальше без изменений.
+type sleepProcessor struct{ delay time.Duration }
+
+func (p *sleepProcessor) Process(d *Data) ([]*Data, error) {
+	time.Sleep(p.delay)
+	return []*Data{d}, nil
+}
+
+func TestMetricsRecordStageTiming(t *testing.T) {
+	const fast, slow = 5 * time.Millisecond, 25 * time.Millisecond
+	processors := []Processor{&sleepProcessor{delay: fast}, &duplicatorProcessor{}, &sleepProcessor{delay: slow}}
+	dm := NewDataManager(&sliceReader{data: numberedData(10)}, processors, &mockWriter{}).CollectMetrics()
+	dm.Manage()
+
+	stats := dm.Metrics()
+	if len(stats) != 3 {
+		t.Fatalf("статистика по %d шагам, ожидалось 3", len(stats))
+	}
+	for i, st := range stats {
+		if st.Stage != i {
+			t.Errorf("шаг %d: Stage = %d", i, st.Stage)
+		}
+	}
+
+	// Каждый элемент проходит первый шаг один раз, последний — дважды (после дублирования).
+	checks := []struct {
+		stage int
+		calls int
+		out   int
+		delay time.Duration
+		name  string
+	}{
+		{0, 10, 10, fast, "*main.sleepProcessor"},
+		{1, 10, 20, 0, "*main.duplicatorProcessor"},
+		{2, 20, 20, slow, "*main.sleepProcessor"},
+	}
+	for _, c := range checks {
+		st := stats[c.stage]
+		if st.Name != c.name || st.Calls != c.calls || st.Out != c.out || st.Errors != 0 {
+			t.Errorf("шаг %d: %s, вызовов %d, на выходе %d, ошибок %d; ожидалось %s, %d, %d, 0",
+				c.stage, st.Name, st.Calls, st.Out, st.Errors, c.name, c.calls, c.out)
+		}
+		if c.delay == 0 {
+			continue
+		}
+		// Sleep не возвращается раньше срока; верхняя граница — с запасом на планировщик.
+		if st.Min < c.delay || st.P50 < c.delay || st.Max > c.delay+50*time.Millisecond {
+			t.Errorf("шаг %d: Min %v, P50 %v, Max %v; ожидалось около %v", c.stage, st.Min, st.P50, st.Max, c.delay)
+		}
+		if st.Total < time.Duration(c.calls)*c.delay {
+			t.Errorf("шаг %d: Total %v меньше %d × %v", c.stage, st.Total, c.calls, c.delay)
+		}
+		if !(st.Min <= st.P50 && st.P50 <= st.P95 && st.P95 <= st.P99 && st.P99 <= st.Max) {
+			t.Errorf("шаг %d: перцентили не упорядочены: %+v", c.stage, st)
+		}
+		// Элементы обрабатываются конкурентно: шаг пропускает больше одного вызова за delay.
+		if sequential := 1 / c.delay.Seconds(); st.Throughput <= sequential {
+			t.Errorf("шаг %d: пропускная способность %.0f/с, ожидалось больше %.0f/с", c.stage, st.Throughput, sequential)
+		}
+	}
+}
+
+func TestMetricsCountErrorsAndResetPerManage(t *testing.T) {
+	failOdd := funcProcessor(func(d *Data) ([]*Data, error) {
+		if d.ID%2 == 1 {
+			return nil, errors.New("нечетный ID")
+		}
+		return []*Data{d}, nil
+	})
+	reader := &sliceReader{data: numberedData(6)}
+	dm := NewDataManager(reader, []Processor{failOdd}, &mockWriter{}).CollectMetrics()
+
+	dm.Manage()
+	if st := dm.Metrics()[0]; st.Calls != 6 || st.Errors != 3 || st.Out != 3 {
+		t.Errorf("вызовов %d, ошибок %d, на выходе %d; ожидалось 6, 3, 3", st.Calls, st.Errors, st.Out)
+	}
+
+	// Повторный Manage начинает статистику заново.
+	reader.data = numberedData(2)
+	dm.Manage()
+	if st := dm.Metrics()[0]; st.Calls != 2 || st.Errors != 1 {
+		t.Errorf("после второго Manage вызовов %d, ошибок %d; ожидалось 2 и 1", st.Calls, st.Errors)
+	}
+}
+
+func TestMetricsDisabledByDefault(t *testing.T) {
+	dm := NewDataManager(&sliceReader{data: numberedData(1)}, []Processor{&duplicatorProcessor{}}, &mockWriter{})
+	dm.Manage()
+	if stats := dm.Metrics(); stats != nil {
+		t.Errorf("Metrics = %v без CollectMetrics, ожидался nil", stats)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i + 1)
+	}
+	for _, tt := range []struct {
+		p    float64
+		want time.Duration
+	}{{0, 1}, {0.5, 50}, {0.95, 95}, {0.99, 99}, {1, 100}} {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %d, ожидалось %d", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile([]time.Duration{7}, 0.99); got != 7 {
+		t.Errorf("перцентиль одного значения = %d, ожидалось 7", got)
+	}
+}
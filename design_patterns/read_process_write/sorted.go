@@ -0,0 +1,48 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SortedWriter — декоратор Writer, который перед записью упорядочивает пакет по ключу.
+//
+// Manage собирает результаты в порядке завершения горутин, поэтому без сортировки
+// порядок записей от запуска к запуску разный. Сортировка устойчивая: записи с равным
+// ключом (например, копии одного элемента) сохраняют взаимный порядок внутри пакета.
+// Входной срез не изменяется — сортируется копия.
+type SortedWriter[K cmp.Ordered] struct {
+	next Writer
+	key  func(*Data) K
+}
+
+// NewSortedWriter оборачивает next, сортируя каждый пакет по key.
+func NewSortedWriter[K cmp.Ordered](next Writer, key func(*Data) K) *SortedWriter[K] {
+	return &SortedWriter[K]{next: next, key: key}
+}
+
+// Write реализует интерфейс Writer.
+func (w *SortedWriter[K]) Write(data []*Data) {
+	sorted := slices.Clone(data)
+	slices.SortStableFunc(sorted, func(a, b *Data) int {
+		return cmp.Compare(w.key(a), w.key(b))
+	})
+	w.next.Write(sorted)
+}
+
+// CanAccept передает сигнал перегрузки обернутого Writer (см. BackpressureWriter).
+// Если обернутый Writer его не поддерживает, SortedWriter всегда готов принять пакет.
+func (w *SortedWriter[K]) CanAccept() bool {
+	if bw, ok := w.next.(BackpressureWriter); ok {
+		return bw.CanAccept()
+	}
+	return true
+}
+
+// ByID — ключ сортировки по Data.ID.
+func ByID(d *Data) int {
+	return d.ID
+}
+
+// Проверка на этапе компиляции, что SortedWriter реализует Writer и BackpressureWriter.
+var _ BackpressureWriter = (*SortedWriter[int])(nil)
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// ids возвращает ID записей в порядке следования.
+func ids(data []*Data) []int {
+	res := make([]int, len(data))
+	for i, d := range data {
+		res[i] = d.ID
+	}
+	return res
+}
+
+func TestSortedWriterSortsBatch(t *testing.T) {
+	writer := &mockWriter{}
+	batch := []*Data{
+		{ID: 3, Payload: "c"},
+		{ID: 1, Payload: "a1"},
+		{ID: 2, Payload: "b"},
+		{ID: 1, Payload: "a2"},
+	}
+	NewSortedWriter(writer, ByID).Write(batch)
+
+	if got := ids(writer.data); !slices.Equal(got, []int{1, 1, 2, 3}) {
+		t.Errorf("записаны ID %v, ожидалось [1 1 2 3]", got)
+	}
+	// Сортировка устойчивая: записи с равным ключом сохраняют исходный порядок.
+	if writer.data[0].Payload != "a1" || writer.data[1].Payload != "a2" {
+		t.Errorf("нарушен порядок равных ключей: %q, %q", writer.data[0].Payload, writer.data[1].Payload)
+	}
+	// Входной пакет не изменяется.
+	if got := ids(batch); !slices.Equal(got, []int{3, 1, 2, 1}) {
+		t.Errorf("входной пакет изменен: %v", got)
+	}
+}
+
+func TestSortedWriterCustomKey(t *testing.T) {
+	writer := &mockWriter{}
+	NewSortedWriter(writer, func(d *Data) string { return d.Payload }).Write([]*Data{
+		{ID: 1, Payload: "pear"},
+		{ID: 2, Payload: "apple"},
+		{ID: 3, Payload: "fig"},
+	})
+
+	if got := ids(writer.data); !slices.Equal(got, []int{2, 3, 1}) {
+		t.Errorf("записаны ID %v, ожидалось [2 3 1]", got)
+	}
+}
+
+func TestMockWriterSortByID(t *testing.T) {
+	writer := &mockWriter{sortByID: true}
+	writer.Write([]*Data{{ID: 5}, {ID: 2}})
+	writer.Write([]*Data{{ID: 4}, {ID: 1}})
+
+	if got := ids(writer.data); !slices.Equal(got, []int{1, 2, 4, 5}) {
+		t.Errorf("накоплены ID %v, ожидалось [1 2 4 5]", got)
+	}
+}
+
+func TestManagerWithSortedWriterIsDeterministic(t *testing.T) {
+	data := make([]*Data, 20)
+	for i := range data {
+		data[i] = &Data{ID: len(data) - i, Payload: "item"}
+	}
+
+	for run := 0; run < 5; run++ {
+		writer := &mockWriter{}
+		reader := &sliceReader{data: data}
+		NewDataManager(reader, []Processor{&duplicatorProcessor{}}, NewSortedWriter(writer, ByID)).Manage()
+
+		got := ids(writer.data)
+		if !slices.IsSorted(got) || len(got) != 2*len(data) {
+			t.Fatalf("запуск %d: записаны ID %v", run, got)
+		}
+	}
+}
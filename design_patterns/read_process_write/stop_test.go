@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// funcProcessor — Processor из функции.
+type funcProcessor func(d *Data) ([]*Data, error)
+
+func (f funcProcessor) Process(d *Data) ([]*Data, error) { return f(d) }
+
+func TestManageStopsOnErrStopPipeline(t *testing.T) {
+	// Элементы 1 и 2 успевают пройти весь конвейер, элемент 3 останавливает его,
+	// а элементы 4 и 5 застают остановку между шагами.
+	var passed sync.WaitGroup
+	passed.Add(2)
+	stopping := make(chan struct{})
+
+	first := funcProcessor(func(d *Data) ([]*Data, error) {
+		switch d.ID {
+		case 3:
+			passed.Wait()
+			close(stopping)
+			return nil, fmt.Errorf("найден элемент %d: %w", d.ID, ErrStopPipeline)
+		case 4, 5:
+			// Ждем остановки, чтобы элемент дошел до следующего шага уже после нее:
+			// Manage отмечает остановку сразу после возврата ErrStopPipeline.
+			<-stopping
+			time.Sleep(20 * time.Millisecond)
+		}
+		return []*Data{d}, nil
+	})
+	var lastCalls []int
+	var mu sync.Mutex
+	last := funcProcessor(func(d *Data) ([]*Data, error) {
+		mu.Lock()
+		lastCalls = append(lastCalls, d.ID)
+		mu.Unlock()
+		if d.ID <= 2 {
+			defer passed.Done()
+		}
+		return []*Data{d}, nil
+	})
+
+	reader := &sliceReader{data: []*Data{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}}
+	writer := &mockWriter{sortByID: true}
+	dm := NewDataManager(reader, []Processor{first, last}, writer)
+	dm.Manage()
+
+	if got := ids(writer.data); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("записаны ID %v, ожидались частичные результаты [1 2]", got)
+	}
+	slices.Sort(lastCalls)
+	if !slices.Equal(lastCalls, []int{1, 2}) {
+		t.Errorf("последний шаг вызван для %v: после остановки обработка должна прекращаться", lastCalls)
+	}
+}
+
+func TestManageResetsStopBetweenRuns(t *testing.T) {
+	stopOnce := true
+	var mu sync.Mutex
+	p := funcProcessor(func(d *Data) ([]*Data, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if stopOnce {
+			stopOnce = false
+			return nil, ErrStopPipeline
+		}
+		return []*Data{d}, nil
+	})
+	writer := &mockWriter{}
+	dm := NewDataManager(&sliceReader{data: []*Data{{ID: 1}}}, []Processor{p}, writer)
+
+	dm.Manage() // Остановлен на единственном элементе.
+	dm.Manage() // Новый запуск обрабатывает элемент как обычно.
+	if got := ids(writer.data); !slices.Equal(got, []int{1}) {
+		t.Errorf("записаны ID %v, ожидалось [1]", got)
+	}
+}
+
+// queueReader отдает каждому вызову Read следующий набор данных.
+type queueReader struct {
+	mu      sync.Mutex
+	batches [][]*Data
+}
+
+func (r *queueReader) Read() []*Data {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	batch := r.batches[0]
+	r.batches = r.batches[1:]
+	return batch
+}
+
+func TestConcurrentManageStopIsPerCall(t *testing.T) {
+	// Первый вызов обрабатывает элементы 1–3 и ждет, пока второй вызов
+	// остановится на элементе 100. Остановка второго не должна затронуть первый.
+	gate := make(chan struct{})
+	var started sync.WaitGroup
+	started.Add(3)
+	first := funcProcessor(func(d *Data) ([]*Data, error) {
+		if d.ID == 100 {
+			return nil, ErrStopPipeline
+		}
+		started.Done()
+		<-gate
+		return []*Data{d}, nil
+	})
+	pass := funcProcessor(func(d *Data) ([]*Data, error) { return []*Data{d}, nil })
+	reader := &queueReader{batches: [][]*Data{{{ID: 1}, {ID: 2}, {ID: 3}}, {{ID: 100}}}}
+	writer := &mockWriter{sortByID: true}
+	dm := NewDataManager(reader, []Processor{first, pass}, writer)
+
+	done := make(chan struct{})
+	go func() {
+		dm.Manage()
+		close(done)
+	}()
+	started.Wait()
+	dm.Manage() // Останавливается на элементе 100.
+	close(gate)
+	<-done
+
+	if got := ids(writer.data); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("записаны ID %v, ожидались [1 2 3]: остановка одного Manage затронула другой", got)
+	}
+}
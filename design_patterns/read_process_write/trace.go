@@ -0,0 +1,207 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+)
+
+// TraceEvent — один записанный вызов шага конвейера: Read, Process или Write.
+// Данные хранятся по значению (снимок на момент вызова), потому что процессоры
+// могут менять *Data на месте.
+type TraceEvent struct {
+	Stage  int    `json:"stage"` // Порядковый номер шага в конвейере: 0 — Reader, далее процессоры, Writer.
+	Step   string `json:"step"`  // "read", "process:<имя>" или "write".
+	Input  []Data `json:"input,omitempty"`
+	Output []Data `json:"output,omitempty"`
+	Err    string `json:"err,omitempty"`
+}
+
+// Trace — трасса выполнения конвейера, пригодная для сохранения в JSON и сравнения с эталоном.
+type Trace struct {
+	Events []TraceEvent `json:"events"`
+}
+
+// Recorder записывает вызовы шагов конвейера в трассу. Шаги оборачиваются декораторами
+// Reader, Processor и Writer в порядке их следования в конвейере — этот порядок задает Stage.
+//
+// DataManager обрабатывает элементы конкурентно, поэтому вызовы Process приходят
+// в произвольном порядке. Trace упорядочивает события по шагу и входным данным,
+// так что трасса одного и того же конвейера на одних и тех же данных всегда одинакова.
+// Writer стоит записывать под SortedWriter, иначе порядок элементов в пакете случаен.
+type Recorder struct {
+	mu     sync.Mutex
+	stages int
+	events []TraceEvent
+}
+
+// NewRecorder создает пустой Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// nextStage выдает номер очередному оборачиваемому шагу.
+func (r *Recorder) nextStage() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stage := r.stages
+	r.stages++
+	return stage
+}
+
+func (r *Recorder) record(e TraceEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+// Reader оборачивает источник данных, записывая прочитанное.
+func (r *Recorder) Reader(next Reader) Reader {
+	return &recordingReader{next: next, rec: r, stage: r.nextStage()}
+}
+
+// Processor оборачивает шаг обработки; name отличает шаги в трассе.
+func (r *Recorder) Processor(name string, next Processor) Processor {
+	return &recordingProcessor{next: next, rec: r, stage: r.nextStage(), name: name}
+}
+
+// Writer оборачивает приемник данных, записывая каждый пакет.
+func (r *Recorder) Writer(next Writer) Writer {
+	return &recordingWriter{next: next, rec: r, stage: r.nextStage()}
+}
+
+// Trace возвращает записанную трассу в каноническом порядке: по Stage,
+// а внутри шага — по входным, затем выходным данным.
+func (r *Recorder) Trace() Trace {
+	r.mu.Lock()
+	events := slices.Clone(r.events)
+	r.mu.Unlock()
+
+	slices.SortStableFunc(events, func(a, b TraceEvent) int {
+		return cmp.Or(
+			cmp.Compare(a.Stage, b.Stage),
+			slices.CompareFunc(a.Input, b.Input, compareData),
+			slices.CompareFunc(a.Output, b.Output, compareData),
+			cmp.Compare(a.Err, b.Err),
+		)
+	})
+	return Trace{Events: events}
+}
+
+func compareData(a, b Data) int {
+	return cmp.Or(cmp.Compare(a.ID, b.ID), cmp.Compare(a.Payload, b.Payload))
+}
+
+// snapshot копирует данные по значению.
+func snapshot(data []*Data) []Data {
+	if len(data) == 0 {
+		return nil
+	}
+	res := make([]Data, len(data))
+	for i, d := range data {
+		res[i] = *d
+	}
+	return res
+}
+
+type recordingReader struct {
+	next  Reader
+	rec   *Recorder
+	stage int
+}
+
+func (r *recordingReader) Read() []*Data {
+	data := r.next.Read()
+	r.rec.record(TraceEvent{Stage: r.stage, Step: "read", Output: snapshot(data)})
+	return data
+}
+
+type recordingProcessor struct {
+	next  Processor
+	rec   *Recorder
+	stage int
+	name  string
+}
+
+func (p *recordingProcessor) Process(d *Data) ([]*Data, error) {
+	// Снимок входа делается до вызова: процессор может изменить d.
+	e := TraceEvent{Stage: p.stage, Step: "process:" + p.name, Input: snapshot([]*Data{d})}
+	out, err := p.next.Process(d)
+	e.Output = snapshot(out)
+	if err != nil {
+		e.Err = err.Error()
+	}
+	p.rec.record(e)
+	return out, err
+}
+
+type recordingWriter struct {
+	next  Writer
+	rec   *Recorder
+	stage int
+}
+
+func (w *recordingWriter) Write(data []*Data) {
+	w.rec.record(TraceEvent{Stage: w.stage, Step: "write", Input: snapshot(data)})
+	w.next.Write(data)
+}
+
+// ReplayReader возвращает Reader, который выдает данные, прочитанные в трассе t.
+// Так записанный вход можно прогнать через новую версию конвейера без исходного источника.
+func ReplayReader(t Trace) Reader {
+	return replayReader{trace: t}
+}
+
+type replayReader struct {
+	trace Trace
+}
+
+func (r replayReader) Read() []*Data {
+	var data []*Data
+	for _, e := range r.trace.Events {
+		if e.Step != "read" {
+			continue
+		}
+		for _, d := range e.Output {
+			data = append(data, &d)
+		}
+	}
+	return data
+}
+
+// WriteTo сохраняет трассу в w в формате JSON.
+func (t Trace) WriteTo(w io.Writer) (int64, error) {
+	b, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(b, '\n'))
+	return int64(n), err
+}
+
+// ReadTrace загружает трассу, сохраненную Trace.WriteTo.
+func ReadTrace(r io.Reader) (Trace, error) {
+	var t Trace
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return Trace{}, fmt.Errorf("не удалось прочитать трассу: %w", err)
+	}
+	return t, nil
+}
+
+// Compare сверяет трассу с эталонной и возвращает ошибку с описанием первого расхождения.
+func (t Trace) Compare(golden Trace) error {
+	for i := range min(len(t.Events), len(golden.Events)) {
+		got, want := t.Events[i], golden.Events[i]
+		if got.Stage != want.Stage || got.Step != want.Step || got.Err != want.Err ||
+			!slices.Equal(got.Input, want.Input) || !slices.Equal(got.Output, want.Output) {
+			return fmt.Errorf("событие %d: получено %+v, ожидалось %+v", i, got, want)
+		}
+	}
+	if len(t.Events) != len(golden.Events) {
+		return fmt.Errorf("событий в трассе: %d, ожидалось %d", len(t.Events), len(golden.Events))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "перезаписать эталонные трассы в testdata")
+
+const goldenTracePath = "testdata/sample_pipeline.golden.json"
+
+// runRecorded прогоняет пример конвейера через Recorder и возвращает трассу.
+// Dedup в трассируемый конвейер не входит: какой из дубликатов пройдет, зависит
+// от порядка горутин, и трасса не была бы детерминированной.
+func runRecorded(reader Reader, upper Processor) Trace {
+	rec := NewRecorder()
+	manager := NewDataManager(
+		rec.Reader(reader),
+		[]Processor{
+			rec.Processor("duplicator", &duplicatorProcessor{}),
+			rec.Processor("upper", upper),
+		},
+		NewSortedWriter(rec.Writer(&mockWriter{}), ByID),
+	)
+	manager.Manage()
+	return rec.Trace()
+}
+
+func loadGoldenTrace(t *testing.T) Trace {
+	t.Helper()
+	f, err := os.Open(goldenTracePath)
+	if err != nil {
+		t.Fatalf("эталонная трасса не найдена (запустите go test -update): %v", err)
+	}
+	defer f.Close()
+	golden, err := ReadTrace(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return golden
+}
+
+func TestSamplePipelineMatchesGoldenTrace(t *testing.T) {
+	trace := runRecorded(&mockReader{}, &upperCaseProcessor{})
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(goldenTracePath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Create(goldenTracePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := trace.WriteTo(f); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := trace.Compare(loadGoldenTrace(t)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReplayMatchesGoldenTrace(t *testing.T) {
+	golden := loadGoldenTrace(t)
+
+	// Вход берется из трассы, а не из mockReader.
+	if err := runRecorded(ReplayReader(golden), &upperCaseProcessor{}).Compare(golden); err != nil {
+		t.Error(err)
+	}
+}
+
+// lowerCaseProcessor — "регрессия": шаг ведет себя иначе, чем при записи эталона.
+type lowerCaseProcessor struct{}
+
+func (p *lowerCaseProcessor) Process(d *Data) ([]*Data, error) {
+	d.Payload = strings.ToLower(d.Payload)
+	return []*Data{d}, nil
+}
+
+func TestReplayDetectsRegression(t *testing.T) {
+	golden := loadGoldenTrace(t)
+
+	err := runRecorded(ReplayReader(golden), &lowerCaseProcessor{}).Compare(golden)
+	if err == nil {
+		t.Fatal("Compare не заметил изменения результата шага upper")
+	}
+}
+
+func TestRecorderSnapshotsInputBeforeProcess(t *testing.T) {
+	rec := NewRecorder()
+	p := rec.Processor("upper", &upperCaseProcessor{})
+	if _, err := p.Process(&Data{ID: 1, Payload: "abc"}); err != nil {
+		t.Fatal(err)
+	}
+
+	e := rec.Trace().Events[0]
+	// upperCaseProcessor меняет элемент на месте, но вход записан до изменения.
+	if e.Input[0].Payload != "abc" || e.Output[0].Payload != "ABC" {
+		t.Errorf("событие: вход %+v, выход %+v", e.Input, e.Output)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func TestManageWithWorkStealingMatchesDefault(t *testing.T) {
+	input := func() []*Data {
+		data := make([]*Data, 200)
+		for i := range data {
+			data[i] = &Data{ID: i, Payload: "item"}
+		}
+		data[7].Payload = "error" // duplicatorProcessor отбрасывает этот элемент.
+		return data
+	}
+	run := func(dm *DataManager) []Data {
+		dm.Manage()
+		w := dm.writer.(*mockWriter)
+		return snapshot(w.data)
+	}
+	processors := func() []Processor { return []Processor{&duplicatorProcessor{}, &upperCaseProcessor{}} }
+
+	want := run(NewDataManager(&sliceReader{data: input()}, processors(), &mockWriter{sortByID: true}))
+	var calls atomic.Int32
+	counted := append(processors(), funcProcessor(func(d *Data) ([]*Data, error) {
+		calls.Add(1)
+		return []*Data{d}, nil
+	}))
+	got := run(NewDataManager(&sliceReader{data: input()}, counted, &mockWriter{sortByID: true}).UseWorkStealing(4))
+
+	slices.SortFunc(want, compareData)
+	slices.SortFunc(got, compareData)
+	if !slices.Equal(got, want) {
+		t.Fatalf("с кражей работы записано %d элементов, по умолчанию %d: результаты различаются", len(got), len(want))
+	}
+	// Каждый элемент обработан ровно один раз: 199 элементов по две копии.
+	if n := calls.Load(); n != 2*199 {
+		t.Errorf("последний шаг вызван %d раз, ожидалось %d", n, 2*199)
+	}
+}
+
+func TestManageWithWorkStealingStops(t *testing.T) {
+	stop := funcProcessor(func(d *Data) ([]*Data, error) {
+		if d.ID == 0 {
+			return nil, ErrStopPipeline
+		}
+		return []*Data{d}, nil
+	})
+	data := make([]*Data, 1000)
+	for i := range data {
+		data[i] = &Data{ID: i}
+	}
+	writer := &mockWriter{sortByID: true}
+	NewDataManager(&sliceReader{data: data}, []Processor{stop}, writer).UseWorkStealing(2).Manage()
+
+	if slices.Contains(ids(writer.data), 0) {
+		t.Error("элемент, остановивший конвейер, не должен попасть в результат")
+	}
+}
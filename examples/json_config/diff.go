@@ -0,0 +1,102 @@
+package main
+
+import "sync"
+
+// SliceDiff — результат сравнения двух наборов значений.
+// Порядок элементов в каждом поле совпадает с порядком в исходных срезах.
+type SliceDiff[T comparable] struct {
+	Added     []T // Есть в новом наборе, нет в старом.
+	Removed   []T // Есть в старом наборе, нет в новом.
+	Unchanged []T // Есть в обоих наборах (в порядке нового).
+}
+
+// Empty сообщает, что наборы совпадают с точностью до порядка.
+func (d SliceDiff[T]) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// DiffSlices сравнивает old и new как множества: повторы учитываются один раз,
+// порядок элементов на результат (кроме порядка внутри полей) не влияет.
+func DiffSlices[T comparable](old, new []T) SliceDiff[T] {
+	inOld := make(map[T]struct{}, len(old))
+	for _, v := range old {
+		inOld[v] = struct{}{}
+	}
+	inNew := make(map[T]struct{}, len(new))
+	for _, v := range new {
+		inNew[v] = struct{}{}
+	}
+
+	var diff SliceDiff[T]
+	seen := make(map[T]struct{}, len(new))
+	for _, v := range new {
+		if _, dup := seen[v]; dup {
+			continue
+		}
+		seen[v] = struct{}{}
+		if _, ok := inOld[v]; ok {
+			diff.Unchanged = append(diff.Unchanged, v)
+		} else {
+			diff.Added = append(diff.Added, v)
+		}
+	}
+	for _, v := range old {
+		if _, ok := inNew[v]; ok {
+			continue
+		}
+		inNew[v] = struct{}{} // Повтор удаленного элемента не попадет в Removed дважды.
+		diff.Removed = append(diff.Removed, v)
+	}
+	return diff
+}
+
+// ServerReconciler применяет изменения списка серверов при перезагрузке конфига:
+// вместо того чтобы заново обрабатывать все серверы, он сравнивает новый список
+// с предыдущим и вызывает колбэки только для добавленных и удаленных.
+// Например, OnAdd запускает опрос нового сервера, а OnRemove его останавливает.
+type ServerReconciler struct {
+	mu       sync.Mutex
+	current  []string
+	onAdd    []func(server string)
+	onRemove []func(server string)
+}
+
+// OnAdd регистрирует колбэк для каждого появившегося в конфиге сервера.
+// Колбэки вызываются в порядке регистрации.
+func (r *ServerReconciler) OnAdd(fn func(server string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onAdd = append(r.onAdd, fn)
+}
+
+// OnRemove регистрирует колбэк для каждого исчезнувшего из конфига сервера.
+func (r *ServerReconciler) OnRemove(fn func(server string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onRemove = append(r.onRemove, fn)
+}
+
+// Apply сравнивает servers с предыдущим примененным списком (изначально пустым),
+// вызывает колбэки — сначала OnRemove для удаленных, затем OnAdd для добавленных —
+// и запоминает servers как текущий список. Возвращает найденные различия.
+//
+// Колбэки вызываются под блокировкой, чтобы изменения применялись строго в порядке
+// вызовов Apply; поэтому из колбэка нельзя вызывать методы ServerReconciler.
+func (r *ServerReconciler) Apply(servers []string) SliceDiff[string] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	diff := DiffSlices(r.current, servers)
+	for _, server := range diff.Removed {
+		for _, fn := range r.onRemove {
+			fn(server)
+		}
+	}
+	for _, server := range diff.Added {
+		for _, fn := range r.onAdd {
+			fn(server)
+		}
+	}
+	r.current = append([]string(nil), servers...)
+	return diff
+}
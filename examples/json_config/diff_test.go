@@ -0,0 +1,67 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffSlices(t *testing.T) {
+	diff := DiffSlices([]string{"a", "b", "c", "b"}, []string{"c", "d", "a", "d"})
+
+	if !slices.Equal(diff.Added, []string{"d"}) {
+		t.Errorf("Added = %v, ожидалось [d]", diff.Added)
+	}
+	if !slices.Equal(diff.Removed, []string{"b"}) {
+		t.Errorf("Removed = %v, ожидалось [b]", diff.Removed)
+	}
+	if !slices.Equal(diff.Unchanged, []string{"c", "a"}) {
+		t.Errorf("Unchanged = %v, ожидалось [c a]", diff.Unchanged)
+	}
+	if diff.Empty() {
+		t.Error("Empty() = true для различающихся наборов")
+	}
+	if !DiffSlices([]int{1, 2}, []int{2, 1, 1}).Empty() {
+		t.Error("наборы, отличающиеся порядком и повторами, должны совпадать")
+	}
+}
+
+func TestAppReloadInvokesServerCallbacks(t *testing.T) {
+	var added, removed []string
+	app := &App{servers: &ServerReconciler{}}
+	app.servers.OnAdd(func(s string) { added = append(added, s) })
+	app.servers.OnRemove(func(s string) { removed = append(removed, s) })
+
+	load := func(data string) {
+		t.Helper()
+		cfg, err := parseConfig([]byte(data))
+		if err != nil {
+			t.Fatalf("неожиданная ошибка: %v", err)
+		}
+		added, removed = nil, nil
+		app.applyConfig(cfg)
+	}
+
+	// Первая загрузка: все серверы новые.
+	load(`{"servers": ["http://a", "http://b", "http://c"]}`)
+	if !slices.Equal(added, []string{"http://a", "http://b", "http://c"}) || len(removed) != 0 {
+		t.Fatalf("первая загрузка: added=%v removed=%v", added, removed)
+	}
+
+	// Смена набора серверов: колбэки только для изменившихся.
+	load(`{"servers": ["http://c", "http://d", "http://a"], "max_concurrency": 3}`)
+	if !slices.Equal(added, []string{"http://d"}) {
+		t.Errorf("added = %v, ожидалось [http://d]", added)
+	}
+	if !slices.Equal(removed, []string{"http://b"}) {
+		t.Errorf("removed = %v, ожидалось [http://b]", removed)
+	}
+
+	// Изменились только другие поля конфига: колбэки не вызываются.
+	load(`{"servers": ["http://a", "http://c", "http://d"], "max_concurrency": 7}`)
+	if len(added) != 0 || len(removed) != 0 {
+		t.Errorf("перестановка серверов: added=%v removed=%v, ожидалось без изменений", added, removed)
+	}
+	if app.config.MaxConcurrency != 7 {
+		t.Errorf("MaxConcurrency = %d, конфиг не применен", app.config.MaxConcurrency)
+	}
+}
@@ -6,20 +6,122 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"sync"
+	"syscall"
 	"time"
+
+	"github.com/andrewhigh08/exp/internal/lifecycle"
+	"github.com/andrewhigh08/exp/internal/periodic"
+)
+
+// Значения по умолчанию для необязательных полей конфига.
+const (
+	defaultPollInterval   = 5 * time.Second
+	defaultRequestTimeout = 10 * time.Second
+	defaultMaxConcurrency = 10
 )
 
 // Config определяет структуру нашего JSON-конфига.
 // Использование структуры вместо `map[string]interface{}` является более безопасным
 // и идиоматичным подходом, так как обеспечивает строгую типизацию.
+//
+// Все поля, кроме Servers, необязательны: отсутствующие значения заполняет ApplyDefaults.
 type Config struct {
-	Servers []string `json:"servers"`
+	Servers        []string `json:"servers"`
+	PollInterval   Duration `json:"poll_interval"`   // Период перечитывания файла, например "5s".
+	RequestTimeout Duration `json:"request_timeout"` // Таймаут одного запроса в /ping.
+	MaxConcurrency int      `json:"max_concurrency"` // Сколько серверов опрашивается одновременно.
+	// Доля здоровых серверов, ниже которой /ping отвечает 503, а не 207 (см. pingStatus).
+	// По умолчанию 0: 503 — только когда нет ни одного здорового сервера.
+	MinHealthyRatio float64 `json:"min_healthy_ratio"`
+}
+
+// ApplyDefaults заполняет незаданные (нулевые) поля значениями по умолчанию.
+// Отрицательные значения не трогаются — их должен отклонить Validate.
+func (c *Config) ApplyDefaults() {
+	if c.PollInterval == 0 {
+		c.PollInterval = Duration(defaultPollInterval)
+	}
+	if c.RequestTimeout == 0 {
+		c.RequestTimeout = Duration(defaultRequestTimeout)
+	}
+	if c.MaxConcurrency == 0 {
+		c.MaxConcurrency = defaultMaxConcurrency
+	}
+}
+
+// Validate проверяет конфиг и возвращает все найденные проблемы сразу (через errors.Join),
+// а не только первую.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.PollInterval <= 0 {
+		errs = append(errs, fmt.Errorf("poll_interval должен быть положительным, получено %s", c.PollInterval))
+	}
+	if c.RequestTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("request_timeout должен быть положительным, получено %s", c.RequestTimeout))
+	}
+	if c.MaxConcurrency <= 0 {
+		errs = append(errs, fmt.Errorf("max_concurrency должен быть положительным, получено %d", c.MaxConcurrency))
+	}
+	if c.MinHealthyRatio < 0 || c.MinHealthyRatio > 1 {
+		errs = append(errs, fmt.Errorf("min_healthy_ratio должен быть от 0 до 1, получено %g", c.MinHealthyRatio))
+	}
+	for i, server := range c.Servers {
+		if server == "" {
+			errs = append(errs, fmt.Errorf("servers[%d]: пустой адрес сервера", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parseConfig разбирает JSON, применяет значения по умолчанию и валидирует результат.
+func parseConfig(data []byte) (Config, error) {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("ошибка парсинга JSON: %w", err)
+	}
+	cfg.ApplyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("некорректная конфигурация: %w", err)
+	}
+	return cfg, nil
+}
+
+// Duration — обертка над time.Duration, которая в JSON записывается строкой ("5s", "1m30s").
+// Стандартный time.Duration сериализуется как число наносекунд, что неудобно для людей.
+type Duration time.Duration
+
+// String возвращает человекочитаемое представление длительности.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalJSON реализует json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON реализует json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("длительность должна быть строкой вида \"5s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
 }
 
 // App — основная структура нашего приложения.
@@ -27,35 +129,79 @@ type Config struct {
 type App struct {
 	config Config
 	mu     sync.RWMutex // RWMutex идеален для конфига: много читателей, редкие писатели.
+	// changes (если задан) получает каждый успешно загруженный конфиг,
+	// чтобы потребители могли реагировать на перезагрузку, не опрашивая App.
+	changes *Observable[Config]
+	// servers (если задан) получает список серверов каждого загруженного конфига
+	// и вызывает колбэки только для добавленных и удаленных серверов.
+	servers *ServerReconciler
+	// transport (если задан) выполняет запросы /ping вместо http.DefaultTransport.
+	transport http.RoundTripper
 }
 
-// loadConfig периодически читает и обновляет конфигурацию приложения.
+// watchConfig загружает конфигурацию сразу, а затем перечитывает ее с периодом из самого
+// конфига, пока sched не остановлен. При смене периода задача перезапускается с новым.
 // Эта функция должна запускаться в отдельной горутине.
-func (a *App) loadConfig(path string) {
+func (a *App) watchConfig(sched *periodic.Scheduler, path string) {
+	a.loadConfig(path)
 	for {
-		// Читаем файл
-		data, err := os.ReadFile(path)
-		if err != nil {
-			log.Printf("Ошибка чтения файла конфигурации '%s': %v", path, err)
-			time.Sleep(5 * time.Second) // В случае ошибки повторяем не так часто
-			continue
+		interval := a.pollInterval()
+		changed := make(chan struct{})
+		var once sync.Once
+		stop := sched.Every(interval, func(context.Context) {
+			a.loadConfig(path)
+			if a.pollInterval() != interval {
+				once.Do(func() { close(changed) })
+			}
+		})
+		select {
+		case <-changed:
+			stop() // Перезапускаем с новым периодом.
+		case <-sched.Done():
+			stop()
+			return
 		}
+	}
+}
 
-		var newConfig Config
-		if err := json.Unmarshal(data, &newConfig); err != nil {
-			log.Printf("Ошибка парсинга JSON из файла '%s': %v", path, err)
-			time.Sleep(5 * time.Second)
-			continue
-		}
+// loadConfig один раз читает файл и применяет конфигурацию. При ошибке текущий
+// конфиг остается в силе до следующей попытки.
+func (a *App) loadConfig(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("Ошибка чтения файла конфигурации '%s': %v", path, err)
+		return
+	}
 
-		// Блокируем мьютекс на запись, чтобы безопасно обновить конфигурацию.
-		a.mu.Lock()
-		a.config = newConfig
-		a.mu.Unlock()
+	newConfig, err := parseConfig(data)
+	if err != nil {
+		log.Printf("Ошибка загрузки конфигурации из файла '%s': %v", path, err)
+		return
+	}
+
+	a.applyConfig(newConfig)
+	log.Println("Конфигурация успешно обновлена.")
+}
 
-		log.Println("Конфигурация успешно обновлена.")
-		time.Sleep(5 * time.Second) // Перезагружаем каждые 5 секунд
+// applyConfig делает newConfig текущим и уведомляет потребителей о перезагрузке.
+func (a *App) applyConfig(newConfig Config) {
+	// Блокируем мьютекс на запись, чтобы безопасно обновить конфигурацию.
+	a.mu.Lock()
+	a.config = newConfig
+	a.mu.Unlock()
+	if a.changes != nil {
+		a.changes.Set(newConfig)
 	}
+	if a.servers != nil {
+		a.servers.Apply(newConfig.Servers)
+	}
+}
+
+// pollInterval возвращает текущий период перечитывания конфига.
+func (a *App) pollInterval() time.Duration {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return time.Duration(a.config.PollInterval)
 }
 
 // pingHandler — это обработчик для эндпоинта /ping.
@@ -64,23 +210,36 @@ func (a *App) pingHandler(w http.ResponseWriter, r *http.Request) {
 	a.mu.RLock()
 	servers := make([]string, len(a.config.Servers))
 	copy(servers, a.config.Servers)
+	timeout := time.Duration(a.config.RequestTimeout)
+	maxConcurrency := a.config.MaxConcurrency
+	minHealthyRatio := a.config.MinHealthyRatio
 	a.mu.RUnlock()
+	// Номер запроса есть, только если обработчик обернут в withRequestID.
+	reqID, _ := requestIDKey.From(r.Context())
+
+	// Клиент с таймаутом: один зависший сервер не должен держать весь /ping.
+	client := &http.Client{Timeout: timeout, Transport: a.transport}
+	// Буферизированный канал работает как семафор и ограничивает число одновременных запросов.
+	sem := make(chan struct{}, maxConcurrency)
 
 	// responseMap будет содержать результаты опроса.
 	responseMap := make(map[string]string)
 	// Для защиты responseMap от конкурентной записи из горутин нужен отдельный мьютекс.
 	var responseMu sync.Mutex
+	healthy := 0 // Защищен responseMu.
 	var wg sync.WaitGroup
 
-	log.Printf("Начинаю опрос %d серверов...", len(servers))
+	log.Printf("[запрос %d] Начинаю опрос %d серверов...", reqID, len(servers))
 
 	for _, serverURL := range servers {
 		wg.Add(1)
 		go func(url string) {
 			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 
 			// Выполняем GET-запрос.
-			resp, err := http.Get(url)
+			resp, err := client.Get(url)
 			var status string
 			if err != nil {
 				status = "ERROR: " + err.Error()
@@ -92,6 +251,9 @@ func (a *App) pingHandler(w http.ResponseWriter, r *http.Request) {
 			// Защищаем запись в responseMap с помощью мьютекса.
 			responseMu.Lock()
 			responseMap[url] = status
+			if isHealthy(resp, err) {
+				healthy++
+			}
 			responseMu.Unlock()
 
 		}(serverURL)
@@ -99,10 +261,11 @@ func (a *App) pingHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Ожидаем завершения всех запросов.
 	wg.Wait()
-	log.Println("Опрос завершен.")
+	log.Printf("[запрос %d] Опрос завершен: здоровы %d из %d.", reqID, healthy, len(servers))
 
-	// Отправляем результат клиенту в формате JSON.
+	// Отправляем результат клиенту в формате JSON; код ответа отражает общее состояние.
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(pingStatus(healthy, len(servers), minHealthyRatio))
 	json.NewEncoder(w).Encode(responseMap)
 }
 
@@ -112,18 +275,68 @@ func main() {
 	flag.Parse()
 
 	// Создаем экземпляр нашего приложения.
+	// До первой загрузки файла используем пустой конфиг со значениями по умолчанию.
+	initialConfig := Config{}
+	initialConfig.ApplyDefaults()
 	app := &App{
-		config: Config{},
+		config:  initialConfig,
+		changes: NewObservable(initialConfig),
+		servers: &ServerReconciler{},
 	}
+	// При перезагрузке реагируем только на изменившиеся серверы.
+	app.servers.OnAdd(func(server string) { log.Printf("Сервер добавлен в конфиг: %s", server) })
+	app.servers.OnRemove(func(server string) { log.Printf("Сервер удален из конфига: %s", server) })
 
-	// Запускаем горутину для динамической перезагрузки конфига.
-	go app.loadConfig(*configPath)
+	// Компоненты регистрируют хуки завершения; выполняются они в обратном порядке:
+	// сначала останавливается сервер, затем — подписчики конфига.
+	shutdown := lifecycle.NewShutdownGroup(lifecycle.ReverseOrder)
+
+	// Пример потребителя, который узнает о перезагрузке конфига через подписку.
+	updates := app.changes.Subscribe()
+	go func() {
+		for cfg := range updates {
+			log.Printf("Получен новый конфиг: %d серверов, max_concurrency=%d", len(cfg.Servers), cfg.MaxConcurrency)
+		}
+	}()
+	shutdown.OnShutdown(func(context.Context) error {
+		app.changes.Unsubscribe(updates)
+		return nil
+	})
+
+	// Запускаем горутину для динамической перезагрузки конфига; при завершении
+	// планировщик останавливается после сервера, но до отписки потребителей конфига.
+	sched := periodic.NewScheduler(context.Background())
+	go app.watchConfig(sched, *configPath)
+	shutdown.OnShutdown(func(context.Context) error {
+		sched.Close()
+		return nil
+	})
 
 	// Регистрируем обработчик эндпоинта.
-	http.HandleFunc("/ping", app.pingHandler)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ping", app.pingHandler)
+	srv := &http.Server{Addr: ":8080", Handler: withRequestID(mux)}
+	shutdown.OnShutdown(srv.Shutdown)
+
+	// Завершаемся по Ctrl+C или SIGTERM.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	log.Println("Сервер запущен на порту :8080")
-	log.Printf("Для проверки откройте в браузере http://localhost:8080/ping")
-	// Запускаем HTTP-сервер. log.Fatal остановит программу, если сервер не сможет запуститься.
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	go func() {
+		log.Println("Сервер запущен на порту :8080")
+		log.Printf("Для проверки откройте в браузере http://localhost:8080/ping")
+		// ErrServerClosed — штатный результат srv.Shutdown, а не ошибка запуска.
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Получен сигнал завершения, останавливаем сервер...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Ошибки при завершении: %v", err)
+	}
+	log.Println("Сервер остановлен.")
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfigAppliesDefaults(t *testing.T) {
+	cfg, err := parseConfig([]byte(`{"servers": ["http://a", "http://b"]}`))
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if got := time.Duration(cfg.PollInterval); got != defaultPollInterval {
+		t.Errorf("PollInterval = %v, ожидалось %v", got, defaultPollInterval)
+	}
+	if got := time.Duration(cfg.RequestTimeout); got != defaultRequestTimeout {
+		t.Errorf("RequestTimeout = %v, ожидалось %v", got, defaultRequestTimeout)
+	}
+	if cfg.MaxConcurrency != defaultMaxConcurrency {
+		t.Errorf("MaxConcurrency = %d, ожидалось %d", cfg.MaxConcurrency, defaultMaxConcurrency)
+	}
+	if len(cfg.Servers) != 2 {
+		t.Errorf("Servers = %v", cfg.Servers)
+	}
+}
+
+func TestParseConfigKeepsExplicitValues(t *testing.T) {
+	cfg, err := parseConfig([]byte(`{"servers": [], "poll_interval": "1m", "request_timeout": "250ms", "max_concurrency": 2}`))
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if got := time.Duration(cfg.PollInterval); got != time.Minute {
+		t.Errorf("PollInterval = %v, ожидалось 1m", got)
+	}
+	if got := time.Duration(cfg.RequestTimeout); got != 250*time.Millisecond {
+		t.Errorf("RequestTimeout = %v, ожидалось 250ms", got)
+	}
+	if cfg.MaxConcurrency != 2 {
+		t.Errorf("MaxConcurrency = %d, ожидалось 2", cfg.MaxConcurrency)
+	}
+}
+
+func TestParseConfigRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{"отрицательный интервал опроса", `{"poll_interval": "-5s"}`, "poll_interval"},
+		{"отрицательный таймаут", `{"request_timeout": "-1s"}`, "request_timeout"},
+		{"отрицательная конкурентность", `{"max_concurrency": -3}`, "max_concurrency"},
+		{"отрицательная доля здоровых", `{"min_healthy_ratio": -0.5}`, "min_healthy_ratio"},
+		{"доля здоровых больше единицы", `{"min_healthy_ratio": 1.5}`, "min_healthy_ratio"},
+		{"пустой адрес сервера", `{"servers": ["http://a", ""]}`, "servers[1]"},
+		{"длительность числом", `{"poll_interval": 5}`, "длительность"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseConfig([]byte(tt.json))
+			if err == nil {
+				t.Fatal("ожидалась ошибка")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ошибка %q не упоминает %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	cfg := Config{PollInterval: Duration(-time.Second), RequestTimeout: Duration(-time.Second), MaxConcurrency: -1}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("ожидалась ошибка")
+	}
+	for _, field := range []string{"poll_interval", "request_timeout", "max_concurrency"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("ошибка не упоминает %s: %v", field, err)
+		}
+	}
+}
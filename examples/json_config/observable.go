@@ -0,0 +1,77 @@
+package main
+
+import "sync"
+
+// Observable — потокобезопасное значение с уведомлениями об изменениях.
+//
+// Позволяет отделить потребителей конфига от цикла его перезагрузки: watchConfig
+// вызывает Set, а каждый заинтересованный компонент читает новые значения из своего канала.
+//
+// Политика для медленных подписчиков — "последнее значение побеждает": у каждого
+// подписчика буфер на одно значение, и если он еще не прочитал предыдущее, оно
+// заменяется новым. Set никогда не блокируется, а подписчик, проснувшись, сразу
+// получает актуальное значение, пропустив промежуточные.
+type Observable[T any] struct {
+	mu    sync.RWMutex
+	value T
+	subs  map[<-chan T]chan T
+}
+
+// NewObservable создает Observable с начальным значением initial.
+func NewObservable[T any](initial T) *Observable[T] {
+	return &Observable[T]{
+		value: initial,
+		subs:  make(map[<-chan T]chan T),
+	}
+}
+
+// Get возвращает текущее значение.
+func (o *Observable[T]) Get() T {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.value
+}
+
+// Set сохраняет новое значение и уведомляет подписчиков.
+// Рассылка идет под блокировкой, поэтому все подписчики видят изменения в порядке вызовов Set.
+func (o *Observable[T]) Set(v T) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.value = v
+	for _, ch := range o.subs {
+		select {
+		case ch <- v:
+		default:
+			// Подписчик не успел прочитать предыдущее значение: вытесняем его.
+			// Писатели в канал работают только под o.mu, поэтому место гарантированно освободится
+			// (если подписчик не прочитал значение сам в этот момент — тогда оно уже свободно).
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- v
+		}
+	}
+}
+
+// Subscribe возвращает канал, в который будут приходить значения из последующих вызовов Set.
+// Текущее значение в канал не отправляется — его можно получить через Get.
+func (o *Observable[T]) Subscribe() <-chan T {
+	ch := make(chan T, 1)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.subs[ch] = ch
+	return ch
+}
+
+// Unsubscribe прекращает доставку в канал ch и закрывает его.
+// Повторный вызов и вызов с неизвестным каналом ничего не делают.
+func (o *Observable[T]) Unsubscribe(ch <-chan T) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if sub, ok := o.subs[ch]; ok {
+		delete(o.subs, ch)
+		close(sub)
+	}
+}
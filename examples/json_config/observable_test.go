@@ -0,0 +1,107 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestObservableGetSet(t *testing.T) {
+	o := NewObservable(1)
+	if got := o.Get(); got != 1 {
+		t.Errorf("Get = %d, ожидалось начальное значение 1", got)
+	}
+	o.Set(2)
+	if got := o.Get(); got != 2 {
+		t.Errorf("Get = %d после Set(2)", got)
+	}
+}
+
+func TestObservableSubscribersReceiveUpdatesInOrder(t *testing.T) {
+	o := NewObservable(0)
+	subs := []<-chan int{o.Subscribe(), o.Subscribe()}
+
+	var wg sync.WaitGroup
+	results := make([][]int, len(subs))
+	for i, ch := range subs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := range ch {
+				results[i] = append(results[i], v)
+			}
+		}()
+	}
+
+	for v := 1; v <= 100; v++ {
+		o.Set(v)
+	}
+	for _, ch := range subs {
+		o.Unsubscribe(ch)
+	}
+	wg.Wait()
+
+	for i, got := range results {
+		// Медленный читатель может пропустить промежуточные значения, но порядок
+		// не нарушается и последнее значение доставляется всегда.
+		for j := 1; j < len(got); j++ {
+			if got[j] <= got[j-1] {
+				t.Fatalf("подписчик %d: нарушен порядок %v", i, got)
+			}
+		}
+		if len(got) == 0 || got[len(got)-1] != 100 {
+			t.Errorf("подписчик %d не получил последнее значение: %v", i, got)
+		}
+	}
+}
+
+func TestObservableSlowSubscriberGetsLatest(t *testing.T) {
+	o := NewObservable("v0")
+	ch := o.Subscribe()
+
+	// Никто не читает: Set не должен блокироваться.
+	done := make(chan struct{})
+	go func() {
+		o.Set("v1")
+		o.Set("v2")
+		o.Set("v3")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Set заблокировался на медленном подписчике")
+	}
+
+	if got := <-ch; got != "v3" {
+		t.Errorf("медленный подписчик получил %q, ожидалось последнее значение v3", got)
+	}
+	select {
+	case v := <-ch:
+		t.Errorf("лишнее значение в канале: %q", v)
+	default:
+	}
+}
+
+func TestObservableUnsubscribeStopsDelivery(t *testing.T) {
+	o := NewObservable(0)
+	ch := o.Subscribe()
+	other := o.Subscribe()
+
+	o.Set(1)
+	if got := <-ch; got != 1 {
+		t.Fatalf("получено %d, ожидалось 1", got)
+	}
+
+	o.Unsubscribe(ch)
+	o.Unsubscribe(ch) // Повторная отписка не должна паниковать.
+	o.Set(2)
+
+	if v, ok := <-ch; ok {
+		t.Errorf("после отписки получено значение %d", v)
+	}
+	// Другой подписчик не читал канал, поэтому значение 1 вытеснено значением 2.
+	if got := <-other; got != 2 {
+		t.Errorf("другой подписчик: %d, ожидалось 2", got)
+	}
+}
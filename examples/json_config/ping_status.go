@@ -0,0 +1,24 @@
+package main
+
+import "net/http"
+
+// pingStatus выбирает HTTP-статус ответа /ping по числу здоровых серверов, чтобы
+// мониторинг мог судить о состоянии по коду ответа, не разбирая тело:
+//   - 200 OK — здоровы все серверы (или список пуст);
+//   - 503 Service Unavailable — не здоров ни один сервер или их доля меньше minHealthyRatio;
+//   - 207 Multi-Status — часть серверов недоступна, но доля здоровых не ниже порога.
+func pingStatus(healthy, total int, minHealthyRatio float64) int {
+	switch {
+	case healthy == total:
+		return http.StatusOK
+	case healthy == 0 || float64(healthy)/float64(total) < minHealthyRatio:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusMultiStatus
+	}
+}
+
+// isHealthy сообщает, считается ли ответ сервера здоровым: сервер ответил, и код 2xx.
+func isHealthy(resp *http.Response, err error) bool {
+	return err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTransport отвечает на запросы без сети: код ответа берется по хосту из statuses,
+// а неизвестные хосты недоступны (ошибка соединения).
+type fakeTransport map[string]int
+
+func (f fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	code, ok := f[req.URL.Host]
+	if !ok {
+		return nil, errors.New("connection refused")
+	}
+	return &http.Response{
+		StatusCode: code,
+		Status:     http.StatusText(code),
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+// pingCode вызывает pingHandler для серверов hosts и возвращает код ответа.
+func pingCode(t *testing.T, transport fakeTransport, minHealthyRatio float64, hosts ...string) int {
+	t.Helper()
+	servers := make([]string, len(hosts))
+	for i, host := range hosts {
+		servers[i] = "http://" + host
+	}
+	app := newTestApp(servers, time.Second)
+	app.config.MinHealthyRatio = minHealthyRatio
+	app.transport = transport
+
+	rec := httptest.NewRecorder()
+	app.pingHandler(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	return rec.Code
+}
+
+func TestPingStatusCode(t *testing.T) {
+	transport := fakeTransport{"up1": 200, "up2": 204, "up3": 200, "broken": 500}
+	tests := []struct {
+		name            string
+		hosts           []string
+		minHealthyRatio float64
+		want            int
+	}{
+		{"все доступны", []string{"up1", "up2"}, 0, http.StatusOK},
+		{"все недоступны", []string{"down1", "down2"}, 0, http.StatusServiceUnavailable},
+		{"ответ с ошибкой не считается здоровым", []string{"broken"}, 0, http.StatusServiceUnavailable},
+		{"часть недоступна", []string{"up1", "down1", "broken"}, 0, http.StatusMultiStatus},
+		{"доля здоровых не ниже порога", []string{"up1", "up2", "down1"}, 0.5, http.StatusMultiStatus},
+		{"доля здоровых ниже порога", []string{"up1", "down1", "down2"}, 0.5, http.StatusServiceUnavailable},
+		{"порог 1 требует всех", []string{"up1", "up2", "up3", "down1"}, 1, http.StatusServiceUnavailable},
+		{"пустой список", nil, 0, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pingCode(t, transport, tt.minHealthyRatio, tt.hosts...); got != tt.want {
+				t.Errorf("код ответа %d, ожидалось %d", got, tt.want)
+			}
+		})
+	}
+}
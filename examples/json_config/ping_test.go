@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewhigh08/exp/internal/fakehttp"
+)
+
+// newTestApp создает App с заданными серверами и значениями по умолчанию для остальных полей.
+func newTestApp(servers []string, timeout time.Duration) *App {
+	cfg := Config{Servers: servers, RequestTimeout: Duration(timeout)}
+	cfg.ApplyDefaults()
+	return &App{config: cfg}
+}
+
+// ping вызывает pingHandler и декодирует ответ.
+func ping(t *testing.T, app *App) map[string]string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	app.pingHandler(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var got map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("некорректный JSON ответа: %v", err)
+	}
+	return got
+}
+
+func TestPingAggregatesStatuses(t *testing.T) {
+	srv := fakehttp.New(t, map[string]fakehttp.Route{
+		"/healthy":     {Status: http.StatusOK},
+		"/unavailable": {Status: http.StatusServiceUnavailable},
+		"/slow":        {Delay: time.Second},
+	})
+	servers := srv.URLs("/healthy", "/unavailable", "/slow", "/missing")
+	app := newTestApp(servers, 50*time.Millisecond)
+
+	got := ping(t, app)
+
+	if len(got) != len(servers) {
+		t.Fatalf("в ответе %d серверов, ожидалось %d: %v", len(got), len(servers), got)
+	}
+	want := map[string]string{
+		srv.URLFor("/healthy"):     "200 OK",
+		srv.URLFor("/unavailable"): "503 Service Unavailable",
+		srv.URLFor("/missing"):     "404 Not Found",
+	}
+	for u, status := range want {
+		if got[u] != status {
+			t.Errorf("%s: %q, ожидалось %q", u, got[u], status)
+		}
+	}
+	if s := got[srv.URLFor("/slow")]; !strings.HasPrefix(s, "ERROR: ") {
+		t.Errorf("медленный сервер: %q, ожидалась ошибка таймаута", s)
+	}
+}
+
+func TestPingRespectsMaxConcurrency(t *testing.T) {
+	const delay = 30 * time.Millisecond
+	srv := fakehttp.New(t, map[string]fakehttp.Route{
+		"/a": {Delay: delay},
+		"/b": {Delay: delay},
+		"/c": {Delay: delay},
+	})
+	app := newTestApp(srv.URLs("/a", "/b", "/c"), time.Second)
+	app.config.MaxConcurrency = 1
+
+	start := time.Now()
+	got := ping(t, app)
+	elapsed := time.Since(start)
+
+	if len(got) != 3 {
+		t.Fatalf("ответ: %v", got)
+	}
+	// При MaxConcurrency = 1 запросы идут по одному.
+	if elapsed < 3*delay {
+		t.Errorf("опрос занял %v, ожидалось не меньше %v при последовательных запросах", elapsed, 3*delay)
+	}
+}
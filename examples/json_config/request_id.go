@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/andrewhigh08/exp/internal/ctxkey"
+)
+
+// requestIDKey — ключ контекста запроса для его порядкового номера.
+var requestIDKey = ctxkey.NewContextKey[uint64]("request_id")
+
+// withRequestID присваивает каждому запросу порядковый номер: кладет его в контекст
+// запроса (см. requestIDKey) и в заголовок ответа X-Request-ID, чтобы строки лога
+// можно было сопоставить с конкретным вызовом.
+func withRequestID(next http.Handler) http.Handler {
+	var counter atomic.Uint64
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := counter.Add(1)
+		w.Header().Set("X-Request-ID", strconv.FormatUint(id, 10))
+		next.ServeHTTP(w, r.WithContext(requestIDKey.WithValue(r.Context(), id)))
+	})
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestWithRequestIDNumbersRequests(t *testing.T) {
+	var seen []uint64
+	handler := withRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := requestIDKey.From(r.Context())
+		if !ok {
+			t.Error("номер запроса отсутствует в контексте")
+		}
+		seen = append(seen, id)
+	}))
+
+	for want := uint64(1); want <= 3; want++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		if got := rec.Header().Get("X-Request-ID"); got != strconv.FormatUint(want, 10) {
+			t.Errorf("X-Request-ID = %q, ожидалось %d", got, want)
+		}
+	}
+	if len(seen) != 3 || seen[0] != 1 || seen[2] != 3 {
+		t.Errorf("номера в контексте %v, ожидалось [1 2 3]", seen)
+	}
+}
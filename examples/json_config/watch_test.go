@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andrewhigh08/exp/internal/periodic"
+)
+
+// waitFor ждет, пока cond станет истинным, не дольше секунды.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("не дождались: %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWatchConfigReloadsAndStops(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	servers := func(app *App) int {
+		app.mu.RLock()
+		defer app.mu.RUnlock()
+		return len(app.config.Servers)
+	}
+
+	write(`{"servers": ["http://a"], "poll_interval": "1ms"}`)
+	app := newTestApp(nil, time.Second)
+	sched := periodic.NewScheduler(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		app.watchConfig(sched, path)
+		close(stopped)
+	}()
+	waitFor(t, "первая загрузка", func() bool { return servers(app) == 1 })
+
+	// Период меняется через сам конфиг: задача перезапускается с новым периодом.
+	write(`{"servers": ["http://a", "http://b"], "poll_interval": "5ms"}`)
+	waitFor(t, "перезагрузка", func() bool { return servers(app) == 2 })
+	if got := app.pollInterval(); got != 5*time.Millisecond {
+		t.Fatalf("период %v, ожидалось 5ms", got)
+	}
+	write(`{"servers": ["http://a", "http://b", "http://c"], "poll_interval": "5ms"}`)
+	waitFor(t, "перезагрузка с новым периодом", func() bool { return servers(app) == 3 })
+
+	sched.Close()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("watchConfig не завершился после Close")
+	}
+}
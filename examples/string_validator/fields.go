@@ -0,0 +1,216 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Rule — правило проверки значения одного поля. Возвращает nil, если значение корректно.
+type Rule func(value any) error
+
+// FieldError — ошибка валидации конкретного поля.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+// Unwrap позволяет проверять исходную ошибку правила через errors.Is / errors.As.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors — все ошибки, найденные при проверке одной структуры.
+// В отличие от возврата первой же ошибки, вызывающий код видит сразу все проблемы.
+type ValidationErrors []*FieldError
+
+func (ve ValidationErrors) Error() string {
+	msgs := make([]string, len(ve))
+	for i, e := range ve {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap возвращает ошибки полей, чтобы errors.Is / errors.As искали по всем ним.
+func (ve ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(ve))
+	for i, e := range ve {
+		errs[i] = e
+	}
+	return errs
+}
+
+// Fields возвращает имена полей с ошибками в порядке проверки (без повторов).
+func (ve ValidationErrors) Fields() []string {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, e := range ve {
+		if !seen[e.Field] {
+			seen[e.Field] = true
+			fields = append(fields, e.Field)
+		}
+	}
+	return fields
+}
+
+var (
+	errUnknownField = errors.New("поле не найдено")
+	errNotStruct    = errors.New("значение не является структурой")
+)
+
+// fieldRules — набор правил для одного поля.
+type fieldRules struct {
+	name  string
+	rules []Rule
+}
+
+// Validator проверяет структуры по набору именованных правил для полей.
+// Поля ищутся по имени через reflect, поэтому один валидатор подходит для любой
+// структуры с такими полями (или указателя на нее).
+type Validator struct {
+	fields []fieldRules
+}
+
+// NewValidator создает пустой валидатор.
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// Field добавляет правила для поля name и возвращает валидатор для цепочки вызовов.
+// Правила одного поля выполняются все, в порядке добавления.
+func (v *Validator) Field(name string, rules ...Rule) *Validator {
+	v.fields = append(v.fields, fieldRules{name: name, rules: rules})
+	return v
+}
+
+// Validate проверяет s и возвращает ValidationErrors со всеми нарушениями
+// или nil, если структура корректна.
+func (v *Validator) Validate(s any) error {
+	rv := reflect.ValueOf(s)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T", errNotStruct, s)
+	}
+
+	var errs ValidationErrors
+	for _, f := range v.fields {
+		fv := rv.FieldByName(f.name)
+		if !fv.IsValid() {
+			errs = append(errs, &FieldError{Field: f.name, Err: errUnknownField})
+			continue
+		}
+		if !fv.CanInterface() {
+			errs = append(errs, &FieldError{Field: f.name, Err: errors.New("поле не экспортировано")})
+			continue
+		}
+		value := fv.Interface()
+		for _, rule := range f.rules {
+			if err := rule(value); err != nil {
+				errs = append(errs, &FieldError{Field: f.name, Err: err})
+			}
+		}
+	}
+
+	// Возвращаем именно nil-интерфейс, а не пустой ValidationErrors:
+	// иначе проверка err != nil у вызывающего кода всегда будет истинной.
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// --- Готовые правила ---
+
+// Required проверяет, что значение не равно нулевому значению своего типа.
+func Required() Rule {
+	return func(value any) error {
+		if value == nil || reflect.ValueOf(value).IsZero() {
+			return errors.New("обязательное поле не заполнено")
+		}
+		return nil
+	}
+}
+
+// Positive проверяет, что число (в том числе time.Duration) строго больше нуля.
+func Positive() Rule {
+	return func(value any) error {
+		rv := reflect.ValueOf(value)
+		switch {
+		case rv.CanInt():
+			if rv.Int() > 0 {
+				return nil
+			}
+		case rv.CanUint():
+			if rv.Uint() > 0 {
+				return nil
+			}
+		case rv.CanFloat():
+			if rv.Float() > 0 {
+				return nil
+			}
+		default:
+			return fmt.Errorf("ожидалось число, получено %T", value)
+		}
+		return fmt.Errorf("должно быть положительным, получено %v", value)
+	}
+}
+
+// MinLen проверяет, что длина строки (в рунах), среза или карты не меньше n.
+func MinLen(n int) Rule {
+	return func(value any) error {
+		var length int
+		if s, ok := value.(string); ok {
+			length = len([]rune(s))
+		} else {
+			rv := reflect.ValueOf(value)
+			switch rv.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map:
+				length = rv.Len()
+			default:
+				return fmt.Errorf("длина не определена для %T", value)
+			}
+		}
+		if length < n {
+			return fmt.Errorf("длина %d меньше минимальной %d", length, n)
+		}
+		return nil
+	}
+}
+
+// Matches проверяет, что строка соответствует регулярному выражению.
+func Matches(re *regexp.Regexp) Rule {
+	return func(value any) error {
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("ожидалась строка, получено %T", value)
+		}
+		if !re.MatchString(s) {
+			return fmt.Errorf("значение %q не соответствует шаблону %s", s, re)
+		}
+		return nil
+	}
+}
+
+// MatchesAll применяет StringValidator к строковому полю: значение должно
+// соответствовать всем его паттернам.
+func MatchesAll(sv *StringValidator) Rule {
+	return func(value any) error {
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("ожидалась строка, получено %T", value)
+		}
+		if !sv.Validate(s) {
+			return fmt.Errorf("значение %q не прошло проверку паттернами", s)
+		}
+		return nil
+	}
+}
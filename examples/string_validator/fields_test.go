@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	Name         string
+	Servers      []string
+	PollInterval time.Duration
+	Workers      int
+	secret       string
+}
+
+func newConfigValidator() *Validator {
+	return NewValidator().
+		Field("Name", Required(), Matches(regexp.MustCompile(`^[a-z]+$`))).
+		Field("Servers", MinLen(1)).
+		Field("PollInterval", Positive()).
+		Field("Workers", Positive())
+}
+
+func TestValidatorValidStructReturnsNil(t *testing.T) {
+	cfg := testConfig{
+		Name:         "prod",
+		Servers:      []string{"http://a"},
+		PollInterval: time.Second,
+		Workers:      4,
+	}
+	if err := newConfigValidator().Validate(cfg); err != nil {
+		t.Errorf("Validate = %v, ожидался nil", err)
+	}
+	// Указатель на структуру тоже поддерживается.
+	if err := newConfigValidator().Validate(&cfg); err != nil {
+		t.Errorf("Validate(&cfg) = %v, ожидался nil", err)
+	}
+}
+
+func TestValidatorReportsAllFailingFields(t *testing.T) {
+	cfg := testConfig{
+		Name:         "",
+		PollInterval: -time.Second,
+		Workers:      4,
+	}
+	err := newConfigValidator().Validate(cfg)
+
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("err = %v, ожидался ValidationErrors", err)
+	}
+	// Пустое имя нарушает оба правила поля Name.
+	if len(verrs) != 4 {
+		t.Errorf("получено %d ошибок, ожидалось 4: %v", len(verrs), verrs)
+	}
+	wantFields := []string{"Name", "Servers", "PollInterval"}
+	if got := verrs.Fields(); !slices.Equal(got, wantFields) {
+		t.Errorf("Fields() = %v, ожидалось %v", got, wantFields)
+	}
+}
+
+func TestValidatorUnwrapsRuleErrors(t *testing.T) {
+	errCustom := errors.New("custom")
+	v := NewValidator().Field("Workers", func(any) error { return errCustom })
+
+	err := v.Validate(testConfig{})
+	if !errors.Is(err, errCustom) {
+		t.Errorf("errors.Is(%v, errCustom) = false", err)
+	}
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Field != "Workers" {
+		t.Errorf("ожидалась *FieldError для поля Workers, получено %v", err)
+	}
+}
+
+func TestValidatorUnknownAndUnexportedFields(t *testing.T) {
+	v := NewValidator().Field("Missing", Required()).Field("secret", Required())
+	err := v.Validate(testConfig{secret: "x"})
+
+	if !errors.Is(err, errUnknownField) {
+		t.Errorf("ожидалась ошибка errUnknownField, получено %v", err)
+	}
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs) != 2 {
+		t.Errorf("ожидалось 2 ошибки, получено %v", err)
+	}
+}
+
+func TestValidatorRejectsNonStruct(t *testing.T) {
+	if err := NewValidator().Validate(42); !errors.Is(err, errNotStruct) {
+		t.Errorf("Validate(42) = %v, ожидалась errNotStruct", err)
+	}
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		value   any
+		wantErr bool
+	}{
+		{"Required строка", Required(), "x", false},
+		{"Required пустая строка", Required(), "", true},
+		{"Required nil", Required(), nil, true},
+		{"Positive int", Positive(), 1, false},
+		{"Positive ноль", Positive(), 0, true},
+		{"Positive uint", Positive(), uint(3), false},
+		{"Positive float", Positive(), -0.5, true},
+		{"Positive не число", Positive(), "1", true},
+		{"MinLen кириллица", MinLen(3), "абв", false},
+		{"MinLen короткая", MinLen(3), "аб", true},
+		{"MinLen карта", MinLen(1), map[string]int{}, true},
+		{"MinLen число", MinLen(1), 5, true},
+		{"Matches", Matches(regexp.MustCompile(`^\d+$`)), "123", false},
+		{"Matches не строка", Matches(regexp.MustCompile(`.`)), 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("rule(%v) = %v, ожидалась ошибка: %t", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchesAll(t *testing.T) {
+	sv := &StringValidator{patterns: []*regexp.Regexp{
+		regexp.MustCompile(`^user_`),
+		regexp.MustCompile(`_test$`),
+	}}
+	rule := MatchesAll(sv)
+	if err := rule("user_1_test"); err != nil {
+		t.Errorf("ожидалось успешное совпадение: %v", err)
+	}
+	if err := rule("admin_test"); err == nil {
+		t.Error("ожидалась ошибка")
+	}
+}
@@ -4,15 +4,19 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"regexp"
+
+	"github.com/andrewhigh08/exp/internal/syncutil"
 )
 
 // StringValidator хранит скомпилированные регулярные выражения для валидации.
 type StringValidator struct {
-	patterns []*regexp.Regexp
+	patterns    []*regexp.Regexp
+	maxLineSize int // Наибольшая длина строки в ValidateStream (см. SetMaxLineSize); 0 — DefaultMaxLineSize.
 }
 
 // NewStringValidator — это конструктор для валидатора.
@@ -117,4 +121,49 @@ func main() {
 		isValid := validator.Validate(tc)
 		fmt.Printf("Строка '%-15s' -> Валидна: %t\n", tc, isValid)
 	}
+
+	// Подробный отчет для ответа API: какие паттерны не совпали и почему.
+	fmt.Println("\n--- Отчет для API (JSON) ---")
+	report, err := json.MarshalIndent(validator.ValidateReport("user_456"), "", "  ")
+	if err != nil {
+		log.Fatalf("Не удалось сериализовать отчет: %v", err)
+	}
+	fmt.Println(string(report))
+
+	// 3. Валидация структуры: все ошибки собираются сразу, а не только первая.
+	type account struct {
+		Login string
+		Email string
+		Age   int
+	}
+	accountValidator := NewValidator().
+		Field("Login", Required(), Matches(regexp.MustCompile(`^user_`))).
+		Field("Email", Required(), Matches(regexp.MustCompile(`^[^@\s]+@[^@\s]+$`))).
+		Field("Age", Positive())
+
+	fmt.Println("\n--- Валидация структур ---")
+	for _, acc := range []account{
+		{Login: "user_007_test", Email: "bond@mi6.uk", Age: 40},
+		{Login: "admin", Email: "no-at-sign", Age: -1},
+	} {
+		if err := accountValidator.Validate(acc); err != nil {
+			fmt.Printf("%+v -> ошибки: %v\n", acc, err)
+		} else {
+			fmt.Printf("%+v -> валидна\n", acc)
+		}
+	}
+
+	// 4. Ленивая инициализация: паттерны загружаются при первой проверке, а не при старте.
+	lazyValidator := syncutil.NewLazyErr(func() (*StringValidator, error) {
+		fmt.Println("Загрузка паттернов при первом обращении...")
+		return NewStringValidator(patternFile)
+	})
+	fmt.Println("\n--- Ленивый валидатор ---")
+	for _, tc := range testCases[:2] {
+		v, err := lazyValidator.Get() // Загрузка произойдет только один раз.
+		if err != nil {
+			log.Fatalf("Ошибка при создании валидатора: %v", err)
+		}
+		fmt.Printf("Строка '%-15s' -> Валидна: %t\n", tc, v.Validate(tc))
+	}
 }
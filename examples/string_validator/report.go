@@ -0,0 +1,38 @@
+package main
+
+import "fmt"
+
+// PatternResult — результат проверки строки одним паттерном.
+type PatternResult struct {
+	Pattern string `json:"pattern"`
+	Matched bool   `json:"matched"`
+	Message string `json:"message,omitempty"` // Заполняется только при несовпадении.
+}
+
+// ValidationReport — подробный результат Validate, пригодный для ответа API в JSON:
+// итог и результат по каждому паттерну в порядке их загрузки.
+type ValidationReport struct {
+	Input   string          `json:"input"`
+	Valid   bool            `json:"valid"`
+	Results []PatternResult `json:"results"`
+}
+
+// ValidateReport проверяет строку всеми паттернами и возвращает отчет. В отличие от
+// Validate, проверка не останавливается на первом несовпадении: в отчет попадают все.
+// Valid в отчете совпадает с результатом Validate.
+func (sv *StringValidator) ValidateReport(str string) ValidationReport {
+	report := ValidationReport{
+		Input:   str,
+		Valid:   true,
+		Results: make([]PatternResult, 0, len(sv.patterns)), // [] вместо null в JSON.
+	}
+	for _, p := range sv.patterns {
+		res := PatternResult{Pattern: p.String(), Matched: p.MatchString(str)}
+		if !res.Matched {
+			res.Message = fmt.Sprintf("строка не соответствует паттерну %s", p)
+			report.Valid = false
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+)
+
+// reportJSON возвращает отчет валидатора sv для str в виде JSON.
+func reportJSON(t *testing.T, sv *StringValidator, str string) string {
+	t.Helper()
+	data, err := json.Marshal(sv.ValidateReport(str))
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	return string(data)
+}
+
+func TestValidateReportValid(t *testing.T) {
+	got := reportJSON(t, newTestStringValidator(), "user_1_test")
+	want := `{"input":"user_1_test","valid":true,"results":[` +
+		`{"pattern":"^user_","matched":true},` +
+		`{"pattern":"_test$","matched":true}]}`
+	if got != want {
+		t.Errorf("отчет\n%s\nожидалось\n%s", got, want)
+	}
+}
+
+func TestValidateReportPartiallyFailing(t *testing.T) {
+	got := reportJSON(t, newTestStringValidator(), "admin_test")
+	want := `{"input":"admin_test","valid":false,"results":[` +
+		`{"pattern":"^user_","matched":false,"message":"строка не соответствует паттерну ^user_"},` +
+		`{"pattern":"_test$","matched":true}]}`
+	if got != want {
+		t.Errorf("отчет\n%s\nожидалось\n%s", got, want)
+	}
+}
+
+func TestValidateReportMatchesValidate(t *testing.T) {
+	sv := &StringValidator{patterns: []*regexp.Regexp{
+		regexp.MustCompile(`^user_`),
+		regexp.MustCompile(`\d{3}`),
+		regexp.MustCompile(`_test$`),
+	}}
+	for _, str := range []string{"user_123_test", "user_456", "admin_123_test", "user_12_test", ""} {
+		if got, want := sv.ValidateReport(str).Valid, sv.Validate(str); got != want {
+			t.Errorf("ValidateReport(%q).Valid = %t, Validate = %t", str, got, want)
+		}
+	}
+}
+
+func TestValidateReportWithoutPatterns(t *testing.T) {
+	got := reportJSON(t, &StringValidator{}, "anything")
+	if want := `{"input":"anything","valid":true,"results":[]}`; got != want {
+		t.Errorf("отчет %s, ожидалось %s", got, want)
+	}
+}
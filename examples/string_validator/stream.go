@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxLineSize — наибольшая длина строки в байтах, которую ValidateStream
+// принимает по умолчанию.
+const DefaultMaxLineSize = 1 << 20
+
+// SetMaxLineSize задает наибольшую длину строки в байтах для ValidateStream и
+// ValidateStreamFrom. n <= 0 возвращает DefaultMaxLineSize. Возвращает sv для
+// цепочки вызовов.
+func (sv *StringValidator) SetMaxLineSize(n int) *StringValidator {
+	sv.maxLineSize = max(n, 0)
+	return sv
+}
+
+// ScanInterruptedError возвращается ValidateStream, если проверка была прервана
+// отменой контекста. LastLine — номер последней проверенной строки: передав
+// LastLine+1 в ValidateStreamFrom, можно продолжить проверку с того же места.
+type ScanInterruptedError struct {
+	LastLine int
+	Err      error
+}
+
+func (e *ScanInterruptedError) Error() string {
+	return fmt.Sprintf("проверка прервана после строки %d: %v", e.LastLine, e.Err)
+}
+
+// Unwrap позволяет проверять причину через errors.Is(err, context.Canceled).
+func (e *ScanInterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateStream построчно проверяет поток r и возвращает номера строк (с 1),
+// не прошедших Validate. Поток читается по одной строке, поэтому размер входных
+// данных не ограничен памятью. Ограничена только длина одной строки (см.
+// SetMaxLineSize): на более длинной строке проверка завершается ошибкой,
+// для которой errors.Is(err, bufio.ErrTooLong).
+//
+// Контекст проверяется перед каждой строкой. При отмене возвращаются уже найденные
+// плохие строки и *ScanInterruptedError с номером последней проверенной строки.
+func (sv *StringValidator) ValidateStream(ctx context.Context, r io.Reader) (badLines []int, err error) {
+	return sv.ValidateStreamFrom(ctx, r, 1)
+}
+
+// ValidateStreamFrom работает как ValidateStream, но пропускает строки с номерами меньше
+// fromLine (не проверяя их). Нумерация строк сохраняется, поэтому после прерывания
+// можно открыть тот же источник заново и продолжить с LastLine+1.
+func (sv *StringValidator) ValidateStreamFrom(ctx context.Context, r io.Reader, fromLine int) (badLines []int, err error) {
+	limit := sv.maxLineSize
+	if limit == 0 {
+		limit = DefaultMaxLineSize
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, limit) // Буфер растет по мере надобности, но не больше limit байт.
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if lineNumber < fromLine {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return badLines, &ScanInterruptedError{LastLine: lineNumber - 1, Err: err}
+		}
+		if !sv.Validate(scanner.Text()) {
+			badLines = append(badLines, lineNumber)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return badLines, fmt.Errorf("ошибка чтения строки %d: %w", lineNumber+1, err)
+	}
+	return badLines, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newTestStringValidator() *StringValidator {
+	return &StringValidator{patterns: []*regexp.Regexp{
+		regexp.MustCompile(`^user_`),
+		regexp.MustCompile(`_test$`),
+	}}
+}
+
+const streamInput = `user_1_test
+admin_test
+user_2_test
+user_3
+
+user_4_test`
+
+func TestValidateStreamReportsBadLines(t *testing.T) {
+	sv := newTestStringValidator()
+	bad, err := sv.ValidateStream(context.Background(), strings.NewReader(streamInput))
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if want := []int{2, 4, 5}; !slices.Equal(bad, want) {
+		t.Errorf("badLines = %v, ожидалось %v", bad, want)
+	}
+}
+
+// cancelingReader отменяет контекст, когда из него прочитано больше limit байт.
+// Чтение идет по одному байту, чтобы bufio.Scanner не забрал весь ввод сразу.
+type cancelingReader struct {
+	r      io.Reader
+	read   int
+	limit  int
+	cancel context.CancelFunc
+}
+
+func (c *cancelingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p[:1])
+	c.read += n
+	if c.read > c.limit {
+		c.cancel()
+	}
+	return n, err
+}
+
+func TestValidateStreamStopsOnCancel(t *testing.T) {
+	sv := newTestStringValidator()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Отмена срабатывает во время чтения третьей строки.
+	limit := len("user_1_test\nadmin_test\n")
+	r := &cancelingReader{r: strings.NewReader(streamInput), limit: limit, cancel: cancel}
+
+	bad, err := sv.ValidateStream(ctx, r)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, ожидалась context.Canceled", err)
+	}
+	var interrupted *ScanInterruptedError
+	if !errors.As(err, &interrupted) {
+		t.Fatalf("ожидалась *ScanInterruptedError, получено %T", err)
+	}
+	if interrupted.LastLine != 2 {
+		t.Errorf("LastLine = %d, ожидалось 2", interrupted.LastLine)
+	}
+	if want := []int{2}; !slices.Equal(bad, want) {
+		t.Errorf("badLines до отмены = %v, ожидалось %v", bad, want)
+	}
+
+	// Продолжаем с места остановки на новом чтении того же источника.
+	rest, err := sv.ValidateStreamFrom(context.Background(), strings.NewReader(streamInput), interrupted.LastLine+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{4, 5}; !slices.Equal(rest, want) {
+		t.Errorf("после возобновления badLines = %v, ожидалось %v", rest, want)
+	}
+}
+
+func TestValidateStreamAlreadyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bad, err := newTestStringValidator().ValidateStream(ctx, strings.NewReader(streamInput))
+	if !errors.Is(err, context.Canceled) || len(bad) != 0 {
+		t.Errorf("bad = %v, err = %v", bad, err)
+	}
+}
+
+func TestValidateStreamLongLines(t *testing.T) {
+	// Строка длиннее стандартного предела bufio.Scanner (64 КБ).
+	long := "user_" + strings.Repeat("x", 100_000) + "_test"
+	input := "user_1_test\n" + long + "\nuser_2\n"
+
+	bad, err := newTestStringValidator().ValidateStream(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("err = %v: строка в 100 КБ должна укладываться в DefaultMaxLineSize", err)
+	}
+	if !slices.Equal(bad, []int{3}) {
+		t.Errorf("плохие строки %v, ожидалось [3]", bad)
+	}
+
+	sv := newTestStringValidator().SetMaxLineSize(1024)
+	bad, err = sv.ValidateStream(context.Background(), strings.NewReader(input))
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Fatalf("err = %v, ожидалась bufio.ErrTooLong", err)
+	}
+	if len(bad) != 0 {
+		t.Errorf("плохие строки %v до длинной строки, ожидалось ни одной", bad)
+	}
+}
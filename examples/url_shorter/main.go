@@ -4,30 +4,92 @@ package main
 
 import (
 	"fmt"
+	"strings"
 )
 
 // Abbreviator — это пользовательский тип на основе строки.
 // Мы определяем для него собственный метод `String()`.
 type Abbreviator string
 
-// String реализует интерфейс `fmt.Stringer` для типа Abbreviator.
-// Когда значение этого типа передается в функцию пакета fmt (например, Println),
-// для его отображения будет автоматически вызван этот метод.
-//
-// Логика: "kubernetes" -> "k" + "8" (длина - 2) + "s" -> "k8s".
-func (s Abbreviator) String() string {
+// Шаблоны аббревиатур для NewAbbreviatorFormat.
+// В шаблон всегда передаются три аргумента: первая руна (%c), количество пропущенных
+// символов (%d) и последняя руна (%c). Шаблону не обязательно использовать их все:
+// с явными индексами (%[1]c, %[3]c) можно пропустить число.
+const (
+	FormatCompact  = "%c%d%c"        // kubernetes -> k8s
+	FormatEllipsis = "%[1]c...%[3]c" // kubernetes -> k...s
+	FormatDashed   = "%c-%d-%c"      // kubernetes -> k-8-s
+)
+
+// AbbreviatorFormat формирует аббревиатуры по заданному шаблону fmt.
+type AbbreviatorFormat struct {
+	template  string
+	trimSpace bool
+	lowercase bool
+}
+
+// FormatOption настраивает нормализацию входа в AbbreviatorFormat.
+type FormatOption func(*AbbreviatorFormat)
+
+// WithTrimSpace включает удаление пробельных символов по краям строки перед сокращением:
+// " kubernetes " -> "k8s", а не " 10 ". Строка только из пробелов дает пустой результат.
+func WithTrimSpace() FormatOption {
+	return func(f *AbbreviatorFormat) { f.trimSpace = true }
+}
+
+// WithLowercase включает приведение строки к нижнему регистру перед сокращением:
+// "Kubernetes" -> "k8s".
+func WithLowercase() FormatOption {
+	return func(f *AbbreviatorFormat) { f.lowercase = true }
+}
+
+// NewAbbreviatorFormat создает форматтер с шаблоном template (см. FormatCompact и др.).
+// По умолчанию строка сокращается как есть; нормализацию включают опции.
+func NewAbbreviatorFormat(template string, opts ...FormatOption) AbbreviatorFormat {
+	f := AbbreviatorFormat{template: template}
+	for _, opt := range opts {
+		opt(&f)
+	}
+	return f
+}
+
+// Abbreviate возвращает аббревиатуру s по шаблону форматтера.
+// Строки из двух и менее символов (после нормализации) возвращаются как есть,
+// поэтому пустая после обрезки пробелов строка дает "".
+func (f AbbreviatorFormat) Abbreviate(s string) string {
+	s = f.normalize(s)
+
 	// Преобразуем в срез рун для корректной работы с многобайтными символами (например, кириллицей).
 	runes := []rune(s)
 	length := len(runes)
 
 	// Если строка слишком короткая для аббревиатуры, возвращаем ее как есть.
 	if length <= 2 {
-		return string(s)
+		return s
 	}
 
-	// Формируем аббревиатуру.
 	// Использование fmt.Sprintf более читаемо и идиоматично, чем ручная конкатенация.
-	return fmt.Sprintf("%c%d%c", runes[0], length-2, runes[length-1])
+	return fmt.Sprintf(f.template, runes[0], length-2, runes[length-1])
+}
+
+// normalize применяет к s включенные опциями шаги нормализации.
+func (f AbbreviatorFormat) normalize(s string) string {
+	if f.trimSpace {
+		s = strings.TrimSpace(s)
+	}
+	if f.lowercase {
+		s = strings.ToLower(s)
+	}
+	return s
+}
+
+// String реализует интерфейс `fmt.Stringer` для типа Abbreviator.
+// Когда значение этого типа передается в функцию пакета fmt (например, Println),
+// для его отображения будет автоматически вызван этот метод.
+//
+// Логика: "kubernetes" -> "k" + "8" (длина - 2) + "s" -> "k8s".
+func (s Abbreviator) String() string {
+	return NewAbbreviatorFormat(FormatCompact).Abbreviate(string(s))
 }
 
 func main() {
@@ -45,4 +107,17 @@ func main() {
 		// что тип `Abbreviator` имеет метод `String() string`, и вызывает его.
 		fmt.Printf("Исходная строка: '%s', результат: %s\n", str, str)
 	}
+
+	fmt.Println("\n--- Альтернативные форматы ---")
+	for _, template := range []string{FormatEllipsis, FormatDashed, "%c(%d)%c"} {
+		f := NewAbbreviatorFormat(template)
+		fmt.Printf("Шаблон %q: %s\n", template, f.Abbreviate("kubernetes"))
+	}
+
+	fmt.Println("\n--- Нормализация входа ---")
+	normalized := NewAbbreviatorFormat(FormatCompact, WithTrimSpace(), WithLowercase())
+	for _, raw := range []string{" Kubernetes ", "\tLocalization\n", "   "} {
+		fmt.Printf("Исходная строка: %q, как есть: %q, с нормализацией: %q\n",
+			raw, NewAbbreviatorFormat(FormatCompact).Abbreviate(raw), normalized.Abbreviate(raw))
+	}
 }
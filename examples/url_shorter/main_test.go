@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestAbbreviatorFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		in       string
+		want     string
+	}{
+		{"compact", FormatCompact, "kubernetes", "k8s"},
+		{"ellipsis", FormatEllipsis, "kubernetes", "k...s"},
+		{"dashed", FormatDashed, "kubernetes", "k-8-s"},
+		{"custom", "%c[%d]%c", "internationalization", "i[18]n"},
+		{"cyrillic", FormatDashed, "адаптация", "а-7-я"},
+		{"three runes", FormatEllipsis, "abc", "a...c"},
+		{"too short", FormatDashed, "hi", "hi"},
+		{"single rune", FormatEllipsis, "я", "я"},
+		{"empty", FormatCompact, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewAbbreviatorFormat(tt.template).Abbreviate(tt.in); got != tt.want {
+				t.Errorf("Abbreviate(%q) с шаблоном %q = %q, ожидалось %q", tt.in, tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAbbreviatorStringUsesCompactFormat(t *testing.T) {
+	if got := Abbreviator("localization").String(); got != "l10n" {
+		t.Errorf("String() = %q, ожидалось %q", got, "l10n")
+	}
+	if got := Abbreviator("ok").String(); got != "ok" {
+		t.Errorf("String() = %q, ожидалось %q", got, "ok")
+	}
+}
+
+func TestAbbreviatorFormatNormalization(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []FormatOption
+		in   string
+		want string
+	}{
+		{"no options", nil, " Kubernetes ", " 10 "},
+		{"trim", []FormatOption{WithTrimSpace()}, " Kubernetes\t", "K8s"},
+		{"lowercase", []FormatOption{WithLowercase()}, "KuberNetes", "k8s"},
+		{"trim and lowercase", []FormatOption{WithTrimSpace(), WithLowercase()}, "\n Адаптация ", "а7я"},
+		{"trim to short", []FormatOption{WithTrimSpace()}, "  hi  ", "hi"},
+		{"all whitespace", []FormatOption{WithTrimSpace(), WithLowercase()}, " \t\n ", ""},
+		{"all whitespace untrimmed", nil, "    ", " 2 "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewAbbreviatorFormat(FormatCompact, tt.opts...).Abbreviate(tt.in); got != tt.want {
+				t.Errorf("Abbreviate(%q) = %q, ожидалось %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,4 +2,7 @@ module github.com/andrewhigh08/exp
 
 go 1.25.5
 
-require golang.org/x/sync v0.18.0
+require (
+	github.com/rivo/uniseg v0.4.7
+	golang.org/x/sync v0.18.0
+)
@@ -0,0 +1,36 @@
+// Package chanutil содержит вспомогательные примитивы для работы с каналами,
+// общие для примеров конкурентности.
+package chanutil
+
+import "sync"
+
+// CloseOnce — обертка над каналом, гарантирующая, что он будет закрыт не более одного раза.
+//
+// Повторный close канала вызывает панику, поэтому компоненты, которые могут закрыть
+// один и тот же канал с разных путей (отписка, завершение работы, очистка зависших
+// подписчиков), закрывают его через Close. Безопасна для конкурентного использования.
+type CloseOnce[T any] struct {
+	ch   chan T
+	once sync.Once
+}
+
+// NewCloseOnce оборачивает канал ch.
+func NewCloseOnce[T any](ch chan T) *CloseOnce[T] {
+	return &CloseOnce[T]{ch: ch}
+}
+
+// C возвращает обернутый канал.
+func (c *CloseOnce[T]) C() chan T {
+	return c.ch
+}
+
+// Close закрывает канал, если он еще не закрыт.
+// Возвращает true, только если закрытие выполнил именно этот вызов.
+func (c *CloseOnce[T]) Close() bool {
+	closed := false
+	c.once.Do(func() {
+		close(c.ch)
+		closed = true
+	})
+	return closed
+}
@@ -0,0 +1,52 @@
+package chanutil
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCloseOnceClosesChannel(t *testing.T) {
+	c := NewCloseOnce(make(chan int, 1))
+	c.C() <- 1
+
+	if !c.Close() {
+		t.Fatal("первый Close() = false, ожидалось true")
+	}
+	if c.Close() {
+		t.Fatal("повторный Close() = true, ожидалось false")
+	}
+	// Буферизованное значение доступно и после закрытия.
+	if v, ok := <-c.C(); !ok || v != 1 {
+		t.Fatalf("<-C() = %d, %v; ожидалось 1, true", v, ok)
+	}
+	if _, ok := <-c.C(); ok {
+		t.Fatal("канал не закрыт")
+	}
+}
+
+func TestCloseOnceConcurrent(t *testing.T) {
+	const goroutines = 100
+	for range 50 {
+		c := NewCloseOnce(make(chan struct{}))
+		var closes atomic.Int64
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		wg.Add(goroutines)
+		for range goroutines {
+			go func() {
+				defer wg.Done()
+				<-start
+				if c.Close() {
+					closes.Add(1)
+				}
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		if got := closes.Load(); got != 1 {
+			t.Fatalf("Close() вернул true %d раз, ожидалось ровно 1", got)
+		}
+	}
+}
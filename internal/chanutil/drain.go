@@ -0,0 +1,25 @@
+package chanutil
+
+import "context"
+
+// Drain читает значения из ch, пока канал не закроется или не отменится ctx.
+//
+// Если канал закрылся, возвращает все прочитанные значения и nil. При отмене ctx
+// возвращает то, что успел прочитать, и ctx.Err(); значения, оставшиеся в канале,
+// не вычитываются, поэтому отправители не должны блокироваться навсегда на полном
+// канале (обычно канал результатов буферизуют или отправляют в него через select с ctx).
+// Чтение из nil-канала продолжается до отмены ctx.
+func Drain[T any](ctx context.Context, ch <-chan T) ([]T, error) {
+	var items []T
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return items, nil
+			}
+			items = append(items, v)
+		case <-ctx.Done():
+			return items, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package chanutil
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDrainUntilClosed(t *testing.T) {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := range 5 {
+			ch <- i
+		}
+	}()
+
+	got, err := Drain(context.Background(), ch)
+	if err != nil {
+		t.Fatalf("Drain() вернул ошибку %v, ожидалось nil", err)
+	}
+	if want := []int{0, 1, 2, 3, 4}; !slices.Equal(got, want) {
+		t.Errorf("Drain() = %v, ожидалось %v", got, want)
+	}
+}
+
+func TestDrainCancelledMidway(t *testing.T) {
+	ch := make(chan int)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		ch <- 1
+		ch <- 2
+		cancel() // Канал так и не закроется.
+	}()
+
+	got, err := Drain(ctx, ch)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Drain() вернул ошибку %v, ожидалось %v", err, context.Canceled)
+	}
+	if want := []int{1, 2}; !slices.Equal(got, want) {
+		t.Errorf("Drain() = %v, ожидалось прочитанное начало %v", got, want)
+	}
+}
+
+func TestDrainDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	got, err := Drain(ctx, make(chan string))
+	if !errors.Is(err, context.DeadlineExceeded) || len(got) != 0 {
+		t.Errorf("Drain() = %v, %v; ожидалось пусто, %v", got, err, context.DeadlineExceeded)
+	}
+}
+
+func TestDrainAlreadyClosed(t *testing.T) {
+	ch := make(chan int, 2)
+	ch <- 7
+	close(ch)
+
+	got, err := Drain(context.Background(), ch)
+	if err != nil || !slices.Equal(got, []int{7}) {
+		t.Errorf("Drain() = %v, %v; ожидалось [7], nil", got, err)
+	}
+
+	empty := make(chan int)
+	close(empty)
+	if got, err := Drain(context.Background(), empty); err != nil || got != nil {
+		t.Errorf("Drain(закрытый пустой канал) = %v, %v; ожидалось nil, nil", got, err)
+	}
+}
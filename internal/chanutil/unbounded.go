@@ -0,0 +1,107 @@
+package chanutil
+
+import "sync"
+
+// UnboundedChan — FIFO-канал без ограничения емкости: Send никогда не блокируется,
+// а значения копятся в растущем срезе, пока получатель их не заберет.
+//
+// Обычный буферизованный канал при переполнении заставляет отправителя выбирать между
+// блокировкой и потерей значения. UnboundedChan не теряет и не блокирует ценой памяти:
+// медленный получатель накапливает очередь, поэтому подходит он только там, где поток
+// ограничен сверху другими средствами.
+//
+// Значения выдаются в порядке Send через канал Receive отдельной горутиной. После Close
+// оставшиеся в очереди значения все равно доставляются, а затем канал Receive закрывается —
+// как у закрытого буферизованного канала. Если получатель перестал читать, горутина
+// доставки остается заблокированной до тех пор, пока он не дочитает канал.
+// Безопасен для конкурентного использования.
+type UnboundedChan[T any] struct {
+	mu     sync.Mutex
+	buf    []T
+	closed bool
+
+	notify chan struct{} // Сигнал горутине доставки о новых значениях или закрытии.
+	out    chan T
+}
+
+// NewUnboundedChan создает канал и запускает горутину доставки.
+func NewUnboundedChan[T any]() *UnboundedChan[T] {
+	c := &UnboundedChan[T]{
+		notify: make(chan struct{}, 1),
+		out:    make(chan T),
+	}
+	go c.run()
+	return c
+}
+
+// Send ставит значение в очередь и никогда не блокируется.
+// Как и отправка в закрытый канал, Send после Close вызывает панику.
+func (c *UnboundedChan[T]) Send(v T) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		panic("chanutil: Send в закрытый UnboundedChan")
+	}
+	c.buf = append(c.buf, v)
+	c.mu.Unlock()
+	c.signal()
+}
+
+// Receive возвращает канал, из которого читаются значения в порядке Send.
+func (c *UnboundedChan[T]) Receive() <-chan T {
+	return c.out
+}
+
+// Close прекращает прием значений. Канал Receive закроется, когда очередь будет вычитана.
+// Повторный вызов безопасен и возвращает false; true — только если закрыл этот вызов.
+func (c *UnboundedChan[T]) Close() bool {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return false
+	}
+	c.closed = true
+	c.mu.Unlock()
+	c.signal()
+	return true
+}
+
+// Len возвращает количество значений в очереди, еще не переданных в канал Receive.
+func (c *UnboundedChan[T]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.buf)
+}
+
+// signal будит горутину доставки, не блокируясь, если сигнал уже ожидает обработки.
+func (c *UnboundedChan[T]) signal() {
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+// run передает значения из очереди в out, пока очередь не опустеет после Close.
+func (c *UnboundedChan[T]) run() {
+	defer close(c.out)
+	var zero T
+	for {
+		c.mu.Lock()
+		if len(c.buf) == 0 {
+			closed := c.closed
+			c.buf = nil // Отдаем сборщику мусора массив, выросший во время пика.
+			c.mu.Unlock()
+			if closed {
+				return
+			}
+			<-c.notify
+			continue
+		}
+		v := c.buf[0]
+		c.buf[0] = zero // Не держим ссылку на выданное значение.
+		c.buf = c.buf[1:]
+		c.mu.Unlock()
+
+		c.out <- v
+	}
+}
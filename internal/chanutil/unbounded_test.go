@@ -0,0 +1,104 @@
+package chanutil
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUnboundedChanFastProducer(t *testing.T) {
+	const n = 10000
+	c := NewUnboundedChan[int]()
+
+	// Отправитель не ждет получателя: все Send завершаются до начала чтения.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range n {
+			c.Send(i)
+		}
+		c.Close()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Send заблокировался без получателя")
+	}
+
+	next := 0
+	for v := range c.Receive() {
+		if v != next {
+			t.Fatalf("получено %d, ожидалось %d", v, next)
+		}
+		next++
+		if next%1000 == 0 {
+			time.Sleep(time.Millisecond) // Медленный получатель.
+		}
+	}
+	if next != n {
+		t.Fatalf("получено %d значений, ожидалось %d", next, n)
+	}
+	if l := c.Len(); l != 0 {
+		t.Errorf("Len() = %d после вычитывания, ожидалось 0", l)
+	}
+}
+
+func TestUnboundedChanConcurrentSenders(t *testing.T) {
+	const senders, perSender = 8, 1000
+	c := NewUnboundedChan[[2]int]()
+
+	var wg sync.WaitGroup
+	wg.Add(senders)
+	for s := range senders {
+		go func() {
+			defer wg.Done()
+			for i := range perSender {
+				c.Send([2]int{s, i})
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		c.Close()
+	}()
+
+	// Порядок значений каждого отправителя сохраняется.
+	next := make([]int, senders)
+	total := 0
+	for v := range c.Receive() {
+		if v[1] != next[v[0]] {
+			t.Fatalf("отправитель %d: получено %d, ожидалось %d", v[0], v[1], next[v[0]])
+		}
+		next[v[0]]++
+		total++
+	}
+	if total != senders*perSender {
+		t.Fatalf("получено %d значений, ожидалось %d", total, senders*perSender)
+	}
+}
+
+func TestUnboundedChanClose(t *testing.T) {
+	c := NewUnboundedChan[string]()
+	c.Send("a")
+	if !c.Close() {
+		t.Fatal("первый Close() = false, ожидалось true")
+	}
+	if c.Close() {
+		t.Fatal("повторный Close() = true, ожидалось false")
+	}
+
+	// Значение, отправленное до Close, доставляется, затем канал закрывается.
+	if v, ok := <-c.Receive(); !ok || v != "a" {
+		t.Fatalf("<-Receive() = %q, %v; ожидалось a, true", v, ok)
+	}
+	if _, ok := <-c.Receive(); ok {
+		t.Fatal("канал не закрыт")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Send после Close не запаниковал")
+		}
+	}()
+	c.Send("b")
+}
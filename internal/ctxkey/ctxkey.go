@@ -0,0 +1,37 @@
+// Package ctxkey содержит типобезопасные ключи для значений контекста.
+package ctxkey
+
+import "context"
+
+// Key — ключ значения контекста типа T.
+//
+// context.WithValue принимает ключ и значение как any, поэтому легко ошибиться:
+// положить значение не того типа, забыть утверждение типа при чтении или случайно
+// совпасть с ключом из другого пакета (например, строкой "user"). Key решает все три
+// проблемы: тип значения зафиксирован параметром T, а ключом в контексте служит сам
+// указатель на Key, поэтому два ключа не совпадают, даже если у них одинаковые имя и тип.
+type Key[T any] struct {
+	name string
+}
+
+// NewContextKey создает новый уникальный ключ. name используется только для отладки.
+func NewContextKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// WithValue возвращает копию ctx, в которой по ключу k хранится v.
+func (k *Key[T]) WithValue(ctx context.Context, v T) context.Context {
+	return context.WithValue(ctx, k, v)
+}
+
+// From возвращает значение по ключу k и true, если оно было сохранено в ctx
+// (или в одном из его родителей).
+func (k *Key[T]) From(ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(k).(T)
+	return v, ok
+}
+
+// String возвращает имя ключа; используется при выводе контекста (fmt.Print(ctx)).
+func (k *Key[T]) String() string {
+	return "ctxkey." + k.name
+}
@@ -0,0 +1,66 @@
+package ctxkey
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestKeyRoundTrip(t *testing.T) {
+	userID := NewContextKey[int]("user_id")
+	ctx := userID.WithValue(context.Background(), 42)
+
+	if v, ok := userID.From(ctx); !ok || v != 42 {
+		t.Errorf("From() = %d, %v; ожидалось 42, true", v, ok)
+	}
+	// Значение видно и в дочерних контекстах.
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if v, ok := userID.From(child); !ok || v != 42 {
+		t.Errorf("From(child) = %d, %v; ожидалось 42, true", v, ok)
+	}
+}
+
+func TestKeyMissing(t *testing.T) {
+	key := NewContextKey[string]("missing")
+	if v, ok := key.From(context.Background()); ok || v != "" {
+		t.Errorf("From() = %q, %v; ожидалось нулевое значение, false", v, ok)
+	}
+}
+
+func TestKeysAreIsolated(t *testing.T) {
+	// Одинаковые имя и тип не делают ключи равными.
+	a := NewContextKey[string]("name")
+	b := NewContextKey[string]("name")
+	other := NewContextKey[int]("name")
+
+	ctx := a.WithValue(context.Background(), "from a")
+	ctx = other.WithValue(ctx, 7)
+
+	if _, ok := b.From(ctx); ok {
+		t.Error("ключ b видит значение, сохраненное под ключом a")
+	}
+	if v, _ := a.From(ctx); v != "from a" {
+		t.Errorf("a.From() = %q, ожидалось %q", v, "from a")
+	}
+	if v, _ := other.From(ctx); v != 7 {
+		t.Errorf("other.From() = %d, ожидалось 7", v)
+	}
+
+	// Строковый ключ context.WithValue с тем же именем не пересекается с Key.
+	ctx = context.WithValue(ctx, "name", "plain")
+	if v, _ := a.From(ctx); v != "from a" {
+		t.Errorf("a.From() после обычного WithValue = %q", v)
+	}
+}
+
+func TestKeyString(t *testing.T) {
+	key := NewContextKey[int]("request_id")
+	ctx := key.WithValue(context.Background(), 1)
+	if s := key.String(); s != "ctxkey.request_id" {
+		t.Errorf("String() = %q, ожидалось ctxkey.request_id", s)
+	}
+	if !strings.Contains(ctx.(interface{ String() string }).String(), "ctxkey.request_id") {
+		t.Errorf("в строке контекста %q нет имени ключа", ctx)
+	}
+}
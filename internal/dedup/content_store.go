@@ -0,0 +1,120 @@
+// Package dedup реализует дедупликацию одинаковых данных по их содержимому.
+package dedup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// Hash — адрес значения в ContentStore: SHA-256 его содержимого.
+type Hash [sha256.Size]byte
+
+// String возвращает хеш в шестнадцатеричном виде.
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// Content — типы значений, которые можно хранить в ContentStore.
+type Content interface {
+	~string | ~[]byte
+}
+
+// ContentStore хранит значения по хешу их содержимого (content-addressable storage):
+// одинаковые значения, сколько бы раз их ни сохранили, занимают одну запись.
+// Подходит для повторяющихся полезных нагрузок — текстов логов, значений кэша.
+//
+// Записи учитывают ссылки: каждый Put и Retain добавляет ссылку, каждый Release
+// снимает одну, и запись удаляется, когда ссылок не остается. Поэтому каждый
+// владелец хеша должен вызвать Release ровно столько раз, сколько брал ссылку.
+//
+// Значения хранятся копией: изменение []byte после Put или после Get не затрагивает
+// хранилище. Безопасен для конкурентного использования.
+type ContentStore[T Content] struct {
+	mu      sync.RWMutex
+	entries map[Hash]*entry
+}
+
+// entry — значение и число ссылок на него.
+type entry struct {
+	value string // Неизменяемая копия содержимого.
+	refs  int
+}
+
+// NewContentStore создает пустое хранилище.
+func NewContentStore[T Content]() *ContentStore[T] {
+	return &ContentStore[T]{entries: make(map[Hash]*entry)}
+}
+
+// Put сохраняет value (если такого содержимого еще нет), добавляет ссылку на него
+// и возвращает его хеш.
+func (s *ContentStore[T]) Put(value T) Hash {
+	h := Hash(sha256.Sum256([]byte(value)))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[h]; ok {
+		e.refs++
+		return h
+	}
+	s.entries[h] = &entry{value: string(value), refs: 1}
+	return h
+}
+
+// Get возвращает значение по хешу. ok == false, если такого значения нет.
+func (s *ContentStore[T]) Get(h Hash) (value T, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.entries[h]
+	if !ok {
+		return value, false
+	}
+	return T(e.value), true
+}
+
+// Retain добавляет ссылку на уже сохраненное значение — например, когда хешем
+// начинает пользоваться еще один владелец. Возвращает false, если значения нет.
+func (s *ContentStore[T]) Retain(h Hash) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[h]
+	if ok {
+		e.refs++
+	}
+	return ok
+}
+
+// Release снимает одну ссылку и удаляет значение, если ссылок не осталось.
+// Возвращает true, если значение удалено, и false, если на него еще ссылаются
+// или его нет.
+func (s *ContentStore[T]) Release(h Hash) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[h]
+	if !ok {
+		return false
+	}
+	e.refs--
+	if e.refs > 0 {
+		return false
+	}
+	delete(s.entries, h)
+	return true
+}
+
+// Refs возвращает число ссылок на значение (0, если его нет).
+func (s *ContentStore[T]) Refs(h Hash) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if e, ok := s.entries[h]; ok {
+		return e.refs
+	}
+	return 0
+}
+
+// Len возвращает число уникальных значений в хранилище.
+func (s *ContentStore[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries)
+}
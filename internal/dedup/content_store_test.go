@@ -0,0 +1,110 @@
+package dedup
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestContentStoreSharesIdenticalContent(t *testing.T) {
+	s := NewContentStore[string]()
+	a := s.Put("disk space is low")
+	b := s.Put(fmt.Sprint("disk space ", "is low")) // Другой экземпляр с тем же содержимым.
+	c := s.Put("user logged in")
+
+	if a != b {
+		t.Errorf("одинаковое содержимое получило разные хеши: %s и %s", a, b)
+	}
+	if a == c {
+		t.Error("разное содержимое получило одинаковый хеш")
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len = %d, ожидалось 2", s.Len())
+	}
+	if s.Refs(a) != 2 || s.Refs(c) != 1 {
+		t.Errorf("Refs = %d и %d, ожидалось 2 и 1", s.Refs(a), s.Refs(c))
+	}
+	if v, ok := s.Get(a); !ok || v != "disk space is low" {
+		t.Errorf("Get = (%q, %v)", v, ok)
+	}
+	if want := Hash(sha256.Sum256([]byte("user logged in"))); c != want {
+		t.Errorf("хеш %s, ожидался SHA-256 содержимого %s", c, want)
+	}
+}
+
+func TestContentStoreReleaseDropsLastReference(t *testing.T) {
+	s := NewContentStore[string]()
+	h := s.Put("v")
+	s.Put("v")
+	if !s.Retain(h) {
+		t.Fatal("Retain существующего значения вернул false")
+	}
+
+	// Три ссылки: первые два Release значение не удаляют.
+	for i := range 2 {
+		if s.Release(h) {
+			t.Fatalf("Release %d удалил значение, на которое еще ссылаются", i+1)
+		}
+		if _, ok := s.Get(h); !ok {
+			t.Fatalf("значение пропало после Release %d", i+1)
+		}
+	}
+	if !s.Release(h) {
+		t.Error("последний Release должен удалить значение")
+	}
+	if _, ok := s.Get(h); ok || s.Len() != 0 || s.Refs(h) != 0 {
+		t.Errorf("после удаления Get ok = %v, Len = %d, Refs = %d", ok, s.Len(), s.Refs(h))
+	}
+
+	// Лишний Release и Retain удаленного значения ничего не делают.
+	if s.Release(h) || s.Retain(h) {
+		t.Error("Release и Retain отсутствующего значения должны вернуть false")
+	}
+	// После удаления то же содержимое сохраняется заново под тем же хешем.
+	if s.Put("v") != h || s.Refs(h) != 1 {
+		t.Error("повторный Put должен создать запись с одной ссылкой")
+	}
+}
+
+func TestContentStoreCopiesBytes(t *testing.T) {
+	s := NewContentStore[[]byte]()
+	buf := []byte("payload")
+	h := s.Put(buf)
+
+	buf[0] = 'X' // Изменение исходного буфера не затрагивает хранилище.
+	got, _ := s.Get(h)
+	if string(got) != "payload" {
+		t.Fatalf("Get = %q после изменения исходного буфера", got)
+	}
+	got[0] = 'Y' // Как и изменение результата Get.
+	if again, _ := s.Get(h); string(again) != "payload" {
+		t.Errorf("Get = %q после изменения результата прошлого Get", again)
+	}
+}
+
+func TestContentStoreConcurrent(t *testing.T) {
+	s := NewContentStore[string]()
+	const goroutines, perGoroutine = 8, 100
+
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perGoroutine {
+				s.Put(fmt.Sprintf("value %d", i%10))
+			}
+		}()
+	}
+	wg.Wait()
+	if s.Len() != 10 {
+		t.Fatalf("Len = %d, ожидалось 10", s.Len())
+	}
+
+	h := s.Put("value 0")
+	s.Release(h)
+	if got, want := s.Refs(h), goroutines*perGoroutine/10; got != want {
+		t.Errorf("Refs = %d, ожидалось %d", got, want)
+	}
+}
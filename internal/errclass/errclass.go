@@ -0,0 +1,116 @@
+// Package errclass классифицирует ошибки на временные (transient — операцию имеет смысл
+// повторить) и постоянные (permanent — повтор не поможет).
+//
+// Классификация определяется в порядке приоритета:
+//  1. Явная пометка Transient или Permanent — ближайшая к вершине цепочки обертки.
+//  2. Правила реестра (Register, RegisterFunc) — первое подошедшее в порядке регистрации.
+//  3. По умолчанию ошибка считается временной: обычно сбой сети или перегрузка.
+//
+// Ошибки отмены context.Canceled и context.DeadlineExceeded зарегистрированы как
+// постоянные: раз вызывающий отменил операцию или истек ее срок, повторять ее незачем.
+package errclass
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// classified — ошибка с явной пометкой класса.
+type classified struct {
+	err       error
+	transient bool
+}
+
+func (e *classified) Error() string { return e.err.Error() }
+func (e *classified) Unwrap() error { return e.err }
+
+// Transient помечает err как временную. Возвращает nil для nil.
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classified{err: err, transient: true}
+}
+
+// Permanent помечает err как постоянную. Возвращает nil для nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classified{err: err, transient: false}
+}
+
+// rule — правило реестра: если match(err), ошибка относится к классу transient.
+type rule struct {
+	match     func(error) bool
+	transient bool
+}
+
+// Registry — набор правил классификации. Безопасен для конкурентного использования.
+// Обычно достаточно общего реестра через функции пакета; отдельный Registry нужен,
+// например, в тестах.
+type Registry struct {
+	mu    sync.RWMutex
+	rules []rule
+}
+
+// NewRegistry создает реестр с правилами для ошибок отмены контекста.
+func NewRegistry() *Registry {
+	r := &Registry{}
+	r.Register(context.Canceled, false)
+	r.Register(context.DeadlineExceeded, false)
+	return r
+}
+
+// Register классифицирует все ошибки, для которых errors.Is(err, target).
+func (r *Registry) Register(target error, transient bool) {
+	r.RegisterFunc(func(err error) bool { return errors.Is(err, target) }, transient)
+}
+
+// RegisterFunc классифицирует ошибки, для которых match возвращает true.
+// Подходит для типов ошибок: match может проверять errors.As.
+func (r *Registry) RegisterFunc(match func(error) bool, transient bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = append(r.rules, rule{match: match, transient: transient})
+}
+
+// IsTransient сообщает, имеет ли смысл повторить операцию, вернувшую err.
+// Для nil возвращает false.
+func (r *Registry) IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var c *classified
+	if errors.As(err, &c) {
+		return c.transient
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, rule := range r.rules {
+		if rule.match(err) {
+			return rule.transient
+		}
+	}
+	return true
+}
+
+// defaultRegistry — общий реестр, с которым работают функции пакета.
+var defaultRegistry = NewRegistry()
+
+// Register добавляет правило в общий реестр (см. Registry.Register).
+func Register(target error, transient bool) {
+	defaultRegistry.Register(target, transient)
+}
+
+// RegisterFunc добавляет правило в общий реестр (см. Registry.RegisterFunc).
+func RegisterFunc(match func(error) bool, transient bool) {
+	defaultRegistry.RegisterFunc(match, transient)
+}
+
+// IsTransient классифицирует err по общему реестру (см. Registry.IsTransient).
+func IsTransient(err error) bool {
+	return defaultRegistry.IsTransient(err)
+}
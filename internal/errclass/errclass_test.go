@@ -0,0 +1,69 @@
+package errclass
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+var errNotFound = errors.New("not found")
+
+func TestIsTransient(t *testing.T) {
+	r := NewRegistry()
+	r.Register(errNotFound, false)
+	r.RegisterFunc(func(err error) bool {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}, true)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"зарегистрированный sentinel", errNotFound, false},
+		{"обернутый sentinel", fmt.Errorf("query: %w", errNotFound), false},
+		{"помечен временной", Transient(errors.New("connection reset")), true},
+		{"помечен постоянной", Permanent(errors.New("bad request")), false},
+		{"пометка важнее реестра", Transient(errNotFound), true},
+		{"внешняя пометка важнее внутренней", Permanent(fmt.Errorf("wrap: %w", Transient(errors.New("x")))), false},
+		{"отмена контекста", context.Canceled, false},
+		{"истек срок контекста", fmt.Errorf("query: %w", context.DeadlineExceeded), false},
+		{"правило по типу", &net.DNSError{IsTimeout: true}, true},
+		{"неизвестная ошибка", errors.New("something broke"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient(%v) = %t, ожидалось %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaggingPreservesError(t *testing.T) {
+	if Transient(nil) != nil || Permanent(nil) != nil {
+		t.Error("пометка nil должна возвращать nil")
+	}
+	err := Permanent(fmt.Errorf("lookup: %w", errNotFound))
+	if !errors.Is(err, errNotFound) {
+		t.Error("помеченная ошибка не разворачивается до исходной")
+	}
+	if err.Error() != "lookup: not found" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	errGone := errors.New("gone")
+	Register(errGone, false)
+	if IsTransient(errGone) {
+		t.Error("ошибка из общего реестра считается временной")
+	}
+	if IsTransient(context.Canceled) {
+		t.Error("context.Canceled считается временной")
+	}
+}
@@ -0,0 +1,102 @@
+// Package fakehttp содержит фабрику фейковых HTTP-серверов для тестов.
+//
+// Сервер поднимается через net/http/httptest на локальном порту и отвечает на
+// зарегистрированные пути заданным статусом с заданной задержкой. Это позволяет
+// тестировать код, опрашивающий внешние URL (worker_pool, json_config),
+// детерминированно и без доступа к сети.
+package fakehttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Route описывает ответ сервера на один путь.
+type Route struct {
+	Status int           // HTTP-статус ответа (0 — 200 OK).
+	Delay  time.Duration // Задержка перед ответом.
+	Body   string        // Тело ответа.
+}
+
+// Server — фейковый HTTP-сервер. Неизвестные пути получают 404.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	routes map[string]Route
+	hits   map[string]int
+}
+
+// New запускает сервер с маршрутами routes (ключ — путь, например "/slow").
+// Сервер автоматически останавливается по окончании теста.
+func New(t testing.TB, routes map[string]Route) *Server {
+	t.Helper()
+
+	s := &Server{
+		routes: make(map[string]Route, len(routes)),
+		hits:   make(map[string]int),
+	}
+	for path, route := range routes {
+		s.routes[path] = route
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	route, ok := s.routes[r.URL.Path]
+	s.hits[r.URL.Path]++
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if route.Delay > 0 {
+		// Если клиент отвалился по таймауту, не держим обработчик до конца задержки.
+		select {
+		case <-time.After(route.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(route.Body))
+}
+
+// Handle добавляет или заменяет маршрут во время работы сервера.
+func (s *Server) Handle(path string, route Route) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes[path] = route
+}
+
+// URLFor возвращает полный URL пути на этом сервере.
+func (s *Server) URLFor(path string) string {
+	return s.URL + path
+}
+
+// URLs возвращает полные URL для всех переданных путей в том же порядке.
+func (s *Server) URLs(paths ...string) []string {
+	urls := make([]string, len(paths))
+	for i, p := range paths {
+		urls[i] = s.URLFor(p)
+	}
+	return urls
+}
+
+// Hits возвращает количество запросов к пути.
+func (s *Server) Hits(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits[path]
+}
@@ -0,0 +1,70 @@
+package fakehttp
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServerRoutes(t *testing.T) {
+	s := New(t, map[string]Route{
+		"/ok":    {Body: "pong"},
+		"/error": {Status: http.StatusServiceUnavailable},
+	})
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"/ok", http.StatusOK, "pong"},
+		{"/error", http.StatusServiceUnavailable, ""},
+		{"/missing", http.StatusNotFound, "404 page not found\n"},
+	}
+	for _, tt := range tests {
+		resp, err := http.Get(s.URLFor(tt.path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.wantStatus || string(body) != tt.wantBody {
+			t.Errorf("%s: %d %q, ожидалось %d %q", tt.path, resp.StatusCode, body, tt.wantStatus, tt.wantBody)
+		}
+	}
+	if s.Hits("/ok") != 1 || s.Hits("/missing") != 1 {
+		t.Errorf("Hits: /ok = %d, /missing = %d", s.Hits("/ok"), s.Hits("/missing"))
+	}
+}
+
+func TestServerDelayAndClientTimeout(t *testing.T) {
+	s := New(t, map[string]Route{"/slow": {Delay: time.Second}})
+	client := &http.Client{Timeout: 20 * time.Millisecond}
+
+	start := time.Now()
+	if _, err := client.Get(s.URLFor("/slow")); err == nil {
+		t.Fatal("ожидался таймаут клиента")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("запрос занял %v, ожидался таймаут около 20ms", elapsed)
+	}
+}
+
+func TestServerURLsAndHandle(t *testing.T) {
+	s := New(t, nil)
+	urls := s.URLs("/a", "/b")
+	if len(urls) != 2 || urls[0] != s.URL+"/a" || urls[1] != s.URL+"/b" {
+		t.Errorf("URLs = %v", urls)
+	}
+
+	s.Handle("/a", Route{Status: http.StatusTeapot})
+	resp, err := http.Get(urls[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTeapot {
+		t.Errorf("статус %d, ожидался %d", resp.StatusCode, http.StatusTeapot)
+	}
+}
@@ -0,0 +1,96 @@
+// Package lifecycle содержит координатор корректного завершения (graceful shutdown)
+// для примеров, которые запускают фоновые горутины, серверы и подписки.
+//
+// Завершение проходит в две фазы:
+//  1. Сигнал: канал Done закрывается, и компоненты перестают принимать новую работу.
+//  2. Очистка: по очереди выполняются зарегистрированные хуки OnShutdown.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Order задает порядок выполнения хуков.
+type Order int
+
+const (
+	// RegistrationOrder выполняет хуки в порядке регистрации.
+	RegistrationOrder Order = iota
+	// ReverseOrder выполняет хуки в обратном порядке, как defer: компонент,
+	// запущенный последним (и зависящий от предыдущих), останавливается первым.
+	ReverseOrder
+)
+
+// Hook — функция очистки. ctx ограничивает время на завершение.
+type Hook func(ctx context.Context) error
+
+// ShutdownGroup собирает хуки завершения компонентов и выполняет их в Shutdown.
+type ShutdownGroup struct {
+	order Order
+
+	mu    sync.Mutex
+	hooks []Hook
+	done  chan struct{}
+
+	once sync.Once
+	err  error
+}
+
+// NewShutdownGroup создает группу, выполняющую хуки в порядке order.
+func NewShutdownGroup(order Order) *ShutdownGroup {
+	return &ShutdownGroup{
+		order: order,
+		done:  make(chan struct{}),
+	}
+}
+
+// OnShutdown регистрирует хук. Хуки, зарегистрированные после начала Shutdown, не выполняются.
+func (g *ShutdownGroup) OnShutdown(hook Hook) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.hooks = append(g.hooks, hook)
+}
+
+// Done возвращает канал, который закрывается в начале Shutdown (первая фаза).
+// Фоновые горутины могут слушать его, чтобы прекратить прием новой работы.
+func (g *ShutdownGroup) Done() <-chan struct{} {
+	return g.done
+}
+
+// Shutdown закрывает Done и последовательно выполняет все хуки (вторая фаза).
+//
+// Ошибка одного хука не останавливает остальные: все ошибки объединяются через
+// errors.Join, каждая — с номером хука в порядке регистрации. Хуки выполняются и
+// при истекшем ctx — решать, можно ли завершиться быстрее, должен сам хук.
+//
+// Повторные вызовы ничего не выполняют и возвращают результат первого.
+func (g *ShutdownGroup) Shutdown(ctx context.Context) error {
+	g.once.Do(func() {
+		g.mu.Lock()
+		close(g.done)
+		hooks := slices.Clone(g.hooks)
+		g.hooks = nil
+		g.mu.Unlock()
+
+		indices := make([]int, len(hooks))
+		for i := range indices {
+			indices[i] = i
+		}
+		if g.order == ReverseOrder {
+			slices.Reverse(indices)
+		}
+
+		var errs []error
+		for _, i := range indices {
+			if err := hooks[i](ctx); err != nil {
+				errs = append(errs, fmt.Errorf("хук завершения #%d: %w", i, err))
+			}
+		}
+		g.err = errors.Join(errs...)
+	})
+	return g.err
+}
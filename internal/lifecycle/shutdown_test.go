@@ -0,0 +1,106 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// recordHook возвращает хук, который дописывает name в order и возвращает err.
+func recordHook(order *[]string, name string, err error) Hook {
+	return func(context.Context) error {
+		*order = append(*order, name)
+		return err
+	}
+}
+
+func TestShutdownRunsHooksInOrder(t *testing.T) {
+	tests := []struct {
+		order Order
+		want  []string
+	}{
+		{RegistrationOrder, []string{"db", "cache", "server"}},
+		{ReverseOrder, []string{"server", "cache", "db"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		g := NewShutdownGroup(tt.order)
+		g.OnShutdown(recordHook(&got, "db", nil))
+		g.OnShutdown(recordHook(&got, "cache", nil))
+		g.OnShutdown(recordHook(&got, "server", nil))
+
+		if err := g.Shutdown(context.Background()); err != nil {
+			t.Fatalf("порядок %d: неожиданная ошибка %v", tt.order, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("порядок %d: хуки выполнены как %v, ожидалось %v", tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestShutdownReportsErrorsWithoutSkipping(t *testing.T) {
+	errCache := errors.New("cache flush failed")
+	errServer := errors.New("server busy")
+
+	var got []string
+	g := NewShutdownGroup(RegistrationOrder)
+	g.OnShutdown(recordHook(&got, "db", nil))
+	g.OnShutdown(recordHook(&got, "cache", errCache))
+	g.OnShutdown(recordHook(&got, "server", errServer))
+	g.OnShutdown(recordHook(&got, "logger", nil))
+
+	err := g.Shutdown(context.Background())
+	if !errors.Is(err, errCache) || !errors.Is(err, errServer) {
+		t.Errorf("err = %v, ожидались обе ошибки хуков", err)
+	}
+	if want := []string{"db", "cache", "server", "logger"}; !slices.Equal(got, want) {
+		t.Errorf("выполнены %v, ожидалось %v — ошибка не должна прерывать остальные хуки", got, want)
+	}
+}
+
+func TestShutdownClosesDoneBeforeHooks(t *testing.T) {
+	g := NewShutdownGroup(RegistrationOrder)
+	g.OnShutdown(func(context.Context) error {
+		select {
+		case <-g.Done():
+			return nil
+		default:
+			return errors.New("Done не закрыт к моменту выполнения хуков")
+		}
+	})
+
+	select {
+	case <-g.Done():
+		t.Fatal("Done закрыт до Shutdown")
+	default:
+	}
+	if err := g.Shutdown(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShutdownPassesContextAndIsIdempotent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	calls := 0
+	g := NewShutdownGroup(RegistrationOrder)
+	g.OnShutdown(func(hookCtx context.Context) error {
+		calls++
+		if _, ok := hookCtx.Deadline(); !ok {
+			t.Error("хук получил контекст без дедлайна")
+		}
+		return errors.New("boom")
+	})
+
+	first := g.Shutdown(ctx)
+	second := g.Shutdown(ctx)
+	if calls != 1 {
+		t.Errorf("хук выполнен %d раз, ожидался 1", calls)
+	}
+	if first == nil || first != second {
+		t.Errorf("повторный Shutdown вернул %v, первый — %v", second, first)
+	}
+}
@@ -0,0 +1,96 @@
+// Package periodic запускает периодическую фоновую работу (перечитывание конфига,
+// очистку кэша) с возможностью ее остановить.
+package periodic
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Scheduler запускает функции по таймеру, каждую в своей горутине, пока задачу не
+// остановят или не отменят родительский контекст планировщика.
+//
+// Вызовы одной задачи не пересекаются: следующий начинается не раньше, чем закончится
+// предыдущий. Если вызов длиннее периода, пропущенные тики не накапливаются — после него
+// задача ждет не дольше одного периода (так ведет себя time.Ticker).
+// Безопасен для конкурентного использования.
+type Scheduler struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// mu упорядочивает запуск задач в Every и Close: после Close новых горутин нет,
+	// и wg.Wait не гоняется с wg.Add.
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewScheduler создает планировщик, все задачи которого останавливаются при отмене ctx.
+func NewScheduler(ctx context.Context) *Scheduler {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Scheduler{ctx: ctx, cancel: cancel}
+}
+
+// Every запускает fn каждые d, начиная через d после вызова. fn получает контекст,
+// который отменяется при остановке задачи, так что долгий вызов может прерваться.
+//
+// Возвращенная stop останавливает задачу и дожидается завершения текущего вызова fn;
+// после ее возврата fn больше не вызывается. Повторный вызов stop ничего не делает.
+// stop нельзя вызывать из самой fn: она будет ждать сама себя.
+// Паникует, если d не положительна.
+func (s *Scheduler) Every(d time.Duration, fn func(ctx context.Context)) (stop func()) {
+	if d <= 0 {
+		panic("periodic: период должен быть положительным")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	done := make(chan struct{})
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(done)
+
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				// Тик и отмена могли наступить одновременно: отмена важнее.
+				if ctx.Err() != nil {
+					return
+				}
+				fn(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// Done возвращает канал, который закрывается при Close или отмене родительского контекста.
+func (s *Scheduler) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// Close останавливает все задачи и дожидается завершения их текущих вызовов.
+// После Close Every не запускает новых задач. Повторный вызов ничего не делает.
+func (s *Scheduler) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	s.cancel()
+	s.wg.Wait()
+}
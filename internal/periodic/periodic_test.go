@@ -0,0 +1,132 @@
+package periodic
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEveryRunsExpectedNumberOfTimes(t *testing.T) {
+	s := NewScheduler(context.Background())
+	defer s.Close()
+
+	var calls atomic.Int32
+	stop := s.Every(10*time.Millisecond, func(context.Context) { calls.Add(1) })
+	time.Sleep(105 * time.Millisecond)
+	stop()
+
+	// За 105ms при периоде 10ms — около 10 вызовов; допуск на неточность таймеров.
+	if n := calls.Load(); n < 6 || n > 11 {
+		t.Errorf("вызовов %d, ожидалось около 10", n)
+	}
+}
+
+func TestEveryStopsPromptly(t *testing.T) {
+	s := NewScheduler(context.Background())
+	defer s.Close()
+
+	var calls atomic.Int32
+	stop := s.Every(time.Millisecond, func(context.Context) { calls.Add(1) })
+	time.Sleep(20 * time.Millisecond)
+
+	start := time.Now()
+	stop()
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("stop занял %v", elapsed)
+	}
+	after := calls.Load()
+	time.Sleep(20 * time.Millisecond)
+	if n := calls.Load(); n != after {
+		t.Errorf("после stop было еще %d вызовов", n-after)
+	}
+	stop() // Повторный вызов безопасен.
+}
+
+func TestEveryDoesNotOverlap(t *testing.T) {
+	s := NewScheduler(context.Background())
+	defer s.Close()
+
+	var running, overlaps, calls atomic.Int32
+	stop := s.Every(time.Millisecond, func(context.Context) {
+		if running.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		time.Sleep(5 * time.Millisecond) // Вызов дольше периода.
+		calls.Add(1)
+		running.Add(-1)
+	})
+	time.Sleep(60 * time.Millisecond)
+	stop()
+
+	if overlaps.Load() != 0 {
+		t.Errorf("вызовы пересекались %d раз", overlaps.Load())
+	}
+	// Пропущенные тики не накапливаются: вызовов не больше, чем помещается подряд.
+	if n := calls.Load(); n < 3 || n > 12 {
+		t.Errorf("вызовов %d, ожидалось от 3 до 12", n)
+	}
+}
+
+func TestStopCancelsRunningCall(t *testing.T) {
+	s := NewScheduler(context.Background())
+	defer s.Close()
+
+	started := make(chan struct{})
+	var once atomic.Bool
+	stop := s.Every(time.Millisecond, func(ctx context.Context) {
+		if once.CompareAndSwap(false, true) {
+			close(started)
+		}
+		<-ctx.Done() // Долгий вызов, прерываемый только отменой.
+	})
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stop не прервал текущий вызов")
+	}
+}
+
+func TestParentContextCancelStopsAll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewScheduler(ctx)
+
+	var a, b atomic.Int32
+	s.Every(time.Millisecond, func(context.Context) { a.Add(1) })
+	s.Every(time.Millisecond, func(context.Context) { b.Add(1) })
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	s.Close() // Дожидается, пока обе задачи увидят отмену.
+
+	na, nb := a.Load(), b.Load()
+	if na == 0 || nb == 0 {
+		t.Fatalf("задачи не запускались: %d и %d вызовов", na, nb)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if a.Load() != na || b.Load() != nb {
+		t.Error("задачи продолжили работу после отмены родительского контекста")
+	}
+
+	// Задача, запущенная после Close, не выполняется.
+	var late atomic.Int32
+	s.Every(time.Millisecond, func(context.Context) { late.Add(1) })()
+	if late.Load() != 0 {
+		t.Error("задача после Close была вызвана")
+	}
+}
+
+func TestEveryPanicsOnNonPositivePeriod(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ожидалась паника при нулевом периоде")
+		}
+	}()
+	NewScheduler(context.Background()).Every(0, func(context.Context) {})
+}
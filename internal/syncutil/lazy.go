@@ -0,0 +1,53 @@
+package syncutil
+
+import "sync"
+
+// Lazy — значение, вычисляемое при первом обращении. Безопасен для конкурентного
+// использования: инициализатор выполняется ровно один раз, а все конкурентные
+// вызовы Get дожидаются его завершения.
+//
+// Если инициализатор паникует, паника передается вызвавшему Get, а значение
+// считается вычисленным: последующие Get вернут нулевое значение T.
+type Lazy[T any] struct {
+	once  sync.Once
+	init  func() T
+	value T
+}
+
+// NewLazy создает значение, которое будет вычислено функцией init при первом Get.
+func NewLazy[T any](init func() T) *Lazy[T] {
+	return &Lazy[T]{init: init}
+}
+
+// Get возвращает значение, вычисляя его при первом вызове.
+func (l *Lazy[T]) Get() T {
+	l.once.Do(func() {
+		l.value = l.init()
+		l.init = nil // Отпускаем замыкание и все, что оно удерживает.
+	})
+	return l.value
+}
+
+// LazyErr — вариант Lazy для инициализатора, который может завершиться ошибкой
+// (подключение к бэкенду, загрузка файла). Ошибка кэшируется вместе со значением:
+// повторной попытки не будет, каждый Get вернет ту же пару (T, error).
+type LazyErr[T any] struct {
+	once  sync.Once
+	init  func() (T, error)
+	value T
+	err   error
+}
+
+// NewLazyErr создает значение, которое будет вычислено функцией init при первом Get.
+func NewLazyErr[T any](init func() (T, error)) *LazyErr[T] {
+	return &LazyErr[T]{init: init}
+}
+
+// Get возвращает значение и ошибку инициализатора, вычисляя их при первом вызове.
+func (l *LazyErr[T]) Get() (T, error) {
+	l.once.Do(func() {
+		l.value, l.err = l.init()
+		l.init = nil
+	})
+	return l.value, l.err
+}
@@ -0,0 +1,71 @@
+package syncutil
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// getConcurrently вызывает get из n горутин одновременно.
+func getConcurrently(n int, get func()) {
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	wg.Add(n)
+	for range n {
+		go func() {
+			defer wg.Done()
+			<-start
+			get()
+		}()
+	}
+	close(start)
+	wg.Wait()
+}
+
+func TestLazyInitializesOnce(t *testing.T) {
+	var calls atomic.Int32
+	lazy := NewLazy(func() []int {
+		calls.Add(1)
+		return []int{1, 2, 3}
+	})
+	if calls.Load() != 0 {
+		t.Fatal("инициализатор вызван до первого Get")
+	}
+
+	getConcurrently(100, func() {
+		if got := lazy.Get(); len(got) != 3 {
+			t.Errorf("Get() = %v", got)
+		}
+	})
+	if n := calls.Load(); n != 1 {
+		t.Errorf("инициализатор вызван %d раз, ожидался 1", n)
+	}
+}
+
+func TestLazyErrCachesError(t *testing.T) {
+	errConnect := errors.New("connection refused")
+	var calls atomic.Int32
+	lazy := NewLazyErr(func() (string, error) {
+		calls.Add(1)
+		return "", errConnect
+	})
+
+	getConcurrently(100, func() {
+		if _, err := lazy.Get(); !errors.Is(err, errConnect) {
+			t.Errorf("Get() err = %v, ожидалось %v", err, errConnect)
+		}
+	})
+	if n := calls.Load(); n != 1 {
+		t.Errorf("инициализатор вызван %d раз, ожидался 1", n)
+	}
+}
+
+func TestLazyErrValue(t *testing.T) {
+	lazy := NewLazyErr(func() (int, error) { return 42, nil })
+	for range 2 {
+		if v, err := lazy.Get(); v != 42 || err != nil {
+			t.Errorf("Get() = (%d, %v), ожидалось (42, nil)", v, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package syncutil
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Nursery — область структурной конкурентности поверх errgroup: владеет дочерними
+// горутинами и гарантирует, что ни одна из них не переживет Wait.
+//
+// Все дочерние горутины получают общий контекст, который отменяется при первой ошибке
+// любой из них, при отмене родительского контекста и при возврате Wait. Так одна
+// неудачная подзадача останавливает остальные, а выход из области — все сразу.
+//
+// Go можно вызывать конкурентно, в том числе из дочерних горутин. После возврата Wait
+// область закрыта: Go паникует, чтобы горутина не пережила свою область.
+type Nursery struct {
+	group *errgroup.Group
+	ctx   context.Context
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewNursery открывает область, дочерние горутины которой отменяются вместе с parent.
+func NewNursery(parent context.Context) *Nursery {
+	group, ctx := errgroup.WithContext(parent)
+	return &Nursery{group: group, ctx: ctx}
+}
+
+// Go запускает fn в дочерней горутине. fn должна вернуться вскоре после отмены ctx,
+// иначе Wait будет ее ждать. Ненулевая ошибка fn отменяет остальные дочерние горутины.
+// Паникует, если Wait уже вернулся.
+func (n *Nursery) Go(fn func(ctx context.Context) error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		panic("syncutil: Go после Wait")
+	}
+	n.group.Go(func() error { return fn(n.ctx) })
+}
+
+// Wait дожидается завершения всех дочерних горутин и закрывает область.
+// Возвращает первую ненулевую ошибку дочерней горутины или nil.
+func (n *Nursery) Wait() error {
+	err := n.group.Wait()
+	n.mu.Lock()
+	n.closed = true
+	n.mu.Unlock()
+	return err
+}
+
+// WithNursery выполняет body в новой области и дожидается всех запущенных в ней горутин.
+// Ошибка body, как и ошибка дочерней горутины, отменяет остальных; возвращается первая
+// из ошибок body и дочерних горутин. При возврате WithNursery дочерних горутин не остается.
+func WithNursery(parent context.Context, body func(n *Nursery) error) error {
+	n := NewNursery(parent)
+	n.Go(func(context.Context) error { return body(n) })
+	return n.Wait()
+}
@@ -0,0 +1,133 @@
+package syncutil
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNurseryCancelsChildrenOnFirstError(t *testing.T) {
+	errBoom := errors.New("boom")
+	n := NewNursery(context.Background())
+
+	const children = 5
+	var cancelled atomic.Int32
+	started := make(chan struct{}, children)
+	for range children {
+		n.Go(func(ctx context.Context) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			cancelled.Add(1)
+			return ctx.Err()
+		})
+	}
+	for range children {
+		<-started
+	}
+	n.Go(func(context.Context) error { return errBoom })
+
+	if err := n.Wait(); !errors.Is(err, errBoom) {
+		t.Errorf("Wait = %v, ожидалось %v", err, errBoom)
+	}
+	if got := cancelled.Load(); got != children {
+		t.Errorf("отменено %d горутин, ожидалось %d", got, children)
+	}
+}
+
+func TestNurseryWaitBlocksUntilChildrenStop(t *testing.T) {
+	n := NewNursery(context.Background())
+
+	var stopped atomic.Bool
+	n.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond) // Медленная очистка после отмены.
+		stopped.Store(true)
+		return nil
+	})
+	n.Go(func(context.Context) error { return errors.New("fail") })
+
+	_ = n.Wait()
+	if !stopped.Load() {
+		t.Error("Wait вернулся раньше, чем завершилась дочерняя горутина")
+	}
+}
+
+func TestNurseryParentCancelStopsChildren(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	n := NewNursery(ctx)
+
+	n.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- n.Wait() }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Wait = %v, ожидалось %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("отмена родительского контекста не остановила дочерние горутины")
+	}
+}
+
+func TestNurseryGoFromChild(t *testing.T) {
+	n := NewNursery(context.Background())
+
+	var ran atomic.Int32
+	n.Go(func(context.Context) error {
+		ran.Add(1)
+		n.Go(func(context.Context) error {
+			ran.Add(1)
+			return nil
+		})
+		return nil
+	})
+	if err := n.Wait(); err != nil {
+		t.Fatalf("Wait = %v", err)
+	}
+	if got := ran.Load(); got != 2 {
+		t.Errorf("выполнено %d горутин, ожидалось 2", got)
+	}
+}
+
+func TestNurseryGoAfterWaitPanics(t *testing.T) {
+	n := NewNursery(context.Background())
+	_ = n.Wait()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("ожидалась паника при Go после Wait")
+		}
+	}()
+	n.Go(func(context.Context) error { return nil })
+}
+
+func TestWithNurseryBodyErrorCancelsChildren(t *testing.T) {
+	errBody := errors.New("body failed")
+	var cancelled atomic.Bool
+
+	err := WithNursery(context.Background(), func(n *Nursery) error {
+		started := make(chan struct{})
+		n.Go(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			cancelled.Store(true)
+			return nil
+		})
+		<-started
+		return errBody
+	})
+
+	if !errors.Is(err, errBody) {
+		t.Errorf("WithNursery = %v, ожидалось %v", err, errBody)
+	}
+	if !cancelled.Load() {
+		t.Error("дочерняя горутина не была отменена и пережила область")
+	}
+}
@@ -0,0 +1,49 @@
+// Package syncutil дополняет пакет sync helper-ами, которые нужны нескольким примерам:
+// ожиданием WaitGroup с таймаутом, ленивой инициализацией значений и областями
+// структурной конкурентности (Nursery).
+package syncutil
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WaitTimeout ждет wg не дольше d. Возвращает true, если группа завершилась вовремя,
+// и false по таймауту.
+//
+// sync.WaitGroup нельзя ждать с отменой, поэтому Wait вызывается в отдельной горутине.
+// По таймауту она не прерывается и завершится вместе с группой: если группа не
+// завершится никогда, горутина утечет.
+func WaitTimeout(wg *sync.WaitGroup, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-waitDone(wg):
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// WaitContext ждет wg до отмены ctx. Возвращает nil, если группа завершилась,
+// иначе ctx.Err(). Об утечке ожидающей горутины см. WaitTimeout.
+func WaitContext(ctx context.Context, wg *sync.WaitGroup) error {
+	select {
+	case <-waitDone(wg):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// waitDone возвращает канал, который закрывается после завершения wg.
+func waitDone(wg *sync.WaitGroup) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return done
+}
@@ -0,0 +1,67 @@
+package syncutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// startGroup возвращает группу из одной горутины, которая завершается после закрытия release.
+func startGroup(release <-chan struct{}) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-release
+	}()
+	return &wg
+}
+
+func TestWaitTimeoutCompletes(t *testing.T) {
+	release := make(chan struct{})
+	wg := startGroup(release)
+	close(release)
+
+	if !WaitTimeout(wg, time.Second) {
+		t.Error("WaitTimeout = false для завершившейся группы")
+	}
+}
+
+func TestWaitTimeoutExpires(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release) // Отпускаем горутину группы и ожидающую горутину.
+	wg := startGroup(release)
+
+	const d = 20 * time.Millisecond
+	start := time.Now()
+	if WaitTimeout(wg, d) {
+		t.Fatal("WaitTimeout = true для незавершенной группы")
+	}
+	if elapsed := time.Since(start); elapsed < d || elapsed > d+time.Second {
+		t.Errorf("WaitTimeout вернулся через %v, ожидалось около %v", elapsed, d)
+	}
+}
+
+func TestWaitContextCompletes(t *testing.T) {
+	release := make(chan struct{})
+	wg := startGroup(release)
+	close(release)
+
+	if err := WaitContext(context.Background(), wg); err != nil {
+		t.Errorf("WaitContext = %v, ожидался nil", err)
+	}
+}
+
+func TestWaitContextCanceled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	wg := startGroup(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := WaitContext(ctx, wg); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitContext = %v, ожидалось %v", err, context.DeadlineExceeded)
+	}
+}
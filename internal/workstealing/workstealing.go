@@ -0,0 +1,193 @@
+// Package workstealing реализует планировщик задач с кражей работы (work stealing)
+// для CPU-нагруженной обработки в конвейерах.
+package workstealing
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrClosed возвращается при попытке отправить задачу в закрытый планировщик.
+var ErrClosed = errors.New("workstealing: планировщик закрыт")
+
+// Scheduler раздает задачи воркерам через личные очереди (деки) вместо одного общего канала.
+//
+// При общем канале все воркеры и отправители конкурируют за одну блокировку канала,
+// что заметно на большом числе коротких CPU-задач. Здесь Submit кладет задачу в дек
+// очередного воркера по кругу, и владелец забирает задачи со своего конца дека, почти
+// не пересекаясь с остальными. Воркер, у которого задачи кончились, крадет их с другого
+// конца чужого дека, поэтому неравномерная нагрузка (несколько долгих задач у одного
+// воркера) выравнивается без участия отправителя.
+//
+// Каждая задача выполняется ровно один раз. Безопасен для конкурентного использования.
+type Scheduler[T any] struct {
+	handle func(T)
+	deques []*deque[T]
+	next   atomic.Uint64 // Счетчик для раздачи задач по декам по кругу.
+
+	pending atomic.Int64 // Задачи в деках, еще не взятые воркерами.
+	steals  atomic.Uint64
+
+	// closeMu не дает Close проскочить между проверкой closed и постановкой задачи в Submit:
+	// иначе воркеры могли бы завершиться, не увидев последнюю задачу.
+	closeMu sync.RWMutex
+	closed  atomic.Bool
+
+	// mu и cond усыпляют воркеров, когда задач нет ни в одном деке.
+	mu       sync.Mutex
+	cond     *sync.Cond
+	sleeping atomic.Int32 // Воркеры, готовящиеся уснуть или спящие на cond.
+
+	wg sync.WaitGroup
+}
+
+// New запускает workers воркеров, обрабатывающих задачи функцией handle.
+// Паникует, если workers меньше 1.
+func New[T any](workers int, handle func(T)) *Scheduler[T] {
+	if workers < 1 {
+		panic("workstealing.New: нужен хотя бы один воркер")
+	}
+	s := &Scheduler[T]{
+		handle: handle,
+		deques: make([]*deque[T], workers),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	for i := range s.deques {
+		s.deques[i] = &deque[T]{}
+	}
+
+	s.wg.Add(workers)
+	for i := range workers {
+		go s.worker(i)
+	}
+	return s
+}
+
+// Submit ставит задачу в дек очередного воркера. После Close возвращает ErrClosed.
+func (s *Scheduler[T]) Submit(task T) error {
+	s.closeMu.RLock()
+	if s.closed.Load() {
+		s.closeMu.RUnlock()
+		return ErrClosed
+	}
+	i := (s.next.Add(1) - 1) % uint64(len(s.deques))
+	s.deques[i].pushBack(task)
+	s.pending.Add(1)
+	s.closeMu.RUnlock()
+
+	// Будим одного спящего воркера; пока все заняты, s.mu не трогаем вовсе.
+	// Воркер увеличивает sleeping до проверки pending, а мы читаем sleeping после
+	// увеличения pending, поэтому хотя бы одна сторона увидит другую и сигнал не потеряется.
+	if s.sleeping.Load() > 0 {
+		s.mu.Lock()
+		s.cond.Signal()
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// Close прекращает прием задач, дожидается выполнения уже поставленных и завершения воркеров.
+// Каждая задача, для которой Submit вернул nil, будет выполнена.
+func (s *Scheduler[T]) Close() {
+	s.closeMu.Lock()
+	s.closed.Store(true)
+	s.closeMu.Unlock()
+
+	s.mu.Lock()
+	s.cond.Broadcast()
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// Steals возвращает количество задач, выполненных не тем воркером, в чей дек они попали.
+func (s *Scheduler[T]) Steals() uint64 {
+	return s.steals.Load()
+}
+
+func (s *Scheduler[T]) worker(id int) {
+	defer s.wg.Done()
+
+	for {
+		if task, ok := s.take(id); ok {
+			s.handle(task)
+			continue
+		}
+
+		s.mu.Lock()
+		s.sleeping.Add(1)
+		for s.pending.Load() == 0 && !s.closed.Load() {
+			s.cond.Wait()
+		}
+		s.sleeping.Add(-1)
+		done := s.pending.Load() == 0 && s.closed.Load()
+		s.mu.Unlock()
+		if done {
+			return
+		}
+	}
+}
+
+// take берет задачу из своего дека, а если он пуст — крадет из чужих,
+// начиная с соседа, чтобы воркеры не нападали на один и тот же дек.
+func (s *Scheduler[T]) take(id int) (T, bool) {
+	if task, ok := s.deques[id].popBack(); ok {
+		s.pending.Add(-1)
+		return task, true
+	}
+	for i := 1; i < len(s.deques); i++ {
+		victim := s.deques[(id+i)%len(s.deques)]
+		if task, ok := victim.popFront(); ok {
+			s.pending.Add(-1)
+			s.steals.Add(1)
+			return task, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// deque — двусторонняя очередь задач одного воркера. Владелец работает с хвостом
+// (свежие задачи, данные которых еще в кэше процессора), воры — с головой.
+type deque[T any] struct {
+	mu    sync.Mutex
+	tasks []T
+}
+
+func (d *deque[T]) pushBack(task T) {
+	d.mu.Lock()
+	d.tasks = append(d.tasks, task)
+	d.mu.Unlock()
+}
+
+func (d *deque[T]) popBack() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var zero T
+	n := len(d.tasks)
+	if n == 0 {
+		return zero, false
+	}
+	task := d.tasks[n-1]
+	d.tasks[n-1] = zero // Не держим ссылку на задачу в хвосте среза.
+	d.tasks = d.tasks[:n-1]
+	return task, true
+}
+
+func (d *deque[T]) popFront() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var zero T
+	if len(d.tasks) == 0 {
+		return zero, false
+	}
+	task := d.tasks[0]
+	d.tasks[0] = zero
+	d.tasks = d.tasks[1:]
+	if len(d.tasks) == 0 {
+		d.tasks = nil // Освобождаем массив, чтобы срез не "уползал" по памяти.
+	}
+	return task, true
+}
@@ -0,0 +1,157 @@
+package workstealing
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSchedulerRunsEachTaskExactlyOnce(t *testing.T) {
+	const tasks = 20000
+	counts := make([]atomic.Int32, tasks)
+	s := New(8, func(i int) { counts[i].Add(1) })
+
+	// Несколько отправителей одновременно.
+	var wg sync.WaitGroup
+	const senders = 4
+	wg.Add(senders)
+	for g := range senders {
+		go func() {
+			defer wg.Done()
+			for i := g; i < tasks; i += senders {
+				if err := s.Submit(i); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	s.Close()
+
+	for i := range counts {
+		if n := counts[i].Load(); n != 1 {
+			t.Fatalf("задача %d выполнена %d раз, ожидался 1", i, n)
+		}
+	}
+}
+
+func TestSchedulerStealsFromBusyWorker(t *testing.T) {
+	const workers = 4
+	release := make(chan struct{})
+	var done atomic.Int32
+	s := New(workers, func(blocking bool) {
+		if blocking {
+			<-release
+		}
+		done.Add(1)
+	})
+
+	// Первая задача занимает воркера, в чей дек попадет каждая workers-я задача.
+	// Остальные задачи из его дека должны выполнить другие воркеры.
+	if err := s.Submit(true); err != nil {
+		t.Fatal(err)
+	}
+	for range 10 * workers {
+		if err := s.Submit(false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for done.Load() < 10*workers {
+		if time.Now().After(deadline) {
+			t.Fatalf("выполнено %d из %d задач, пока один воркер заблокирован", done.Load(), 10*workers)
+		}
+		runtime.Gosched()
+	}
+	close(release)
+	s.Close()
+
+	if s.Steals() == 0 {
+		t.Error("Steals() = 0, ожидалась кража задач у заблокированного воркера")
+	}
+}
+
+func TestSchedulerSubmitAfterClose(t *testing.T) {
+	s := New(2, func(int) {})
+	s.Close()
+	if err := s.Submit(1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Submit после Close = %v, ожидалась ErrClosed", err)
+	}
+}
+
+func TestSchedulerCloseWithIdleWorkers(t *testing.T) {
+	s := New(4, func(int) {})
+	time.Sleep(10 * time.Millisecond) // Воркеры успевают уснуть.
+	closed := make(chan struct{})
+	go func() {
+		s.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close не разбудил простаивающих воркеров")
+	}
+}
+
+// spin — CPU-нагрузка без аллокаций: n итераций xorshift.
+func spin(n int) uint64 {
+	x := uint64(n) | 1
+	for range n {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+	}
+	return x
+}
+
+// imbalancedCost задает неравномерную нагрузку: каждая восьмая задача в 100 раз тяжелее.
+func imbalancedCost(i int) int {
+	if i%8 == 0 {
+		return 20000
+	}
+	return 200
+}
+
+var sink atomic.Uint64
+
+func BenchmarkImbalanced(b *testing.B) {
+	const tasks = 4096
+	workers := runtime.GOMAXPROCS(0)
+
+	b.Run("shared-channel", func(b *testing.B) {
+		for range b.N {
+			jobs := make(chan int, workers)
+			var wg sync.WaitGroup
+			wg.Add(workers)
+			for range workers {
+				go func() {
+					defer wg.Done()
+					for i := range jobs {
+						sink.Add(spin(imbalancedCost(i)))
+					}
+				}()
+			}
+			for i := range tasks {
+				jobs <- i
+			}
+			close(jobs)
+			wg.Wait()
+		}
+	})
+
+	b.Run("work-stealing", func(b *testing.B) {
+		for range b.N {
+			s := New(workers, func(i int) { sink.Add(spin(imbalancedCost(i))) })
+			for i := range tasks {
+				_ = s.Submit(i)
+			}
+			s.Close()
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package main
+
+// ContainsFunc — версия `contains` для любых типов, в том числе несравнимых
+// (срезы, карты, структуры с такими полями). Вместо оператора `==`, который
+// доступен только для `comparable`, равенство задает функция eq.
+//
+// Аналог slices.ContainsFunc, но с явной парой "элемент-цель", как у `contains`.
+func ContainsFunc[T any](s []T, target T, eq func(a, b T) bool) bool {
+	for _, el := range s {
+		if eq(el, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// EqSet — множество с пользовательским равенством.
+//
+// Обычное множество map[T]struct{} требует `comparable` ключей и хеширования.
+// Для произвольного eq хеша нет, поэтому элементы хранятся в срезе, а проверки
+// выполняются линейным поиском: O(n) на операцию. Подходит для небольших наборов.
+type EqSet[T any] struct {
+	items []T
+	eq    func(a, b T) bool
+}
+
+// NewEqSet создает пустое множество с функцией равенства eq.
+func NewEqSet[T any](eq func(a, b T) bool) *EqSet[T] {
+	return &EqSet[T]{eq: eq}
+}
+
+// Add добавляет элемент, если равного ему еще нет. Возвращает true, если элемент добавлен.
+func (s *EqSet[T]) Add(v T) bool {
+	if s.Contains(v) {
+		return false
+	}
+	s.items = append(s.items, v)
+	return true
+}
+
+// Contains сообщает, есть ли в множестве элемент, равный v.
+func (s *EqSet[T]) Contains(v T) bool {
+	return ContainsFunc(s.items, v, s.eq)
+}
+
+// Len возвращает количество элементов.
+func (s *EqSet[T]) Len() int {
+	return len(s.items)
+}
+
+// Items возвращает элементы в порядке добавления. Срез — копия, его можно изменять.
+func (s *EqSet[T]) Items() []T {
+	return append([]T(nil), s.items...)
+}
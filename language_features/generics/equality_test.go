@@ -0,0 +1,81 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// tagged — структура со срезом: не comparable, поэтому contains к ней неприменим.
+type tagged struct {
+	Name string
+	Tags []string
+}
+
+func deepEqual[T any](a, b T) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func TestContainsFuncDeepEqual(t *testing.T) {
+	items := []tagged{
+		{Name: "a", Tags: []string{"x", "y"}},
+		{Name: "b", Tags: nil},
+	}
+
+	tests := []struct {
+		name   string
+		target tagged
+		want   bool
+	}{
+		{"равный по содержимому", tagged{Name: "a", Tags: []string{"x", "y"}}, true},
+		{"другой порядок тегов", tagged{Name: "a", Tags: []string{"y", "x"}}, false},
+		{"nil-срез", tagged{Name: "b"}, true},
+		// reflect.DeepEqual различает nil и пустой срез.
+		{"пустой срез вместо nil", tagged{Name: "b", Tags: []string{}}, false},
+		{"отсутствует", tagged{Name: "c"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContainsFunc(items, tt.target, deepEqual[tagged]); got != tt.want {
+				t.Errorf("ContainsFunc(%+v) = %t, ожидалось %t", tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainsFuncCustomEquality(t *testing.T) {
+	words := []string{"Go", "Rust"}
+	if !ContainsFunc(words, "go", strings.EqualFold) {
+		t.Error("ожидалось совпадение без учета регистра")
+	}
+	if ContainsFunc(nil, "go", strings.EqualFold) {
+		t.Error("пустой срез ничего не содержит")
+	}
+}
+
+func TestEqSet(t *testing.T) {
+	set := NewEqSet(deepEqual[tagged])
+
+	if !set.Add(tagged{Name: "a", Tags: []string{"x"}}) {
+		t.Fatal("первый элемент должен добавиться")
+	}
+	if set.Add(tagged{Name: "a", Tags: []string{"x"}}) {
+		t.Error("равный по DeepEqual элемент не должен добавиться повторно")
+	}
+	if !set.Add(tagged{Name: "a", Tags: []string{"x", "z"}}) {
+		t.Error("элемент с другими тегами должен добавиться")
+	}
+
+	if set.Len() != 2 {
+		t.Errorf("Len = %d, ожидалось 2", set.Len())
+	}
+	if !set.Contains(tagged{Name: "a", Tags: []string{"x", "z"}}) {
+		t.Error("Contains не нашел добавленный элемент")
+	}
+
+	items := set.Items()
+	items[0].Name = "изменено"
+	if set.Items()[0].Name != "a" {
+		t.Error("Items должен возвращать копию")
+	}
+}
@@ -5,6 +5,7 @@ package main
 
 import (
 	"fmt"
+	"reflect"
 )
 
 // Number — это интерфейс, который используется как "ограничение" (constraint) для дженериков.
@@ -131,10 +132,105 @@ func demoTypeApproximation() {
 	fmt.Println("Сумма `[]CustomInt` напрямую:", sumUnionInterface(customInts))
 }
 
+func demoMergeMaps() {
+	fmt.Println("\n--- 6. Слияние карт `MergeMaps` с разрешением конфликтов ---")
+	defaults := map[string]int{"workers": 4, "retries": 3}
+	overrides := map[string]int{"workers": 16, "timeout": 30}
+
+	// При конфликте берем большее из значений.
+	merged := MergeMaps(defaults, overrides, func(_ string, a, b int) int {
+		return max(a, b)
+	})
+	fmt.Println("Результат слияния:", merged)
+}
+
+func demoContainsFunc() {
+	fmt.Println("\n--- 7. `ContainsFunc` и `EqSet` для несравнимых типов ---")
+	// Структура со срезом не удовлетворяет `comparable`: contains(routes, ...) не скомпилируется.
+	type Route struct {
+		Path    string
+		Methods []string
+	}
+	routes := []Route{{Path: "/users", Methods: []string{"GET", "POST"}}}
+	eq := func(a, b Route) bool { return reflect.DeepEqual(a, b) }
+
+	fmt.Println("Есть ли маршрут /users [GET POST]?:",
+		ContainsFunc(routes, Route{Path: "/users", Methods: []string{"GET", "POST"}}, eq))
+
+	set := NewEqSet(eq)
+	set.Add(Route{Path: "/a", Methods: []string{"GET"}})
+	set.Add(Route{Path: "/a", Methods: []string{"GET"}}) // Дубликат по содержимому.
+	fmt.Println("Уникальных маршрутов в EqSet:", set.Len())
+}
+
+func demoTopK() {
+	fmt.Println("\n--- 8. `TopK` — k наибольших без полной сортировки ---")
+	latencies := map[string]int{"/users": 120, "/orders": 950, "/health": 3, "/search": 430, "/login": 80}
+	urls := make([]string, 0, len(latencies))
+	for url := range latencies {
+		urls = append(urls, url)
+	}
+	slowest := TopK(urls, 3, func(a, b string) bool { return latencies[a] < latencies[b] })
+	fmt.Println("Три самых медленных URL:", slowest)
+}
+
+func demoPaginate() {
+	fmt.Println("\n--- 9. Пагинация `Paginate` и `PaginateCursor` ---")
+	ids := []int{3, 8, 15, 16, 23, 42, 57}
+	page, more := Paginate(ids, 5, 10)
+	fmt.Printf("Paginate(offset=5, limit=10): %v, есть еще: %t\n", page, more)
+
+	var cur Cursor[int]
+	for i := 1; ; i++ {
+		page, next, more := PaginateCursor(ids, func(id int) int { return id }, cur, 3)
+		fmt.Printf("Страница %d по курсору: %v\n", i, page)
+		if !more {
+			break
+		}
+		cur = next
+	}
+}
+
+func demoPartition() {
+	fmt.Println("\n--- 10. `Partition` — разделение по предикату за один проход ---")
+	keys := []string{"user:1", "user:2", "user:3", "user:4"}
+	cache := map[string]string{"user:1": "Alice", "user:3": "Carol"}
+	hits, misses := Partition(keys, func(k string) bool {
+		_, ok := cache[k]
+		return ok
+	})
+	fmt.Printf("Попадания в кеш: %v, промахи: %v\n", hits, misses)
+}
+
+func demoPercentile() {
+	fmt.Println("\n--- 11. `Percentile`/`Quantiles` — перцентили с интерполяцией ---")
+	latencies := []float64{12, 7, 150, 9, 11, 30, 8, 10, 14, 95}
+	qs, _ := Quantiles(latencies, 50, 90, 99)
+	fmt.Printf("p50 = %.1f мс, p90 = %.1f мс, p99 = %.1f мс\n", qs[0], qs[1], qs[2])
+}
+
+func demoZip() {
+	fmt.Println("\n--- 12. `Pair`, `Zip`/`Unzip` — связывание ключей со значениями ---")
+	keys := []string{"user:1", "user:2", "user:3"}
+	values := []string{"Alice", "Bob", "Carol"} // Как из MGet: значения в порядке ключей.
+	for _, p := range Zip(keys, values) {
+		fmt.Printf("%s -> %s\n", p.First, p.Second)
+	}
+	ids, names := Unzip(Zip(keys, values[:2]))
+	fmt.Printf("После Unzip короткого Zip: %v %v\n", ids, names)
+}
+
 func main() {
 	demoSum()
 	demoContains()
 	demoAny()
 	demoUnionInterface()
 	demoTypeApproximation()
+	demoMergeMaps()
+	demoContainsFunc()
+	demoTopK()
+	demoPaginate()
+	demoPartition()
+	demoPercentile()
+	demoZip()
 }
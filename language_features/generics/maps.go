@@ -0,0 +1,26 @@
+package main
+
+// MergeMaps добавляет все пары из src в dst и возвращает dst.
+//
+// Если ключ есть в обеих картах, итоговое значение определяет resolve(key, a, b),
+// где a — значение из dst, b — значение из src. При resolve == nil побеждает значение из src.
+//
+// Как и append, функция работает с nil: если dst == nil, создается новая карта,
+// поэтому результат нужно всегда использовать (dst = MergeMaps(dst, src, resolve)).
+// Если обе карты nil, возвращается nil.
+func MergeMaps[K comparable, V any](dst, src map[K]V, resolve func(key K, a, b V) V) map[K]V {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[K]V, len(src))
+	}
+	for k, b := range src {
+		if a, ok := dst[k]; ok && resolve != nil {
+			dst[k] = resolve(k, a, b)
+			continue
+		}
+		dst[k] = b
+	}
+	return dst
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestMergeMaps(t *testing.T) {
+	sumResolve := func(_ string, a, b int) int { return a + b }
+
+	tests := []struct {
+		name    string
+		dst     map[string]int
+		src     map[string]int
+		resolve func(string, int, int) int
+		want    map[string]int
+	}{
+		{
+			name:    "непересекающиеся ключи",
+			dst:     map[string]int{"a": 1},
+			src:     map[string]int{"b": 2, "c": 3},
+			resolve: sumResolve,
+			want:    map[string]int{"a": 1, "b": 2, "c": 3},
+		},
+		{
+			name:    "пересекающиеся ключи",
+			dst:     map[string]int{"a": 1, "b": 2},
+			src:     map[string]int{"b": 10, "c": 3},
+			resolve: sumResolve,
+			want:    map[string]int{"a": 1, "b": 12, "c": 3},
+		},
+		{
+			name: "без resolve побеждает src",
+			dst:  map[string]int{"a": 1},
+			src:  map[string]int{"a": 2},
+			want: map[string]int{"a": 2},
+		},
+		{
+			name:    "nil dst",
+			src:     map[string]int{"a": 1},
+			resolve: sumResolve,
+			want:    map[string]int{"a": 1},
+		},
+		{
+			name:    "nil src",
+			dst:     map[string]int{"a": 1},
+			resolve: sumResolve,
+			want:    map[string]int{"a": 1},
+		},
+		{
+			name: "обе nil",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeMaps(tt.dst, tt.src, tt.resolve)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("MergeMaps = %v, ожидалось %v", got, tt.want)
+			}
+			if (got == nil) != (tt.want == nil) {
+				t.Errorf("MergeMaps = %#v, ожидалось %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeMapsResolverArguments(t *testing.T) {
+	var calls []string
+	dst := map[string]string{"host": "localhost", "port": "80"}
+	src := map[string]string{"port": "8080", "user": "admin"}
+
+	got := MergeMaps(dst, src, func(key, a, b string) string {
+		calls = append(calls, key+":"+a+"->"+b)
+		return b
+	})
+
+	if len(calls) != 1 || calls[0] != "port:80->8080" {
+		t.Errorf("resolve вызван с %v, ожидался один вызов port:80->8080", calls)
+	}
+	if got["port"] != "8080" || got["user"] != "admin" || got["host"] != "localhost" {
+		t.Errorf("результат %v", got)
+	}
+	// dst изменяется на месте, как при append с достаточной емкостью.
+	if dst["user"] != "admin" {
+		t.Error("ожидалось, что dst будет дополнен на месте")
+	}
+}
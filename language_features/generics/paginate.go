@@ -0,0 +1,47 @@
+package main
+
+import (
+	"cmp"
+	"sort"
+)
+
+// Paginate возвращает страницу items[offset : offset+limit] и признак того, что за ней
+// есть еще элементы. Границы приводятся к допустимым: отрицательный offset считается
+// нулем, offset за концом среза дает пустую страницу, а limit больше остатка — остаток.
+// При limit <= 0 страница пуста, но hasMore сообщает, есть ли элементы начиная с offset.
+//
+// Страница — подсрез items без копирования; емкость обрезана, поэтому append
+// к странице не затрет следующие элементы исходного среза.
+func Paginate[T any](items []T, offset, limit int) (page []T, hasMore bool) {
+	offset = min(max(offset, 0), len(items))
+	end := offset + min(max(limit, 0), len(items)-offset)
+	return items[offset:end:end], end < len(items)
+}
+
+// Cursor — позиция для PaginateCursor: ключ последнего элемента предыдущей страницы.
+// Нулевое значение Cursor означает начало списка.
+type Cursor[C cmp.Ordered] struct {
+	after C
+	valid bool
+}
+
+// PaginateCursor — keyset-пагинация: возвращает до limit элементов с ключом строго
+// больше курсора, курсор следующей страницы и признак того, что она есть.
+// items должны быть отсортированы по возрастанию key, а ключи — уникальны.
+//
+// В отличие от Paginate, страницы не "съезжают", если между запросами элементы
+// добавили или удалили: курсор указывает на ключ, а не на позицию, и элемент,
+// на котором остановились, может уже не существовать. Поиск начала страницы —
+// бинарный, O(log n).
+func PaginateCursor[T any, C cmp.Ordered](items []T, key func(T) C, cur Cursor[C], limit int) (page []T, next Cursor[C], hasMore bool) {
+	start := 0
+	if cur.valid {
+		start = sort.Search(len(items), func(i int) bool { return key(items[i]) > cur.after })
+	}
+	page, hasMore = Paginate(items, start, limit)
+	if len(page) == 0 {
+		// Пустая страница: курсор не двигаем, повторный запрос увидит новые элементы.
+		return page, cur, hasMore
+	}
+	return page, Cursor[C]{after: key(page[len(page)-1]), valid: true}, hasMore
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		name          string
+		offset, limit int
+		want          []int
+		wantMore      bool
+	}{
+		{"первая страница", 0, 2, []int{1, 2}, true},
+		{"середина", 2, 2, []int{3, 4}, true},
+		{"ровно до конца", 3, 2, []int{4, 5}, false},
+		{"limit больше остатка", 3, 10, []int{4, 5}, false},
+		{"offset за концом", 10, 2, []int{}, false},
+		{"offset равен длине", 5, 2, []int{}, false},
+		{"отрицательный offset", -3, 2, []int{1, 2}, true},
+		{"нулевой limit", 1, 0, []int{}, true},
+		{"отрицательный limit в конце", 5, -1, []int{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, more := Paginate(items, tt.offset, tt.limit)
+			if !slices.Equal(page, tt.want) || more != tt.wantMore {
+				t.Errorf("Paginate(%d, %d) = (%v, %t), ожидалось (%v, %t)",
+					tt.offset, tt.limit, page, more, tt.want, tt.wantMore)
+			}
+		})
+	}
+
+	if page, more := Paginate[int](nil, 0, 10); len(page) != 0 || more {
+		t.Errorf("Paginate(nil) = (%v, %t)", page, more)
+	}
+}
+
+func TestPaginateAppendDoesNotClobber(t *testing.T) {
+	items := []int{1, 2, 3}
+	page, _ := Paginate(items, 0, 1)
+	_ = append(page, 100)
+	if items[1] != 2 {
+		t.Errorf("append к странице изменил исходный срез: %v", items)
+	}
+}
+
+type logEntry struct {
+	ID  int
+	Msg string
+}
+
+func entryID(e logEntry) int { return e.ID }
+
+// ids возвращает ID записей страницы.
+func ids(page []logEntry) []int {
+	res := make([]int, len(page))
+	for i, e := range page {
+		res[i] = e.ID
+	}
+	return res
+}
+
+func TestPaginateCursorWalk(t *testing.T) {
+	entries := []logEntry{{0, "a"}, {2, "b"}, {5, "c"}, {7, "d"}, {9, "e"}}
+
+	// Ключ 0 не путается с начальным курсором.
+	var cur Cursor[int]
+	var got [][]int
+	for {
+		page, next, more := PaginateCursor(entries, entryID, cur, 2)
+		got = append(got, ids(page))
+		if !more {
+			break
+		}
+		cur = next
+	}
+	want := [][]int{{0, 2}, {5, 7}, {9}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("страницы = %v, ожидалось %v", got, want)
+	}
+}
+
+func TestPaginateCursorStableUnderChanges(t *testing.T) {
+	entries := []logEntry{{1, ""}, {2, ""}, {3, ""}, {4, ""}}
+	page, cur, _ := PaginateCursor(entries, entryID, Cursor[int]{}, 2)
+	if !slices.Equal(ids(page), []int{1, 2}) {
+		t.Fatalf("первая страница = %v", ids(page))
+	}
+
+	// Между запросами удалили последний элемент страницы и добавили новый в конец.
+	entries = []logEntry{{1, ""}, {3, ""}, {4, ""}, {5, ""}}
+	page, cur, more := PaginateCursor(entries, entryID, cur, 2)
+	if !slices.Equal(ids(page), []int{3, 4}) || !more {
+		t.Errorf("вторая страница = (%v, %t), ожидалось ([3 4], true)", ids(page), more)
+	}
+
+	page, cur, more = PaginateCursor(entries, entryID, cur, 2)
+	if !slices.Equal(ids(page), []int{5}) || more {
+		t.Errorf("третья страница = (%v, %t), ожидалось ([5], false)", ids(page), more)
+	}
+
+	// За концом — пустая страница, курсор не сдвигается.
+	page, next, more := PaginateCursor(entries, entryID, cur, 2)
+	if len(page) != 0 || more || next != cur {
+		t.Errorf("за концом: (%v, %+v, %t), ожидалась пустая страница с прежним курсором", ids(page), next, more)
+	}
+}
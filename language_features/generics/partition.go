@@ -0,0 +1,18 @@
+package main
+
+// Partition за один проход разделяет s на элементы, удовлетворяющие pred (matched),
+// и остальные (unmatched). Порядок элементов в обоих результатах совпадает с порядком в s.
+//
+// В отличие от двух вызовов фильтрации, pred вызывается ровно один раз на элемент —
+// это важно, если он дорогой или имеет побочные эффекты. Результаты — новые срезы,
+// s не изменяется. Пустая группа возвращается как nil.
+func Partition[T any](s []T, pred func(T) bool) (matched, unmatched []T) {
+	for _, v := range s {
+		if pred(v) {
+			matched = append(matched, v)
+		} else {
+			unmatched = append(unmatched, v)
+		}
+	}
+	return matched, unmatched
+}
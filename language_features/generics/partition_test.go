@@ -0,0 +1,67 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPartition(t *testing.T) {
+	isEven := func(n int) bool { return n%2 == 0 }
+
+	tests := []struct {
+		name          string
+		in            []int
+		wantMatched   []int
+		wantUnmatched []int
+	}{
+		{
+			name:        "все подходят",
+			in:          []int{2, 4, 6},
+			wantMatched: []int{2, 4, 6},
+		},
+		{
+			name:          "ни один не подходит",
+			in:            []int{1, 3, 5},
+			wantUnmatched: []int{1, 3, 5},
+		},
+		{
+			name:          "вперемешку, порядок сохраняется",
+			in:            []int{5, 2, 8, 1, 4, 7, 3},
+			wantMatched:   []int{2, 8, 4},
+			wantUnmatched: []int{5, 1, 7, 3},
+		},
+		{
+			name: "пустой срез",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, unmatched := Partition(tt.in, isEven)
+			if !slices.Equal(matched, tt.wantMatched) {
+				t.Errorf("matched = %v, ожидалось %v", matched, tt.wantMatched)
+			}
+			if !slices.Equal(unmatched, tt.wantUnmatched) {
+				t.Errorf("unmatched = %v, ожидалось %v", unmatched, tt.wantUnmatched)
+			}
+		})
+	}
+}
+
+func TestPartitionCallsPredOnce(t *testing.T) {
+	in := []string{"a", "bb", "ccc", "dd"}
+	calls := 0
+	long, short := Partition(in, func(s string) bool {
+		calls++
+		return len(s) > 1
+	})
+	if calls != len(in) {
+		t.Errorf("pred вызван %d раз, ожидалось %d", calls, len(in))
+	}
+	if !slices.Equal(long, []string{"bb", "ccc", "dd"}) || !slices.Equal(short, []string{"a"}) {
+		t.Errorf("Partition = (%v, %v)", long, short)
+	}
+	// Исходный срез не изменяется.
+	if !slices.Equal(in, []string{"a", "bb", "ccc", "dd"}) {
+		t.Errorf("исходный срез изменен: %v", in)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// errNoValues возвращается при вычислении перцентиля пустого набора.
+var errNoValues = errors.New("перцентиль пустого набора не определен")
+
+// Percentile возвращает p-й перцентиль (0 <= p <= 100) значений values.
+//
+// Используется линейная интерполяция между соседними элементами отсортированного
+// набора (как method="linear" в NumPy): перцентилю p соответствует позиция
+// p/100*(n-1), а дробная часть позиции задает долю пути между соседями.
+// Входной срез не изменяется — сортируется копия. Для целочисленных T (например,
+// time.Duration) интерполированное значение округляется к нулю.
+func Percentile[T Number](values []T, p float64) (T, error) {
+	res, err := Quantiles(values, p)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return res[0], nil
+}
+
+// Quantiles вычисляет сразу несколько перцентилей ps (см. Percentile), сортируя
+// копию values один раз. Результаты идут в порядке ps.
+func Quantiles[T Number](values []T, ps ...float64) ([]T, error) {
+	if len(values) == 0 {
+		return nil, errNoValues
+	}
+	for _, p := range ps {
+		// Условие записано через отрицание, чтобы NaN тоже считался недопустимым.
+		if !(p >= 0 && p <= 100) {
+			return nil, fmt.Errorf("перцентиль %v вне диапазона [0, 100]", p)
+		}
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	res := make([]T, len(ps))
+	for i, p := range ps {
+		pos := p / 100 * float64(len(sorted)-1)
+		lo := int(pos)
+		if lo == len(sorted)-1 {
+			res[i] = sorted[lo]
+			continue
+		}
+		frac := pos - float64(lo)
+		res[i] = T(float64(sorted[lo]) + frac*float64(sorted[lo+1]-sorted[lo]))
+	}
+	return res, nil
+}
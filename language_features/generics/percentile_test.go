@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPercentileKnownDataset(t *testing.T) {
+	// 1..10: совпадает с numpy.percentile(..., method="linear").
+	values := []float64{7, 2, 9, 1, 10, 4, 3, 8, 6, 5}
+	original := slices.Clone(values)
+
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0, 1},
+		{50, 5.5},
+		{90, 9.1},
+		{99, 9.91},
+		{100, 10},
+	}
+	for _, tt := range tests {
+		got, err := Percentile(values, tt.p)
+		if err != nil {
+			t.Fatalf("Percentile(p=%v): %v", tt.p, err)
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Percentile(p=%v) = %v, ожидалось %v", tt.p, got, tt.want)
+		}
+	}
+	if !slices.Equal(values, original) {
+		t.Errorf("входной срез изменен: %v", values)
+	}
+}
+
+func TestQuantilesDurations(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	got, err := Quantiles(latencies, 50, 90, 99)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Duration{50500 * time.Microsecond, 90100 * time.Microsecond, 99010 * time.Microsecond}
+	if !slices.Equal(got, want) {
+		t.Errorf("Quantiles = %v, ожидалось %v", got, want)
+	}
+}
+
+func TestPercentileEdgeCases(t *testing.T) {
+	if got, err := Percentile([]int64{42}, 99); err != nil || got != 42 {
+		t.Errorf("один элемент: (%d, %v), ожидалось (42, nil)", got, err)
+	}
+	if _, err := Percentile([]float64{}, 50); !errors.Is(err, errNoValues) {
+		t.Errorf("пустой набор: err = %v, ожидалось %v", err, errNoValues)
+	}
+	for _, p := range []float64{-1, 100.5, math.NaN()} {
+		if _, err := Percentile([]float64{1, 2}, p); err == nil {
+			t.Errorf("p = %v: ожидалась ошибка диапазона", p)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import "container/heap"
+
+// TopK возвращает k наибольших (по less) элементов items, отсортированных по убыванию:
+// первым идет самый "большой". Чтобы получить k наименьших, достаточно поменять
+// аргументы less местами.
+//
+// Вместо полной сортировки за O(n log n) используется min-heap размером k: в куче
+// хранятся k лучших из просмотренных элементов, а на вершине — худший из них,
+// который вытесняется, если встречается элемент больше. Итого O(n log k) времени
+// и O(k) дополнительной памяти — выгодно, когда k намного меньше n
+// ("10 самых медленных URL" из тысяч ответов). Исходный срез не изменяется.
+func TopK[T any](items []T, k int, less func(a, b T) bool) []T {
+	if k <= 0 || len(items) == 0 {
+		return nil
+	}
+	k = min(k, len(items))
+
+	h := &boundedHeap[T]{items: make([]T, 0, k), less: less}
+	for _, item := range items {
+		if h.Len() < k {
+			heap.Push(h, item)
+			continue
+		}
+		// Вершина — наименьший из k лучших; заменяем его, только если новый элемент больше.
+		if less(h.items[0], item) {
+			h.items[0] = item
+			heap.Fix(h, 0)
+		}
+	}
+
+	// Извлекаем по возрастанию и раскладываем с конца, чтобы получить убывающий порядок.
+	res := make([]T, h.Len())
+	for i := len(res) - 1; i >= 0; i-- {
+		res[i] = heap.Pop(h).(T)
+	}
+	return res
+}
+
+// boundedHeap — min-heap по функции less, реализующий heap.Interface.
+type boundedHeap[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h *boundedHeap[T]) Len() int           { return len(h.items) }
+func (h *boundedHeap[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *boundedHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *boundedHeap[T]) Push(x any)         { h.items = append(h.items, x.(T)) }
+
+func (h *boundedHeap[T]) Pop() any {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items = h.items[:n-1]
+	return item
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func TestTopKMatchesFullSort(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 200; iter++ {
+		n := rng.Intn(50)
+		items := make([]int, n)
+		for i := range items {
+			items[i] = rng.Intn(20) // Маленький диапазон — много повторов.
+		}
+		k := rng.Intn(60)
+		original := slices.Clone(items)
+
+		got := TopK(items, k, intLess)
+
+		sorted := slices.Clone(items)
+		slices.Sort(sorted)
+		slices.Reverse(sorted)
+		want := sorted[:min(max(k, 0), n)]
+		if len(want) == 0 {
+			want = nil
+		}
+
+		if !slices.Equal(got, want) {
+			t.Fatalf("TopK(%v, %d) = %v, ожидалось %v", items, k, got, want)
+		}
+		if !slices.Equal(items, original) {
+			t.Fatalf("TopK изменил исходный срез: %v -> %v", original, items)
+		}
+	}
+}
+
+func TestTopKEdgeCases(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []int
+		k     int
+		want  []int
+	}{
+		{"k = 0", []int{3, 1, 2}, 0, nil},
+		{"отрицательное k", []int{3, 1, 2}, -1, nil},
+		{"пустой срез", nil, 3, nil},
+		{"k больше длины", []int{3, 1, 2}, 10, []int{3, 2, 1}},
+		{"k равно длине", []int{1, 2}, 2, []int{2, 1}},
+		{"k = 1", []int{5, 9, 7}, 1, []int{9}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TopK(tt.items, tt.k, intLess); !slices.Equal(got, tt.want) {
+				t.Errorf("TopK = %v, ожидалось %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopKWithStructs(t *testing.T) {
+	type response struct {
+		URL      string
+		Duration time.Duration
+	}
+	responses := []response{
+		{"/a", 30 * time.Millisecond},
+		{"/b", 500 * time.Millisecond},
+		{"/c", 10 * time.Millisecond},
+		{"/d", 200 * time.Millisecond},
+	}
+
+	slowest := TopK(responses, 2, func(a, b response) bool { return a.Duration < b.Duration })
+	if len(slowest) != 2 || slowest[0].URL != "/b" || slowest[1].URL != "/d" {
+		t.Errorf("самые медленные: %v, ожидалось /b, /d", slowest)
+	}
+
+	// Обратный less дает k наименьших.
+	fastest := TopK(responses, 1, func(a, b response) bool { return a.Duration > b.Duration })
+	if len(fastest) != 1 || fastest[0].URL != "/c" {
+		t.Errorf("самый быстрый: %v, ожидалось /c", fastest)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+// Pair — пара значений разных типов, например ключ и значение из MGet.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip объединяет as и bs поэлементно в пары. Длина результата — длина более
+// короткого среза: лишние элементы длинного отбрасываются. Для пустого входа — nil.
+func Zip[A, B any](as []A, bs []B) []Pair[A, B] {
+	n := min(len(as), len(bs))
+	if n == 0 {
+		return nil
+	}
+	pairs := make([]Pair[A, B], n)
+	for i := range n {
+		pairs[i] = Pair[A, B]{First: as[i], Second: bs[i]}
+	}
+	return pairs
+}
+
+// Unzip разбирает пары обратно на два среза одинаковой длины — обратная к Zip операция
+// для срезов равной длины. Для пустого входа возвращает (nil, nil).
+func Unzip[A, B any](pairs []Pair[A, B]) ([]A, []B) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	as := make([]A, len(pairs))
+	bs := make([]B, len(pairs))
+	for i, p := range pairs {
+		as[i], bs[i] = p.First, p.Second
+	}
+	return as, bs
+}
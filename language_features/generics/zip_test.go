@@ -0,0 +1,62 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestZip(t *testing.T) {
+	keys := []string{"user:1", "user:2", "user:3"}
+
+	tests := []struct {
+		name   string
+		values []int
+		want   []Pair[string, int]
+	}{
+		{
+			name:   "равная длина",
+			values: []int{10, 20, 30},
+			want:   []Pair[string, int]{{"user:1", 10}, {"user:2", 20}, {"user:3", 30}},
+		},
+		{
+			name:   "второй короче",
+			values: []int{10},
+			want:   []Pair[string, int]{{"user:1", 10}},
+		},
+		{
+			name:   "второй длиннее",
+			values: []int{10, 20, 30, 40, 50},
+			want:   []Pair[string, int]{{"user:1", 10}, {"user:2", 20}, {"user:3", 30}},
+		},
+		{
+			name: "второй пустой",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Zip(keys, tt.values); !slices.Equal(got, tt.want) {
+				t.Errorf("Zip = %v, ожидалось %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnzipRoundTrip(t *testing.T) {
+	keys := []string{"a", "b", "c"}
+	values := []float64{1.5, 2.5, 3.5}
+
+	gotKeys, gotValues := Unzip(Zip(keys, values))
+	if !slices.Equal(gotKeys, keys) || !slices.Equal(gotValues, values) {
+		t.Errorf("Unzip(Zip) = (%v, %v), ожидалось (%v, %v)", gotKeys, gotValues, keys, values)
+	}
+
+	// Для срезов разной длины круг возвращает общий префикс.
+	gotKeys, gotValues = Unzip(Zip(keys, values[:2]))
+	if !slices.Equal(gotKeys, keys[:2]) || !slices.Equal(gotValues, values[:2]) {
+		t.Errorf("Unzip(Zip) = (%v, %v), ожидался префикс длины 2", gotKeys, gotValues)
+	}
+
+	if a, b := Unzip[string, int](nil); a != nil || b != nil {
+		t.Errorf("Unzip(nil) = (%v, %v), ожидалось (nil, nil)", a, b)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+// As — обобщенная форма "comma-ok": приводит v к типу T.
+// Возвращает (значение, true), если v содержит значение типа T (или, когда T —
+// интерфейс, значение, реализующее T), иначе нулевое значение T и false.
+//
+// Для nil-интерфейса результат всегда false, а для интерфейса с nil-указателем
+// (см. interfaceValues) — true: тип в нем есть, хотя значение nil.
+func As[T any](v any) (T, bool) {
+	t, ok := v.(T)
+	return t, ok
+}
+
+// AsOr приводит v к типу T, а если это невозможно, возвращает def.
+// Удобна там, где отсутствие значения нужного типа не ошибка, а повод взять значение
+// по умолчанию, и "comma-ok" блок был бы лишним.
+func AsOr[T any](v any, def T) T {
+	if t, ok := v.(T); ok {
+		return t
+	}
+	return def
+}
@@ -0,0 +1,80 @@
+package main
+
+import "testing"
+
+func TestAsReturnsValueOfMatchingType(t *testing.T) {
+	duck := &Duck{Name: "Дональд"}
+	var r Runner = duck
+
+	if got, ok := As[*Duck](r); !ok || got != duck {
+		t.Errorf("As[*Duck] = (%v, %v), ожидалось (%v, true)", got, ok, duck)
+	}
+	// Приведение к интерфейсу проверяет набор методов, а не конкретный тип.
+	if got, ok := As[Flyer](r); !ok || got.Fly() != duck.Fly() {
+		t.Errorf("As[Flyer] = (%v, %v), ожидалась утка", got, ok)
+	}
+	if got, ok := As[int](42); !ok || got != 42 {
+		t.Errorf("As[int](42) = (%v, %v)", got, ok)
+	}
+}
+
+func TestAsWrongType(t *testing.T) {
+	var r Runner = &Human{Name: "Джон"}
+
+	if got, ok := As[*Duck](r); ok || got != nil {
+		t.Errorf("As[*Duck](Human) = (%v, %v), ожидалось (nil, false)", got, ok)
+	}
+	if _, ok := As[Flyer](r); ok {
+		t.Error("Human не реализует Flyer, но As вернул true")
+	}
+	if got, ok := As[string](42); ok || got != "" {
+		t.Errorf("As[string](42) = (%q, %v)", got, ok)
+	}
+}
+
+func TestAsNil(t *testing.T) {
+	if _, ok := As[*Human](nil); ok {
+		t.Error("As для nil-интерфейса вернул true")
+	}
+	if _, ok := As[Runner](nil); ok {
+		t.Error("As[Runner](nil) вернул true")
+	}
+
+	// Интерфейс с nil-указателем содержит тип, поэтому приведение к нему успешно.
+	var nilHuman *Human
+	var r Runner = nilHuman
+	if got, ok := As[*Human](r); !ok || got != nil {
+		t.Errorf("As[*Human](nil-указатель) = (%v, %v), ожидалось (nil, true)", got, ok)
+	}
+}
+
+func TestAsOr(t *testing.T) {
+	def := &Duck{Name: "по умолчанию"}
+	duck := &Duck{Name: "Дональд"}
+
+	tests := []struct {
+		name string
+		v    any
+		want *Duck
+	}{
+		{"подходящий тип", duck, duck},
+		{"другой тип", &Human{Name: "Джон"}, def},
+		{"nil", nil, def},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AsOr(tt.v, def); got != tt.want {
+				t.Errorf("AsOr = %v, ожидалось %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := AsOr(any("timeout"), 30); got != 30 {
+		t.Errorf("AsOr[int] для строки = %d, ожидалось 30", got)
+	}
+	// Интерфейс с nil-указателем дает nil, а не значение по умолчанию.
+	var nilDuck *Duck
+	if got := AsOr(any(nilDuck), def); got != nil {
+		t.Errorf("AsOr для nil-указателя = %v, ожидался nil", got)
+	}
+}
@@ -1,6 +1,6 @@
 // Package main демонстрирует "сложные" и не всегда очевидные аспекты работы с интерфейсами в Go.
 // 1. Внутреннее представление интерфейсов (nil-интерфейс vs интерфейс с nil-значением).
-// 2. Полиморфизм и безопасное приведение типов (type assertion).
+// 2. Полиморфизм и безопасное приведение типов (type assertion), в том числе обобщенное (As, AsOr).
 package main
 
 import "fmt"
@@ -132,6 +132,14 @@ func typeAssertion(r Runner) {
 	default:
 		fmt.Printf("   Неизвестный тип: %T\n", v)
 	}
+
+	// 3. Обобщенные помощники As и AsOr
+	// Сокращают "comma-ok" блок, когда нужно лишь значение или значение по умолчанию.
+	if flyer, ok := As[Flyer](r); ok {
+		fmt.Println("   As[Flyer]: умеет летать —", flyer.Fly())
+	}
+	name := AsOr[*Human](r, &Human{Name: "не человек"}).Name
+	fmt.Println("   AsOr[*Human]:", name)
 }
 
 func main() {
@@ -0,0 +1,75 @@
+package main
+
+import "context"
+
+// ContextLogReader — источник логов, чтение из которого можно прервать через контекст.
+// Для блокирующих источников (сеть, pipe) это единственный способ не повиснуть навсегда
+// в ожидании следующего сообщения.
+//
+// ReadLogContext возвращает ctx.Err(), если контекст отменен до получения сообщения,
+// и io.EOF, когда сообщения заканчиваются.
+type ContextLogReader interface {
+	ReadLogContext(ctx context.Context) (*LogMessage, error)
+}
+
+// readResult — результат одного вызова ReadLog.
+type readResult struct {
+	msg *LogMessage
+	err error
+}
+
+// contextReader адаптирует обычный LogReader к ContextLogReader.
+//
+// ReadLog выполняется в отдельной горутине, а ReadLogContext ждет либо ее результата,
+// либо отмены контекста. Прервать сам ReadLog адаптер не может: после отмены горутина
+// продолжает ждать источник. Ее результат не теряется — его получит следующий вызов
+// ReadLogContext, поэтому ReadLog никогда не вызывается конкурентно сам с собой.
+//
+// Контекст, который нельзя отменить (ctx.Done() == nil, например context.Background()
+// в Aggregate), прерывать нечего: тогда ReadLog вызывается напрямую, без горутины
+// и канала на каждое сообщение.
+type contextReader struct {
+	reader  LogReader
+	pending chan readResult // Незавершенное чтение, начатое прошлым (прерванным) вызовом.
+}
+
+// WithContext возвращает ContextLogReader для r. Если r уже поддерживает контекст,
+// он возвращается как есть; иначе оборачивается адаптером.
+// Адаптер, как и большинство LogReader, не предназначен для конкурентных вызовов.
+func WithContext(r LogReader) ContextLogReader {
+	if cr, ok := r.(ContextLogReader); ok {
+		return cr
+	}
+	return &contextReader{reader: r}
+}
+
+// ReadLogContext реализует ContextLogReader.
+func (c *contextReader) ReadLogContext(ctx context.Context) (*LogMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if ctx.Done() == nil {
+		if c.pending != nil {
+			res := <-c.pending
+			c.pending = nil
+			return res.msg, res.err
+		}
+		return c.reader.ReadLog()
+	}
+	if c.pending == nil {
+		ch := make(chan readResult, 1)
+		go func() {
+			msg, err := c.reader.ReadLog()
+			ch <- readResult{msg: msg, err: err}
+		}()
+		c.pending = ch
+	}
+
+	select {
+	case res := <-c.pending:
+		c.pending = nil
+		return res.msg, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingReader отдает заданные сообщения, а затем блокируется в ReadLog,
+// пока не будет закрыт release. Контекст он не поддерживает.
+type blockingReader struct {
+	messages []*LogMessage
+	release  chan struct{}
+	calls    atomic.Int32
+}
+
+func (r *blockingReader) ReadLog() (*LogMessage, error) {
+	r.calls.Add(1)
+	if len(r.messages) > 0 {
+		msg := r.messages[0]
+		r.messages = r.messages[1:]
+		return msg, nil
+	}
+	<-r.release
+	return &LogMessage{Level: "INFO", Message: "после release"}, nil
+}
+
+// ctxBlockingReader поддерживает контекст: ReadLogContext разблокируется только отменой.
+type ctxBlockingReader struct {
+	ctxCalls atomic.Int32
+}
+
+func (r *ctxBlockingReader) ReadLog() (*LogMessage, error) {
+	panic("при наличии ReadLogContext агрегатор не должен вызывать ReadLog")
+}
+
+func (r *ctxBlockingReader) ReadLogContext(ctx context.Context) (*LogMessage, error) {
+	r.ctxCalls.Add(1)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// aggregateAsync запускает AggregateContext и возвращает канал с его результатом.
+func aggregateAsync(ctx context.Context, la *LogAggregator) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- la.AggregateContext(ctx) }()
+	return done
+}
+
+// waitShutdown ждет завершения агрегатора не дольше секунды.
+func waitShutdown(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("агрегатор не завершился после отмены контекста")
+		return nil
+	}
+}
+
+func TestAggregateContextStopsBlockedReader(t *testing.T) {
+	reader := &blockingReader{
+		messages: []*LogMessage{{Level: "INFO", Message: "a"}, {Level: "INFO", Message: "b"}},
+		release:  make(chan struct{}),
+	}
+	defer close(reader.release) // Отпускаем зависшую горутину адаптера.
+	storage := &countingStorage{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := aggregateAsync(ctx, NewLogAggregator(reader, nil, storage, 2))
+
+	// Ждем, пока агрегатор прочитает оба сообщения и заблокируется на третьем.
+	for reader.calls.Load() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := waitShutdown(t, done); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, ожидалось %v", err, context.Canceled)
+	}
+	if storage.count() != 2 {
+		t.Errorf("сохранено %d сообщений, ожидалось 2 — прочитанное должно обрабатываться до конца", storage.count())
+	}
+}
+
+func TestAggregateContextUsesContextReader(t *testing.T) {
+	reader := &ctxBlockingReader{}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	done := aggregateAsync(ctx, NewLogAggregator(reader, nil, &countingStorage{}, 1))
+	if err := waitShutdown(t, done); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, ожидалось %v", err, context.DeadlineExceeded)
+	}
+	if reader.ctxCalls.Load() == 0 {
+		t.Error("ReadLogContext не вызывался")
+	}
+}
+
+// pendingReader блокирует первый ReadLog до закрытия release и отдает из него сообщение;
+// следующие вызовы сразу возвращают io.EOF.
+type pendingReader struct {
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (r *pendingReader) ReadLog() (*LogMessage, error) {
+	if r.calls.Add(1) > 1 {
+		return nil, io.EOF
+	}
+	<-r.release
+	return &LogMessage{Level: "INFO", Message: "отложенное"}, nil
+}
+
+func TestAggregateContextDeliversPendingReadToNextCall(t *testing.T) {
+	reader := &pendingReader{release: make(chan struct{})}
+	storage := &countingStorage{}
+	la := NewLogAggregator(reader, nil, storage, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := aggregateAsync(ctx, la)
+	for reader.calls.Load() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := waitShutdown(t, done); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, ожидалось %v", err, context.Canceled)
+	}
+
+	// Источник отдает сообщение уже после отмены: его обрабатывает следующий вызов.
+	close(reader.release)
+	if err := waitShutdown(t, aggregateAsync(context.Background(), la)); err != nil {
+		t.Fatalf("повторный AggregateContext: %v", err)
+	}
+	if storage.count() != 1 {
+		t.Errorf("сохранено %d сообщений, ожидалось 1 — отложенное сообщение потеряно", storage.count())
+	}
+	if n := reader.calls.Load(); n != 2 {
+		t.Errorf("ReadLog вызван %d раз, ожидалось 2 — незавершенное чтение запущено повторно", n)
+	}
+}
+
+func TestWithContextKeepsPendingRead(t *testing.T) {
+	reader := &blockingReader{release: make(chan struct{})}
+	cr := WithContext(reader)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cr.ReadLogContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, ожидалось %v", err, context.Canceled)
+	}
+
+	// Чтение начинается и прерывается по таймауту, пока источник заблокирован.
+	short, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	if _, err := cr.ReadLogContext(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, ожидалось %v", err, context.DeadlineExceeded)
+	}
+
+	// Источник отдал сообщение уже после отмены: его получает следующий вызов,
+	// а ReadLog не запускается повторно.
+	close(reader.release)
+	msg, err := cr.ReadLogContext(context.Background())
+	if err != nil || msg.Message != "после release" {
+		t.Fatalf("ReadLogContext = (%v, %v), ожидалось отложенное сообщение", msg, err)
+	}
+	if n := reader.calls.Load(); n != 1 {
+		t.Errorf("ReadLog вызван %d раз, ожидался 1", n)
+	}
+}
+
+func TestWithContextReturnsContextReaderAsIs(t *testing.T) {
+	reader := &ctxBlockingReader{}
+	if got := WithContext(reader); got != ContextLogReader(reader) {
+		t.Error("WithContext обернул reader, уже поддерживающий контекст")
+	}
+}
+
+// repeatReader бесконечно отдает одно и то же сообщение.
+type repeatReader struct{ msg *LogMessage }
+
+func (r *repeatReader) ReadLog() (*LogMessage, error) { return r.msg, nil }
+
+func TestWithContextReadsDirectlyWithoutCancellation(t *testing.T) {
+	cr := WithContext(&repeatReader{msg: &LogMessage{Message: "ping"}})
+	// Контекст без отмены: ни горутины, ни канала на сообщение — ни одной аллокации.
+	allocs := testing.AllocsPerRun(100, func() {
+		if msg, err := cr.ReadLogContext(context.Background()); err != nil || msg.Message != "ping" {
+			t.Fatalf("ReadLogContext = (%v, %v)", msg, err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("аллокаций на чтение: %v, ожидалось 0", allocs)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/andrewhigh08/exp/internal/dedup"
+)
+
+// DedupStorage — LogStorage, который хранит последние limit сообщений, а их тексты —
+// в dedup.ContentStore: повторяющиеся сообщения ("connection refused" тысячу раз
+// подряд) занимают одну копию текста, а каждая запись держит только ее хеш.
+//
+// Когда сообщение вытесняется из истории, ссылка на его текст освобождается, и текст
+// удаляется из хранилища вместе с последним ссылающимся на него сообщением.
+// Безопасен для конкурентного использования.
+type DedupStorage struct {
+	limit int
+	texts *dedup.ContentStore[string]
+
+	mu      sync.Mutex
+	entries []dedupEntry // От старых к новым.
+}
+
+// dedupEntry — сохраненное сообщение с текстом, вынесенным в ContentStore.
+type dedupEntry struct {
+	timestamp time.Time
+	level     string
+	text      dedup.Hash
+}
+
+// NewDedupStorage создает хранилище на последние limit сообщений. Паникует, если
+// limit меньше 1.
+func NewDedupStorage(limit int) *DedupStorage {
+	if limit < 1 {
+		panic("NewDedupStorage: limit должен быть не меньше 1")
+	}
+	return &DedupStorage{
+		limit: limit,
+		texts: dedup.NewContentStore[string](),
+	}
+}
+
+// StoreLog реализует LogStorage.
+func (s *DedupStorage) StoreLog(msg *LogMessage) error {
+	h := s.texts.Put(msg.Message)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, dedupEntry{timestamp: msg.Timestamp, level: msg.Level, text: h})
+	if len(s.entries) > s.limit {
+		s.texts.Release(s.entries[0].text)
+		s.entries[0] = dedupEntry{}
+		s.entries = s.entries[1:]
+	}
+	return nil
+}
+
+// Messages возвращает сохраненные сообщения от старых к новым.
+func (s *DedupStorage) Messages() []*LogMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*LogMessage, 0, len(s.entries))
+	for _, e := range s.entries {
+		text, _ := s.texts.Get(e.text) // Запись держит ссылку, поэтому текст есть всегда.
+		out = append(out, &LogMessage{Timestamp: e.timestamp, Level: e.level, Message: text})
+	}
+	return out
+}
+
+// Len возвращает количество сохраненных сообщений.
+func (s *DedupStorage) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Unique возвращает количество различных текстов среди сохраненных сообщений.
+func (s *DedupStorage) Unique() int {
+	return s.texts.Len()
+}
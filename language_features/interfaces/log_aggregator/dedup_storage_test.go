@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// storedTexts возвращает тексты сохраненных сообщений по порядку.
+func storedTexts(s *DedupStorage) []string {
+	var texts []string
+	for _, msg := range s.Messages() {
+		texts = append(texts, msg.Message)
+	}
+	return texts
+}
+
+func TestDedupStorageSharesRepeatedTexts(t *testing.T) {
+	s := NewDedupStorage(10)
+	for _, text := range []string{"connection refused", "user logged in", "connection refused", "connection refused"} {
+		if err := s.StoreLog(&LogMessage{Level: "ERROR", Message: text}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if s.Len() != 4 {
+		t.Errorf("Len = %d, ожидалось 4", s.Len())
+	}
+	if s.Unique() != 2 {
+		t.Errorf("Unique = %d, ожидалось 2", s.Unique())
+	}
+	want := []string{"connection refused", "user logged in", "connection refused", "connection refused"}
+	if got := storedTexts(s); !slices.Equal(got, want) {
+		t.Errorf("Messages = %q, ожидалось %q", got, want)
+	}
+}
+
+func TestDedupStorageReleasesEvictedTexts(t *testing.T) {
+	s := NewDedupStorage(2)
+	for _, text := range []string{"a", "b", "a", "c"} {
+		s.StoreLog(&LogMessage{Message: text})
+	}
+
+	// Вытеснены первые "a" и "b": "b" больше не нужен, "a" держит третье сообщение.
+	if got := storedTexts(s); !slices.Equal(got, []string{"a", "c"}) {
+		t.Errorf("Messages = %q, ожидалось [a c]", got)
+	}
+	if s.Unique() != 2 {
+		t.Errorf("Unique = %d, ожидалось 2: текст вытесненного сообщения не освобожден", s.Unique())
+	}
+}
+
+func TestDedupStorageInAggregator(t *testing.T) {
+	reader := &mockReader{}
+	for i := range 30 {
+		reader.messages = append(reader.messages, &LogMessage{Level: "WARN", Message: fmt.Sprintf("retry %d", i%3)})
+	}
+	s := NewDedupStorage(100)
+	NewLogAggregator(reader, nil, s, 4).Aggregate()
+
+	if s.Len() != 30 || s.Unique() != 3 {
+		t.Errorf("Len = %d, Unique = %d, ожидалось 30 и 3", s.Len(), s.Unique())
+	}
+}
+
+func TestNewDedupStoragePanicsOnInvalidLimit(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ожидалась паника")
+		}
+	}()
+	NewDedupStorage(0)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Interner хранит по одному экземпляру каждой встреченной строки.
+//
+// В логах много повторяющихся значений (уровни, префиксы, имена сервисов): без
+// интернирования каждое сообщение держит свою копию, а подстрока, вырезанная из
+// строки лога, держит в памяти всю исходную строку. Intern возвращает канонический
+// экземпляр, поэтому миллион сообщений уровня "INFO" ссылаются на одну строку.
+//
+// Основан на sync.Map: после прогрева почти все вызовы — чтения без блокировок.
+// Записи никогда не удаляются, поэтому Interner подходит для данных с ограниченным
+// набором значений и не подходит для уникальных строк (например, текста сообщений).
+// Нулевое значение готово к использованию.
+type Interner struct {
+	strings sync.Map // string -> string
+	size    atomic.Int64
+}
+
+// Intern возвращает канонический экземпляр строки, равной s.
+func (in *Interner) Intern(s string) string {
+	if v, ok := in.strings.Load(s); ok {
+		return v.(string)
+	}
+	// Копируем s перед сохранением: если s — подстрока большой строки,
+	// в таблице должна остаться только она, а не вся исходная строка.
+	c := strings.Clone(s)
+	v, loaded := in.strings.LoadOrStore(c, c)
+	if !loaded {
+		in.size.Add(1)
+	}
+	return v.(string)
+}
+
+// Len возвращает количество уникальных строк.
+func (in *Interner) Len() int {
+	return int(in.size.Load())
+}
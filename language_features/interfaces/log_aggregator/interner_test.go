@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestInternerReturnsCanonicalInstance(t *testing.T) {
+	var in Interner
+	a := in.Intern(strings.Repeat("INFO", 1))
+	b := in.Intern(string([]byte("INFO")))
+
+	if a != b {
+		t.Fatalf("Intern вернул разные значения: %q и %q", a, b)
+	}
+	if unsafe.StringData(a) != unsafe.StringData(b) {
+		t.Error("равные строки не разделяют один экземпляр")
+	}
+	if in.Len() != 1 {
+		t.Errorf("Len = %d, ожидался 1", in.Len())
+	}
+}
+
+func TestInternerDoesNotRetainSourceString(t *testing.T) {
+	var in Interner
+	line := "2024-01-02T15:04:05Z WARN disk space is low"
+	level := in.Intern(line[21:25])
+
+	if level != "WARN" {
+		t.Fatalf("Intern = %q", level)
+	}
+	// Канонический экземпляр — копия, а не подстрока исходной строки.
+	if unsafe.StringData(level) == unsafe.StringData(line[21:25]) {
+		t.Error("Interner сохранил подстроку, удерживающую всю строку лога")
+	}
+}
+
+func TestInternerConcurrent(t *testing.T) {
+	var in Interner
+	levels := []string{"DEBUG", "INFO", "WARN", "ERROR"}
+
+	var wg sync.WaitGroup
+	results := make([][]string, 8)
+	for g := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				// Каждый раз новая строка, чтобы проверять именно интернирование.
+				s := string([]byte(levels[i%len(levels)]))
+				results[g] = append(results[g], in.Intern(s))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if in.Len() != len(levels) {
+		t.Errorf("Len = %d, ожидалось %d", in.Len(), len(levels))
+	}
+	canonical := make(map[string]*byte)
+	for _, res := range results {
+		for _, s := range res {
+			p := unsafe.StringData(s)
+			if prev, ok := canonical[s]; ok && prev != p {
+				t.Fatalf("для %q получены разные экземпляры", s)
+			}
+			canonical[s] = p
+		}
+	}
+}
+
+// BenchmarkRetainedLevels сравнивает память, удерживаемую уровнями 10 000 сообщений
+// после того, как сами строки лога больше не нужны (метрика retained-B/op): без
+// интернирования каждая подстрока держит свою строку лога целиком.
+func BenchmarkRetainedLevels(b *testing.B) {
+	const n = 10_000
+	levels := []string{"DEBUG", "INFO", "WARN", "ERROR"}
+	lines := func() []string {
+		res := make([]string, n)
+		for i := range res {
+			res[i] = fmt.Sprintf("%s request %06d handled by worker %d", levels[i%len(levels)], i, i%8)
+		}
+		return res
+	}
+
+	run := func(b *testing.B, extract func(line string) string) {
+		b.ReportAllocs()
+		var retained uint64
+		for range b.N {
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+
+			src := lines()
+			kept := make([]string, n)
+			for i, line := range src {
+				kept[i] = extract(line[:strings.IndexByte(line, ' ')])
+			}
+			src = nil
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+
+			retained += after.HeapAlloc - min(after.HeapAlloc, before.HeapAlloc)
+			runtime.KeepAlive(kept)
+		}
+		b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+	}
+
+	b.Run("substring", func(b *testing.B) {
+		run(b, func(level string) string { return level })
+	})
+	b.Run("interned", func(b *testing.B) {
+		var in Interner
+		run(b, in.Intern)
+	})
+}
@@ -3,10 +3,12 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -46,24 +48,42 @@ type LogManager interface {
 
 // LogAggregator — реализация LogManager.
 type LogAggregator struct {
-	reader       LogReader
 	transformers []LogTransformer // Теперь это срез для поддержки цепочки трансформаций
 	storage      LogStorage
 	numWorkers   int // Количество воркеров для параллельной обработки
+
+	// reader — источник, приведенный к ContextLogReader (см. WithContext). Создается один
+	// раз: адаптер помнит чтение, незавершенное прерванным AggregateContext, и отдает его
+	// результат следующему вызову вместо конкурентного ReadLog.
+	reader ContextLogReader
 }
 
 // NewLogAggregator — конструктор для LogAggregator.
 func NewLogAggregator(reader LogReader, transformers []LogTransformer, storage LogStorage, numWorkers int) *LogAggregator {
 	return &LogAggregator{
-		reader:       reader,
 		transformers: transformers,
 		storage:      storage,
 		numWorkers:   numWorkers,
+		reader:       WithContext(reader),
 	}
 }
 
 // Aggregate запускает конвейер: читает логи и распределяет их по воркерам для обработки.
+// Отменить его нельзя, поэтому источник без ContextLogReader читается напрямую,
+// без горутины на каждое сообщение (см. contextReader).
 func (la *LogAggregator) Aggregate() {
+	_ = la.AggregateContext(context.Background())
+}
+
+// AggregateContext работает как Aggregate, но прекращает чтение при отмене ctx,
+// даже если источник заблокирован в ожидании следующего сообщения. Если reader
+// реализует ContextLogReader, используется его ReadLogContext, иначе — адаптер WithContext.
+// Сообщение, которое источник вернул уже после отмены, не теряется: его получит
+// следующий вызов AggregateContext. Сами вызовы не должны пересекаться.
+//
+// Уже прочитанные сообщения обрабатываются до конца. Возвращает ctx.Err(), если
+// чтение было прервано отменой, и nil, если источник иссяк.
+func (la *LogAggregator) AggregateContext(ctx context.Context) error {
 	var wg sync.WaitGroup
 	jobs := make(chan *LogMessage, la.numWorkers)
 
@@ -80,19 +100,33 @@ func (la *LogAggregator) Aggregate() {
 	}
 
 	// 2. Читаем логи из источника и отправляем их в канал `jobs`
+	var readErr error
+read:
 	for {
-		logMsg, err := la.reader.ReadLog()
+		logMsg, err := la.reader.ReadLogContext(ctx)
 		if err != nil {
 			// Если источник иссяк, прекращаем чтение.
 			if errors.Is(err, io.EOF) {
 				fmt.Println("Источник логов иссяк. Завершение чтения.")
 				break
 			}
+			// Контекст отменен — прекращаем чтение, не дожидаясь источника.
+			if ctx.Err() != nil {
+				fmt.Println("Чтение логов прервано.")
+				readErr = ctx.Err()
+				break
+			}
 			// Логируем ошибку чтения и продолжаем.
 			log.Printf("Ошибка чтения лога: %v\n", err)
 			continue
 		}
-		jobs <- logMsg
+		select {
+		case jobs <- logMsg:
+		case <-ctx.Done():
+			fmt.Println("Чтение логов прервано.")
+			readErr = ctx.Err()
+			break read
+		}
 	}
 
 	// 3. Закрываем канал `jobs`, чтобы воркеры завершили свою работу после обработки всех сообщений.
@@ -101,6 +135,7 @@ func (la *LogAggregator) Aggregate() {
 	// 4. Ожидаем, пока все воркеры полностью завершат работу.
 	wg.Wait()
 	fmt.Println("Вся обработка завершена.")
+	return readErr
 }
 
 // processLog выполняет полную цепочку обработки для одного лог-сообщения.
@@ -187,4 +222,41 @@ func main() {
 	// 2. Создаем и запускаем менеджер агрегации с 2 воркерами.
 	manager := NewLogAggregator(reader, transformers, storage, 2)
 	manager.Aggregate()
+
+	// 3. Тот же конвейер, но источник — текстовый лог, разбираемый регулярным выражением.
+	rawLogs := `2024-01-02T15:04:05Z INFO service started
+не лог, а случайная строка
+2024-01-02T15:04:06Z ERROR connection refused`
+	pattern := regexp.MustCompile(`^(?P<timestamp>\S+) (?P<level>[A-Z]+) (?P<message>.*)$`)
+	textReader, err := NewRegexLogReader(strings.NewReader(rawLogs), pattern, time.RFC3339)
+	if err != nil {
+		log.Fatal(err)
+	}
+	NewLogAggregator(textReader, nil, storage, 1).Aggregate()
+	fmt.Printf("Пропущено неразобранных строк: %d\n", textReader.Skipped())
+
+	// 4. Поток больше, чем можно сохранить: храним равномерную выборку из 3 сообщений.
+	burst := &mockReader{}
+	for i := 1; i <= 20; i++ {
+		burst.messages = append(burst.messages, &LogMessage{Timestamp: time.Now(), Level: "INFO", Message: fmt.Sprintf("event %d", i)})
+	}
+	sampling := NewSamplingStorage(3, 1)
+	NewLogAggregator(burst, nil, sampling, 2).Aggregate()
+	fmt.Printf("Из %d сообщений в выборке:\n", sampling.Seen())
+	for _, msg := range sampling.Samples() {
+		fmt.Printf("  %s\n", msg.Message)
+	}
+
+	// 5. Поток с повторами: одинаковые тексты хранятся в одном экземпляре.
+	noisy := &mockReader{}
+	for i := 1; i <= 12; i++ {
+		text := "connection refused"
+		if i%4 == 0 {
+			text = fmt.Sprintf("reconnected after %d attempts", i)
+		}
+		noisy.messages = append(noisy.messages, &LogMessage{Timestamp: time.Now(), Level: "ERROR", Message: text})
+	}
+	deduped := NewDedupStorage(10)
+	NewLogAggregator(noisy, nil, deduped, 2).Aggregate()
+	fmt.Printf("Сохранено сообщений: %d, различных текстов: %d\n", deduped.Len(), deduped.Unique())
 }
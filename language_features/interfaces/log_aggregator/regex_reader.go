@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Имена групп, которые должен содержать шаблон RegexLogReader.
+const (
+	groupTimestamp = "timestamp"
+	groupLevel     = "level"
+	groupMessage   = "message"
+)
+
+// RegexLogReader — LogReader для текстовых логов: читает io.Reader построчно
+// и разбирает каждую строку регулярным выражением с именованными группами
+// (?P<timestamp>...), (?P<level>...) и (?P<message>...).
+//
+// Строки, которые не совпали с шаблоном или содержат некорректное время,
+// пропускаются и учитываются в Skipped, чтобы один "мусорный" фрагмент
+// не останавливал разбор всего файла.
+type RegexLogReader struct {
+	mu         sync.Mutex
+	scanner    *bufio.Scanner
+	pattern    *regexp.Regexp
+	timeLayout string
+	idx        map[string]int // Индексы именованных групп в результате FindStringSubmatch.
+	skipped    int
+	levels     Interner // Уровней немного, а подстрока держала бы в памяти всю строку лога.
+}
+
+// NewRegexLogReader создает читатель. timeLayout — формат времени для time.Parse
+// (например, time.RFC3339). Возвращает ошибку, если в pattern нет нужных групп.
+func NewRegexLogReader(r io.Reader, pattern *regexp.Regexp, timeLayout string) (*RegexLogReader, error) {
+	idx := make(map[string]int)
+	for _, name := range []string{groupTimestamp, groupLevel, groupMessage} {
+		i := pattern.SubexpIndex(name)
+		if i < 0 {
+			return nil, fmt.Errorf("в шаблоне %q нет именованной группы %q", pattern, name)
+		}
+		idx[name] = i
+	}
+	return &RegexLogReader{
+		scanner:    bufio.NewScanner(r),
+		pattern:    pattern,
+		timeLayout: timeLayout,
+		idx:        idx,
+	}, nil
+}
+
+// ReadLog возвращает следующее разобранное сообщение или io.EOF, когда строки закончились.
+func (r *RegexLogReader) ReadLog() (*LogMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		m := r.pattern.FindStringSubmatch(line)
+		if m == nil {
+			r.skipped++
+			continue
+		}
+		ts, err := time.Parse(r.timeLayout, m[r.idx[groupTimestamp]])
+		if err != nil {
+			r.skipped++
+			continue
+		}
+		return &LogMessage{
+			Timestamp: ts,
+			Level:     r.levels.Intern(m[r.idx[groupLevel]]),
+			Message:   m[r.idx[groupMessage]],
+		}, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// Skipped возвращает количество пропущенных (неразобранных) строк.
+func (r *RegexLogReader) Skipped() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.skipped
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Формат вида "2024-01-02 15:04:05 [INFO] сообщение".
+var bracketFormat = regexp.MustCompile(`^(?P<timestamp>\S+ \S+) \[(?P<level>[A-Z]+)\] (?P<message>.*)$`)
+
+const bracketLayout = "2006-01-02 15:04:05"
+
+func TestRegexLogReaderParsesLines(t *testing.T) {
+	input := strings.Join([]string{
+		"2024-01-02 15:04:05 [INFO] user logged in",
+		"это строка без формата",
+		"2024-01-02 15:04:06 [WARN] disk space is low",
+		"2024-13-45 99:99:99 [INFO] некорректное время",
+		"",
+		"2024-01-02 15:04:07 [ERROR] connection lost",
+	}, "\n")
+
+	r, err := NewRegexLogReader(strings.NewReader(input), bracketFormat, bracketLayout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []LogMessage{
+		{Timestamp: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), Level: "INFO", Message: "user logged in"},
+		{Timestamp: time.Date(2024, 1, 2, 15, 4, 6, 0, time.UTC), Level: "WARN", Message: "disk space is low"},
+		{Timestamp: time.Date(2024, 1, 2, 15, 4, 7, 0, time.UTC), Level: "ERROR", Message: "connection lost"},
+	}
+	for i, w := range want {
+		got, err := r.ReadLog()
+		if err != nil {
+			t.Fatalf("сообщение %d: %v", i, err)
+		}
+		if !got.Timestamp.Equal(w.Timestamp) || got.Level != w.Level || got.Message != w.Message {
+			t.Errorf("сообщение %d = %+v, ожидалось %+v", i, *got, w)
+		}
+	}
+
+	if _, err := r.ReadLog(); !errors.Is(err, io.EOF) {
+		t.Errorf("после последней строки err = %v, ожидался io.EOF", err)
+	}
+	// Повторный вызов после EOF тоже возвращает EOF.
+	if _, err := r.ReadLog(); !errors.Is(err, io.EOF) {
+		t.Errorf("повторный вызов: err = %v, ожидался io.EOF", err)
+	}
+	if r.Skipped() != 3 {
+		t.Errorf("Skipped = %d, ожидалось 3", r.Skipped())
+	}
+}
+
+func TestRegexLogReaderRequiresNamedGroups(t *testing.T) {
+	pattern := regexp.MustCompile(`^(?P<timestamp>\S+) (?P<message>.*)$`)
+	if _, err := NewRegexLogReader(strings.NewReader(""), pattern, time.RFC3339); err == nil {
+		t.Error("ожидалась ошибка: в шаблоне нет группы level")
+	}
+}
+
+// errReader возвращает ошибку чтения после данных.
+type errReader struct {
+	data string
+	err  error
+}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestRegexLogReaderPropagatesReadError(t *testing.T) {
+	errBroken := errors.New("broken pipe")
+	src := &errReader{data: "2024-01-02 15:04:05 [INFO] ok\n", err: errBroken}
+	r, err := NewRegexLogReader(src, bracketFormat, bracketLayout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.ReadLog(); err != nil {
+		t.Fatalf("первая строка: %v", err)
+	}
+	if _, err := r.ReadLog(); !errors.Is(err, errBroken) {
+		t.Errorf("err = %v, ожидалась ошибка чтения", err)
+	}
+}
+
+func TestRegexLogReaderInAggregator(t *testing.T) {
+	input := "2024-01-02 15:04:05 [INFO] a\nмусор\n2024-01-02 15:04:06 [INFO] b\n"
+	r, err := NewRegexLogReader(strings.NewReader(input), bracketFormat, bracketLayout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := &countingStorage{}
+	NewLogAggregator(r, nil, storage, 2).Aggregate()
+
+	if storage.count() != 2 {
+		t.Errorf("сохранено %d сообщений, ожидалось 2", storage.count())
+	}
+}
+
+// countingStorage считает сохраненные сообщения.
+type countingStorage struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (s *countingStorage) StoreLog(*LogMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.n++
+	return nil
+}
+
+func (s *countingStorage) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.n
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+)
+
+// Reservoir хранит равномерную случайную выборку из k элементов потока неизвестной длины
+// (reservoir sampling, алгоритм R).
+//
+// Первые k элементов попадают в выборку целиком. Элемент номер n > k заменяет случайный
+// элемент выборки с вероятностью k/n. В итоге каждый из увиденных элементов оказывается
+// в выборке с одинаковой вероятностью k/n, а память не зависит от длины потока.
+//
+// Источник случайности инициализируется переданным seed: один и тот же поток с одним
+// seed всегда дает одну и ту же выборку. Безопасен для конкурентного использования,
+// но при конкурентных Offer порядок элементов, а значит и выборка, не детерминирован.
+type Reservoir[T any] struct {
+	k int
+
+	mu      sync.Mutex
+	rnd     *rand.Rand
+	seen    int
+	samples []T
+}
+
+// NewReservoir создает выборку на k элементов. Паникует, если k меньше 1.
+func NewReservoir[T any](k int, seed int64) *Reservoir[T] {
+	if k < 1 {
+		panic("NewReservoir: размер выборки должен быть не меньше 1")
+	}
+	return &Reservoir[T]{
+		k:       k,
+		rnd:     rand.New(rand.NewSource(seed)),
+		samples: make([]T, 0, k),
+	}
+}
+
+// Offer предлагает элемент выборке; он попадает в нее с вероятностью k/n,
+// где n — число всех предложенных элементов.
+func (r *Reservoir[T]) Offer(item T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seen++
+	if len(r.samples) < r.k {
+		r.samples = append(r.samples, item)
+		return
+	}
+	if j := r.rnd.Intn(r.seen); j < r.k {
+		r.samples[j] = item
+	}
+}
+
+// Samples возвращает копию текущей выборки: не больше k элементов.
+func (r *Reservoir[T]) Samples() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.samples)
+}
+
+// Seen возвращает число элементов, предложенных выборке.
+func (r *Reservoir[T]) Seen() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.seen
+}
+
+// SamplingStorage — LogStorage для потоков, которые не помещаются в хранилище целиком:
+// сохраняет равномерную выборку сообщений ограниченного размера.
+type SamplingStorage struct {
+	*Reservoir[*LogMessage]
+}
+
+// NewSamplingStorage создает хранилище, удерживающее выборку из k сообщений.
+func NewSamplingStorage(k int, seed int64) *SamplingStorage {
+	return &SamplingStorage{Reservoir: NewReservoir[*LogMessage](k, seed)}
+}
+
+// StoreLog реализует LogStorage.
+func (s *SamplingStorage) StoreLog(msg *LogMessage) error {
+	s.Offer(msg)
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestReservoirHoldsExactlyK(t *testing.T) {
+	r := NewReservoir[int](10, 1)
+	for i := range 3 {
+		r.Offer(i)
+	}
+	// Пока элементов меньше k, в выборке все они.
+	if got := r.Samples(); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Fatalf("Samples = %v, ожидалось [0 1 2]", got)
+	}
+
+	for i := 3; i < 1000; i++ {
+		r.Offer(i)
+	}
+	got := r.Samples()
+	if len(got) != 10 {
+		t.Fatalf("в выборке %d элементов, ожидалось 10", len(got))
+	}
+	if r.Seen() != 1000 {
+		t.Errorf("Seen = %d, ожидалось 1000", r.Seen())
+	}
+	slices.Sort(got)
+	if len(slices.Compact(got)) != 10 {
+		t.Errorf("в выборке есть повторы: %v", got)
+	}
+}
+
+func TestReservoirDeterministicWithSeed(t *testing.T) {
+	sample := func(seed int64) []int {
+		r := NewReservoir[int](5, seed)
+		for i := range 100 {
+			r.Offer(i)
+		}
+		return r.Samples()
+	}
+	if a, b := sample(42), sample(42); !slices.Equal(a, b) {
+		t.Errorf("одинаковый seed дал разные выборки: %v и %v", a, b)
+	}
+}
+
+func TestReservoirUniform(t *testing.T) {
+	const n, k, runs = 20, 5, 20000
+	counts := make([]int, n)
+	for seed := range int64(runs) {
+		r := NewReservoir[int](k, seed)
+		for i := range n {
+			r.Offer(i)
+		}
+		for _, v := range r.Samples() {
+			counts[v]++
+		}
+	}
+
+	// Каждый элемент попадает в выборку с вероятностью k/n. Проверяем критерием хи-квадрат:
+	// при 19 степенях свободы значение выше 43.8 случайно встречается реже, чем в 0.1% случаев.
+	expected := float64(runs) * k / n
+	var chi2 float64
+	for _, c := range counts {
+		d := float64(c) - expected
+		chi2 += d * d / expected
+	}
+	if chi2 > 43.8 || math.IsNaN(chi2) {
+		t.Errorf("распределение неравномерно: хи-квадрат = %.1f, частоты %v", chi2, counts)
+	}
+}
+
+func TestReservoirConcurrent(t *testing.T) {
+	r := NewReservoir[int](8, 1)
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 500 {
+				r.Offer(g*500 + i)
+			}
+		}()
+	}
+	wg.Wait()
+	if r.Seen() != 4000 || len(r.Samples()) != 8 {
+		t.Errorf("Seen = %d, в выборке %d; ожидалось 4000 и 8", r.Seen(), len(r.Samples()))
+	}
+}
+
+func TestSamplingStorageInAggregator(t *testing.T) {
+	var messages []*LogMessage
+	for i := range 200 {
+		messages = append(messages, &LogMessage{Level: "INFO", Message: fmt.Sprintf("msg %d", i)})
+	}
+	storage := NewSamplingStorage(10, 7)
+	NewLogAggregator(&mockReader{messages: messages}, nil, storage, 4).Aggregate()
+
+	if storage.Seen() != 200 {
+		t.Errorf("хранилище получило %d сообщений, ожидалось 200", storage.Seen())
+	}
+	if got := storage.Samples(); len(got) != 10 {
+		t.Errorf("сохранено %d сообщений, ожидалось 10", len(got))
+	}
+}
+
+func TestNewReservoirPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ожидалась паника при нулевом размере выборки")
+		}
+	}()
+	NewReservoir[int](0, 1)
+}